@@ -0,0 +1,14 @@
+data "sonarqube_alm_setting_validation" "github" {
+  key = "my-github"
+
+  lifecycle {
+    postcondition {
+      condition     = self.valid
+      error_message = "Sonarqube can not reach GitHub: ${self.error_message}"
+    }
+  }
+}
+
+data "sonarqube_alm_setting_validation" "binding" {
+  project = "my-project"
+}
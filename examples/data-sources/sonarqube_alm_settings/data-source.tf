@@ -0,0 +1,5 @@
+data "sonarqube_alm_settings" "all" {}
+
+output "unused_alm_settings" {
+  value = [for alm_setting in data.sonarqube_alm_settings.all.alm_settings : alm_setting.key if alm_setting.projects == 0]
+}
@@ -0,0 +1,7 @@
+data "sonarqube_project_branches" "branches" {
+  project = "my_project"
+}
+
+output "inactive_branches" {
+  value = [for branch in data.sonarqube_project_branches.branches.branches : branch.name if !branch.excluded_from_purge]
+}
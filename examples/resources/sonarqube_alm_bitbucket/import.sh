@@ -0,0 +1,2 @@
+# Bitbucket Server integrations are imported using {key}/{personal_access_token}
+terraform import sonarqube_alm_bitbucket.bitbucket-alm mybitbucket/my-personal-access-token
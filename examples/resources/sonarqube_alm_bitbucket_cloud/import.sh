@@ -0,0 +1,2 @@
+# Bitbucket Cloud integrations are imported using {key}/{client_secret}
+terraform import sonarqube_alm_bitbucket_cloud.bitbucket-cloud-alm mybitbucketcloud/my-oauth-consumer-secret
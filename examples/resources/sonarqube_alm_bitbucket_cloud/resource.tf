@@ -0,0 +1,6 @@
+resource "sonarqube_alm_bitbucket_cloud" "bitbucket-cloud-alm" {
+  key           = "mybitbucketcloud"
+  client_id     = "my_oauth_consumer_key"
+  client_secret = "my_oauth_consumer_secret"
+  workspace     = "my-workspace"
+}
@@ -0,0 +1,2 @@
+# GitHub integrations are imported using {key}. The secrets can not be read back and are updated in place on the next apply.
+terraform import sonarqube_alm_github.github-alm github-alm
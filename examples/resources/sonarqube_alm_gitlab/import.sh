@@ -0,0 +1,2 @@
+# GitLab integrations are imported using {key}/{personal_access_token}
+terraform import sonarqube_alm_gitlab.gitlab-alm gitlab-alm/my-personal-access-token
@@ -0,0 +1,2 @@
+# Applications are imported using their key
+terraform import sonarqube_application.main my-application
@@ -0,0 +1,31 @@
+resource "sonarqube_project" "backend" {
+  name    = "Backend"
+  project = "backend"
+}
+
+resource "sonarqube_project" "frontend" {
+  name    = "Frontend"
+  project = "frontend"
+}
+
+resource "sonarqube_application" "main" {
+  key         = "my-application"
+  name        = "My application"
+  description = "The backend and the frontend of my application"
+  visibility  = "private"
+  tags        = ["web"]
+  projects    = [sonarqube_project.backend.project, sonarqube_project.frontend.project]
+
+  # The branches of the projects must have been analyzed before
+  branch {
+    name = "release"
+    project {
+      key    = sonarqube_project.backend.project
+      branch = "release-1.0"
+    }
+    project {
+      key    = sonarqube_project.frontend.project
+      branch = "release-2.3"
+    }
+  }
+}
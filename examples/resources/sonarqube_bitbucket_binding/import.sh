@@ -0,0 +1,2 @@
+# Bitbucket Server bindings are imported using {project}/{repository}/{slug}
+terraform import sonarqube_bitbucket_binding.bitbucket-binding my_project/PROJ/my-repo
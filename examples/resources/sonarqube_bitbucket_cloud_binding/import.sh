@@ -0,0 +1,2 @@
+# Bitbucket Cloud bindings are imported using {project}/{repository}
+terraform import sonarqube_bitbucket_cloud_binding.bitbucket-cloud-binding my_project/my-repo
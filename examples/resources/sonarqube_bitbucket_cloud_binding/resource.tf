@@ -0,0 +1,18 @@
+resource "sonarqube_alm_bitbucket_cloud" "bitbucket-cloud-alm" {
+  key           = "mybitbucketcloud"
+  client_id     = "my_oauth_consumer_key"
+  client_secret = "my_oauth_consumer_secret"
+  workspace     = "my-workspace"
+}
+
+resource "sonarqube_project" "main" {
+  name       = "SonarQube"
+  project    = "my_project"
+  visibility = "public"
+}
+
+resource "sonarqube_bitbucket_cloud_binding" "bitbucket-cloud-binding" {
+  alm_setting = sonarqube_alm_bitbucket_cloud.bitbucket-cloud-alm.key
+  project     = sonarqube_project.main.project
+  repository  = "my-repo"
+}
@@ -0,0 +1,6 @@
+# The global new code period is imported using "newCodePeriod"
+terraform import sonarqube_new_code_periods.code_period newCodePeriod
+
+# A project new code period is imported using {project}, a branch new code period using {project}/{branch}
+terraform import sonarqube_new_code_periods.reference my-project
+terraform import sonarqube_new_code_periods.reference my-project/main
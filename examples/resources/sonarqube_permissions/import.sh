@@ -0,0 +1,9 @@
+# Global permissions are imported using {type}/{name}, where type is "group" or "user"
+terraform import sonarqube_permissions.my_global_admins group/my-admins
+
+# Project permissions are imported using {project_key}/{type}/{name}. Group names may contain "/", the name is the rest of the id
+terraform import sonarqube_permissions.my_project_admins my-project/group/my-project-admins
+terraform import sonarqube_permissions.my_team my-project/group/my-team/backend
+
+# Permission template permissions are imported using template_id/{template_id}/{type}/{name} or template_name/{template_name}/{type}/{name}
+terraform import sonarqube_permissions.internal_admins template_name/my-template/group/my-project-admins
@@ -3,3 +3,23 @@ resource "sonarqube_portfolio" "main" {
   name        = "portfolio-name"
   description = "portfolio-description"
 }
+
+resource "sonarqube_application" "team" {
+  key  = "team-application"
+  name = "Team application"
+}
+
+resource "sonarqube_portfolio" "business_unit" {
+  key         = "business-unit"
+  name        = "Business unit"
+  description = "The portfolios and applications of the business unit"
+
+  children {
+    key  = sonarqube_portfolio.main.key
+    type = "portfolio"
+  }
+  children {
+    key  = sonarqube_application.team.key
+    type = "application"
+  }
+}
@@ -0,0 +1,10 @@
+resource "sonarqube_project" "main" {
+  name       = "SonarQube"
+  project    = "my_project"
+  visibility = "public"
+
+  # Set deletion_protection to false and apply before destroying the project
+  deletion_protection = true
+  # Keep a dump of the project on the server when it is deleted, requires the Enterprise edition
+  export_on_delete = true
+}
@@ -0,0 +1,2 @@
+# Project branches are imported using the project key and the branch name
+terraform import sonarqube_project_branch.release my_project/release/1.0
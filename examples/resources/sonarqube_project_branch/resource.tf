@@ -0,0 +1,12 @@
+resource "sonarqube_project" "main" {
+  name       = "SonarQube"
+  project    = "my_project"
+  visibility = "public"
+}
+
+# The branch must have been analyzed at least once
+resource "sonarqube_project_branch" "release" {
+  project             = sonarqube_project.main.project
+  name                = "release/1.0"
+  excluded_from_purge = true
+}
@@ -0,0 +1,2 @@
+# Project links are imported using the project key and the id of the link
+terraform import sonarqube_project_link.homepage my_project/AY000000000000000001
@@ -0,0 +1,17 @@
+resource "sonarqube_project" "main" {
+  name       = "SonarQube"
+  project    = "my_project"
+  visibility = "public"
+}
+
+resource "sonarqube_project_link" "homepage" {
+  project = sonarqube_project.main.project
+  name    = "homepage"
+  url     = "https://example.com"
+}
+
+resource "sonarqube_project_link" "runbook" {
+  project = sonarqube_project.main.project
+  name    = "Runbook"
+  url     = "https://example.com/runbook"
+}
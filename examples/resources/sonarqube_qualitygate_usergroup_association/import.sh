@@ -0,0 +1,5 @@
+# Group associations are imported using {gatename}/group/{group_name}
+terraform import sonarqube_qualitygate_usergroup_association.main my-gate/group/my-group
+
+# User associations are imported using {gatename}/user/{login_name}
+terraform import sonarqube_qualitygate_usergroup_association.main my-gate/user/my-user
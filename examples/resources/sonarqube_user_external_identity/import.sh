@@ -0,0 +1,2 @@
+# External identities are imported using the login name of the user
+terraform import sonarqube_user_external_identity.remote_user remote-user
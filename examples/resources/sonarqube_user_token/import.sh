@@ -0,0 +1,2 @@
+# User tokens are imported using {login_name}/{name}. The token value can not be read back and is left empty.
+terraform import sonarqube_user_token.token my-user/my-token
@@ -0,0 +1,333 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// AlmSettingsService wraps api/alm_settings
+type AlmSettingsService service
+
+// AlmDefinitions is returned by api/alm_settings/list_definitions. Secrets are never returned.
+type AlmDefinitions struct {
+	Azure          []AzureDefinition          `json:"azure"`
+	Bitbucket      []BitbucketDefinition      `json:"bitbucket"`
+	BitbucketCloud []BitbucketCloudDefinition `json:"bitbucketcloud"`
+	Github         []GithubDefinition         `json:"github"`
+	Gitlab         []GitlabDefinition         `json:"gitlab"`
+}
+
+// AzureDefinition is an Azure DevOps instance setting
+type AzureDefinition struct {
+	Key string `json:"key"`
+	URL string `json:"url"`
+}
+
+// BitbucketDefinition is a Bitbucket Server or Data Center instance setting
+type BitbucketDefinition struct {
+	Key string `json:"key"`
+	URL string `json:"url"`
+}
+
+// BitbucketCloudDefinition is a Bitbucket Cloud workspace setting
+type BitbucketCloudDefinition struct {
+	Key       string `json:"key"`
+	Workspace string `json:"workspace"`
+	ClientID  string `json:"clientId"`
+}
+
+// GithubDefinition is a GitHub instance setting
+type GithubDefinition struct {
+	Key      string `json:"key"`
+	URL      string `json:"url"`
+	AppID    string `json:"appId"`
+	ClientID string `json:"clientId"`
+}
+
+// GitlabDefinition is a GitLab instance setting
+type GitlabDefinition struct {
+	Key string `json:"key"`
+	URL string `json:"url"`
+}
+
+// AlmBinding is returned by api/alm_settings/get_binding.
+// For Azure DevOps Slug holds the project name and Repository the repository name.
+// For Bitbucket Server Repository holds the project key and Slug the repository slug.
+type AlmBinding struct {
+	Key                   string `json:"key"`
+	Alm                   string `json:"alm"`
+	Repository            string `json:"repository"`
+	Slug                  string `json:"slug"`
+	URL                   string `json:"url"`
+	SummaryCommentEnabled bool   `json:"summaryCommentEnabled,omitempty"`
+	Monorepo              bool   `json:"monorepo"`
+}
+
+// AlmBindingCount is returned by api/alm_settings/count_binding
+type AlmBindingCount struct {
+	Key      string `json:"key"`
+	Projects int    `json:"projects"`
+}
+
+// AlmValidation is the outcome of api/alm_settings/validate and api/alm_settings/validate_binding.
+// Message holds the reason SonarQube gave when the validation failed.
+type AlmValidation struct {
+	Valid   bool
+	Message string
+}
+
+// AzureRequest holds the parameters of api/alm_settings/create_azure and api/alm_settings/update_azure.
+// NewKey is only used on update.
+type AzureRequest struct {
+	Key                 string `url:"key"`
+	NewKey              string `url:"newKey,omitempty"`
+	PersonalAccessToken string `url:"personalAccessToken"`
+	URL                 string `url:"url"`
+}
+
+// BitbucketRequest holds the parameters of api/alm_settings/create_bitbucket and api/alm_settings/update_bitbucket.
+// NewKey is only used on update.
+type BitbucketRequest struct {
+	Key                 string `url:"key"`
+	NewKey              string `url:"newKey,omitempty"`
+	PersonalAccessToken string `url:"personalAccessToken"`
+	URL                 string `url:"url"`
+}
+
+// BitbucketCloudRequest holds the parameters of api/alm_settings/create_bitbucketcloud and
+// api/alm_settings/update_bitbucketcloud. NewKey is only used on update.
+type BitbucketCloudRequest struct {
+	Key          string `url:"key"`
+	NewKey       string `url:"newKey,omitempty"`
+	ClientID     string `url:"clientId"`
+	ClientSecret string `url:"clientSecret"`
+	Workspace    string `url:"workspace"`
+}
+
+// GithubRequest holds the parameters of api/alm_settings/create_github and api/alm_settings/update_github.
+// NewKey is only used on update.
+type GithubRequest struct {
+	Key           string `url:"key"`
+	NewKey        string `url:"newKey,omitempty"`
+	AppID         string `url:"appId"`
+	ClientID      string `url:"clientId"`
+	ClientSecret  string `url:"clientSecret"`
+	PrivateKey    string `url:"privateKey"`
+	URL           string `url:"url"`
+	WebhookSecret string `url:"webhookSecret"`
+}
+
+// GitlabRequest holds the parameters of api/alm_settings/create_gitlab and api/alm_settings/update_gitlab.
+// NewKey is only used on update.
+type GitlabRequest struct {
+	Key                 string `url:"key"`
+	NewKey              string `url:"newKey,omitempty"`
+	PersonalAccessToken string `url:"personalAccessToken"`
+	URL                 string `url:"url"`
+}
+
+// AzureBindingRequest holds the parameters of api/alm_settings/set_azure_binding
+type AzureBindingRequest struct {
+	AlmSetting     string `url:"almSetting"`
+	Monorepo       bool   `url:"monorepo"`
+	Project        string `url:"project"`
+	ProjectName    string `url:"projectName"`
+	RepositoryName string `url:"repositoryName"`
+}
+
+// BitbucketBindingRequest holds the parameters of api/alm_settings/set_bitbucket_binding.
+// Repository is the key of the Bitbucket project and Slug the slug of the repository.
+type BitbucketBindingRequest struct {
+	AlmSetting string `url:"almSetting"`
+	Monorepo   bool   `url:"monorepo"`
+	Project    string `url:"project"`
+	Repository string `url:"repository"`
+	Slug       string `url:"slug"`
+}
+
+// BitbucketCloudBindingRequest holds the parameters of api/alm_settings/set_bitbucketcloud_binding.
+// Repository is the slug of the repository.
+type BitbucketCloudBindingRequest struct {
+	AlmSetting string `url:"almSetting"`
+	Monorepo   bool   `url:"monorepo"`
+	Project    string `url:"project"`
+	Repository string `url:"repository"`
+}
+
+// GithubBindingRequest holds the parameters of api/alm_settings/set_github_binding
+type GithubBindingRequest struct {
+	AlmSetting            string `url:"almSetting"`
+	Monorepo              bool   `url:"monorepo"`
+	Project               string `url:"project"`
+	Repository            string `url:"repository"`
+	SummaryCommentEnabled bool   `url:"summaryCommentEnabled"`
+}
+
+// GitlabBindingRequest holds the parameters of api/alm_settings/set_gitlab_binding
+type GitlabBindingRequest struct {
+	AlmSetting string `url:"almSetting"`
+	Monorepo   bool   `url:"monorepo"`
+	Project    string `url:"project"`
+	Repository string `url:"repository"`
+}
+
+// ListDefinitions calls api/alm_settings/list_definitions
+func (s *AlmSettingsService) ListDefinitions(ctx context.Context) (*AlmDefinitions, error) {
+	definitions := AlmDefinitions{}
+	if err := s.client.get(ctx, "api/alm_settings/list_definitions", nil, &definitions); err != nil {
+		return nil, err
+	}
+	return &definitions, nil
+}
+
+// CountBinding calls api/alm_settings/count_binding
+func (s *AlmSettingsService) CountBinding(ctx context.Context, key string) (*AlmBindingCount, error) {
+	count := AlmBindingCount{}
+	params := url.Values{
+		"almSetting": []string{key},
+	}
+	if err := s.client.get(ctx, "api/alm_settings/count_binding", params, &count); err != nil {
+		return nil, err
+	}
+	return &count, nil
+}
+
+// Delete calls api/alm_settings/delete
+func (s *AlmSettingsService) Delete(ctx context.Context, key string) error {
+	params := url.Values{
+		"key": []string{key},
+	}
+	return s.client.post(ctx, "api/alm_settings/delete", params, nil)
+}
+
+// CreateAzure calls api/alm_settings/create_azure
+func (s *AlmSettingsService) CreateAzure(ctx context.Context, request AzureRequest) error {
+	return s.client.post(ctx, "api/alm_settings/create_azure", encode(request), nil)
+}
+
+// UpdateAzure calls api/alm_settings/update_azure
+func (s *AlmSettingsService) UpdateAzure(ctx context.Context, request AzureRequest) error {
+	return s.client.post(ctx, "api/alm_settings/update_azure", encode(request), nil)
+}
+
+// CreateBitbucket calls api/alm_settings/create_bitbucket
+func (s *AlmSettingsService) CreateBitbucket(ctx context.Context, request BitbucketRequest) error {
+	return s.client.post(ctx, "api/alm_settings/create_bitbucket", encode(request), nil)
+}
+
+// UpdateBitbucket calls api/alm_settings/update_bitbucket
+func (s *AlmSettingsService) UpdateBitbucket(ctx context.Context, request BitbucketRequest) error {
+	return s.client.post(ctx, "api/alm_settings/update_bitbucket", encode(request), nil)
+}
+
+// CreateBitbucketCloud calls api/alm_settings/create_bitbucketcloud
+func (s *AlmSettingsService) CreateBitbucketCloud(ctx context.Context, request BitbucketCloudRequest) error {
+	return s.client.post(ctx, "api/alm_settings/create_bitbucketcloud", encode(request), nil)
+}
+
+// UpdateBitbucketCloud calls api/alm_settings/update_bitbucketcloud
+func (s *AlmSettingsService) UpdateBitbucketCloud(ctx context.Context, request BitbucketCloudRequest) error {
+	return s.client.post(ctx, "api/alm_settings/update_bitbucketcloud", encode(request), nil)
+}
+
+// CreateGithub calls api/alm_settings/create_github
+func (s *AlmSettingsService) CreateGithub(ctx context.Context, request GithubRequest) error {
+	return s.client.post(ctx, "api/alm_settings/create_github", encode(request), nil)
+}
+
+// UpdateGithub calls api/alm_settings/update_github
+func (s *AlmSettingsService) UpdateGithub(ctx context.Context, request GithubRequest) error {
+	return s.client.post(ctx, "api/alm_settings/update_github", encode(request), nil)
+}
+
+// CreateGitlab calls api/alm_settings/create_gitlab
+func (s *AlmSettingsService) CreateGitlab(ctx context.Context, request GitlabRequest) error {
+	return s.client.post(ctx, "api/alm_settings/create_gitlab", encode(request), nil)
+}
+
+// UpdateGitlab calls api/alm_settings/update_gitlab
+func (s *AlmSettingsService) UpdateGitlab(ctx context.Context, request GitlabRequest) error {
+	return s.client.post(ctx, "api/alm_settings/update_gitlab", encode(request), nil)
+}
+
+// GetBinding calls api/alm_settings/get_binding
+func (s *AlmSettingsService) GetBinding(ctx context.Context, project string) (*AlmBinding, error) {
+	binding := AlmBinding{}
+	params := url.Values{
+		"project": []string{project},
+	}
+	if err := s.client.get(ctx, "api/alm_settings/get_binding", params, &binding); err != nil {
+		return nil, err
+	}
+	return &binding, nil
+}
+
+// DeleteBinding calls api/alm_settings/delete_binding
+func (s *AlmSettingsService) DeleteBinding(ctx context.Context, project string) error {
+	params := url.Values{
+		"project": []string{project},
+	}
+	return s.client.post(ctx, "api/alm_settings/delete_binding", params, nil)
+}
+
+// SetAzureBinding calls api/alm_settings/set_azure_binding
+func (s *AlmSettingsService) SetAzureBinding(ctx context.Context, request AzureBindingRequest) error {
+	return s.client.post(ctx, "api/alm_settings/set_azure_binding", encode(request), nil)
+}
+
+// SetBitbucketBinding calls api/alm_settings/set_bitbucket_binding
+func (s *AlmSettingsService) SetBitbucketBinding(ctx context.Context, request BitbucketBindingRequest) error {
+	return s.client.post(ctx, "api/alm_settings/set_bitbucket_binding", encode(request), nil)
+}
+
+// SetBitbucketCloudBinding calls api/alm_settings/set_bitbucketcloud_binding
+func (s *AlmSettingsService) SetBitbucketCloudBinding(ctx context.Context, request BitbucketCloudBindingRequest) error {
+	return s.client.post(ctx, "api/alm_settings/set_bitbucketcloud_binding", encode(request), nil)
+}
+
+// SetGithubBinding calls api/alm_settings/set_github_binding
+func (s *AlmSettingsService) SetGithubBinding(ctx context.Context, request GithubBindingRequest) error {
+	return s.client.post(ctx, "api/alm_settings/set_github_binding", encode(request), nil)
+}
+
+// SetGitlabBinding calls api/alm_settings/set_gitlab_binding
+func (s *AlmSettingsService) SetGitlabBinding(ctx context.Context, request GitlabBindingRequest) error {
+	return s.client.post(ctx, "api/alm_settings/set_gitlab_binding", encode(request), nil)
+}
+
+// Validate calls api/alm_settings/validate, which checks that SonarQube reaches the DevOps platform with the
+// credentials of the setting
+func (s *AlmSettingsService) Validate(ctx context.Context, key string) (*AlmValidation, error) {
+	params := url.Values{
+		"key": []string{key},
+	}
+	return almValidation(s.client.get(ctx, "api/alm_settings/validate", params, nil))
+}
+
+// ValidateBinding calls api/alm_settings/validate_binding, which checks that SonarQube reaches the repository bound
+// to a project
+func (s *AlmSettingsService) ValidateBinding(ctx context.Context, project string) (*AlmValidation, error) {
+	params := url.Values{
+		"project": []string{project},
+	}
+	return almValidation(s.client.get(ctx, "api/alm_settings/validate_binding", params, nil))
+}
+
+// almValidation converts the response of a validation. SonarQube reports a failed validation as a bad request.
+func almValidation(err error) (*AlmValidation, error) {
+	var apiError *APIError
+	if errors.As(err, &apiError) && apiError.StatusCode == http.StatusBadRequest {
+		message := apiError.Error()
+		if len(apiError.Errors) > 0 {
+			message = strings.Join(apiError.Messages(), "; ")
+		}
+		return &AlmValidation{Valid: false, Message: message}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &AlmValidation{Valid: true}, nil
+}
@@ -0,0 +1,55 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestAlmSettingsValidate(t *testing.T) {
+	cases := []struct {
+		name       string
+		statusCode int
+		body       string
+		want       *AlmValidation
+		wantErr    bool
+	}{
+		{
+			name:       "valid",
+			statusCode: http.StatusNoContent,
+			want:       &AlmValidation{Valid: true},
+		},
+		{
+			name:       "invalid",
+			statusCode: http.StatusBadRequest,
+			body:       `{"errors":[{"msg":"Invalid personal access token"}]}`,
+			want:       &AlmValidation{Valid: false, Message: "Invalid personal access token"},
+		},
+		{
+			name:       "unknown setting",
+			statusCode: http.StatusNotFound,
+			body:       `{"errors":[{"msg":"DevOps Platform Setting 'missing' not found"}]}`,
+			wantErr:    true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/sonar/api/alm_settings/validate" || r.URL.Query().Get("key") != "my-alm" {
+					t.Errorf("unexpected request %s?%s", r.URL.Path, r.URL.RawQuery)
+				}
+				w.WriteHeader(tc.statusCode)
+				w.Write([]byte(tc.body))
+			})
+
+			validation, err := c.AlmSettings.Validate(context.Background(), "my-alm")
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("err = %+v, wantErr %v", err, tc.wantErr)
+			}
+			if !reflect.DeepEqual(validation, tc.want) {
+				t.Errorf("validation = %+v, want %+v", validation, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package client
+
+import (
+	"context"
+	"net/url"
+	"strings"
+)
+
+// ApplicationsService wraps api/applications, which is only available in the Developer, Enterprise and Data Center
+// editions
+type ApplicationsService service
+
+// Application is returned by api/applications/create and api/applications/show
+type Application struct {
+	Key         string               `json:"key"`
+	Name        string               `json:"name"`
+	Description string               `json:"description,omitempty"`
+	Visibility  string               `json:"visibility"`
+	Branch      string               `json:"branch,omitempty"`
+	IsMain      bool                 `json:"isMain"`
+	Tags        []string             `json:"tags,omitempty"`
+	Projects    []ApplicationProject `json:"projects"`
+	Branches    []ApplicationBranch  `json:"branches,omitempty"`
+}
+
+// ApplicationProject is a project of an Application. When an application branch is shown, Branch is the branch of
+// the project included in it, and Selected reports whether the project is part of the application branch at all.
+type ApplicationProject struct {
+	Key      string `json:"key"`
+	Name     string `json:"name"`
+	Branch   string `json:"branch,omitempty"`
+	IsMain   bool   `json:"isMain"`
+	Enabled  bool   `json:"enabled"`
+	Selected bool   `json:"selected"`
+}
+
+// ApplicationBranch is a branch of an Application
+type ApplicationBranch struct {
+	Name   string `json:"name"`
+	IsMain bool   `json:"isMain"`
+}
+
+// ApplicationsCreateRequest holds the parameters of api/applications/create
+type ApplicationsCreateRequest struct {
+	Key         string `url:"key"`
+	Name        string `url:"name"`
+	Description string `url:"description,omitempty"`
+	Visibility  string `url:"visibility,omitempty"`
+}
+
+// ApplicationBranchRequest holds the parameters of api/applications/create_branch and api/applications/update_branch.
+// Projects and ProjectBranches are parallel lists: the application branch includes the branch ProjectBranches[i] of
+// the project Projects[i].
+type ApplicationBranchRequest struct {
+	Application     string   `url:"application"`
+	Branch          string   `url:"branch"`
+	Name            string   `url:"name,omitempty"`
+	Projects        []string `url:"project"`
+	ProjectBranches []string `url:"projectBranch"`
+}
+
+// Create calls api/applications/create
+func (s *ApplicationsService) Create(ctx context.Context, request ApplicationsCreateRequest) (*Application, error) {
+	response := struct {
+		Application Application `json:"application"`
+	}{}
+	if err := s.client.post(ctx, "api/applications/create", encode(request), &response); err != nil {
+		return nil, err
+	}
+	return &response.Application, nil
+}
+
+// Show calls api/applications/show. An empty branch shows the main branch of the application.
+func (s *ApplicationsService) Show(ctx context.Context, application string, branch string) (*Application, error) {
+	response := struct {
+		Application Application `json:"application"`
+	}{}
+	params := url.Values{
+		"application": []string{application},
+	}
+	if branch != "" {
+		params.Set("branch", branch)
+	}
+	if err := s.client.get(ctx, "api/applications/show", params, &response); err != nil {
+		return nil, err
+	}
+	return &response.Application, nil
+}
+
+// Update calls api/applications/update
+func (s *ApplicationsService) Update(ctx context.Context, application string, name string, description string) error {
+	params := url.Values{
+		"application": []string{application},
+		"name":        []string{name},
+		"description": []string{description},
+	}
+	return s.client.post(ctx, "api/applications/update", params, nil)
+}
+
+// Delete calls api/applications/delete
+func (s *ApplicationsService) Delete(ctx context.Context, application string) error {
+	params := url.Values{
+		"application": []string{application},
+	}
+	return s.client.post(ctx, "api/applications/delete", params, nil)
+}
+
+// SetTags calls api/applications/set_tags. An empty list of tags removes every tag from the application.
+func (s *ApplicationsService) SetTags(ctx context.Context, application string, tags []string) error {
+	params := url.Values{
+		"application": []string{application},
+		"tags":        []string{strings.Join(tags, ",")},
+	}
+	return s.client.post(ctx, "api/applications/set_tags", params, nil)
+}
+
+// AddProject calls api/applications/add_project
+func (s *ApplicationsService) AddProject(ctx context.Context, application string, project string) error {
+	params := url.Values{
+		"application": []string{application},
+		"project":     []string{project},
+	}
+	return s.client.post(ctx, "api/applications/add_project", params, nil)
+}
+
+// RemoveProject calls api/applications/remove_project
+func (s *ApplicationsService) RemoveProject(ctx context.Context, application string, project string) error {
+	params := url.Values{
+		"application": []string{application},
+		"project":     []string{project},
+	}
+	return s.client.post(ctx, "api/applications/remove_project", params, nil)
+}
+
+// CreateBranch calls api/applications/create_branch
+func (s *ApplicationsService) CreateBranch(ctx context.Context, request ApplicationBranchRequest) error {
+	return s.client.post(ctx, "api/applications/create_branch", encode(request), nil)
+}
+
+// UpdateBranch calls api/applications/update_branch, which renames an application branch and replaces its projects
+func (s *ApplicationsService) UpdateBranch(ctx context.Context, request ApplicationBranchRequest) error {
+	return s.client.post(ctx, "api/applications/update_branch", encode(request), nil)
+}
+
+// DeleteBranch calls api/applications/delete_branch
+func (s *ApplicationsService) DeleteBranch(ctx context.Context, application string, branch string) error {
+	params := url.Values{
+		"application": []string{application},
+		"branch":      []string{branch},
+	}
+	return s.client.post(ctx, "api/applications/delete_branch", params, nil)
+}
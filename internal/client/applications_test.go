@@ -0,0 +1,54 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestApplicationsCreateBranch(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/sonar/api/applications/create_branch" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		r.ParseForm()
+		if r.PostForm.Get("application") != "my-app" || r.PostForm.Get("branch") != "release" || r.PostForm.Has("name") {
+			t.Errorf("unexpected form %q", r.PostForm.Encode())
+		}
+		// The projects and their branches are sent as repeated parameters, in the same order
+		if !reflect.DeepEqual(r.PostForm["project"], []string{"a", "b"}) || !reflect.DeepEqual(r.PostForm["projectBranch"], []string{"release-1", "release-2"}) {
+			t.Errorf("unexpected form %q", r.PostForm.Encode())
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	err := c.Applications.CreateBranch(context.Background(), ApplicationBranchRequest{
+		Application:     "my-app",
+		Branch:          "release",
+		Projects:        []string{"a", "b"},
+		ProjectBranches: []string{"release-1", "release-2"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+}
+
+func TestApplicationsShowBranch(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("application") != "my-app" || r.URL.Query().Get("branch") != "release" {
+			t.Errorf("unexpected query %q", r.URL.RawQuery)
+		}
+		w.Write([]byte(`{"application":{"key":"my-app","name":"My App","visibility":"private","branch":"release","isMain":false,
+			"projects":[{"key":"a","name":"A","branch":"release-1","isMain":false,"enabled":true,"selected":true}]}}`))
+	})
+
+	application, err := c.Applications.Show(context.Background(), "my-app", "release")
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	want := []ApplicationProject{{Key: "a", Name: "A", Branch: "release-1", Enabled: true, Selected: true}}
+	if application.Branch != "release" || !reflect.DeepEqual(application.Projects, want) {
+		t.Errorf("application = %+v, want branch release with projects %+v", application, want)
+	}
+}
@@ -0,0 +1,25 @@
+package client
+
+import (
+	"encoding/base64"
+	"net/http"
+)
+
+// Authenticator adds the credentials to the headers of every request
+type Authenticator func(header http.Header)
+
+// BasicAuth authenticates with a login and password. A token can be used as login with an empty password, which is the
+// only way to authenticate with a token before SonarQube 10.0.
+func BasicAuth(login string, password string) Authenticator {
+	credentials := base64.StdEncoding.EncodeToString([]byte(login + ":" + password))
+	return func(header http.Header) {
+		header.Set("Authorization", "Basic "+credentials)
+	}
+}
+
+// BearerAuth authenticates with a token sent as Bearer token, supported since SonarQube 10.0
+func BearerAuth(token string) Authenticator {
+	return func(header http.Header) {
+		header.Set("Authorization", "Bearer "+token)
+	}
+}
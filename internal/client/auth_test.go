@@ -0,0 +1,71 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-retryablehttp"
+)
+
+func TestAuthenticators(t *testing.T) {
+	cases := []struct {
+		name          string
+		authenticator Authenticator
+		want          string
+	}{
+		{"basic", BasicAuth("admin", "secret"), "Basic YWRtaW46c2VjcmV0"},
+		{"basic token", BasicAuth("squ_token", ""), "Basic c3F1X3Rva2VuOg=="},
+		{"bearer", BearerAuth("squ_token"), "Bearer squ_token"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			header := http.Header{}
+			tc.authenticator(header)
+			if got := header.Get("Authorization"); got != tc.want {
+				t.Errorf("Authorization = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestClientSendsCredentialsInHeader(t *testing.T) {
+	var gotAuthorization string
+	var gotUser *url.Userinfo
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuthorization = r.Header.Get("Authorization")
+		gotUser = r.URL.User
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	t.Cleanup(server.Close)
+
+	// Credentials in the base URL must never be used, nor show up in errors
+	baseURL, _ := url.Parse(server.URL)
+	baseURL.User = url.UserPassword("admin", "url-secret")
+
+	httpClient := retryablehttp.NewClient()
+	httpClient.RetryMax = 0
+	httpClient.Logger = nil
+	c := New(httpClient, *baseURL, BearerAuth("squ_token"))
+
+	err := c.post(context.Background(), "api/users/change_password", url.Values{"password": []string{"new-secret"}}, nil)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	if gotAuthorization != "Bearer squ_token" {
+		t.Errorf("Authorization = %q, want %q", gotAuthorization, "Bearer squ_token")
+	}
+	if gotUser != nil {
+		t.Errorf("request URL contains user info %q", gotUser)
+	}
+	for _, secret := range []string{"url-secret", "new-secret", "squ_token"} {
+		if strings.Contains(err.Error(), secret) {
+			t.Errorf("error %q contains secret %q", err.Error(), secret)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package client
+
+import (
+	"context"
+	"net/url"
+)
+
+// CEService wraps api/ce, the web service of the compute engine running the background tasks of SonarQube
+type CEService service
+
+// CETask is a background task returned by api/ce/task
+type CETask struct {
+	ID           string `json:"id"`
+	Type         string `json:"type"`
+	ComponentKey string `json:"componentKey"`
+	// Status is one of PENDING, IN_PROGRESS, SUCCESS, FAILED or CANCELED
+	Status       string `json:"status"`
+	ErrorMessage string `json:"errorMessage"`
+	SubmittedAt  string `json:"submittedAt"`
+	ExecutedAt   string `json:"executedAt"`
+}
+
+// Task calls api/ce/task
+func (s *CEService) Task(ctx context.Context, id string) (*CETask, error) {
+	response := struct {
+		Task CETask `json:"task"`
+	}{}
+	params := url.Values{
+		"id": []string{id},
+	}
+	if err := s.client.get(ctx, "api/ce/task", params, &response); err != nil {
+		return nil, err
+	}
+	return &response.Task, nil
+}
@@ -0,0 +1,203 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+)
+
+// Client is a typed client for the SonarQube web API. Each web service is exposed as a field
+// holding one method per action of that service, e.g. Client.Projects.Create for api/projects/create.
+type Client struct {
+	httpClient    *retryablehttp.Client
+	baseURL       url.URL
+	authenticator Authenticator
+	// organization is sent with every request to a web service scoped to organizations, see SetOrganization
+	organization string
+
+	AlmSettings     *AlmSettingsService
+	Applications    *ApplicationsService
+	CE              *CEService
+	Components      *ComponentsService
+	NewCodePeriods  *NewCodePeriodsService
+	Permissions     *PermissionsService
+	Plugins         *PluginsService
+	ProjectDump     *ProjectDumpService
+	ProjectLinks    *ProjectLinksService
+	ProjectBranches *ProjectBranchesService
+	ProjectTags     *ProjectTagsService
+	Projects        *ProjectsService
+	QualityGates    *QualityGatesService
+	QualityProfiles *QualityProfilesService
+	Rules           *RulesService
+	Settings        *SettingsService
+	System          *SystemService
+	UserGroups      *UserGroupsService
+	UserTokens      *UserTokensService
+	Users           *UsersService
+	UsersManagement *UsersManagementService
+	Views           *ViewsService
+	Webhooks        *WebhooksService
+	Webservices     *WebservicesService
+}
+
+// service is embedded by every web service so they can share the underlying client
+type service struct {
+	client *Client
+}
+
+// New returns a Client sending requests through httpClient to the SonarQube instance at baseURL.
+// Credentials are only ever sent in the Authorization header, any user info in baseURL is dropped.
+func New(httpClient *retryablehttp.Client, baseURL url.URL, authenticator Authenticator) *Client {
+	baseURL.User = nil
+	c := &Client{
+		httpClient:    httpClient,
+		baseURL:       baseURL,
+		authenticator: authenticator,
+	}
+
+	common := &service{client: c}
+	c.AlmSettings = (*AlmSettingsService)(common)
+	c.Applications = (*ApplicationsService)(common)
+	c.CE = (*CEService)(common)
+	c.Components = (*ComponentsService)(common)
+	c.NewCodePeriods = (*NewCodePeriodsService)(common)
+	c.Permissions = (*PermissionsService)(common)
+	c.Plugins = (*PluginsService)(common)
+	c.ProjectDump = (*ProjectDumpService)(common)
+	c.ProjectLinks = (*ProjectLinksService)(common)
+	c.ProjectBranches = (*ProjectBranchesService)(common)
+	c.ProjectTags = (*ProjectTagsService)(common)
+	c.Projects = (*ProjectsService)(common)
+	c.QualityGates = (*QualityGatesService)(common)
+	c.QualityProfiles = (*QualityProfilesService)(common)
+	c.Rules = (*RulesService)(common)
+	c.Settings = (*SettingsService)(common)
+	c.System = (*SystemService)(common)
+	c.UserGroups = (*UserGroupsService)(common)
+	c.UserTokens = (*UserTokensService)(common)
+	c.Users = (*UsersService)(common)
+	c.UsersManagement = (*UsersManagementService)(common)
+	c.Views = (*ViewsService)(common)
+	c.Webhooks = (*WebhooksService)(common)
+	c.Webservices = (*WebservicesService)(common)
+	return c
+}
+
+// organizationScopedServices are the web services whose actions require an organization on SonarCloud
+var organizationScopedServices = []string{
+	"api/permissions/",
+	"api/projects/",
+	"api/qualitygates/",
+	"api/qualityprofiles/",
+	"api/rules/",
+	"api/user_groups/",
+	"api/webhooks/",
+}
+
+// SetOrganization makes the client send the organization parameter with every request to a web service scoped
+// to organizations, e.g. api/projects. SonarCloud requires it, SonarQube has no organizations.
+func (c *Client) SetOrganization(organization string) {
+	c.organization = organization
+}
+
+// withOrganization returns params with the organization of the client added when the endpoint requires it
+func (c *Client) withOrganization(endpoint string, params url.Values) url.Values {
+	if c.organization == "" || params.Has("organization") {
+		return params
+	}
+	for _, prefix := range organizationScopedServices {
+		if strings.HasPrefix(endpoint, prefix) {
+			scoped := url.Values{}
+			for key, values := range params {
+				scoped[key] = values
+			}
+			scoped.Set("organization", c.organization)
+			return scoped
+		}
+	}
+	return params
+}
+
+// Paging is returned by the /search style endpoints
+type Paging struct {
+	PageIndex int64 `json:"pageIndex"`
+	PageSize  int64 `json:"pageSize"`
+	Total     int64 `json:"total"`
+}
+
+func (c *Client) get(ctx context.Context, endpoint string, params url.Values, out interface{}) error {
+	return c.do(ctx, http.MethodGet, endpoint, params, out)
+}
+
+func (c *Client) post(ctx context.Context, endpoint string, params url.Values, out interface{}) error {
+	return c.do(ctx, http.MethodPost, endpoint, params, out)
+}
+
+// delete is only used by the v2 web API, which identifies resources by their path instead of parameters
+func (c *Client) delete(ctx context.Context, endpoint string, params url.Values, out interface{}) error {
+	return c.do(ctx, http.MethodDelete, endpoint, params, out)
+}
+
+// do sends a request to endpoint (e.g. "api/projects/create") and decodes a successful response into out.
+// out may be nil for actions that do not return a body.
+// POST parameters are form encoded in the body so values like passwords and secrets never appear in URLs.
+func (c *Client) do(ctx context.Context, method string, endpoint string, params url.Values, out interface{}) error {
+	params = c.withOrganization(endpoint, params)
+
+	u := c.baseURL
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + endpoint
+
+	var body interface{}
+	if method == http.MethodPost {
+		body = []byte(params.Encode())
+	} else {
+		u.RawQuery = params.Encode()
+	}
+
+	ctx, retries := withRetryCounter(ctx)
+	req, err := retryablehttp.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return fmt.Errorf("failed to prepare %s %s request: %w", method, endpoint, err)
+	}
+	if method == http.MethodPost {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	if c.authenticator != nil {
+		c.authenticator(req.Header)
+	}
+
+	start := time.Now()
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		logRequest(ctx, method, endpoint, params, 0, time.Since(start), *retries, nil)
+		return fmt.Errorf("failed to execute %s %s request: %w", method, endpoint, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read %s %s response body: %w", method, endpoint, err)
+	}
+	logRequest(ctx, method, endpoint, params, resp.StatusCode, time.Since(start), *retries, respBody)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return newAPIError(method, endpoint, resp.StatusCode, respBody)
+	}
+
+	if out == nil || len(respBody) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("failed to decode %s %s response: %w", method, endpoint, err)
+	}
+	return nil
+}
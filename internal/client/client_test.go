@@ -0,0 +1,145 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-retryablehttp"
+)
+
+// newTestClient returns a Client talking to an httptest server serving handler
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	httpClient := retryablehttp.NewClient()
+	httpClient.Logger = nil
+	// Return every response as is so tests can assert on the error mapping
+	httpClient.CheckRetry = func(ctx context.Context, resp *http.Response, err error) (bool, error) {
+		return false, err
+	}
+
+	baseURL, err := url.Parse(server.URL + "/sonar/")
+	if err != nil {
+		t.Fatalf("failed to parse test server url: %+v", err)
+	}
+	return New(httpClient, *baseURL, BasicAuth("admin", "secret"))
+}
+
+func TestClientDoBuildsRequest(t *testing.T) {
+	var gotMethod, gotPath, gotQuery, gotContentType string
+	var gotForm url.Values
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		gotMethod = r.Method
+		gotPath = r.URL.Path
+		gotQuery = r.URL.RawQuery
+		gotContentType = r.Header.Get("Content-Type")
+		r.ParseForm()
+		gotForm = r.PostForm
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	params := url.Values{"project": []string{"my-project"}}
+	if err := c.post(context.Background(), "api/projects/delete", params, nil); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+
+	if gotMethod != http.MethodPost {
+		t.Errorf("method = %q, want %q", gotMethod, http.MethodPost)
+	}
+	if gotPath != "/sonar/api/projects/delete" {
+		t.Errorf("path = %q, want %q", gotPath, "/sonar/api/projects/delete")
+	}
+	if gotQuery != "" {
+		t.Errorf("query = %q, POST parameters should be sent in the body", gotQuery)
+	}
+	if gotContentType != "application/x-www-form-urlencoded" {
+		t.Errorf("content type = %q, want %q", gotContentType, "application/x-www-form-urlencoded")
+	}
+	if gotForm.Get("project") != "my-project" {
+		t.Errorf("form = %q, want project=my-project", gotForm.Encode())
+	}
+}
+
+func TestClientDoGetParameters(t *testing.T) {
+	var gotQuery string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		gotQuery = r.URL.RawQuery
+		w.Write([]byte(`{}`))
+	})
+
+	params := url.Values{"project": []string{"my-project"}}
+	if err := c.get(context.Background(), "api/components/show", params, nil); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+
+	if gotQuery != "project=my-project" {
+		t.Errorf("query = %q, want %q", gotQuery, "project=my-project")
+	}
+}
+
+func TestClientSendsOrganization(t *testing.T) {
+	queries := map[string]string{}
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		queries[strings.TrimPrefix(r.URL.Path, "/sonar/")] = r.URL.RawQuery
+		w.Write([]byte(`{}`))
+	})
+	c.SetOrganization("my-org")
+
+	for _, endpoint := range []string{"api/projects/search", "api/components/show"} {
+		if err := c.get(context.Background(), endpoint, url.Values{"q": []string{"x"}}, nil); err != nil {
+			t.Fatalf("unexpected error: %+v", err)
+		}
+	}
+	// An explicit organization takes precedence
+	if err := c.get(context.Background(), "api/qualitygates/list", url.Values{"organization": []string{"other"}}, nil); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+
+	want := map[string]string{
+		"api/projects/search":   "organization=my-org&q=x",
+		"api/components/show":   "q=x",
+		"api/qualitygates/list": "organization=other",
+	}
+	for endpoint, query := range want {
+		if queries[endpoint] != query {
+			t.Errorf("%s query = %q, want %q", endpoint, queries[endpoint], query)
+		}
+	}
+}
+
+func TestClientDoDecodesResponse(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"paging":{"pageIndex":2,"pageSize":50,"total":120}}`))
+	})
+
+	out := struct {
+		Paging Paging `json:"paging"`
+	}{}
+	if err := c.get(context.Background(), "api/projects/search", nil, &out); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+
+	want := Paging{PageIndex: 2, PageSize: 50, Total: 120}
+	if out.Paging != want {
+		t.Errorf("paging = %+v, want %+v", out.Paging, want)
+	}
+}
+
+func TestClientDoInvalidJSON(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`not json`))
+	})
+
+	out := struct{}{}
+	err := c.get(context.Background(), "api/projects/search", nil, &out)
+	if err == nil || !strings.Contains(err.Error(), "failed to decode GET api/projects/search response") {
+		t.Errorf("error = %v, want a decode error", err)
+	}
+}
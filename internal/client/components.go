@@ -0,0 +1,35 @@
+package client
+
+import (
+	"context"
+	"net/url"
+)
+
+// ComponentsService wraps api/components
+type ComponentsService service
+
+// Component is a project, portfolio or application as returned by api/components/show
+type Component struct {
+	Key          string   `json:"key"`
+	Name         string   `json:"name"`
+	Description  string   `json:"description"`
+	Qualifier    string   `json:"qualifier"`
+	AnalysisDate string   `json:"analysisDate"`
+	Version      string   `json:"version"`
+	Tags         []string `json:"tags,omitempty"`
+	Visibility   string   `json:"visibility"`
+}
+
+// Show calls api/components/show
+func (s *ComponentsService) Show(ctx context.Context, component string) (*Component, error) {
+	response := struct {
+		Component Component `json:"component"`
+	}{}
+	params := url.Values{
+		"component": []string{component},
+	}
+	if err := s.client.get(ctx, "api/components/show", params, &response); err != nil {
+		return nil, err
+	}
+	return &response.Component, nil
+}
@@ -0,0 +1,72 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ErrorMessage is a single entry of the errors SonarQube returns for a failed request
+type ErrorMessage struct {
+	Message string `json:"msg,omitempty"`
+}
+
+// APIError is returned for every response with a non 2xx status code
+type APIError struct {
+	Method     string
+	Endpoint   string
+	StatusCode int
+	Errors     []ErrorMessage
+}
+
+// newAPIError builds an APIError from a failed response. The body is only used when it holds SonarQube error messages.
+func newAPIError(method string, endpoint string, statusCode int, body []byte) *APIError {
+	apiError := &APIError{
+		Method:     method,
+		Endpoint:   endpoint,
+		StatusCode: statusCode,
+	}
+
+	// The v2 web API returns a single message instead of a list of errors
+	errorResponse := struct {
+		Errors  []ErrorMessage `json:"errors"`
+		Message string         `json:"message"`
+	}{}
+	if len(body) > 0 && json.Unmarshal(body, &errorResponse) == nil {
+		apiError.Errors = errorResponse.Errors
+		if len(apiError.Errors) == 0 && errorResponse.Message != "" {
+			apiError.Errors = []ErrorMessage{{Message: errorResponse.Message}}
+		}
+	}
+	return apiError
+}
+
+func (e *APIError) Error() string {
+	if len(e.Errors) == 0 {
+		return fmt.Sprintf("%s %s returned status code %d", e.Method, e.Endpoint, e.StatusCode)
+	}
+	return fmt.Sprintf("%s %s returned status code %d: %s", e.Method, e.Endpoint, e.StatusCode, strings.Join(e.Messages(), "; "))
+}
+
+// Messages returns every message SonarQube returned
+func (e *APIError) Messages() []string {
+	messages := make([]string, len(e.Errors))
+	for i, errorMessage := range e.Errors {
+		messages[i] = errorMessage.Message
+	}
+	return messages
+}
+
+// IsNotFound reports whether err is an APIError for a 404 response, i.e. the requested object does not exist
+func IsNotFound(err error) bool {
+	var apiError *APIError
+	return errors.As(err, &apiError) && apiError.StatusCode == http.StatusNotFound
+}
+
+// IsForbidden reports whether err is an APIError for a request the credentials are not allowed to make
+func IsForbidden(err error) bool {
+	var apiError *APIError
+	return errors.As(err, &apiError) && (apiError.StatusCode == http.StatusUnauthorized || apiError.StatusCode == http.StatusForbidden)
+}
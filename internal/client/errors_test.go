@@ -0,0 +1,113 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestAPIError(t *testing.T) {
+	cases := []struct {
+		name       string
+		statusCode int
+		body       string
+		want       string
+		messages   []string
+	}{
+		{
+			name:       "single message",
+			statusCode: http.StatusBadRequest,
+			body:       `{"errors":[{"msg":"Project key already exists"}]}`,
+			want:       "POST api/projects/create returned status code 400: Project key already exists",
+			messages:   []string{"Project key already exists"},
+		},
+		{
+			name:       "multiple messages",
+			statusCode: http.StatusBadRequest,
+			body:       `{"errors":[{"msg":"first"},{"msg":"second"}]}`,
+			want:       "POST api/projects/create returned status code 400: first; second",
+			messages:   []string{"first", "second"},
+		},
+		{
+			name:       "v2 message",
+			statusCode: http.StatusNotFound,
+			body:       `{"message":"User 'AYq' not found"}`,
+			want:       "POST api/projects/create returned status code 404: User 'AYq' not found",
+			messages:   []string{"User 'AYq' not found"},
+		},
+		{
+			name:       "empty errors",
+			statusCode: http.StatusBadRequest,
+			body:       `{"errors":[]}`,
+			want:       "POST api/projects/create returned status code 400",
+			messages:   []string{},
+		},
+		{
+			name:       "empty body",
+			statusCode: http.StatusNotFound,
+			body:       ``,
+			want:       "POST api/projects/create returned status code 404",
+			messages:   []string{},
+		},
+		{
+			name:       "body without errors",
+			statusCode: http.StatusInternalServerError,
+			body:       `<html>oops</html>`,
+			want:       "POST api/projects/create returned status code 500",
+			messages:   []string{},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.statusCode)
+				w.Write([]byte(tc.body))
+			})
+
+			err := c.post(context.Background(), "api/projects/create", nil, nil)
+
+			var apiError *APIError
+			if !errors.As(err, &apiError) {
+				t.Fatalf("error = %v, want an *APIError", err)
+			}
+			if apiError.Method != http.MethodPost || apiError.Endpoint != "api/projects/create" || apiError.StatusCode != tc.statusCode {
+				t.Errorf("unexpected error fields %+v", apiError)
+			}
+			if err.Error() != tc.want {
+				t.Errorf("error = %q, want %q", err.Error(), tc.want)
+			}
+			if !reflect.DeepEqual(apiError.Messages(), tc.messages) {
+				t.Errorf("messages = %q, want %q", apiError.Messages(), tc.messages)
+			}
+		})
+	}
+}
+
+func TestIsNotFound(t *testing.T) {
+	notFound := &APIError{Method: http.MethodGet, Endpoint: "api/qualitygates/show", StatusCode: http.StatusNotFound}
+	badRequest := &APIError{Method: http.MethodGet, Endpoint: "api/qualitygates/show", StatusCode: http.StatusBadRequest}
+
+	cases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"not found", notFound, true},
+		{"wrapped not found", fmt.Errorf("resourceSonarqubeQualityGateRead: %w", notFound), true},
+		{"other status code", badRequest, false},
+		{"other error", errors.New("connection refused"), false},
+		{"nil", nil, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := IsNotFound(tc.err); got != tc.want {
+				t.Errorf("IsNotFound() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
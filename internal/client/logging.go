@@ -0,0 +1,116 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// maxLoggedBodySize is the number of bytes of a response body written to the logs
+const maxLoggedBodySize = 4096
+
+// redacted replaces the value of secret parameters and response fields in the logs
+const redacted = "***"
+
+// secretFields are the parameters and response fields that are never logged. Names are compared case insensitively and
+// without underscores, so "personal_access_token" also matches the "personalAccessToken" parameter of the web API.
+var secretFields = map[string]bool{
+	"token":               true,
+	"pass":                true,
+	"password":            true,
+	"previouspassword":    true,
+	"secret":              true,
+	"personalaccesstoken": true,
+	"privatekey":          true,
+	"clientsecret":        true,
+	"webhooksecret":       true,
+}
+
+// isSecretField reports whether the parameter or response field name holds a secret
+func isSecretField(name string) bool {
+	return secretFields[strings.ToLower(strings.ReplaceAll(name, "_", ""))]
+}
+
+// isSecuredSetting reports whether key is a setting SonarQube stores encrypted, e.g. sonar.auth.github.clientSecret.secured
+func isSecuredSetting(key string) bool {
+	return strings.HasPrefix(key, "sonar.") && strings.HasSuffix(key, ".secured")
+}
+
+// redactParameters returns a copy of params with the values of secrets masked. The values of a secured setting are
+// masked as well, as api/settings/set sends them in the value, values and fieldValues parameters.
+func redactParameters(params url.Values) url.Values {
+	securedSetting := isSecuredSetting(params.Get("key"))
+	redactedParams := make(url.Values, len(params))
+	for name, values := range params {
+		if isSecretField(name) || (securedSetting && (name == "value" || name == "values" || name == "fieldValues")) {
+			redactedParams[name] = []string{redacted}
+			continue
+		}
+		redactedParams[name] = values
+	}
+	return redactedParams
+}
+
+// redactBody returns body with the values of secret fields masked, truncated to maxLoggedBodySize bytes.
+// Bodies that are not JSON are only truncated.
+func redactBody(body []byte) string {
+	var decoded interface{}
+	if err := json.Unmarshal(body, &decoded); err == nil {
+		if encoded, err := json.Marshal(redactValue(decoded)); err == nil {
+			body = encoded
+		}
+	}
+
+	if len(body) > maxLoggedBodySize {
+		return string(body[:maxLoggedBodySize]) + "...(truncated)"
+	}
+	return string(body)
+}
+
+// redactValue masks the secret fields of a decoded JSON value, including the values of secured settings
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		key, _ := v["key"].(string)
+		securedSetting := isSecuredSetting(key)
+		for field, fieldValue := range v {
+			if isSecretField(field) || (securedSetting && (field == "value" || field == "values" || field == "fieldValues")) {
+				v[field] = redacted
+				continue
+			}
+			v[field] = redactValue(fieldValue)
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = redactValue(v[i])
+		}
+	}
+	return value
+}
+
+// retriesKey is the context key holding the retry count of the request being sent
+type retriesKey struct{}
+
+// withRetryCounter returns a context LogRetry records the number of retries of a request into
+func withRetryCounter(ctx context.Context) (context.Context, *int) {
+	retries := 0
+	return context.WithValue(ctx, retriesKey{}, &retries), &retries
+}
+
+// logRequest writes one debug entry per request with its redacted parameters, the response status, latency,
+// number of retries and the redacted and truncated response body
+func logRequest(ctx context.Context, method string, endpoint string, params url.Values, statusCode int, latency time.Duration, retries int, body []byte) {
+	tflog.Debug(ctx, "SonarQube API request", map[string]interface{}{
+		"method":      method,
+		"endpoint":    endpoint,
+		"parameters":  map[string][]string(redactParameters(params)),
+		"status_code": statusCode,
+		"latency_ms":  latency.Milliseconds(),
+		"retries":     retries,
+		"body":        redactBody(body),
+	})
+}
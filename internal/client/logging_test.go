@@ -0,0 +1,101 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+)
+
+func TestRedactParameters(t *testing.T) {
+	params := url.Values{
+		"name":                []string{"my-alm"},
+		"personalAccessToken": []string{"glpat-123"},
+		"clientSecret":        []string{"s3cr3t"},
+		"password":            []string{"hunter2"},
+	}
+
+	want := url.Values{
+		"name":                []string{"my-alm"},
+		"personalAccessToken": []string{redacted},
+		"clientSecret":        []string{redacted},
+		"password":            []string{redacted},
+	}
+	if got := redactParameters(params); !reflect.DeepEqual(got, want) {
+		t.Errorf("redactParameters = %v, want %v", got, want)
+	}
+	if params.Get("password") != "hunter2" {
+		t.Errorf("redactParameters must not modify its argument")
+	}
+}
+
+func TestRedactParametersSecuredSetting(t *testing.T) {
+	params := url.Values{
+		"key":   []string{"sonar.auth.github.clientSecret.secured"},
+		"value": []string{"s3cr3t"},
+	}
+
+	got := redactParameters(params)
+	if got.Get("key") != "sonar.auth.github.clientSecret.secured" || got.Get("value") != redacted {
+		t.Errorf("redactParameters = %v, want the value masked", got)
+	}
+}
+
+func TestRedactBody(t *testing.T) {
+	body := `{"login":"admin","name":"ci","token":"squ_123","settings":[{"key":"sonar.a.secured","value":"x"},{"key":"sonar.b","value":"y"}]}`
+
+	got := redactBody([]byte(body))
+	for _, secret := range []string{"squ_123", `"x"`} {
+		if strings.Contains(got, secret) {
+			t.Errorf("redactBody = %s, must not contain %s", got, secret)
+		}
+	}
+	for _, kept := range []string{`"login":"admin"`, `"value":"y"`} {
+		if !strings.Contains(got, kept) {
+			t.Errorf("redactBody = %s, want it to contain %s", got, kept)
+		}
+	}
+}
+
+func TestRedactBodyTruncates(t *testing.T) {
+	got := redactBody(bytes.Repeat([]byte("a"), maxLoggedBodySize+10))
+	if len(got) != maxLoggedBodySize+len("...(truncated)") || !strings.HasSuffix(got, "...(truncated)") {
+		t.Errorf("redactBody returned %d bytes, want the body truncated to %d bytes", len(got), maxLoggedBodySize)
+	}
+}
+
+func TestClientLogsRequests(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"login":"admin","name":"ci","token":"squ_123"}`))
+	})
+
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+	if _, err := c.UserTokens.Generate(ctx, UserTokensGenerateRequest{Login: "admin", Name: "ci"}); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+
+	entries, err := tflogtest.MultilineJSONDecode(&output)
+	if err != nil {
+		t.Fatalf("failed to decode log entries: %+v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("logged %d entries, want 1: %v", len(entries), entries)
+	}
+
+	entry := entries[0]
+	if entry["@message"] != "SonarQube API request" || entry["method"] != "POST" || entry["endpoint"] != "api/user_tokens/generate" {
+		t.Errorf("unexpected entry %v", entry)
+	}
+	if entry["status_code"] != float64(http.StatusOK) || entry["retries"] != float64(0) {
+		t.Errorf("unexpected status code or retries in %v", entry)
+	}
+	if body, _ := entry["body"].(string); strings.Contains(body, "squ_123") || !strings.Contains(body, `"name":"ci"`) {
+		t.Errorf("body = %q, want the token masked", body)
+	}
+}
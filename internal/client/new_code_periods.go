@@ -0,0 +1,57 @@
+package client
+
+import (
+	"context"
+	"net/url"
+)
+
+// NewCodePeriodsService wraps api/new_code_periods
+type NewCodePeriodsService service
+
+// NewCodePeriod is returned by api/new_code_periods/show
+type NewCodePeriod struct {
+	Project        string `json:"projectKey"`
+	Branch         string `json:"branchKey"`
+	Type           string `json:"type"`
+	Value          string `json:"value,omitempty"`
+	EffectiveValue string `json:"effectiveValue"`
+	Inherited      bool   `json:"inherited"`
+}
+
+// NewCodePeriodsSetRequest holds the parameters of api/new_code_periods/set
+type NewCodePeriodsSetRequest struct {
+	Type    string `url:"type"`
+	Branch  string `url:"branch,omitempty"`
+	Project string `url:"project,omitempty"`
+	Value   string `url:"value,omitempty"`
+}
+
+// Set calls api/new_code_periods/set
+func (s *NewCodePeriodsService) Set(ctx context.Context, request NewCodePeriodsSetRequest) error {
+	return s.client.post(ctx, "api/new_code_periods/set", encode(request), nil)
+}
+
+// Show calls api/new_code_periods/show. Leaving project and branch empty shows the global setting.
+func (s *NewCodePeriodsService) Show(ctx context.Context, project string, branch string) (*NewCodePeriod, error) {
+	period := NewCodePeriod{}
+	if err := s.client.get(ctx, "api/new_code_periods/show", newCodePeriodScope(project, branch), &period); err != nil {
+		return nil, err
+	}
+	return &period, nil
+}
+
+// Unset calls api/new_code_periods/unset. Leaving project and branch empty unsets the global setting.
+func (s *NewCodePeriodsService) Unset(ctx context.Context, project string, branch string) error {
+	return s.client.post(ctx, "api/new_code_periods/unset", newCodePeriodScope(project, branch), nil)
+}
+
+func newCodePeriodScope(project string, branch string) url.Values {
+	params := url.Values{}
+	if branch != "" {
+		params.Add("branch", branch)
+	}
+	if project != "" {
+		params.Add("project", project)
+	}
+	return params
+}
@@ -0,0 +1,73 @@
+package client
+
+import (
+	"context"
+)
+
+const (
+	// maxPageSize is the largest page size accepted by most paginated endpoints
+	maxPageSize = 500
+	// maxPermissionsPageSize is the largest page size accepted by the api/permissions and api/qualitygates search endpoints
+	maxPermissionsPageSize = 100
+)
+
+// Pager walks every page of a paginated endpoint. The *Pages methods of the services return one, e.g. UsersService.SearchPages.
+type Pager[T any] struct {
+	fetch func(ctx context.Context, page int) ([]T, Paging, error)
+}
+
+// newPager returns a Pager calling fetch for the pages 1, 2, ... until Paging.Total items have been returned
+func newPager[T any](fetch func(ctx context.Context, page int) ([]T, Paging, error)) *Pager[T] {
+	return &Pager[T]{fetch: fetch}
+}
+
+// Each calls fn for every item in order and stops as soon as fn returns false. Pages are only requested when needed.
+func (p *Pager[T]) Each(ctx context.Context, fn func(item T) bool) error {
+	seen := int64(0)
+	for page := 1; ; page++ {
+		items, paging, err := p.fetch(ctx, page)
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			if !fn(item) {
+				return nil
+			}
+		}
+
+		seen += int64(len(items))
+		// An empty page also ends the walk so a wrong total can not make us loop forever
+		if len(items) == 0 || seen >= paging.Total {
+			return nil
+		}
+	}
+}
+
+// All returns the items of every page
+func (p *Pager[T]) All(ctx context.Context) ([]T, error) {
+	var all []T
+	err := p.Each(ctx, func(item T) bool {
+		all = append(all, item)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return all, nil
+}
+
+// Find returns the first item for which match returns true, or nil when no item matches
+func (p *Pager[T]) Find(ctx context.Context, match func(item T) bool) (*T, error) {
+	var found *T
+	err := p.Each(ctx, func(item T) bool {
+		if match(item) {
+			found = &item
+			return false
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return found, nil
+}
@@ -0,0 +1,162 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+// testPager serves total items split into pages of pageSize and records the requested pages
+func testPager(total int, pageSize int, requested *[]int) *Pager[int] {
+	return newPager(func(ctx context.Context, page int) ([]int, Paging, error) {
+		*requested = append(*requested, page)
+		var items []int
+		for i := (page - 1) * pageSize; i < page*pageSize && i < total; i++ {
+			items = append(items, i)
+		}
+		return items, Paging{PageIndex: int64(page), PageSize: int64(pageSize), Total: int64(total)}, nil
+	})
+}
+
+func TestPagerAll(t *testing.T) {
+	var requested []int
+	items, err := testPager(7, 3, &requested).All(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+
+	if want := []int{0, 1, 2, 3, 4, 5, 6}; !reflect.DeepEqual(items, want) {
+		t.Errorf("items = %v, want %v", items, want)
+	}
+	if want := []int{1, 2, 3}; !reflect.DeepEqual(requested, want) {
+		t.Errorf("requested pages = %v, want %v", requested, want)
+	}
+}
+
+func TestPagerAllEmpty(t *testing.T) {
+	var requested []int
+	items, err := testPager(0, 3, &requested).All(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+
+	if len(items) != 0 {
+		t.Errorf("items = %v, want none", items)
+	}
+	if want := []int{1}; !reflect.DeepEqual(requested, want) {
+		t.Errorf("requested pages = %v, want %v", requested, want)
+	}
+}
+
+func TestPagerFindStopsEarly(t *testing.T) {
+	var requested []int
+	item, err := testPager(100, 3, &requested).Find(context.Background(), func(item int) bool {
+		return item == 4
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+
+	if item == nil || *item != 4 {
+		t.Errorf("item = %v, want 4", item)
+	}
+	if want := []int{1, 2}; !reflect.DeepEqual(requested, want) {
+		t.Errorf("requested pages = %v, want %v", requested, want)
+	}
+}
+
+func TestPagerFindNoMatch(t *testing.T) {
+	var requested []int
+	item, err := testPager(5, 2, &requested).Find(context.Background(), func(item int) bool {
+		return item == 42
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+
+	if item != nil {
+		t.Errorf("item = %v, want nil", *item)
+	}
+	if want := []int{1, 2, 3}; !reflect.DeepEqual(requested, want) {
+		t.Errorf("requested pages = %v, want %v", requested, want)
+	}
+}
+
+func TestPagerStopsOnEmptyPage(t *testing.T) {
+	// A total larger than the number of items must not make the pager loop forever
+	pages := 0
+	pager := newPager(func(ctx context.Context, page int) ([]int, Paging, error) {
+		pages++
+		if page > 1 {
+			return nil, Paging{Total: 10}, nil
+		}
+		return []int{1, 2}, Paging{Total: 10}, nil
+	})
+
+	if _, err := pager.All(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	if pages != 2 {
+		t.Errorf("pages = %d, want 2", pages)
+	}
+}
+
+func TestPagerError(t *testing.T) {
+	want := errors.New("boom")
+	pager := newPager(func(ctx context.Context, page int) ([]int, Paging, error) {
+		return nil, Paging{}, want
+	})
+
+	if _, err := pager.All(context.Background()); !errors.Is(err, want) {
+		t.Errorf("error = %v, want %v", err, want)
+	}
+}
+
+func TestUsersSearchPages(t *testing.T) {
+	var requested []string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		requested = append(requested, query.Get("p")+"/"+query.Get("ps"))
+		page, _ := strconv.Atoi(query.Get("p"))
+		fmt.Fprintf(w, `{"paging":{"pageIndex":%d,"pageSize":500,"total":1001},"users":[{"login":"user-%d"}]}`, page, page)
+	})
+
+	user, err := c.Users.SearchPages(UsersSearchRequest{Query: "user"}).Find(context.Background(), func(user User) bool {
+		return user.Login == "user-3"
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+
+	if user == nil || user.Login != "user-3" {
+		t.Errorf("user = %+v, want user-3", user)
+	}
+	if want := []string{"1/500", "2/500", "3/500"}; !reflect.DeepEqual(requested, want) {
+		t.Errorf("requested pages = %v, want %v", requested, want)
+	}
+}
+
+func TestRulesSearchPages(t *testing.T) {
+	var requested []string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.Query().Get("p"))
+		// api/rules/search reports paging at the top level
+		fmt.Fprintf(w, `{"total":2,"p":%s,"ps":1,"rules":[{"key":"rule-%s"}]}`, r.URL.Query().Get("p"), r.URL.Query().Get("p"))
+	})
+
+	rules, err := c.Rules.SearchPages(RulesSearchRequest{PageSize: 1}).All(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+
+	if len(rules) != 2 || rules[0].Key != "rule-1" || rules[1].Key != "rule-2" {
+		t.Errorf("rules = %+v, want rule-1 and rule-2", rules)
+	}
+	if want := []string{"1", "2"}; !reflect.DeepEqual(requested, want) {
+		t.Errorf("requested pages = %v, want %v", requested, want)
+	}
+}
@@ -0,0 +1,261 @@
+package client
+
+import (
+	"context"
+	"net/url"
+)
+
+// PermissionsService wraps api/permissions
+type PermissionsService service
+
+// PermissionsRequest holds the parameters shared by the api/permissions add_* and remove_* actions.
+// Login or GroupName selects the target, ProjectKey, TemplateID or TemplateName the scope.
+type PermissionsRequest struct {
+	Permission   string `url:"permission"`
+	Login        string `url:"login,omitempty"`
+	GroupName    string `url:"groupName,omitempty"`
+	ProjectKey   string `url:"projectKey,omitempty"`
+	TemplateID   string `url:"templateId,omitempty"`
+	TemplateName string `url:"templateName,omitempty"`
+}
+
+// PermissionsSearchRequest holds the parameters of the api/permissions users, groups, template_users and template_groups actions
+type PermissionsSearchRequest struct {
+	ProjectKey   string `url:"projectKey,omitempty"`
+	TemplateID   string `url:"templateId,omitempty"`
+	TemplateName string `url:"templateName,omitempty"`
+	Query        string `url:"q,omitempty"`
+	Page         int    `url:"p,omitempty"`
+	PageSize     int    `url:"ps,omitempty"`
+}
+
+// UserPermissions is returned by api/permissions/users and api/permissions/template_users
+type UserPermissions struct {
+	Login       string   `json:"login"`
+	Name        string   `json:"name,omitempty"`
+	Email       string   `json:"email,omitempty"`
+	Permissions []string `json:"permissions,omitempty"`
+}
+
+// UserPermissionsPage is a page of UserPermissions
+type UserPermissionsPage struct {
+	Paging Paging            `json:"paging"`
+	Users  []UserPermissions `json:"users"`
+}
+
+// GroupPermissions is returned by api/permissions/groups and api/permissions/template_groups
+type GroupPermissions struct {
+	Id          string   `json:"id"`
+	Name        string   `json:"name,omitempty"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions,omitempty"`
+}
+
+// GroupPermissionsPage is a page of GroupPermissions
+type GroupPermissionsPage struct {
+	Paging Paging             `json:"paging"`
+	Groups []GroupPermissions `json:"groups"`
+}
+
+// PermissionTemplate is returned by the api/permissions template actions
+type PermissionTemplate struct {
+	ID                string `json:"id,omitempty"`
+	Name              string `json:"name,omitempty"`
+	Description       string `json:"description,omitempty"`
+	ProjectKeyPattern string `json:"projectKeyPattern,omitempty"`
+}
+
+// PermissionTemplatesPage is a page of PermissionTemplate
+type PermissionTemplatesPage struct {
+	Paging              Paging               `json:"paging"`
+	PermissionTemplates []PermissionTemplate `json:"permissionTemplates"`
+}
+
+// PermissionTemplateRequest holds the parameters of api/permissions/create_template and api/permissions/update_template.
+// ID is only used on update, Name only on create.
+type PermissionTemplateRequest struct {
+	ID                string `url:"id,omitempty"`
+	Name              string `url:"name,omitempty"`
+	Description       string `url:"description"`
+	ProjectKeyPattern string `url:"projectKeyPattern"`
+}
+
+// AddUser calls api/permissions/add_user
+func (s *PermissionsService) AddUser(ctx context.Context, request PermissionsRequest) error {
+	return s.client.post(ctx, "api/permissions/add_user", encode(request), nil)
+}
+
+// AddGroup calls api/permissions/add_group
+func (s *PermissionsService) AddGroup(ctx context.Context, request PermissionsRequest) error {
+	return s.client.post(ctx, "api/permissions/add_group", encode(request), nil)
+}
+
+// AddUserToTemplate calls api/permissions/add_user_to_template
+func (s *PermissionsService) AddUserToTemplate(ctx context.Context, request PermissionsRequest) error {
+	return s.client.post(ctx, "api/permissions/add_user_to_template", encode(request), nil)
+}
+
+// AddGroupToTemplate calls api/permissions/add_group_to_template
+func (s *PermissionsService) AddGroupToTemplate(ctx context.Context, request PermissionsRequest) error {
+	return s.client.post(ctx, "api/permissions/add_group_to_template", encode(request), nil)
+}
+
+// RemoveUser calls api/permissions/remove_user
+func (s *PermissionsService) RemoveUser(ctx context.Context, request PermissionsRequest) error {
+	return s.client.post(ctx, "api/permissions/remove_user", encode(request), nil)
+}
+
+// RemoveGroup calls api/permissions/remove_group
+func (s *PermissionsService) RemoveGroup(ctx context.Context, request PermissionsRequest) error {
+	return s.client.post(ctx, "api/permissions/remove_group", encode(request), nil)
+}
+
+// RemoveUserFromTemplate calls api/permissions/remove_user_from_template
+func (s *PermissionsService) RemoveUserFromTemplate(ctx context.Context, request PermissionsRequest) error {
+	return s.client.post(ctx, "api/permissions/remove_user_from_template", encode(request), nil)
+}
+
+// RemoveGroupFromTemplate calls api/permissions/remove_group_from_template
+func (s *PermissionsService) RemoveGroupFromTemplate(ctx context.Context, request PermissionsRequest) error {
+	return s.client.post(ctx, "api/permissions/remove_group_from_template", encode(request), nil)
+}
+
+// Users calls api/permissions/users
+func (s *PermissionsService) Users(ctx context.Context, request PermissionsSearchRequest) (*UserPermissionsPage, error) {
+	page := UserPermissionsPage{}
+	if err := s.client.get(ctx, "api/permissions/users", encode(request), &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// UsersPages walks every page of api/permissions/users
+func (s *PermissionsService) UsersPages(request PermissionsSearchRequest) *Pager[UserPermissions] {
+	if request.PageSize == 0 {
+		request.PageSize = maxPermissionsPageSize
+	}
+	return newPager(func(ctx context.Context, page int) ([]UserPermissions, Paging, error) {
+		request.Page = page
+		response, err := s.Users(ctx, request)
+		if err != nil {
+			return nil, Paging{}, err
+		}
+		return response.Users, response.Paging, nil
+	})
+}
+
+// Groups calls api/permissions/groups
+func (s *PermissionsService) Groups(ctx context.Context, request PermissionsSearchRequest) (*GroupPermissionsPage, error) {
+	page := GroupPermissionsPage{}
+	if err := s.client.get(ctx, "api/permissions/groups", encode(request), &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// GroupsPages walks every page of api/permissions/groups
+func (s *PermissionsService) GroupsPages(request PermissionsSearchRequest) *Pager[GroupPermissions] {
+	if request.PageSize == 0 {
+		request.PageSize = maxPermissionsPageSize
+	}
+	return newPager(func(ctx context.Context, page int) ([]GroupPermissions, Paging, error) {
+		request.Page = page
+		response, err := s.Groups(ctx, request)
+		if err != nil {
+			return nil, Paging{}, err
+		}
+		return response.Groups, response.Paging, nil
+	})
+}
+
+// TemplateUsers calls api/permissions/template_users
+func (s *PermissionsService) TemplateUsers(ctx context.Context, request PermissionsSearchRequest) (*UserPermissionsPage, error) {
+	page := UserPermissionsPage{}
+	if err := s.client.get(ctx, "api/permissions/template_users", encode(request), &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// TemplateUsersPages walks every page of api/permissions/template_users
+func (s *PermissionsService) TemplateUsersPages(request PermissionsSearchRequest) *Pager[UserPermissions] {
+	if request.PageSize == 0 {
+		request.PageSize = maxPermissionsPageSize
+	}
+	return newPager(func(ctx context.Context, page int) ([]UserPermissions, Paging, error) {
+		request.Page = page
+		response, err := s.TemplateUsers(ctx, request)
+		if err != nil {
+			return nil, Paging{}, err
+		}
+		return response.Users, response.Paging, nil
+	})
+}
+
+// TemplateGroups calls api/permissions/template_groups
+func (s *PermissionsService) TemplateGroups(ctx context.Context, request PermissionsSearchRequest) (*GroupPermissionsPage, error) {
+	page := GroupPermissionsPage{}
+	if err := s.client.get(ctx, "api/permissions/template_groups", encode(request), &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// TemplateGroupsPages walks every page of api/permissions/template_groups
+func (s *PermissionsService) TemplateGroupsPages(request PermissionsSearchRequest) *Pager[GroupPermissions] {
+	if request.PageSize == 0 {
+		request.PageSize = maxPermissionsPageSize
+	}
+	return newPager(func(ctx context.Context, page int) ([]GroupPermissions, Paging, error) {
+		request.Page = page
+		response, err := s.TemplateGroups(ctx, request)
+		if err != nil {
+			return nil, Paging{}, err
+		}
+		return response.Groups, response.Paging, nil
+	})
+}
+
+// CreateTemplate calls api/permissions/create_template
+func (s *PermissionsService) CreateTemplate(ctx context.Context, request PermissionTemplateRequest) (*PermissionTemplate, error) {
+	response := struct {
+		PermissionTemplate PermissionTemplate `json:"permissionTemplate"`
+	}{}
+	if err := s.client.post(ctx, "api/permissions/create_template", encode(request), &response); err != nil {
+		return nil, err
+	}
+	return &response.PermissionTemplate, nil
+}
+
+// SearchTemplates calls api/permissions/search_templates
+func (s *PermissionsService) SearchTemplates(ctx context.Context, query string) (*PermissionTemplatesPage, error) {
+	page := PermissionTemplatesPage{}
+	params := url.Values{
+		"q": []string{query},
+	}
+	if err := s.client.get(ctx, "api/permissions/search_templates", params, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// UpdateTemplate calls api/permissions/update_template
+func (s *PermissionsService) UpdateTemplate(ctx context.Context, request PermissionTemplateRequest) error {
+	return s.client.post(ctx, "api/permissions/update_template", encode(request), nil)
+}
+
+// DeleteTemplate calls api/permissions/delete_template
+func (s *PermissionsService) DeleteTemplate(ctx context.Context, templateID string) error {
+	params := url.Values{
+		"templateId": []string{templateID},
+	}
+	return s.client.post(ctx, "api/permissions/delete_template", params, nil)
+}
+
+// SetDefaultTemplate calls api/permissions/set_default_template
+func (s *PermissionsService) SetDefaultTemplate(ctx context.Context, templateID string) error {
+	params := url.Values{
+		"templateId": []string{templateID},
+	}
+	return s.client.post(ctx, "api/permissions/set_default_template", params, nil)
+}
@@ -0,0 +1,56 @@
+package client
+
+import (
+	"context"
+	"net/url"
+)
+
+// PluginsService wraps api/plugins
+type PluginsService service
+
+// Plugin is returned by api/plugins/installed
+type Plugin struct {
+	Key                 string `json:"key"`
+	Name                string `json:"name"`
+	Description         string `json:"description"`
+	Version             string `json:"version"`
+	License             string `json:"license"`
+	OrganizationName    string `json:"organizationName"`
+	OrganizationURL     string `json:"organizationUrl"`
+	EditionBundled      bool   `json:"editionBundled"`
+	HomepageURL         string `json:"homepageUrl"`
+	IssueTrackerURL     string `json:"issueTrackerUrl"`
+	ImplementationBuild string `json:"implementationBuild"`
+	Filename            string `json:"filename"`
+	Hash                string `json:"hash"`
+	SonarLintSupported  bool   `json:"sonarLintSupported"`
+	DocumentationPath   string `json:"documentationPath"`
+	UpdatedAt           int    `json:"updatedAt"`
+}
+
+// Install calls api/plugins/install
+func (s *PluginsService) Install(ctx context.Context, key string) error {
+	params := url.Values{
+		"key": []string{key},
+	}
+	return s.client.post(ctx, "api/plugins/install", params, nil)
+}
+
+// Installed calls api/plugins/installed
+func (s *PluginsService) Installed(ctx context.Context) ([]Plugin, error) {
+	response := struct {
+		Plugins []Plugin `json:"plugins"`
+	}{}
+	if err := s.client.get(ctx, "api/plugins/installed", nil, &response); err != nil {
+		return nil, err
+	}
+	return response.Plugins, nil
+}
+
+// Uninstall calls api/plugins/uninstall
+func (s *PluginsService) Uninstall(ctx context.Context, key string) error {
+	params := url.Values{
+		"key": []string{key},
+	}
+	return s.client.post(ctx, "api/plugins/uninstall", params, nil)
+}
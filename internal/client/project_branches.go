@@ -0,0 +1,68 @@
+package client
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+// ProjectBranchesService wraps api/project_branches
+type ProjectBranchesService service
+
+// Branch is returned by api/project_branches/list
+type Branch struct {
+	Name              string       `json:"name"`
+	IsMain            bool         `json:"isMain"`
+	Type              string       `json:"type"`
+	Status            BranchStatus `json:"status"`
+	AnalysisDate      string       `json:"analysisDate"`
+	ExcludedFromPurge bool         `json:"excludedFromPurge"`
+}
+
+// BranchStatus is the quality gate status of a Branch
+type BranchStatus struct {
+	QualityGateStatus string `json:"qualityGateStatus"`
+}
+
+// List calls api/project_branches/list
+func (s *ProjectBranchesService) List(ctx context.Context, project string) ([]Branch, error) {
+	response := struct {
+		Branches []Branch `json:"branches"`
+	}{}
+	params := url.Values{
+		"project": []string{project},
+	}
+	if err := s.client.get(ctx, "api/project_branches/list", params, &response); err != nil {
+		return nil, err
+	}
+	return response.Branches, nil
+}
+
+// Rename calls api/project_branches/rename, which renames the main branch of a project
+func (s *ProjectBranchesService) Rename(ctx context.Context, project string, name string) error {
+	params := url.Values{
+		"name":    []string{name},
+		"project": []string{project},
+	}
+	return s.client.post(ctx, "api/project_branches/rename", params, nil)
+}
+
+// Delete calls api/project_branches/delete, which deletes a branch other than the main branch
+func (s *ProjectBranchesService) Delete(ctx context.Context, project string, branch string) error {
+	params := url.Values{
+		"branch":  []string{branch},
+		"project": []string{project},
+	}
+	return s.client.post(ctx, "api/project_branches/delete", params, nil)
+}
+
+// SetAutomaticDeletionProtection calls api/project_branches/set_automatic_deletion_protection, which sets whether a
+// branch is excluded from the automatic deletion of inactive branches
+func (s *ProjectBranchesService) SetAutomaticDeletionProtection(ctx context.Context, project string, branch string, value bool) error {
+	params := url.Values{
+		"branch":  []string{branch},
+		"project": []string{project},
+		"value":   []string{strconv.FormatBool(value)},
+	}
+	return s.client.post(ctx, "api/project_branches/set_automatic_deletion_protection", params, nil)
+}
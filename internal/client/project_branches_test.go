@@ -0,0 +1,43 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestProjectBranchesSetAutomaticDeletionProtection(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/sonar/api/project_branches/set_automatic_deletion_protection" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		r.ParseForm()
+		if r.PostForm.Get("project") != "my-project" || r.PostForm.Get("branch") != "release/1.0" || r.PostForm.Get("value") != "false" {
+			t.Errorf("unexpected form %q", r.PostForm.Encode())
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	if err := c.ProjectBranches.SetAutomaticDeletionProtection(context.Background(), "my-project", "release/1.0", false); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+}
+
+func TestProjectBranchesList(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/sonar/api/project_branches/list" || r.URL.Query().Get("project") != "my-project" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.String())
+		}
+		w.Write([]byte(`{"branches":[{"name":"release/1.0","isMain":false,"type":"BRANCH","status":{"qualityGateStatus":"ERROR"},"analysisDate":"2024-01-02T03:04:05+0000","excludedFromPurge":true}]}`))
+	})
+
+	branches, err := c.ProjectBranches.List(context.Background(), "my-project")
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+
+	want := Branch{Name: "release/1.0", Type: "BRANCH", Status: BranchStatus{QualityGateStatus: "ERROR"}, AnalysisDate: "2024-01-02T03:04:05+0000", ExcludedFromPurge: true}
+	if len(branches) != 1 || branches[0] != want {
+		t.Errorf("branches = %+v, want [%+v]", branches, want)
+	}
+}
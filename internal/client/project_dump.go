@@ -0,0 +1,29 @@
+package client
+
+import (
+	"context"
+	"net/url"
+)
+
+// ProjectDumpService wraps api/project_dump, which is only available in the Enterprise and Data Center editions
+type ProjectDumpService service
+
+// ProjectDumpExport is returned by api/project_dump/export
+type ProjectDumpExport struct {
+	// TaskID identifies the background task writing the dump, see CEService.Task
+	TaskID      string `json:"taskId"`
+	ProjectKey  string `json:"projectKey"`
+	ProjectName string `json:"projectName"`
+}
+
+// Export calls api/project_dump/export, which triggers the export of a project to a dump file on the server
+func (s *ProjectDumpService) Export(ctx context.Context, key string) (*ProjectDumpExport, error) {
+	export := ProjectDumpExport{}
+	params := url.Values{
+		"key": []string{key},
+	}
+	if err := s.client.post(ctx, "api/project_dump/export", params, &export); err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
@@ -0,0 +1,58 @@
+package client
+
+import (
+	"context"
+	"net/url"
+)
+
+// ProjectLinksService wraps api/project_links
+type ProjectLinksService service
+
+// ProjectLink is returned by api/project_links/create and api/project_links/search
+type ProjectLink struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Type is one of homepage, ci, issue or scm for the links named after them, custom otherwise
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+// ProjectLinksCreateRequest holds the parameters of api/project_links/create
+type ProjectLinksCreateRequest struct {
+	ProjectKey string `url:"projectKey"`
+	Name       string `url:"name"`
+	URL        string `url:"url"`
+}
+
+// Create calls api/project_links/create
+func (s *ProjectLinksService) Create(ctx context.Context, request ProjectLinksCreateRequest) (*ProjectLink, error) {
+	response := struct {
+		Link ProjectLink `json:"link"`
+	}{}
+	if err := s.client.post(ctx, "api/project_links/create", encode(request), &response); err != nil {
+		return nil, err
+	}
+	return &response.Link, nil
+}
+
+// Delete calls api/project_links/delete
+func (s *ProjectLinksService) Delete(ctx context.Context, id string) error {
+	params := url.Values{
+		"id": []string{id},
+	}
+	return s.client.post(ctx, "api/project_links/delete", params, nil)
+}
+
+// Search calls api/project_links/search, which lists the links of a project
+func (s *ProjectLinksService) Search(ctx context.Context, projectKey string) ([]ProjectLink, error) {
+	response := struct {
+		Links []ProjectLink `json:"links"`
+	}{}
+	params := url.Values{
+		"projectKey": []string{projectKey},
+	}
+	if err := s.client.get(ctx, "api/project_links/search", params, &response); err != nil {
+		return nil, err
+	}
+	return response.Links, nil
+}
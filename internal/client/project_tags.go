@@ -0,0 +1,19 @@
+package client
+
+import (
+	"context"
+	"net/url"
+	"strings"
+)
+
+// ProjectTagsService wraps api/project_tags
+type ProjectTagsService service
+
+// Set calls api/project_tags/set. An empty list of tags removes every tag from the project.
+func (s *ProjectTagsService) Set(ctx context.Context, project string, tags []string) error {
+	params := url.Values{
+		"project": []string{project},
+		"tags":    []string{strings.Join(tags, ",")},
+	}
+	return s.client.post(ctx, "api/project_tags/set", params, nil)
+}
@@ -0,0 +1,97 @@
+package client
+
+import (
+	"context"
+	"net/url"
+)
+
+// ProjectsService wraps api/projects
+type ProjectsService service
+
+// Project is returned by api/projects/create and api/projects/search
+type Project struct {
+	Key       string `json:"key"`
+	Name      string `json:"name"`
+	Qualifier string `json:"qualifier"`
+}
+
+// ProjectsCreateRequest holds the parameters of api/projects/create
+type ProjectsCreateRequest struct {
+	Name       string `url:"name"`
+	Project    string `url:"project"`
+	Visibility string `url:"visibility,omitempty"`
+}
+
+// ProjectsPage is a page of Project returned by api/projects/search
+type ProjectsPage struct {
+	Paging     Paging    `json:"paging"`
+	Components []Project `json:"components"`
+}
+
+// ProjectsSearchRequest holds the parameters of api/projects/search
+type ProjectsSearchRequest struct {
+	Query    string `url:"q,omitempty"`
+	Page     int    `url:"p,omitempty"`
+	PageSize int    `url:"ps,omitempty"`
+}
+
+// Create calls api/projects/create
+func (s *ProjectsService) Create(ctx context.Context, request ProjectsCreateRequest) (*Project, error) {
+	response := struct {
+		Project Project `json:"project"`
+	}{}
+	if err := s.client.post(ctx, "api/projects/create", encode(request), &response); err != nil {
+		return nil, err
+	}
+	return &response.Project, nil
+}
+
+// Search calls api/projects/search
+func (s *ProjectsService) Search(ctx context.Context, request ProjectsSearchRequest) (*ProjectsPage, error) {
+	page := ProjectsPage{}
+	if err := s.client.get(ctx, "api/projects/search", encode(request), &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// SearchPages walks every page of api/projects/search
+func (s *ProjectsService) SearchPages(request ProjectsSearchRequest) *Pager[Project] {
+	if request.PageSize == 0 {
+		request.PageSize = maxPageSize
+	}
+	return newPager(func(ctx context.Context, page int) ([]Project, Paging, error) {
+		request.Page = page
+		response, err := s.Search(ctx, request)
+		if err != nil {
+			return nil, Paging{}, err
+		}
+		return response.Components, response.Paging, nil
+	})
+}
+
+// Delete calls api/projects/delete
+func (s *ProjectsService) Delete(ctx context.Context, project string) error {
+	params := url.Values{
+		"project": []string{project},
+	}
+	return s.client.post(ctx, "api/projects/delete", params, nil)
+}
+
+// UpdateKey calls api/projects/update_key
+func (s *ProjectsService) UpdateKey(ctx context.Context, from string, to string) error {
+	params := url.Values{
+		"from": []string{from},
+		"to":   []string{to},
+	}
+	return s.client.post(ctx, "api/projects/update_key", params, nil)
+}
+
+// UpdateVisibility calls api/projects/update_visibility
+func (s *ProjectsService) UpdateVisibility(ctx context.Context, project string, visibility string) error {
+	params := url.Values{
+		"project":    []string{project},
+		"visibility": []string{visibility},
+	}
+	return s.client.post(ctx, "api/projects/update_visibility", params, nil)
+}
@@ -0,0 +1,48 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestProjectsCreate(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/sonar/api/projects/create" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		r.ParseForm()
+		if r.PostForm.Get("project") != "my-project" || r.PostForm.Get("name") != "My Project" {
+			t.Errorf("unexpected form %q", r.PostForm.Encode())
+		}
+		if r.PostForm.Has("visibility") {
+			t.Errorf("visibility should be omitted when empty, got %q", r.PostForm.Encode())
+		}
+		w.Write([]byte(`{"project":{"key":"my-project","name":"My Project","qualifier":"TRK"}}`))
+	})
+
+	project, err := c.Projects.Create(context.Background(), ProjectsCreateRequest{
+		Name:    "My Project",
+		Project: "my-project",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+
+	want := Project{Key: "my-project", Name: "My Project", Qualifier: "TRK"}
+	if *project != want {
+		t.Errorf("project = %+v, want %+v", *project, want)
+	}
+}
+
+func TestProjectsDeleteError(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"errors":[{"msg":"Project 'my-project' not found"}]}`))
+	})
+
+	err := c.Projects.Delete(context.Background(), "my-project")
+	if !IsNotFound(err) {
+		t.Errorf("IsNotFound(%v) = false, want true", err)
+	}
+}
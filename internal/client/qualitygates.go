@@ -0,0 +1,302 @@
+package client
+
+import (
+	"context"
+	"net/url"
+)
+
+// QualityGatesService wraps api/qualitygates
+type QualityGatesService service
+
+// QualityGate is returned by api/qualitygates/show
+type QualityGate struct {
+	ID         string                 `json:"id"`
+	Name       string                 `json:"name"`
+	Conditions []QualityGateCondition `json:"conditions"`
+	IsBuiltIn  bool                   `json:"isBuiltIn"`
+	Actions    QualityGateActions     `json:"actions"`
+}
+
+// QualityGateCondition is a condition of a QualityGate. Error holds the threshold.
+type QualityGateCondition struct {
+	ID     string `json:"id"`
+	Metric string `json:"metric"`
+	OP     string `json:"op"`
+	Error  string `json:"error"`
+}
+
+// QualityGateActions lists the actions the authenticated user may perform on a QualityGate
+type QualityGateActions struct {
+	Rename            bool `json:"rename"`
+	SetAsDefault      bool `json:"setAsDefault"`
+	Copy              bool `json:"copy"`
+	AssociateProjects bool `json:"associateProjects"`
+	Delete            bool `json:"delete"`
+	ManageConditions  bool `json:"manageConditions"`
+}
+
+// ProjectQualityGate is returned by api/qualitygates/get_by_project
+type ProjectQualityGate struct {
+	Id      string `json:"id"`
+	Name    string `json:"name"`
+	Default bool   `json:"default"`
+}
+
+// QualityGateConditionRequest holds the parameters of api/qualitygates/create_condition and api/qualitygates/update_condition.
+// GateName, or GateID on servers which predate gateName, is only used on create, ID only on update.
+type QualityGateConditionRequest struct {
+	ID       string `url:"id,omitempty"`
+	GateName string `url:"gateName,omitempty"`
+	GateID   string `url:"gateId,omitempty"`
+	Metric   string `url:"metric"`
+	OP       string `url:"op"`
+	Error    string `url:"error"`
+}
+
+// QualityGatePermissionRequest holds the parameters of the api/qualitygates add_*/remove_* permission actions
+type QualityGatePermissionRequest struct {
+	GateName  string `url:"gateName"`
+	Login     string `url:"login,omitempty"`
+	GroupName string `url:"groupName,omitempty"`
+}
+
+// QualityGateSearchPermissionsRequest holds the parameters of api/qualitygates/search_users and api/qualitygates/search_groups
+type QualityGateSearchPermissionsRequest struct {
+	GateName string `url:"gateName"`
+	Query    string `url:"q,omitempty"`
+	Selected string `url:"selected,omitempty"`
+	Page     int    `url:"p,omitempty"`
+	PageSize int    `url:"ps,omitempty"`
+}
+
+// QualityGatePermission is a user or group returned by api/qualitygates/search_users and api/qualitygates/search_groups
+type QualityGatePermission struct {
+	Login       string `json:"login,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Selected    bool   `json:"selected"`
+}
+
+// QualityGatePermissionsPage is a page of QualityGatePermission. Only one of Users and Groups is set.
+type QualityGatePermissionsPage struct {
+	Paging Paging                  `json:"paging"`
+	Groups []QualityGatePermission `json:"groups,omitempty"`
+	Users  []QualityGatePermission `json:"users,omitempty"`
+}
+
+// Create calls api/qualitygates/create and returns the name of the new gate
+func (s *QualityGatesService) Create(ctx context.Context, name string) (string, error) {
+	response := struct {
+		Name string `json:"name"`
+	}{}
+	params := url.Values{
+		"name": []string{name},
+	}
+	if err := s.client.post(ctx, "api/qualitygates/create", params, &response); err != nil {
+		return "", err
+	}
+	return response.Name, nil
+}
+
+// Copy calls api/qualitygates/copy and returns the name of the new gate
+func (s *QualityGatesService) Copy(ctx context.Context, sourceName string, name string) (string, error) {
+	response := struct {
+		Name string `json:"name"`
+	}{}
+	params := url.Values{
+		"name":       []string{name},
+		"sourceName": []string{sourceName},
+	}
+	if err := s.client.post(ctx, "api/qualitygates/copy", params, &response); err != nil {
+		return "", err
+	}
+	return response.Name, nil
+}
+
+// Show calls api/qualitygates/show
+func (s *QualityGatesService) Show(ctx context.Context, name string) (*QualityGate, error) {
+	gate := QualityGate{}
+	params := url.Values{
+		"name": []string{name},
+	}
+	if err := s.client.get(ctx, "api/qualitygates/show", params, &gate); err != nil {
+		return nil, err
+	}
+	return &gate, nil
+}
+
+// List calls api/qualitygates/list. The gates are returned without their conditions.
+func (s *QualityGatesService) List(ctx context.Context) ([]QualityGate, error) {
+	response := struct {
+		QualityGates []QualityGate `json:"qualitygates"`
+	}{}
+	if err := s.client.get(ctx, "api/qualitygates/list", nil, &response); err != nil {
+		return nil, err
+	}
+	return response.QualityGates, nil
+}
+
+// Destroy calls api/qualitygates/destroy
+func (s *QualityGatesService) Destroy(ctx context.Context, name string) error {
+	params := url.Values{
+		"name": []string{name},
+	}
+	return s.client.post(ctx, "api/qualitygates/destroy", params, nil)
+}
+
+// Rename calls api/qualitygates/rename
+func (s *QualityGatesService) Rename(ctx context.Context, currentName string, name string) error {
+	params := url.Values{
+		"currentName": []string{currentName},
+		"name":        []string{name},
+	}
+	return s.client.post(ctx, "api/qualitygates/rename", params, nil)
+}
+
+// SetAsDefault calls api/qualitygates/set_as_default
+func (s *QualityGatesService) SetAsDefault(ctx context.Context, name string) error {
+	params := url.Values{
+		"name": []string{name},
+	}
+	return s.client.post(ctx, "api/qualitygates/set_as_default", params, nil)
+}
+
+// CreateCondition calls api/qualitygates/create_condition
+func (s *QualityGatesService) CreateCondition(ctx context.Context, request QualityGateConditionRequest) (*QualityGateCondition, error) {
+	condition := QualityGateCondition{}
+	if err := s.client.post(ctx, "api/qualitygates/create_condition", encode(request), &condition); err != nil {
+		return nil, err
+	}
+	return &condition, nil
+}
+
+// UpdateCondition calls api/qualitygates/update_condition
+func (s *QualityGatesService) UpdateCondition(ctx context.Context, request QualityGateConditionRequest) error {
+	return s.client.post(ctx, "api/qualitygates/update_condition", encode(request), nil)
+}
+
+// DeleteCondition calls api/qualitygates/delete_condition
+func (s *QualityGatesService) DeleteCondition(ctx context.Context, id string) error {
+	params := url.Values{
+		"id": []string{id},
+	}
+	return s.client.post(ctx, "api/qualitygates/delete_condition", params, nil)
+}
+
+// Select calls api/qualitygates/select
+func (s *QualityGatesService) Select(ctx context.Context, gateName string, projectKey string) error {
+	params := url.Values{
+		"gateName":   []string{gateName},
+		"projectKey": []string{projectKey},
+	}
+	return s.client.post(ctx, "api/qualitygates/select", params, nil)
+}
+
+// SelectByID calls api/qualitygates/select with the gateId parameter, for servers which predate gateName
+func (s *QualityGatesService) SelectByID(ctx context.Context, gateID string, projectKey string) error {
+	params := url.Values{
+		"gateId":     []string{gateID},
+		"projectKey": []string{projectKey},
+	}
+	return s.client.post(ctx, "api/qualitygates/select", params, nil)
+}
+
+// Deselect calls api/qualitygates/deselect
+func (s *QualityGatesService) Deselect(ctx context.Context, gateName string, projectKey string) error {
+	params := url.Values{
+		"gateName":   []string{gateName},
+		"projectKey": []string{projectKey},
+	}
+	return s.client.post(ctx, "api/qualitygates/deselect", params, nil)
+}
+
+// GetByProject calls api/qualitygates/get_by_project
+func (s *QualityGatesService) GetByProject(ctx context.Context, project string) (*ProjectQualityGate, error) {
+	response := struct {
+		QualityGate ProjectQualityGate `json:"qualityGate"`
+	}{}
+	params := url.Values{
+		"project": []string{project},
+	}
+	if err := s.client.get(ctx, "api/qualitygates/get_by_project", params, &response); err != nil {
+		return nil, err
+	}
+	return &response.QualityGate, nil
+}
+
+// AddUser calls api/qualitygates/add_user
+func (s *QualityGatesService) AddUser(ctx context.Context, request QualityGatePermissionRequest) error {
+	return s.client.post(ctx, "api/qualitygates/add_user", encode(request), nil)
+}
+
+// AddGroup calls api/qualitygates/add_group
+func (s *QualityGatesService) AddGroup(ctx context.Context, request QualityGatePermissionRequest) error {
+	return s.client.post(ctx, "api/qualitygates/add_group", encode(request), nil)
+}
+
+// RemoveUser calls api/qualitygates/remove_user
+func (s *QualityGatesService) RemoveUser(ctx context.Context, request QualityGatePermissionRequest) error {
+	return s.client.post(ctx, "api/qualitygates/remove_user", encode(request), nil)
+}
+
+// RemoveGroup calls api/qualitygates/remove_group
+func (s *QualityGatesService) RemoveGroup(ctx context.Context, request QualityGatePermissionRequest) error {
+	return s.client.post(ctx, "api/qualitygates/remove_group", encode(request), nil)
+}
+
+// SearchUsers calls api/qualitygates/search_users
+func (s *QualityGatesService) SearchUsers(ctx context.Context, request QualityGateSearchPermissionsRequest) (*QualityGatePermissionsPage, error) {
+	page := QualityGatePermissionsPage{}
+	if err := s.client.get(ctx, "api/qualitygates/search_users", encode(request), &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// SearchUsersPages walks every page of api/qualitygates/search_users
+func (s *QualityGatesService) SearchUsersPages(request QualityGateSearchPermissionsRequest) *Pager[QualityGatePermission] {
+	if request.PageSize == 0 {
+		request.PageSize = maxPermissionsPageSize
+	}
+	return newPager(func(ctx context.Context, page int) ([]QualityGatePermission, Paging, error) {
+		request.Page = page
+		response, err := s.SearchUsers(ctx, request)
+		if err != nil {
+			return nil, Paging{}, err
+		}
+		return response.Users, response.Paging, nil
+	})
+}
+
+// SearchGroups calls api/qualitygates/search_groups
+func (s *QualityGatesService) SearchGroups(ctx context.Context, request QualityGateSearchPermissionsRequest) (*QualityGatePermissionsPage, error) {
+	page := QualityGatePermissionsPage{}
+	if err := s.client.get(ctx, "api/qualitygates/search_groups", encode(request), &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// SearchGroupsPages walks every page of api/qualitygates/search_groups
+func (s *QualityGatesService) SearchGroupsPages(request QualityGateSearchPermissionsRequest) *Pager[QualityGatePermission] {
+	if request.PageSize == 0 {
+		request.PageSize = maxPermissionsPageSize
+	}
+	return newPager(func(ctx context.Context, page int) ([]QualityGatePermission, Paging, error) {
+		request.Page = page
+		response, err := s.SearchGroups(ctx, request)
+		if err != nil {
+			return nil, Paging{}, err
+		}
+		return response.Groups, response.Paging, nil
+	})
+}
+
+// DeselectByID calls api/qualitygates/deselect with the gateId parameter, for servers which predate gateName
+func (s *QualityGatesService) DeselectByID(ctx context.Context, gateID string, projectKey string) error {
+	params := url.Values{
+		"gateId":     []string{gateID},
+		"projectKey": []string{projectKey},
+	}
+	return s.client.post(ctx, "api/qualitygates/deselect", params, nil)
+}
@@ -0,0 +1,58 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestQualityGatesShow(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/sonar/api/qualitygates/show" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if got := r.URL.Query().Get("name"); got != "my-gate" {
+			t.Errorf("name = %q, want %q", got, "my-gate")
+		}
+		w.Write([]byte(`{
+			"name": "my-gate",
+			"isBuiltIn": false,
+			"conditions": [{"id": "AX1", "metric": "new_coverage", "op": "LT", "error": "80"}],
+			"actions": {"rename": true, "setAsDefault": true}
+		}`))
+	})
+
+	gate, err := c.QualityGates.Show(context.Background(), "my-gate")
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+
+	if gate.Name != "my-gate" || gate.IsBuiltIn {
+		t.Errorf("unexpected gate %+v", gate)
+	}
+	wantConditions := []QualityGateCondition{{ID: "AX1", Metric: "new_coverage", OP: "LT", Error: "80"}}
+	if !reflect.DeepEqual(gate.Conditions, wantConditions) {
+		t.Errorf("conditions = %+v, want %+v", gate.Conditions, wantConditions)
+	}
+	if !gate.Actions.Rename || !gate.Actions.SetAsDefault {
+		t.Errorf("actions = %+v, want rename and setAsDefault", gate.Actions)
+	}
+}
+
+func TestQualityGatesCreate(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/sonar/api/qualitygates/create" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		w.Write([]byte(`{"name":"my-gate"}`))
+	})
+
+	name, err := c.QualityGates.Create(context.Background(), "my-gate")
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	if name != "my-gate" {
+		t.Errorf("name = %q, want %q", name, "my-gate")
+	}
+}
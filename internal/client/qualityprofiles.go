@@ -0,0 +1,179 @@
+package client
+
+import (
+	"context"
+	"net/url"
+)
+
+// QualityProfilesService wraps api/qualityprofiles
+type QualityProfilesService service
+
+// QualityProfile is returned by api/qualityprofiles/create and api/qualityprofiles/search
+type QualityProfile struct {
+	Key                       string                `json:"key"`
+	Name                      string                `json:"name"`
+	Language                  string                `json:"language"`
+	LanguageName              string                `json:"languageName"`
+	IsInherited               bool                  `json:"isInherited"`
+	IsBuiltIn                 bool                  `json:"isBuiltIn"`
+	ActiveRuleCount           int                   `json:"activeRuleCount"`
+	ActiveDeprecatedRuleCount int                   `json:"activeDeprecatedRuleCount"`
+	IsDefault                 bool                  `json:"isDefault"`
+	RuleUpdatedAt             string                `json:"ruleUpdatedAt"`
+	LastUsed                  string                `json:"lastUsed"`
+	Actions                   QualityProfileActions `json:"actions"`
+}
+
+// QualityProfileActions lists the actions the authenticated user may perform on a QualityProfile
+type QualityProfileActions struct {
+	Edit              bool `json:"edit"`
+	SetAsDefault      bool `json:"setAsDefault"`
+	Copy              bool `json:"copy"`
+	Delete            bool `json:"delete"`
+	AssociateProjects bool `json:"associateProjects"`
+}
+
+// QualityProfileProject is returned by api/qualityprofiles/projects
+type QualityProfileProject struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Key      string `json:"key"`
+	Selected bool   `json:"selected"`
+}
+
+// QualityProfileProjectsPage is a page of QualityProfileProject
+type QualityProfileProjectsPage struct {
+	Paging  Paging                  `json:"paging"`
+	Results []QualityProfileProject `json:"results"`
+}
+
+// QualityProfileProjectsRequest holds the parameters of api/qualityprofiles/projects
+type QualityProfileProjectsRequest struct {
+	Key      string `url:"key"`
+	Query    string `url:"q,omitempty"`
+	Selected string `url:"selected,omitempty"`
+	Page     int    `url:"p,omitempty"`
+	PageSize int    `url:"ps,omitempty"`
+}
+
+// ActivateRuleRequest holds the parameters of api/qualityprofiles/activate_rule
+type ActivateRuleRequest struct {
+	Key      string `url:"key"`
+	Rule     string `url:"rule"`
+	Params   string `url:"params"`
+	Reset    string `url:"reset"`
+	Severity string `url:"severity"`
+}
+
+// Create calls api/qualityprofiles/create
+func (s *QualityProfilesService) Create(ctx context.Context, name string, language string) (*QualityProfile, error) {
+	response := struct {
+		Profile  QualityProfile `json:"profile"`
+		Warnings []string       `json:"warnings"`
+	}{}
+	params := url.Values{
+		"name":     []string{name},
+		"language": []string{language},
+	}
+	if err := s.client.post(ctx, "api/qualityprofiles/create", params, &response); err != nil {
+		return nil, err
+	}
+	return &response.Profile, nil
+}
+
+// Search calls api/qualityprofiles/search and returns every quality profile
+func (s *QualityProfilesService) Search(ctx context.Context) ([]QualityProfile, error) {
+	response := struct {
+		Profiles []QualityProfile `json:"profiles"`
+	}{}
+	if err := s.client.get(ctx, "api/qualityprofiles/search", nil, &response); err != nil {
+		return nil, err
+	}
+	return response.Profiles, nil
+}
+
+// Delete calls api/qualityprofiles/delete
+func (s *QualityProfilesService) Delete(ctx context.Context, qualityProfile string, language string) error {
+	params := url.Values{
+		"qualityProfile": []string{qualityProfile},
+		"language":       []string{language},
+	}
+	return s.client.post(ctx, "api/qualityprofiles/delete", params, nil)
+}
+
+// SetDefault calls api/qualityprofiles/set_default
+func (s *QualityProfilesService) SetDefault(ctx context.Context, qualityProfile string, language string) error {
+	params := url.Values{
+		"qualityProfile": []string{qualityProfile},
+		"language":       []string{language},
+	}
+	return s.client.post(ctx, "api/qualityprofiles/set_default", params, nil)
+}
+
+// ChangeParent calls api/qualityprofiles/change_parent. An empty parent removes the inheritance.
+func (s *QualityProfilesService) ChangeParent(ctx context.Context, qualityProfile string, language string, parentQualityProfile string) error {
+	params := url.Values{
+		"qualityProfile":       []string{qualityProfile},
+		"language":             []string{language},
+		"parentQualityProfile": []string{parentQualityProfile},
+	}
+	return s.client.post(ctx, "api/qualityprofiles/change_parent", params, nil)
+}
+
+// AddProject calls api/qualityprofiles/add_project
+func (s *QualityProfilesService) AddProject(ctx context.Context, qualityProfile string, language string, project string) error {
+	params := url.Values{
+		"language":       []string{language},
+		"project":        []string{project},
+		"qualityProfile": []string{qualityProfile},
+	}
+	return s.client.post(ctx, "api/qualityprofiles/add_project", params, nil)
+}
+
+// RemoveProject calls api/qualityprofiles/remove_project
+func (s *QualityProfilesService) RemoveProject(ctx context.Context, qualityProfile string, language string, project string) error {
+	params := url.Values{
+		"language":       []string{language},
+		"project":        []string{project},
+		"qualityProfile": []string{qualityProfile},
+	}
+	return s.client.post(ctx, "api/qualityprofiles/remove_project", params, nil)
+}
+
+// Projects calls api/qualityprofiles/projects
+func (s *QualityProfilesService) Projects(ctx context.Context, request QualityProfileProjectsRequest) (*QualityProfileProjectsPage, error) {
+	page := QualityProfileProjectsPage{}
+	if err := s.client.get(ctx, "api/qualityprofiles/projects", encode(request), &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// ProjectsPages walks every page of api/qualityprofiles/projects
+func (s *QualityProfilesService) ProjectsPages(request QualityProfileProjectsRequest) *Pager[QualityProfileProject] {
+	if request.PageSize == 0 {
+		request.PageSize = maxPageSize
+	}
+	return newPager(func(ctx context.Context, page int) ([]QualityProfileProject, Paging, error) {
+		request.Page = page
+		response, err := s.Projects(ctx, request)
+		if err != nil {
+			return nil, Paging{}, err
+		}
+		return response.Results, response.Paging, nil
+	})
+}
+
+// ActivateRule calls api/qualityprofiles/activate_rule
+func (s *QualityProfilesService) ActivateRule(ctx context.Context, request ActivateRuleRequest) error {
+	return s.client.post(ctx, "api/qualityprofiles/activate_rule", encode(request), nil)
+}
+
+// DeactivateRule calls api/qualityprofiles/deactivate_rule
+func (s *QualityProfilesService) DeactivateRule(ctx context.Context, key string, rule string) error {
+	params := url.Values{
+		"key":  []string{key},
+		"rule": []string{rule},
+	}
+	return s.client.post(ctx, "api/qualityprofiles/deactivate_rule", params, nil)
+}
@@ -0,0 +1,60 @@
+package client
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// encode turns a request struct into url.Values using its `url` struct tags.
+//
+// The tag holds the parameter name followed by optional flags:
+//   - omitempty skips the parameter when the field has its zero value
+//   - comma sends a []string as a single comma separated value instead of repeating the parameter
+//
+// Supported field types are string, bool, int and []string.
+func encode(request interface{}) url.Values {
+	params := url.Values{}
+
+	v := reflect.Indirect(reflect.ValueOf(request))
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		tag := t.Field(i).Tag.Get("url")
+		if tag == "" || tag == "-" {
+			continue
+		}
+		name, flags, _ := strings.Cut(tag, ",")
+		omitEmpty := strings.Contains(flags, "omitempty")
+		field := v.Field(i)
+
+		if omitEmpty && field.IsZero() {
+			continue
+		}
+
+		switch field.Kind() {
+		case reflect.String:
+			params.Add(name, field.String())
+		case reflect.Bool:
+			params.Add(name, strconv.FormatBool(field.Bool()))
+		case reflect.Int, reflect.Int64:
+			params.Add(name, strconv.FormatInt(field.Int(), 10))
+		case reflect.Slice:
+			values := field.Interface().([]string)
+			if omitEmpty && len(values) == 0 {
+				continue
+			}
+			if strings.Contains(flags, "comma") {
+				params.Add(name, strings.Join(values, ","))
+				continue
+			}
+			for _, value := range values {
+				params.Add(name, value)
+			}
+		default:
+			panic(fmt.Sprintf("client: unsupported type %s for url parameter %q", field.Type(), name))
+		}
+	}
+	return params
+}
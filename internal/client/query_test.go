@@ -0,0 +1,48 @@
+package client
+
+import (
+	"testing"
+)
+
+func TestEncode(t *testing.T) {
+	request := struct {
+		Name     string   `url:"name"`
+		Empty    string   `url:"empty"`
+		Omitted  string   `url:"omitted,omitempty"`
+		Enabled  bool     `url:"enabled"`
+		Page     int      `url:"p,omitempty"`
+		Tags     []string `url:"tags,comma"`
+		Values   []string `url:"values"`
+		Ignored  string
+		Excluded string `url:"-"`
+	}{
+		Name:     "my-project",
+		Enabled:  true,
+		Page:     2,
+		Tags:     []string{"a", "b"},
+		Values:   []string{"x", "y"},
+		Ignored:  "ignored",
+		Excluded: "excluded",
+	}
+
+	got := encode(request).Encode()
+	want := "empty=&enabled=true&name=my-project&p=2&tags=a%2Cb&values=x&values=y"
+	if got != want {
+		t.Errorf("encode() = %q, want %q", got, want)
+	}
+}
+
+func TestEncodeOmitEmpty(t *testing.T) {
+	request := struct {
+		Name   string   `url:"name,omitempty"`
+		Local  bool     `url:"local,omitempty"`
+		Page   int      `url:"p,omitempty"`
+		Values []string `url:"values,omitempty"`
+	}{
+		Values: []string{},
+	}
+
+	if got := encode(&request).Encode(); got != "" {
+		t.Errorf("encode() = %q, want an empty query", got)
+	}
+}
@@ -0,0 +1,83 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// RetryPolicy is a retryablehttp.CheckRetry that only retries connection errors and responses signalling a temporary
+// condition: 429 Too Many Requests, 502 Bad Gateway, 503 Service Unavailable and 504 Gateway Timeout.
+// Any other response, in particular every other 4xx, is returned as is because repeating a POST that SonarQube
+// rejected will not succeed and may not be safe.
+func RetryPolicy(ctx context.Context, resp *http.Response, err error) (bool, error) {
+	// Do not retry once the context is done
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+
+	if err != nil {
+		// Let retryablehttp decide which connection errors are worth retrying, e.g. not TLS verification failures
+		return retryablehttp.DefaultRetryPolicy(ctx, nil, err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true, nil
+	}
+	return false, nil
+}
+
+// Backoff is a retryablehttp.Backoff waiting as long as the Retry-After header of the response asks for, at most max,
+// and exponentially longer between min and max for every attempt otherwise
+func Backoff(min, max time.Duration, attemptNum int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if wait, ok := retryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			if wait > max {
+				return max
+			}
+			return wait
+		}
+	}
+	return retryablehttp.DefaultBackoff(min, max, attemptNum, nil)
+}
+
+// retryAfter parses a Retry-After header holding either a number of seconds or an HTTP date
+func retryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if wait := date.Sub(now); wait > 0 {
+			return wait, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// LogRetry is a retryablehttp.RequestLogHook logging every retry through the Terraform logging system.
+// It also records the number of retries so it is part of the entry logged once the request completes.
+func LogRetry(_ retryablehttp.Logger, req *http.Request, retryNumber int) {
+	if retryNumber == 0 {
+		return
+	}
+	if retries, ok := req.Context().Value(retriesKey{}).(*int); ok {
+		*retries = retryNumber
+	}
+	tflog.Warn(req.Context(), "Retrying SonarQube request", map[string]interface{}{
+		"method": req.Method,
+		"path":   req.URL.Path,
+		"retry":  retryNumber,
+	})
+}
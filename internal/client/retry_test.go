@@ -0,0 +1,185 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	cases := []struct {
+		statusCode int
+		want       bool
+	}{
+		{http.StatusOK, false},
+		{http.StatusBadRequest, false},
+		{http.StatusUnauthorized, false},
+		{http.StatusNotFound, false},
+		{http.StatusConflict, false},
+		{http.StatusTooManyRequests, true},
+		{http.StatusInternalServerError, false},
+		{http.StatusNotImplemented, false},
+		{http.StatusBadGateway, true},
+		{http.StatusServiceUnavailable, true},
+		{http.StatusGatewayTimeout, true},
+	}
+
+	for _, c := range cases {
+		retry, err := RetryPolicy(context.Background(), &http.Response{StatusCode: c.statusCode}, nil)
+		if err != nil {
+			t.Errorf("RetryPolicy(%d) returned unexpected error: %+v", c.statusCode, err)
+		}
+		if retry != c.want {
+			t.Errorf("RetryPolicy(%d) = %t, want %t", c.statusCode, retry, c.want)
+		}
+	}
+}
+
+func TestRetryPolicyConnectionError(t *testing.T) {
+	retry, _ := RetryPolicy(context.Background(), nil, errors.New("connection reset by peer"))
+	if !retry {
+		t.Errorf("RetryPolicy should retry connection errors")
+	}
+}
+
+func TestRetryPolicyContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	retry, err := RetryPolicy(ctx, &http.Response{StatusCode: http.StatusServiceUnavailable}, nil)
+	if retry || !errors.Is(err, context.Canceled) {
+		t.Errorf("RetryPolicy = %t, %v, want false, %v", retry, err, context.Canceled)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		value  string
+		want   time.Duration
+		wantOk bool
+	}{
+		{"", 0, false},
+		{"5", 5 * time.Second, true},
+		{"-1", 0, false},
+		{"soon", 0, false},
+		{"Sun, 01 Oct 2023 12:00:30 GMT", 30 * time.Second, true},
+		{"Sun, 01 Oct 2023 11:59:00 GMT", 0, true},
+	}
+
+	for _, c := range cases {
+		got, ok := retryAfter(c.value, now)
+		if got != c.want || ok != c.wantOk {
+			t.Errorf("retryAfter(%q) = %s, %t, want %s, %t", c.value, got, ok, c.want, c.wantOk)
+		}
+	}
+}
+
+func TestBackoffHonorsRetryAfter(t *testing.T) {
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{"7"}}}
+	if got := Backoff(time.Second, 30*time.Second, 1, resp); got != 7*time.Second {
+		t.Errorf("Backoff = %s, want %s", got, 7*time.Second)
+	}
+
+	if got := Backoff(time.Second, 30*time.Second, 1, &http.Response{Header: http.Header{}}); got != 2*time.Second {
+		t.Errorf("Backoff without Retry-After = %s, want %s", got, 2*time.Second)
+	}
+}
+
+func TestBackoffClampsRetryAfter(t *testing.T) {
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{"3600"}}}
+	if got := Backoff(time.Second, 30*time.Second, 1, resp); got != 30*time.Second {
+		t.Errorf("Backoff = %s, want %s", got, 30*time.Second)
+	}
+}
+
+func TestClientRetriesUnavailable(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"name":"my-gate"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	c := newRetryingTestClient(t, server.URL)
+	name, err := c.QualityGates.Create(context.Background(), "my-gate")
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	if name != "my-gate" {
+		t.Errorf("name = %q, want %q", name, "my-gate")
+	}
+	if attempts != 3 {
+		t.Errorf("attempts = %d, want 3", attempts)
+	}
+}
+
+func TestClientDoesNotRetryBadRequest(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"errors":[{"msg":"Name has already been taken"}]}`))
+	}))
+	t.Cleanup(server.Close)
+
+	c := newRetryingTestClient(t, server.URL)
+	_, err := c.QualityGates.Create(context.Background(), "my-gate")
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		t.Fatalf("error = %v, want an APIError with status code 400", err)
+	}
+	if attempts != 1 {
+		t.Errorf("attempts = %d, want 1", attempts)
+	}
+}
+
+func TestClientRetriesExhausted(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	t.Cleanup(server.Close)
+
+	c := newRetryingTestClient(t, server.URL)
+	_, err := c.QualityGates.Create(context.Background(), "my-gate")
+
+	// The last response is returned instead of retryablehttp's "giving up" error
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadGateway {
+		t.Fatalf("error = %v, want an APIError with status code 502", err)
+	}
+}
+
+// newRetryingTestClient returns a Client configured like the provider configures it
+func newRetryingTestClient(t *testing.T, serverURL string) *Client {
+	t.Helper()
+
+	httpClient := retryablehttp.NewClient()
+	httpClient.Logger = nil
+	httpClient.RetryMax = 3
+	httpClient.RetryWaitMin = time.Millisecond
+	httpClient.RetryWaitMax = time.Millisecond
+	httpClient.CheckRetry = RetryPolicy
+	httpClient.Backoff = Backoff
+	httpClient.ErrorHandler = retryablehttp.PassthroughErrorHandler
+	httpClient.RequestLogHook = LogRetry
+
+	baseURL, err := url.Parse(serverURL)
+	if err != nil {
+		t.Fatalf("failed to parse test server url: %+v", err)
+	}
+	return New(httpClient, *baseURL, BasicAuth("admin", "secret"))
+}
@@ -0,0 +1,159 @@
+package client
+
+import (
+	"context"
+	"net/url"
+)
+
+// RulesService wraps api/rules
+type RulesService service
+
+// Rule is returned by the api/rules actions
+type Rule struct {
+	Key         string      `json:"key"`
+	Repo        string      `json:"repo"`
+	Name        string      `json:"name"`
+	CreatedAt   string      `json:"createdAt"`
+	UpdatedAt   string      `json:"updatedAt"`
+	HtmlDesc    string      `json:"htmlDesc,omitempty"`
+	MdDesc      string      `json:"mdDesc,omitempty"`
+	Severity    string      `json:"severity"`
+	Status      string      `json:"status"`
+	InternalKey string      `json:"internalKey"`
+	IsTemplate  bool        `json:"isTemplate"`
+	Tags        []string    `json:"tags"`
+	TemplateKey string      `json:"templateKey,omitempty"`
+	SysTags     []string    `json:"sysTags"`
+	Lang        string      `json:"lang"`
+	LangName    string      `json:"langName"`
+	Scope       string      `json:"scope"`
+	IsExternal  bool        `json:"isExternal"`
+	Type        string      `json:"type"`
+	Params      []RuleParam `json:"params,omitempty"`
+}
+
+// RuleParam is a parameter of a Rule
+type RuleParam struct {
+	Key          string `json:"key"`
+	HtmlDesc     string `json:"htmlDesc"`
+	DefaultValue string `json:"defaultValue"`
+	Type         string `json:"type"`
+	// Value is only set on the parameters of an ActiveRule
+	Value string `json:"value,omitempty"`
+}
+
+// ActiveRule describes the activation of a rule in a quality profile, as returned by api/rules/show
+type ActiveRule struct {
+	QProfile string      `json:"qProfile"`
+	Inherit  string      `json:"inherit"`
+	Severity string      `json:"severity"`
+	Params   []RuleParam `json:"params"`
+}
+
+// RuleDetails is returned by api/rules/show
+type RuleDetails struct {
+	Rule    Rule         `json:"rule"`
+	Actives []ActiveRule `json:"actives"`
+}
+
+// RulesPage is a page of Rule. api/rules/search reports paging at the top level.
+type RulesPage struct {
+	Rules    []Rule `json:"rules"`
+	Total    int    `json:"total"`
+	Page     int    `json:"p"`
+	PageSize int    `json:"ps"`
+}
+
+// RulesCreateRequest holds the parameters of api/rules/create
+type RulesCreateRequest struct {
+	CustomKey           string `url:"customKey"`
+	MarkdownDescription string `url:"markdownDescription"`
+	Name                string `url:"name"`
+	Params              string `url:"params"`
+	PreventReactivation string `url:"preventReactivation"`
+	Severity            string `url:"severity"`
+	Status              string `url:"status"`
+	TemplateKey         string `url:"templateKey"`
+	Type                string `url:"type"`
+}
+
+// RulesUpdateRequest holds the parameters of api/rules/update
+type RulesUpdateRequest struct {
+	Key                 string `url:"key"`
+	MarkdownDescription string `url:"markdown_description"`
+	Name                string `url:"name"`
+	Params              string `url:"params"`
+	Severity            string `url:"severity"`
+	Status              string `url:"status"`
+}
+
+// RulesSearchRequest holds the parameters of api/rules/search. QProfile and Activation restrict the search to the
+// rules activated (or not) in a quality profile.
+type RulesSearchRequest struct {
+	RuleKey    string `url:"rule_key,omitempty"`
+	QProfile   string `url:"qprofile,omitempty"`
+	Activation string `url:"activation,omitempty"`
+	Page       int    `url:"p,omitempty"`
+	PageSize   int    `url:"ps,omitempty"`
+}
+
+// Create calls api/rules/create
+func (s *RulesService) Create(ctx context.Context, request RulesCreateRequest) (*Rule, error) {
+	response := struct {
+		Rule Rule `json:"rule"`
+	}{}
+	if err := s.client.post(ctx, "api/rules/create", encode(request), &response); err != nil {
+		return nil, err
+	}
+	return &response.Rule, nil
+}
+
+// Search calls api/rules/search
+func (s *RulesService) Search(ctx context.Context, request RulesSearchRequest) (*RulesPage, error) {
+	page := RulesPage{}
+	if err := s.client.get(ctx, "api/rules/search", encode(request), &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// SearchPages walks every page of api/rules/search
+func (s *RulesService) SearchPages(request RulesSearchRequest) *Pager[Rule] {
+	if request.PageSize == 0 {
+		request.PageSize = maxPageSize
+	}
+	return newPager(func(ctx context.Context, page int) ([]Rule, Paging, error) {
+		request.Page = page
+		response, err := s.Search(ctx, request)
+		if err != nil {
+			return nil, Paging{}, err
+		}
+		return response.Rules, Paging{PageIndex: int64(response.Page), PageSize: int64(response.PageSize), Total: int64(response.Total)}, nil
+	})
+}
+
+// Show calls api/rules/show including the activations of the rule
+func (s *RulesService) Show(ctx context.Context, key string) (*RuleDetails, error) {
+	details := RuleDetails{}
+	params := url.Values{
+		"key":     []string{key},
+		"actives": []string{"true"},
+	}
+	if err := s.client.get(ctx, "api/rules/show", params, &details); err != nil {
+		return nil, err
+	}
+	return &details, nil
+}
+
+// Update calls api/rules/update
+func (s *RulesService) Update(ctx context.Context, request RulesUpdateRequest) error {
+	return s.client.post(ctx, "api/rules/update", encode(request), nil)
+}
+
+// Delete calls api/rules/delete
+func (s *RulesService) Delete(ctx context.Context, key string) error {
+	params := url.Values{
+		"key": []string{key},
+	}
+	return s.client.post(ctx, "api/rules/delete", params, nil)
+}
@@ -0,0 +1,102 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// SettingsService wraps api/settings
+type SettingsService service
+
+// Setting is returned by api/settings/values
+type Setting struct {
+	Key         string              `json:"key"`
+	Value       string              `json:"value"`
+	Values      []string            `json:"values"`
+	Inherited   bool                `json:"inherited"`
+	FieldValues []map[string]string `json:"fieldValues"`
+}
+
+// SettingsSetRequest holds the parameters of api/settings/set. Only one of Value, Values and FieldValues should be set.
+type SettingsSetRequest struct {
+	Key         string
+	Value       string
+	Values      []string
+	FieldValues []map[string]string
+	Component   string
+}
+
+// Values calls api/settings/values. Both keys and component are optional.
+func (s *SettingsService) Values(ctx context.Context, keys []string, component string) ([]Setting, error) {
+	response := struct {
+		Settings []Setting `json:"settings"`
+	}{}
+	params := url.Values{}
+	if len(keys) > 0 {
+		params.Set("keys", strings.Join(keys, ","))
+	}
+	if component != "" {
+		params.Set("component", component)
+	}
+	if err := s.client.get(ctx, "api/settings/values", params, &response); err != nil {
+		return nil, err
+	}
+	return response.Settings, nil
+}
+
+// SecuredKeys calls api/settings/values and returns the keys of the secured settings which are set. SonarQube only
+// lists these keys in setSecuredSettings, never in settings.
+func (s *SettingsService) SecuredKeys(ctx context.Context, keys []string, component string) ([]string, error) {
+	response := struct {
+		SetSecuredSettings []string `json:"setSecuredSettings"`
+	}{}
+	params := url.Values{}
+	if len(keys) > 0 {
+		params.Set("keys", strings.Join(keys, ","))
+	}
+	if component != "" {
+		params.Set("component", component)
+	}
+	if err := s.client.get(ctx, "api/settings/values", params, &response); err != nil {
+		return nil, err
+	}
+	return response.SetSecuredSettings, nil
+}
+
+// Set calls api/settings/set
+func (s *SettingsService) Set(ctx context.Context, request SettingsSetRequest) error {
+	params := url.Values{
+		"key": []string{request.Key},
+	}
+	if request.Value != "" {
+		params.Add("value", request.Value)
+	}
+	for _, value := range request.Values {
+		params.Add("values", value)
+	}
+	for _, fieldValue := range request.FieldValues {
+		b, err := json.Marshal(fieldValue)
+		if err != nil {
+			return fmt.Errorf("failed to encode field values of setting %s: %w", request.Key, err)
+		}
+		params.Add("fieldValues", string(b))
+	}
+	if request.Component != "" {
+		params.Add("component", request.Component)
+	}
+	return s.client.post(ctx, "api/settings/set", params, nil)
+}
+
+// Reset calls api/settings/reset. component is optional.
+func (s *SettingsService) Reset(ctx context.Context, keys []string, component string) error {
+	params := url.Values{
+		"keys": []string{strings.Join(keys, ",")},
+	}
+	if component != "" {
+		params.Set("component", component)
+	}
+	return s.client.post(ctx, "api/settings/reset", params, nil)
+}
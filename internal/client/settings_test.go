@@ -0,0 +1,80 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestSettingsValues(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/sonar/api/settings/values" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("keys"); got != "sonar.a,sonar.b" {
+			t.Errorf("keys = %q, want %q", got, "sonar.a,sonar.b")
+		}
+		if r.URL.Query().Has("component") {
+			t.Errorf("component should be omitted when empty, got %q", r.URL.RawQuery)
+		}
+		w.Write([]byte(`{"settings":[{"key":"sonar.a","value":"1"},{"key":"sonar.b","values":["x","y"],"inherited":true}]}`))
+	})
+
+	settings, err := c.Settings.Values(context.Background(), []string{"sonar.a", "sonar.b"}, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+
+	want := []Setting{
+		{Key: "sonar.a", Value: "1"},
+		{Key: "sonar.b", Values: []string{"x", "y"}, Inherited: true},
+	}
+	if !reflect.DeepEqual(settings, want) {
+		t.Errorf("settings = %+v, want %+v", settings, want)
+	}
+}
+
+func TestSettingsSecuredKeys(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("keys"); got != "sonar.auth.token.secured" {
+			t.Errorf("keys = %q, want %q", got, "sonar.auth.token.secured")
+		}
+		w.Write([]byte(`{"settings":[],"setSecuredSettings":["sonar.auth.token.secured"]}`))
+	})
+
+	keys, err := c.Settings.SecuredKeys(context.Background(), []string{"sonar.auth.token.secured"}, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	if want := []string{"sonar.auth.token.secured"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("keys = %q, want %q", keys, want)
+	}
+}
+
+func TestSettingsSetFieldValues(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		query := r.PostForm
+		if query.Get("key") != "sonar.issue.ignore.multicriteria" || query.Get("component") != "my-project" {
+			t.Errorf("unexpected form %q", query.Encode())
+		}
+		want := []string{`{"resourceKey":"**/*.go","ruleKey":"go:S100"}`}
+		if got := query["fieldValues"]; !reflect.DeepEqual(got, want) {
+			t.Errorf("fieldValues = %q, want %q", got, want)
+		}
+		if query.Has("value") || query.Has("values") {
+			t.Errorf("value and values should be omitted, got %q", query.Encode())
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	err := c.Settings.Set(context.Background(), SettingsSetRequest{
+		Key:         "sonar.issue.ignore.multicriteria",
+		FieldValues: []map[string]string{{"ruleKey": "go:S100", "resourceKey": "**/*.go"}},
+		Component:   "my-project",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+}
@@ -0,0 +1,60 @@
+package client
+
+import (
+	"context"
+)
+
+// SystemService wraps api/system
+type SystemService service
+
+// SystemInfo is the subset of api/system/info the provider relies on
+type SystemInfo struct {
+	System struct {
+		Version string `json:"Version"`
+		Edition string `json:"Edition"`
+	} `json:"System"`
+}
+
+// Info calls api/system/info
+func (s *SystemService) Info(ctx context.Context) (*SystemInfo, error) {
+	info := SystemInfo{}
+	if err := s.client.get(ctx, "api/system/info", nil, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// SystemStatus is the response of api/system/status
+type SystemStatus struct {
+	ID      string `json:"id"`
+	Version string `json:"version"`
+	// Status is one of STARTING, UP, DOWN, RESTARTING, DB_MIGRATION_NEEDED or DB_MIGRATION_RUNNING
+	Status string `json:"status"`
+}
+
+// Status calls api/system/status, which does not require authentication
+func (s *SystemService) Status(ctx context.Context) (*SystemStatus, error) {
+	status := SystemStatus{}
+	if err := s.client.get(ctx, "api/system/status", nil, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// SystemHealth is the response of api/system/health
+type SystemHealth struct {
+	// Health is one of GREEN, YELLOW or RED
+	Health string `json:"health"`
+	Causes []struct {
+		Message string `json:"message"`
+	} `json:"causes"`
+}
+
+// Health calls api/system/health, which requires the system passcode or the 'Administer System' permission
+func (s *SystemService) Health(ctx context.Context) (*SystemHealth, error) {
+	health := SystemHealth{}
+	if err := s.client.get(ctx, "api/system/health", nil, &health); err != nil {
+		return nil, err
+	}
+	return &health, nil
+}
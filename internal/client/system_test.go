@@ -0,0 +1,38 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestSystemStatus(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/sonar/api/system/status" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		w.Write([]byte(`{"id":"20230921","version":"10.2.1","status":"DB_MIGRATION_NEEDED"}`))
+	})
+
+	status, err := c.System.Status(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+
+	want := SystemStatus{ID: "20230921", Version: "10.2.1", Status: "DB_MIGRATION_NEEDED"}
+	if *status != want {
+		t.Errorf("status = %+v, want %+v", *status, want)
+	}
+}
+
+func TestSystemHealthForbidden(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"errors":[{"msg":"Insufficient privileges"}]}`))
+	})
+
+	_, err := c.System.Health(context.Background())
+	if !IsForbidden(err) {
+		t.Errorf("IsForbidden(%v) = false, want true", err)
+	}
+}
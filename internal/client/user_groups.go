@@ -0,0 +1,155 @@
+package client
+
+import (
+	"context"
+	"net/url"
+)
+
+// UserGroupsService wraps api/user_groups
+type UserGroupsService service
+
+// Group is returned by the api/user_groups actions. SonarQube 10+ no longer returns ID.
+type Group struct {
+	ID           string   `json:"id,omitempty"`
+	Organization string   `json:"organization,omitempty"`
+	Name         string   `json:"name,omitempty"`
+	Description  string   `json:"description,omitempty"`
+	MembersCount int      `json:"membersCount,omitempty"`
+	IsDefault    bool     `json:"default,omitempty"`
+	Permissions  []string `json:"permissions,omitempty"`
+}
+
+// GroupsPage is a page of Group
+type GroupsPage struct {
+	Paging Paging  `json:"paging"`
+	Groups []Group `json:"groups"`
+}
+
+// GroupMember is returned by api/user_groups/users
+type GroupMember struct {
+	Login string `json:"login,omitempty"`
+	Name  string `json:"name,omitempty"`
+}
+
+// GroupMembersPage is a page of GroupMember
+type GroupMembersPage struct {
+	Paging  Paging        `json:"paging"`
+	Members []GroupMember `json:"users"`
+}
+
+// UserGroupsSearchRequest holds the parameters of api/user_groups/search
+type UserGroupsSearchRequest struct {
+	Query    string `url:"q,omitempty"`
+	Page     int    `url:"p,omitempty"`
+	PageSize int    `url:"ps,omitempty"`
+}
+
+// UserGroupsUsersRequest holds the parameters of api/user_groups/users
+type UserGroupsUsersRequest struct {
+	Name     string `url:"name"`
+	Query    string `url:"q,omitempty"`
+	Page     int    `url:"p,omitempty"`
+	PageSize int    `url:"ps,omitempty"`
+}
+
+// UserGroupsUpdateRequest holds the parameters of api/user_groups/update.
+// Name is only sent when the group is renamed, Description is always sent so it can be cleared.
+type UserGroupsUpdateRequest struct {
+	CurrentName string `url:"currentName"`
+	Name        string `url:"name,omitempty"`
+	Description string `url:"description"`
+}
+
+// Create calls api/user_groups/create
+func (s *UserGroupsService) Create(ctx context.Context, name string, description string) (*Group, error) {
+	response := struct {
+		Group Group `json:"group"`
+	}{}
+	params := url.Values{
+		"name":        []string{name},
+		"description": []string{description},
+	}
+	if err := s.client.post(ctx, "api/user_groups/create", params, &response); err != nil {
+		return nil, err
+	}
+	return &response.Group, nil
+}
+
+// Search calls api/user_groups/search
+func (s *UserGroupsService) Search(ctx context.Context, request UserGroupsSearchRequest) (*GroupsPage, error) {
+	page := GroupsPage{}
+	if err := s.client.get(ctx, "api/user_groups/search", encode(request), &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// SearchPages walks every page of api/user_groups/search
+func (s *UserGroupsService) SearchPages(request UserGroupsSearchRequest) *Pager[Group] {
+	if request.PageSize == 0 {
+		request.PageSize = maxPageSize
+	}
+	return newPager(func(ctx context.Context, page int) ([]Group, Paging, error) {
+		request.Page = page
+		response, err := s.Search(ctx, request)
+		if err != nil {
+			return nil, Paging{}, err
+		}
+		return response.Groups, response.Paging, nil
+	})
+}
+
+// Update calls api/user_groups/update
+func (s *UserGroupsService) Update(ctx context.Context, request UserGroupsUpdateRequest) error {
+	return s.client.post(ctx, "api/user_groups/update", encode(request), nil)
+}
+
+// Delete calls api/user_groups/delete
+func (s *UserGroupsService) Delete(ctx context.Context, name string) error {
+	params := url.Values{
+		"name": []string{name},
+	}
+	return s.client.post(ctx, "api/user_groups/delete", params, nil)
+}
+
+// AddUser calls api/user_groups/add_user
+func (s *UserGroupsService) AddUser(ctx context.Context, name string, login string) error {
+	params := url.Values{
+		"name":  []string{name},
+		"login": []string{login},
+	}
+	return s.client.post(ctx, "api/user_groups/add_user", params, nil)
+}
+
+// RemoveUser calls api/user_groups/remove_user
+func (s *UserGroupsService) RemoveUser(ctx context.Context, name string, login string) error {
+	params := url.Values{
+		"name":  []string{name},
+		"login": []string{login},
+	}
+	return s.client.post(ctx, "api/user_groups/remove_user", params, nil)
+}
+
+// Users calls api/user_groups/users
+func (s *UserGroupsService) Users(ctx context.Context, request UserGroupsUsersRequest) (*GroupMembersPage, error) {
+	page := GroupMembersPage{}
+	if err := s.client.get(ctx, "api/user_groups/users", encode(request), &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// UsersPages walks every page of api/user_groups/users
+func (s *UserGroupsService) UsersPages(request UserGroupsUsersRequest) *Pager[GroupMember] {
+	if request.PageSize == 0 {
+		request.PageSize = maxPageSize
+	}
+	return newPager(func(ctx context.Context, page int) ([]GroupMember, Paging, error) {
+		request.Page = page
+		response, err := s.Users(ctx, request)
+		if err != nil {
+			return nil, Paging{}, err
+		}
+		return response.Members, response.Paging, nil
+	})
+}
@@ -0,0 +1,76 @@
+package client
+
+import (
+	"context"
+	"net/url"
+)
+
+// UserTokensService wraps api/user_tokens
+type UserTokensService service
+
+// Token is returned by api/user_tokens/generate and api/user_tokens/search.
+// Token is only set in the response of api/user_tokens/generate.
+type Token struct {
+	Login          string       `json:"login,omitempty"`
+	Name           string       `json:"name,omitempty"`
+	Token          string       `json:"token,omitempty"`
+	ExpirationDate string       `json:"expirationDate,omitempty"`
+	Type           string       `json:"type,omitempty"`
+	CreatedAt      string       `json:"createdAt,omitempty"`
+	IsExpired      bool         `json:"isExpired,omitempty"`
+	Project        TokenProject `json:"project,omitempty"`
+}
+
+// TokenProject is the project a PROJECT_ANALYSIS_TOKEN is restricted to
+type TokenProject struct {
+	Key  string `json:"key,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+// UserTokens is returned by api/user_tokens/search
+type UserTokens struct {
+	Login  string  `json:"login,omitempty"`
+	Tokens []Token `json:"userTokens,omitempty"`
+}
+
+// UserTokensGenerateRequest holds the parameters of api/user_tokens/generate
+type UserTokensGenerateRequest struct {
+	Name           string `url:"name"`
+	Type           string `url:"type"`
+	Login          string `url:"login,omitempty"`
+	ProjectKey     string `url:"projectKey,omitempty"`
+	ExpirationDate string `url:"expirationDate,omitempty"`
+}
+
+// Generate calls api/user_tokens/generate
+func (s *UserTokensService) Generate(ctx context.Context, request UserTokensGenerateRequest) (*Token, error) {
+	token := Token{}
+	if err := s.client.post(ctx, "api/user_tokens/generate", encode(request), &token); err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// Search calls api/user_tokens/search. An empty login searches the tokens of the authenticated user.
+func (s *UserTokensService) Search(ctx context.Context, login string) (*UserTokens, error) {
+	tokens := UserTokens{}
+	params := url.Values{}
+	if login != "" {
+		params.Set("login", login)
+	}
+	if err := s.client.get(ctx, "api/user_tokens/search", params, &tokens); err != nil {
+		return nil, err
+	}
+	return &tokens, nil
+}
+
+// Revoke calls api/user_tokens/revoke. An empty login revokes a token of the authenticated user.
+func (s *UserTokensService) Revoke(ctx context.Context, name string, login string) error {
+	params := url.Values{
+		"name": []string{name},
+	}
+	if login != "" {
+		params.Set("login", login)
+	}
+	return s.client.post(ctx, "api/user_tokens/revoke", params, nil)
+}
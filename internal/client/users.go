@@ -0,0 +1,119 @@
+package client
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+// UsersService wraps api/users
+type UsersService service
+
+// User is returned by the api/users actions
+type User struct {
+	Login       string   `json:"login,omitempty"`
+	Name        string   `json:"name,omitempty"`
+	Email       string   `json:"email,omitempty"`
+	Permissions []string `json:"permissions,omitempty"`
+	IsActive    bool     `json:"active,omitempty"`
+	IsLocal     bool     `json:"local,omitempty"`
+
+	ExternalIdentity string `json:"externalIdentity,omitempty"`
+	ExternalProvider string `json:"externalProvider,omitempty"`
+}
+
+// UsersPage is a page of User
+type UsersPage struct {
+	Paging Paging `json:"paging"`
+	Users  []User `json:"users"`
+}
+
+// UsersCreateRequest holds the parameters of api/users/create
+type UsersCreateRequest struct {
+	Login    string `url:"login"`
+	Name     string `url:"name"`
+	Local    bool   `url:"local"`
+	Password string `url:"password,omitempty"`
+	Email    string `url:"email,omitempty"`
+}
+
+// UsersSearchRequest holds the parameters of api/users/search
+type UsersSearchRequest struct {
+	Query    string `url:"q,omitempty"`
+	Page     int    `url:"p,omitempty"`
+	PageSize int    `url:"ps,omitempty"`
+}
+
+// UsersUpdateIdentityProviderRequest holds the parameters of api/users/update_identity_provider
+type UsersUpdateIdentityProviderRequest struct {
+	Login               string `url:"login"`
+	NewExternalIdentity string `url:"newExternalIdentity,omitempty"`
+	NewExternalProvider string `url:"newExternalProvider"`
+}
+
+// Create calls api/users/create
+func (s *UsersService) Create(ctx context.Context, request UsersCreateRequest) (*User, error) {
+	response := struct {
+		User User `json:"user"`
+	}{}
+	if err := s.client.post(ctx, "api/users/create", encode(request), &response); err != nil {
+		return nil, err
+	}
+	return &response.User, nil
+}
+
+// Search calls api/users/search
+func (s *UsersService) Search(ctx context.Context, request UsersSearchRequest) (*UsersPage, error) {
+	page := UsersPage{}
+	if err := s.client.get(ctx, "api/users/search", encode(request), &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// SearchPages walks every page of api/users/search
+func (s *UsersService) SearchPages(request UsersSearchRequest) *Pager[User] {
+	if request.PageSize == 0 {
+		request.PageSize = maxPageSize
+	}
+	return newPager(func(ctx context.Context, page int) ([]User, Paging, error) {
+		request.Page = page
+		response, err := s.Search(ctx, request)
+		if err != nil {
+			return nil, Paging{}, err
+		}
+		return response.Users, response.Paging, nil
+	})
+}
+
+// UpdateEmail calls api/users/update to change the email of a user
+func (s *UsersService) UpdateEmail(ctx context.Context, login string, email string) error {
+	params := url.Values{
+		"login": []string{login},
+		"email": []string{email},
+	}
+	return s.client.post(ctx, "api/users/update", params, nil)
+}
+
+// ChangePassword calls api/users/change_password
+func (s *UsersService) ChangePassword(ctx context.Context, login string, password string) error {
+	params := url.Values{
+		"login":    []string{login},
+		"password": []string{password},
+	}
+	return s.client.post(ctx, "api/users/change_password", params, nil)
+}
+
+// Deactivate calls api/users/deactivate
+func (s *UsersService) Deactivate(ctx context.Context, login string, anonymize bool) error {
+	params := url.Values{
+		"login":     []string{login},
+		"anonymize": []string{strconv.FormatBool(anonymize)},
+	}
+	return s.client.post(ctx, "api/users/deactivate", params, nil)
+}
+
+// UpdateIdentityProvider calls api/users/update_identity_provider
+func (s *UsersService) UpdateIdentityProvider(ctx context.Context, request UsersUpdateIdentityProviderRequest) error {
+	return s.client.post(ctx, "api/users/update_identity_provider", encode(request), nil)
+}
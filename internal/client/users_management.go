@@ -0,0 +1,55 @@
+package client
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+// UsersManagementService wraps api/v2/users-management, which replaces api/users in recent SonarQube versions
+type UsersManagementService service
+
+// ManagedUser is a user returned by api/v2/users-management/users
+type ManagedUser struct {
+	ID     string `json:"id"`
+	Login  string `json:"login"`
+	Name   string `json:"name"`
+	Email  string `json:"email"`
+	Active bool   `json:"active"`
+	Local  bool   `json:"local"`
+}
+
+// SearchUsersPages walks every page of api/v2/users-management/users for the active users matching q
+func (s *UsersManagementService) SearchUsersPages(q string) *Pager[ManagedUser] {
+	return newPager(func(ctx context.Context, page int) ([]ManagedUser, Paging, error) {
+		response := struct {
+			Users []ManagedUser `json:"users"`
+			Page  Paging        `json:"page"`
+		}{}
+		params := url.Values{
+			"q":         []string{q},
+			"pageIndex": []string{strconv.Itoa(page)},
+			"pageSize":  []string{strconv.Itoa(maxPageSize)},
+		}
+		if err := s.client.get(ctx, "api/v2/users-management/users", params, &response); err != nil {
+			return nil, Paging{}, err
+		}
+		return response.Users, response.Page, nil
+	})
+}
+
+// FindUser searches every page of api/v2/users-management/users for the active user with the given login. It
+// returns nil when there is none.
+func (s *UsersManagementService) FindUser(ctx context.Context, login string) (*ManagedUser, error) {
+	return s.SearchUsersPages(login).Find(ctx, func(user ManagedUser) bool {
+		return user.Login == login
+	})
+}
+
+// DeactivateUser calls DELETE api/v2/users-management/users/{id}, which deactivates a user
+func (s *UsersManagementService) DeactivateUser(ctx context.Context, id string, anonymize bool) error {
+	params := url.Values{
+		"anonymize": []string{strconv.FormatBool(anonymize)},
+	}
+	return s.client.delete(ctx, "api/v2/users-management/users/"+url.PathEscape(id), params, nil)
+}
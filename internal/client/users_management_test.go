@@ -0,0 +1,73 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestUsersManagementFindUser(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/sonar/api/v2/users-management/users" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if r.URL.Query().Get("q") != "john" {
+			t.Errorf("unexpected query %q", r.URL.RawQuery)
+		}
+		w.Write([]byte(`{"users":[{"id":"1","login":"johnny"},{"id":"2","login":"john","active":true}]}`))
+	})
+
+	user, err := c.UsersManagement.FindUser(context.Background(), "john")
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	want := ManagedUser{ID: "2", Login: "john", Active: true}
+	if user == nil || *user != want {
+		t.Errorf("user = %+v, want %+v", user, want)
+	}
+}
+
+func TestUsersManagementFindUserOnLaterPage(t *testing.T) {
+	pages := []string{}
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		pages = append(pages, r.URL.Query().Get("pageIndex"))
+		switch r.URL.Query().Get("pageIndex") {
+		case "1":
+			w.Write([]byte(`{"users":[{"id":"1","login":"john1"}],"page":{"pageIndex":1,"pageSize":1,"total":3}}`))
+		case "2":
+			w.Write([]byte(`{"users":[{"id":"2","login":"john"}],"page":{"pageIndex":2,"pageSize":1,"total":3}}`))
+		default:
+			t.Errorf("unexpected page %q", r.URL.RawQuery)
+		}
+	})
+
+	user, err := c.UsersManagement.FindUser(context.Background(), "john")
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	if user == nil || user.ID != "2" {
+		t.Errorf("user = %+v, want the user with id 2", user)
+	}
+	if strings.Join(pages, ",") != "1,2" {
+		t.Errorf("pages = %v, want 1,2", pages)
+	}
+}
+
+func TestUsersManagementDeactivateUser(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete || r.URL.Path != "/sonar/api/v2/users-management/users/AY-1" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if r.URL.Query().Get("anonymize") != "true" {
+			t.Errorf("unexpected query %q", r.URL.RawQuery)
+		}
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message":"User 'AY-1' not found"}`))
+	})
+
+	err := c.UsersManagement.DeactivateUser(context.Background(), "AY-1", true)
+	if !IsNotFound(err) {
+		t.Errorf("IsNotFound(%v) = false, want true", err)
+	}
+}
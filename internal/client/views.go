@@ -0,0 +1,229 @@
+package client
+
+import (
+	"context"
+	"net/url"
+	"strings"
+)
+
+// ViewsService wraps api/views, which manages portfolios
+type ViewsService service
+
+// Portfolio is returned by api/views/create and api/views/show
+type Portfolio struct {
+	Key              string             `json:"key"`
+	Name             string             `json:"name"`
+	Desc             string             `json:"desc,omitempty"`
+	Qualifier        string             `json:"qualifier"`
+	Visibility       string             `json:"visibility"`
+	SelectionMode    string             `json:"selectionMode"`
+	Branch           string             `json:"branch,omitempty"`
+	Tags             []string           `json:"tags,omitempty"`
+	Regexp           string             `json:"regexp,omitempty"`
+	SelectedProjects []PortfolioProject `json:"selectedProjects,omitempty"`
+	SubViews         []PortfolioSubView `json:"subViews,omitempty"`
+}
+
+// PortfolioProject is a project manually selected in a Portfolio
+type PortfolioProject struct {
+	ProjectKey       string   `json:"projectKey"`
+	SelectedBranches []string `json:"selectedBranches,omitempty"`
+}
+
+// PortfolioSubView is a child of a Portfolio returned by api/views/show: a portfolio reference (VW), a local view (SVW)
+// or an application (APP)
+type PortfolioSubView struct {
+	Key       string `json:"key"`
+	Name      string `json:"name"`
+	Qualifier string `json:"qualifier"`
+}
+
+// ViewsCreateRequest holds the parameters of api/views/create
+type ViewsCreateRequest struct {
+	Key         string `url:"key"`
+	Name        string `url:"name"`
+	Description string `url:"description"`
+	Visibility  string `url:"visibility"`
+}
+
+// Create calls api/views/create
+func (s *ViewsService) Create(ctx context.Context, request ViewsCreateRequest) (*Portfolio, error) {
+	portfolio := Portfolio{}
+	if err := s.client.post(ctx, "api/views/create", encode(request), &portfolio); err != nil {
+		return nil, err
+	}
+	return &portfolio, nil
+}
+
+// Show calls api/views/show
+func (s *ViewsService) Show(ctx context.Context, key string) (*Portfolio, error) {
+	portfolio := Portfolio{}
+	params := url.Values{
+		"key": []string{key},
+	}
+	if err := s.client.get(ctx, "api/views/show", params, &portfolio); err != nil {
+		return nil, err
+	}
+	return &portfolio, nil
+}
+
+// Update calls api/views/update
+func (s *ViewsService) Update(ctx context.Context, key string, name string, description string) error {
+	params := url.Values{
+		"key":         []string{key},
+		"description": []string{description},
+		"name":        []string{name},
+	}
+	return s.client.post(ctx, "api/views/update", params, nil)
+}
+
+// Delete calls api/views/delete
+func (s *ViewsService) Delete(ctx context.Context, key string) error {
+	params := url.Values{
+		"key": []string{key},
+	}
+	return s.client.post(ctx, "api/views/delete", params, nil)
+}
+
+// SetNoneMode calls api/views/set_none_mode
+func (s *ViewsService) SetNoneMode(ctx context.Context, portfolio string) error {
+	params := url.Values{
+		"portfolio": []string{portfolio},
+	}
+	return s.client.post(ctx, "api/views/set_none_mode", params, nil)
+}
+
+// SetManualMode calls api/views/set_manual_mode
+func (s *ViewsService) SetManualMode(ctx context.Context, portfolio string) error {
+	params := url.Values{
+		"portfolio": []string{portfolio},
+	}
+	return s.client.post(ctx, "api/views/set_manual_mode", params, nil)
+}
+
+// SetTagsMode calls api/views/set_tags_mode. An empty branch selects the main branch.
+func (s *ViewsService) SetTagsMode(ctx context.Context, portfolio string, tags []string, branch string) error {
+	params := url.Values{
+		"portfolio": []string{portfolio},
+		"tags":      []string{strings.Join(tags, ",")},
+	}
+	if branch != "" {
+		params.Add("branch", branch)
+	}
+	return s.client.post(ctx, "api/views/set_tags_mode", params, nil)
+}
+
+// SetRegexpMode calls api/views/set_regexp_mode. An empty branch selects the main branch.
+func (s *ViewsService) SetRegexpMode(ctx context.Context, portfolio string, regexp string, branch string) error {
+	params := url.Values{
+		"portfolio": []string{portfolio},
+		"regexp":    []string{regexp},
+	}
+	if branch != "" {
+		params.Add("branch", branch)
+	}
+	return s.client.post(ctx, "api/views/set_regexp_mode", params, nil)
+}
+
+// SetRemainingProjectsMode calls api/views/set_remaining_projects_mode. An empty branch selects the main branch.
+func (s *ViewsService) SetRemainingProjectsMode(ctx context.Context, portfolio string, branch string) error {
+	params := url.Values{
+		"portfolio": []string{portfolio},
+	}
+	if branch != "" {
+		params.Add("branch", branch)
+	}
+	return s.client.post(ctx, "api/views/set_remaining_projects_mode", params, nil)
+}
+
+// AddProject calls api/views/add_project
+func (s *ViewsService) AddProject(ctx context.Context, key string, project string) error {
+	params := url.Values{
+		"key":     []string{key},
+		"project": []string{project},
+	}
+	return s.client.post(ctx, "api/views/add_project", params, nil)
+}
+
+// RemoveProject calls api/views/remove_project
+func (s *ViewsService) RemoveProject(ctx context.Context, key string, project string) error {
+	params := url.Values{
+		"key":     []string{key},
+		"project": []string{project},
+	}
+	return s.client.post(ctx, "api/views/remove_project", params, nil)
+}
+
+// AddProjectBranch calls api/views/add_project_branch
+func (s *ViewsService) AddProjectBranch(ctx context.Context, key string, project string, branch string) error {
+	params := url.Values{
+		"key":     []string{key},
+		"project": []string{project},
+		"branch":  []string{branch},
+	}
+	return s.client.post(ctx, "api/views/add_project_branch", params, nil)
+}
+
+// RemoveProjectBranch calls api/views/remove_project_branch
+func (s *ViewsService) RemoveProjectBranch(ctx context.Context, key string, project string, branch string) error {
+	params := url.Values{
+		"key":     []string{key},
+		"project": []string{project},
+		"branch":  []string{branch},
+	}
+	return s.client.post(ctx, "api/views/remove_project_branch", params, nil)
+}
+
+// AddPortfolio calls api/views/add_portfolio
+func (s *ViewsService) AddPortfolio(ctx context.Context, portfolio string, reference string) error {
+	params := url.Values{
+		"portfolio": []string{portfolio},
+		"reference": []string{reference},
+	}
+	return s.client.post(ctx, "api/views/add_portfolio", params, nil)
+}
+
+// RemovePortfolio calls api/views/remove_portfolio
+func (s *ViewsService) RemovePortfolio(ctx context.Context, portfolio string, reference string) error {
+	params := url.Values{
+		"portfolio": []string{portfolio},
+		"reference": []string{reference},
+	}
+	return s.client.post(ctx, "api/views/remove_portfolio", params, nil)
+}
+
+// AddLocalView calls api/views/add_local_view
+func (s *ViewsService) AddLocalView(ctx context.Context, key string, refKey string) error {
+	params := url.Values{
+		"key":     []string{key},
+		"ref_key": []string{refKey},
+	}
+	return s.client.post(ctx, "api/views/add_local_view", params, nil)
+}
+
+// RemoveLocalView calls api/views/remove_local_view
+func (s *ViewsService) RemoveLocalView(ctx context.Context, key string, refKey string) error {
+	params := url.Values{
+		"key":     []string{key},
+		"ref_key": []string{refKey},
+	}
+	return s.client.post(ctx, "api/views/remove_local_view", params, nil)
+}
+
+// AddApplication calls api/views/add_application
+func (s *ViewsService) AddApplication(ctx context.Context, portfolio string, application string) error {
+	params := url.Values{
+		"portfolio":   []string{portfolio},
+		"application": []string{application},
+	}
+	return s.client.post(ctx, "api/views/add_application", params, nil)
+}
+
+// RemoveApplication calls api/views/remove_application
+func (s *ViewsService) RemoveApplication(ctx context.Context, portfolio string, application string) error {
+	params := url.Values{
+		"portfolio":   []string{portfolio},
+		"application": []string{application},
+	}
+	return s.client.post(ctx, "api/views/remove_application", params, nil)
+}
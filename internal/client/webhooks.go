@@ -0,0 +1,73 @@
+package client
+
+import (
+	"context"
+	"net/url"
+)
+
+// WebhooksService wraps api/webhooks
+type WebhooksService service
+
+// Webhook is returned by api/webhooks/create and api/webhooks/list
+type Webhook struct {
+	Key    string `json:"key"`
+	Name   string `json:"name"`
+	Url    string `json:"url"`
+	Secret string `json:"secret"`
+}
+
+// WebhooksCreateRequest holds the parameters of api/webhooks/create
+type WebhooksCreateRequest struct {
+	Name    string `url:"name"`
+	Url     string `url:"url"`
+	Secret  string `url:"secret,omitempty"`
+	Project string `url:"project,omitempty"`
+}
+
+// WebhooksUpdateRequest holds the parameters of api/webhooks/update
+type WebhooksUpdateRequest struct {
+	Webhook string `url:"webhook"`
+	Name    string `url:"name"`
+	Url     string `url:"url"`
+	Secret  string `url:"secret,omitempty"`
+	Project string `url:"project,omitempty"`
+}
+
+// Create calls api/webhooks/create
+func (s *WebhooksService) Create(ctx context.Context, request WebhooksCreateRequest) (*Webhook, error) {
+	response := struct {
+		Webhook Webhook `json:"webhook"`
+	}{}
+	if err := s.client.post(ctx, "api/webhooks/create", encode(request), &response); err != nil {
+		return nil, err
+	}
+	return &response.Webhook, nil
+}
+
+// List calls api/webhooks/list. An empty project lists the global webhooks.
+func (s *WebhooksService) List(ctx context.Context, project string) ([]Webhook, error) {
+	response := struct {
+		Webhooks []Webhook `json:"webhooks"`
+	}{}
+	params := url.Values{}
+	if project != "" {
+		params.Set("project", project)
+	}
+	if err := s.client.get(ctx, "api/webhooks/list", params, &response); err != nil {
+		return nil, err
+	}
+	return response.Webhooks, nil
+}
+
+// Update calls api/webhooks/update
+func (s *WebhooksService) Update(ctx context.Context, request WebhooksUpdateRequest) error {
+	return s.client.post(ctx, "api/webhooks/update", encode(request), nil)
+}
+
+// Delete calls api/webhooks/delete
+func (s *WebhooksService) Delete(ctx context.Context, webhook string) error {
+	params := url.Values{
+		"webhook": []string{webhook},
+	}
+	return s.client.post(ctx, "api/webhooks/delete", params, nil)
+}
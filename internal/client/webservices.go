@@ -0,0 +1,61 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+)
+
+// WebservicesService wraps api/webservices, which documents the web API exposed by the server, and the OpenAPI
+// document of the v2 web API
+type WebservicesService service
+
+// WebService is a web service returned by api/webservices/list, e.g. api/projects
+type WebService struct {
+	Path    string             `json:"path"`
+	Actions []WebServiceAction `json:"actions"`
+}
+
+// WebServiceAction is an action of a WebService, e.g. create for api/projects/create
+type WebServiceAction struct {
+	Key             string                  `json:"key"`
+	Post            bool                    `json:"post"`
+	DeprecatedSince string                  `json:"deprecatedSince,omitempty"`
+	Params          []WebServiceActionParam `json:"params"`
+}
+
+// WebServiceActionParam is a parameter of a WebServiceAction
+type WebServiceActionParam struct {
+	Key             string `json:"key"`
+	Required        bool   `json:"required"`
+	DeprecatedSince string `json:"deprecatedSince,omitempty"`
+}
+
+// APIDocument is the subset of the OpenAPI document of the v2 web API the provider relies on.
+// Paths maps every path, e.g. "/api/v2/users-management/users/{id}", to its operations keyed by HTTP method.
+type APIDocument struct {
+	Paths map[string]map[string]json.RawMessage `json:"paths"`
+}
+
+// List calls api/webservices/list. Internal web services are not returned.
+func (s *WebservicesService) List(ctx context.Context) ([]WebService, error) {
+	response := struct {
+		WebServices []WebService `json:"webServices"`
+	}{}
+	params := url.Values{
+		"include_internal": []string{"false"},
+	}
+	if err := s.client.get(ctx, "api/webservices/list", params, &response); err != nil {
+		return nil, err
+	}
+	return response.WebServices, nil
+}
+
+// APIDocs calls api/v2/api-docs, which returns the OpenAPI document of the v2 web API available since SonarQube 10
+func (s *WebservicesService) APIDocs(ctx context.Context) (*APIDocument, error) {
+	document := APIDocument{}
+	if err := s.client.get(ctx, "api/v2/api-docs", nil, &document); err != nil {
+		return nil, err
+	}
+	return &document, nil
+}
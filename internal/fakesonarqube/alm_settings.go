@@ -0,0 +1,353 @@
+package fakesonarqube
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// almSetting is a DevOps platform instance. Like SonarQube, the fake never returns the secrets of a setting.
+type almSetting struct {
+	Key       string
+	Alm       string
+	URL       string
+	AppID     string
+	ClientID  string
+	Workspace string
+}
+
+func (a *almSetting) response() map[string]interface{} {
+	if a.Alm == "bitbucketcloud" {
+		// Bitbucket Cloud settings have a workspace instead of a URL
+		return map[string]interface{}{"key": a.Key, "workspace": a.Workspace, "clientId": a.ClientID}
+	}
+	response := map[string]interface{}{"key": a.Key, "url": a.URL}
+	if a.Alm == "github" {
+		response["appId"], response["clientId"] = a.AppID, a.ClientID
+	}
+	return response
+}
+
+// almBinding binds a project to a repository of a DevOps platform instance
+type almBinding struct {
+	almSetting            string
+	repository            string
+	slug                  string
+	summaryCommentEnabled bool
+	monorepo              bool
+}
+
+func (s *Server) registerAlmSettings() {
+	s.handle(http.MethodGet, "api/alm_settings/list_definitions", s.listAlmDefinitions)
+	s.handle(http.MethodPost, "api/alm_settings/delete", s.deleteAlmSetting)
+	for _, alm := range []string{"azure", "bitbucket", "bitbucketcloud", "github", "gitlab"} {
+		alm := alm
+		s.handle(http.MethodPost, "api/alm_settings/create_"+alm, func(r *request) (interface{}, error) {
+			return s.createAlmSetting(r, alm)
+		})
+		s.handle(http.MethodPost, "api/alm_settings/update_"+alm, func(r *request) (interface{}, error) {
+			return s.updateAlmSetting(r, alm)
+		})
+		s.handle(http.MethodPost, "api/alm_settings/set_"+alm+"_binding", func(r *request) (interface{}, error) {
+			return s.setAlmBinding(r, alm)
+		})
+	}
+	s.handle(http.MethodGet, "api/alm_settings/get_binding", s.getAlmBinding)
+	s.handle(http.MethodPost, "api/alm_settings/delete_binding", s.deleteAlmBinding)
+	s.handle(http.MethodGet, "api/alm_settings/count_binding", s.countAlmBinding)
+	s.handle(http.MethodGet, "api/alm_settings/validate", s.validateAlmSetting)
+	s.handle(http.MethodGet, "api/alm_settings/validate_binding", s.validateAlmBinding)
+}
+
+func (s *Server) findAlmSetting(key string) *almSetting {
+	for _, a := range s.almSettings {
+		if a.Key == key {
+			return a
+		}
+	}
+	return nil
+}
+
+func (s *Server) existingAlmSetting(key string) (*almSetting, error) {
+	if a := s.findAlmSetting(key); a != nil {
+		return a, nil
+	}
+	return nil, notFound("DevOps Platform Setting '%s' not found", key)
+}
+
+// almSettingParams validates the parameters specific to a DevOps platform. Secrets are only required on creation.
+func almSettingParams(r *request, alm string, setting *almSetting, create bool) error {
+	var required []string
+	switch alm {
+	case "github":
+		required = []string{"appId", "clientId", "url"}
+		if create {
+			required = append(required, "clientSecret", "privateKey")
+		}
+	case "bitbucketcloud":
+		required = []string{"clientId", "workspace"}
+		if create {
+			required = append(required, "clientSecret")
+		}
+	default:
+		required = []string{"url"}
+		if create {
+			required = append(required, "personalAccessToken")
+		}
+	}
+	for _, name := range required {
+		if _, err := r.required(name); err != nil {
+			return err
+		}
+	}
+
+	setting.Alm, setting.URL = alm, r.param("url")
+	switch alm {
+	case "github":
+		setting.AppID, setting.ClientID = r.param("appId"), r.param("clientId")
+	case "bitbucketcloud":
+		setting.ClientID, setting.Workspace = r.param("clientId"), r.param("workspace")
+	}
+	return nil
+}
+
+func (s *Server) createAlmSetting(r *request, alm string) (interface{}, error) {
+	key, err := r.required("key")
+	if err != nil {
+		return nil, err
+	}
+	if s.findAlmSetting(key) != nil {
+		return nil, badRequest("An DevOps Platform setting with key '%s' already exists", key)
+	}
+
+	setting := &almSetting{Key: key}
+	if err := almSettingParams(r, alm, setting, true); err != nil {
+		return nil, err
+	}
+	s.almSettings = append(s.almSettings, setting)
+	return nil, nil
+}
+
+func (s *Server) updateAlmSetting(r *request, alm string) (interface{}, error) {
+	key, err := r.required("key")
+	if err != nil {
+		return nil, err
+	}
+	setting, err := s.existingAlmSetting(key)
+	if err != nil {
+		return nil, err
+	}
+	if setting.Alm != alm {
+		return nil, badRequest("DevOps Platform Setting '%s' is not a %s setting", key, alm)
+	}
+
+	updated := *setting
+	if err := almSettingParams(r, alm, &updated, false); err != nil {
+		return nil, err
+	}
+	if newKey := r.param("newKey"); newKey != "" && newKey != key {
+		if s.findAlmSetting(newKey) != nil {
+			return nil, badRequest("An DevOps Platform setting with key '%s' already exists", newKey)
+		}
+		updated.Key = newKey
+		for _, binding := range s.almBindings {
+			if binding.almSetting == key {
+				binding.almSetting = newKey
+			}
+		}
+	}
+	*setting = updated
+	return nil, nil
+}
+
+func (s *Server) deleteAlmSetting(r *request) (interface{}, error) {
+	key, err := r.required("key")
+	if err != nil {
+		return nil, err
+	}
+	setting, err := s.existingAlmSetting(key)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range s.almSettings {
+		if s.almSettings[i] == setting {
+			s.almSettings = append(s.almSettings[:i], s.almSettings[i+1:]...)
+			break
+		}
+	}
+	for project, binding := range s.almBindings {
+		if binding.almSetting == key {
+			delete(s.almBindings, project)
+		}
+	}
+	return nil, nil
+}
+
+func (s *Server) listAlmDefinitions(r *request) (interface{}, error) {
+	definitions := map[string][]map[string]interface{}{}
+	for _, alm := range []string{"azure", "bitbucket", "bitbucketcloud", "github", "gitlab"} {
+		definitions[alm] = []map[string]interface{}{}
+	}
+	for _, setting := range s.almSettings {
+		definitions[setting.Alm] = append(definitions[setting.Alm], setting.response())
+	}
+	return definitions, nil
+}
+
+func (s *Server) countAlmBinding(r *request) (interface{}, error) {
+	key, err := r.required("almSetting")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.existingAlmSetting(key); err != nil {
+		return nil, err
+	}
+
+	projects := 0
+	for _, binding := range s.almBindings {
+		if binding.almSetting == key {
+			projects++
+		}
+	}
+	return map[string]interface{}{"key": key, "projects": projects}, nil
+}
+
+func (s *Server) setAlmBinding(r *request, alm string) (interface{}, error) {
+	key, err := r.required("almSetting")
+	if err != nil {
+		return nil, err
+	}
+	projectKey, err := r.required("project")
+	if err != nil {
+		return nil, err
+	}
+	monorepo, err := r.boolean("monorepo", false)
+	if err != nil {
+		return nil, err
+	}
+	setting, err := s.existingAlmSetting(key)
+	if err != nil {
+		return nil, err
+	}
+	p, err := s.existingProject(projectKey)
+	if err != nil {
+		return nil, err
+	}
+	if setting.Alm != alm {
+		return nil, badRequest("DevOps Platform Setting '%s' is not a %s setting", key, alm)
+	}
+
+	binding := &almBinding{almSetting: setting.Key, monorepo: monorepo}
+	switch alm {
+	case "azure":
+		if binding.slug, err = r.required("projectName"); err != nil {
+			return nil, err
+		}
+		if binding.repository, err = r.required("repositoryName"); err != nil {
+			return nil, err
+		}
+	case "bitbucket":
+		if binding.repository, err = r.required("repository"); err != nil {
+			return nil, err
+		}
+		if binding.slug, err = r.required("slug"); err != nil {
+			return nil, err
+		}
+	case "github":
+		if binding.repository, err = r.required("repository"); err != nil {
+			return nil, err
+		}
+		if binding.summaryCommentEnabled, err = r.boolean("summaryCommentEnabled", true); err != nil {
+			return nil, err
+		}
+	default:
+		if binding.repository, err = r.required("repository"); err != nil {
+			return nil, err
+		}
+	}
+	s.almBindings[p.Key] = binding
+	return nil, nil
+}
+
+func (s *Server) getAlmBinding(r *request) (interface{}, error) {
+	projectKey, err := r.required("project")
+	if err != nil {
+		return nil, err
+	}
+	p, err := s.existingProject(projectKey)
+	if err != nil {
+		return nil, err
+	}
+	binding, ok := s.almBindings[p.Key]
+	if !ok {
+		return nil, notFound("Project '%s' is not bound to any DevOps Platform", p.Key)
+	}
+
+	setting := s.findAlmSetting(binding.almSetting)
+	response := map[string]interface{}{
+		"key":        setting.Key,
+		"alm":        setting.Alm,
+		"url":        setting.URL,
+		"repository": binding.repository,
+		"monorepo":   binding.monorepo,
+	}
+	if binding.slug != "" {
+		response["slug"] = binding.slug
+	}
+	if setting.Alm == "github" {
+		response["summaryCommentEnabled"] = binding.summaryCommentEnabled
+	}
+	return response, nil
+}
+
+func (s *Server) deleteAlmBinding(r *request) (interface{}, error) {
+	projectKey, err := r.required("project")
+	if err != nil {
+		return nil, err
+	}
+	p, err := s.existingProject(projectKey)
+	if err != nil {
+		return nil, err
+	}
+	delete(s.almBindings, p.Key)
+	return nil, nil
+}
+
+// validate stands for SonarQube reaching the DevOps platform. The fake can not reach hosts of the reserved .invalid
+// top level domain, every other setting is valid.
+func (a *almSetting) validate() error {
+	u, err := url.Parse(a.URL)
+	if err == nil && strings.HasSuffix(u.Hostname(), ".invalid") {
+		return badRequest("Could not reach %s, please check the URL and the credentials of the DevOps Platform Setting '%s'", a.URL, a.Key)
+	}
+	return nil
+}
+
+func (s *Server) validateAlmSetting(r *request) (interface{}, error) {
+	key, err := r.required("key")
+	if err != nil {
+		return nil, err
+	}
+	setting, err := s.existingAlmSetting(key)
+	if err != nil {
+		return nil, err
+	}
+	return nil, setting.validate()
+}
+
+func (s *Server) validateAlmBinding(r *request) (interface{}, error) {
+	projectKey, err := r.required("project")
+	if err != nil {
+		return nil, err
+	}
+	p, err := s.existingProject(projectKey)
+	if err != nil {
+		return nil, err
+	}
+	binding, ok := s.almBindings[p.Key]
+	if !ok {
+		return nil, notFound("Project '%s' is not bound to any DevOps Platform", p.Key)
+	}
+	return nil, s.findAlmSetting(binding.almSetting).validate()
+}
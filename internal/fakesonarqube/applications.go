@@ -0,0 +1,358 @@
+package fakesonarqube
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// applicationMainBranch is the name of the main branch of every application
+const applicationMainBranch = "main"
+
+// application is an application of the Developer, Enterprise and Data Center editions. Its main branch aggregates the
+// main branches of its projects, its other branches select a branch of some of its projects.
+type application struct {
+	*project
+
+	projects []string
+	branches []*applicationBranch
+}
+
+// applicationBranch maps the keys of the projects of an application branch to their branch
+type applicationBranch struct {
+	Name     string
+	projects map[string]string
+}
+
+func (s *Server) registerApplications() {
+	s.handle(http.MethodPost, "api/applications/create", s.createApplication)
+	s.handle(http.MethodGet, "api/applications/show", s.showApplication)
+	s.handle(http.MethodPost, "api/applications/update", s.updateApplication)
+	s.handle(http.MethodPost, "api/applications/delete", s.deleteApplication)
+	s.handle(http.MethodPost, "api/applications/set_tags", s.setApplicationTags)
+	s.handle(http.MethodPost, "api/applications/add_project", s.addApplicationProject)
+	s.handle(http.MethodPost, "api/applications/remove_project", s.removeApplicationProject)
+	s.handle(http.MethodPost, "api/applications/create_branch", s.createApplicationBranch)
+	s.handle(http.MethodPost, "api/applications/update_branch", s.updateApplicationBranch)
+	s.handle(http.MethodPost, "api/applications/delete_branch", s.deleteApplicationBranch)
+}
+
+// hasApplications reports whether the edition includes applications
+func (s *Server) hasApplications() bool {
+	if s.options.Organization != "" {
+		return false
+	}
+	edition := strings.ToLower(strings.ReplaceAll(s.options.Edition, " ", ""))
+	return edition == "developer" || edition == "enterprise" || edition == "datacenter"
+}
+
+func (s *Server) existingApplication(r *request) (*application, error) {
+	key, err := r.required("application")
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range s.applications {
+		if a.Key == key {
+			return a, nil
+		}
+	}
+	return nil, notFound("Application '%s' not found", key)
+}
+
+func (a *application) branch(name string) *applicationBranch {
+	for _, b := range a.branches {
+		if b.Name == name {
+			return b
+		}
+	}
+	return nil
+}
+
+// applicationResponse returns an application as shown for one of its branches, or for its main branch when b is nil
+func (s *Server) applicationResponse(a *application, b *applicationBranch) map[string]interface{} {
+	tags := a.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	response := map[string]interface{}{
+		"key":        a.Key,
+		"name":       a.Name,
+		"visibility": a.Visibility,
+		"tags":       tags,
+		"branch":     applicationMainBranch,
+		"isMain":     b == nil,
+	}
+	if a.Description != "" {
+		response["description"] = a.Description
+	}
+
+	projects := []map[string]interface{}{}
+	for _, key := range a.projects {
+		p, _ := s.existingProject(key)
+		project := map[string]interface{}{"key": p.Key, "name": p.Name, "enabled": true, "selected": true, "branch": p.branches[0].Name, "isMain": true}
+		if b != nil {
+			branch, selected := b.projects[key]
+			project["selected"], project["branch"], project["isMain"] = selected, branch, selected && branch == p.branches[0].Name
+			if !selected {
+				delete(project, "branch")
+			}
+		}
+		projects = append(projects, project)
+	}
+	response["projects"] = projects
+
+	if b == nil {
+		branches := []map[string]interface{}{{"name": applicationMainBranch, "isMain": true}}
+		for _, branch := range a.branches {
+			branches = append(branches, map[string]interface{}{"name": branch.Name, "isMain": false})
+		}
+		response["branches"] = branches
+	} else {
+		response["branch"] = b.Name
+	}
+	return response
+}
+
+func (s *Server) createApplication(r *request) (interface{}, error) {
+	name, err := r.required("name")
+	if err != nil {
+		return nil, err
+	}
+	visibility, err := r.oneOf("visibility", "public", "private", "public")
+	if err != nil {
+		return nil, err
+	}
+	key := r.param("key")
+	if key == "" {
+		key = name
+	}
+	if !projectKeyPattern.MatchString(key) {
+		return nil, badRequest("Malformed key for Application: '%s'. Allowed characters are alphanumeric, '-', '_', '.' and ':', with at least one non-digit.", key)
+	}
+	if _, err := s.component(key); err == nil {
+		return nil, badRequest("Could not create Application with key: \"%s\". A similar key already exists: \"%s\"", key, key)
+	}
+
+	a := &application{
+		project: &project{Key: key, Name: name, Description: r.param("description"), Qualifier: "APP", Visibility: visibility, CreatedAt: now()},
+	}
+	s.applications = append(s.applications, a)
+	return map[string]interface{}{"application": s.applicationResponse(a, nil)}, nil
+}
+
+func (s *Server) showApplication(r *request) (interface{}, error) {
+	a, err := s.existingApplication(r)
+	if err != nil {
+		return nil, err
+	}
+	var b *applicationBranch
+	if name := r.param("branch"); name != "" && name != applicationMainBranch {
+		if b = a.branch(name); b == nil {
+			return nil, notFound("Branch '%s' not found for application '%s'", name, a.Key)
+		}
+	}
+	return map[string]interface{}{"application": s.applicationResponse(a, b)}, nil
+}
+
+func (s *Server) updateApplication(r *request) (interface{}, error) {
+	a, err := s.existingApplication(r)
+	if err != nil {
+		return nil, err
+	}
+	name, err := r.required("name")
+	if err != nil {
+		return nil, err
+	}
+	a.Name, a.Description = name, r.param("description")
+	return nil, nil
+}
+
+func (s *Server) deleteApplication(r *request) (interface{}, error) {
+	a, err := s.existingApplication(r)
+	if err != nil {
+		return nil, err
+	}
+	for i := range s.applications {
+		if s.applications[i] == a {
+			s.applications = append(s.applications[:i], s.applications[i+1:]...)
+			break
+		}
+	}
+	s.renameComponent(a.Key, "")
+	return nil, nil
+}
+
+func (s *Server) setApplicationTags(r *request) (interface{}, error) {
+	a, err := s.existingApplication(r)
+	if err != nil {
+		return nil, err
+	}
+	if !r.has("tags") {
+		return nil, badRequest("The 'tags' parameter is missing")
+	}
+
+	tags := []string{}
+	seen := map[string]bool{}
+	for _, tag := range r.commaSeparated("tags") {
+		if !tagPattern.MatchString(tag) {
+			return nil, badRequest("Tag '%s' is invalid. Tags accept only the characters: a-z, 0-9, '+', '-', '#', '.'", tag)
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	sort.Strings(tags)
+	a.Tags = tags
+	return nil, nil
+}
+
+func (s *Server) addApplicationProject(r *request) (interface{}, error) {
+	a, err := s.existingApplication(r)
+	if err != nil {
+		return nil, err
+	}
+	key, err := r.required("project")
+	if err != nil {
+		return nil, err
+	}
+	p, err := s.existingProject(key)
+	if err != nil {
+		return nil, err
+	}
+	if contains(a.projects, p.Key) {
+		return nil, badRequest("Project '%s' is already part of application '%s'", p.Key, a.Key)
+	}
+	a.projects = append(a.projects, p.Key)
+	return nil, nil
+}
+
+func (s *Server) removeApplicationProject(r *request) (interface{}, error) {
+	a, err := s.existingApplication(r)
+	if err != nil {
+		return nil, err
+	}
+	key, err := r.required("project")
+	if err != nil {
+		return nil, err
+	}
+	if !contains(a.projects, key) {
+		return nil, notFound("Project '%s' is not part of application '%s'", key, a.Key)
+	}
+	a.removeProject(key)
+	return nil, nil
+}
+
+// removeProject removes a project from the application and from its branches
+func (a *application) removeProject(key string) {
+	projects := a.projects[:0]
+	for _, project := range a.projects {
+		if project != key {
+			projects = append(projects, project)
+		}
+	}
+	a.projects = projects
+	for _, b := range a.branches {
+		delete(b.projects, key)
+	}
+}
+
+// applicationBranchProjects validates the parallel project and projectBranch parameters of an application branch
+func (s *Server) applicationBranchProjects(r *request, a *application) (map[string]string, error) {
+	projects, branches := r.params("project"), r.params("projectBranch")
+	if len(projects) == 0 {
+		return nil, badRequest("The 'project' parameter is missing")
+	}
+	if len(projects) != len(branches) {
+		return nil, badRequest("The number of 'project' and 'projectBranch' parameters must be the same")
+	}
+
+	result := map[string]string{}
+	for i, key := range projects {
+		p, err := s.existingProject(key)
+		if err != nil {
+			return nil, err
+		}
+		if !contains(a.projects, p.Key) {
+			return nil, badRequest("Project '%s' is not part of application '%s'", p.Key, a.Key)
+		}
+		if _, ok := result[p.Key]; ok {
+			return nil, badRequest("Project '%s' is selected more than once", p.Key)
+		}
+		if p.branch(branches[i]) == nil {
+			return nil, notFound("Branch '%s' not found for project '%s'", branches[i], p.Key)
+		}
+		result[p.Key] = branches[i]
+	}
+	return result, nil
+}
+
+func (s *Server) createApplicationBranch(r *request) (interface{}, error) {
+	a, err := s.existingApplication(r)
+	if err != nil {
+		return nil, err
+	}
+	name, err := r.required("branch")
+	if err != nil {
+		return nil, err
+	}
+	if name == applicationMainBranch || a.branch(name) != nil {
+		return nil, badRequest("A branch with name '%s' already exists in application '%s'", name, a.Key)
+	}
+	projects, err := s.applicationBranchProjects(r, a)
+	if err != nil {
+		return nil, err
+	}
+	a.branches = append(a.branches, &applicationBranch{Name: name, projects: projects})
+	return nil, nil
+}
+
+func (s *Server) updateApplicationBranch(r *request) (interface{}, error) {
+	a, err := s.existingApplication(r)
+	if err != nil {
+		return nil, err
+	}
+	branch, err := r.required("branch")
+	if err != nil {
+		return nil, err
+	}
+	name, err := r.required("name")
+	if err != nil {
+		return nil, err
+	}
+	b := a.branch(branch)
+	if b == nil {
+		return nil, notFound("Branch '%s' not found for application '%s'", branch, a.Key)
+	}
+	if name != branch && (name == applicationMainBranch || a.branch(name) != nil) {
+		return nil, badRequest("A branch with name '%s' already exists in application '%s'", name, a.Key)
+	}
+	projects, err := s.applicationBranchProjects(r, a)
+	if err != nil {
+		return nil, err
+	}
+	b.Name, b.projects = name, projects
+	return nil, nil
+}
+
+func (s *Server) deleteApplicationBranch(r *request) (interface{}, error) {
+	a, err := s.existingApplication(r)
+	if err != nil {
+		return nil, err
+	}
+	branch, err := r.required("branch")
+	if err != nil {
+		return nil, err
+	}
+	b := a.branch(branch)
+	if b == nil {
+		return nil, notFound("Branch '%s' not found for application '%s'", branch, a.Key)
+	}
+	for i := range a.branches {
+		if a.branches[i] == b {
+			a.branches = append(a.branches[:i], a.branches[i+1:]...)
+			break
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,134 @@
+package fakesonarqube
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// defaultBranchesToKeepWhenInactive is the default value of sonar.dbcleaner.branchesToKeepWhenInactive
+var defaultBranchesToKeepWhenInactive = []string{"main", "master", "develop", "trunk", "release-.*", "branch-.*"}
+
+// ceTask is a background task of the compute engine. The fake runs a task while it is polled: the first api/ce/task
+// reports it IN_PROGRESS, the following ones report it done.
+type ceTask struct {
+	ID           string
+	Type         string
+	ComponentKey string
+	Status       string
+	SubmittedAt  string
+	ExecutedAt   string
+	ErrorMessage string
+
+	// run completes the task, it returns the error message of a failed task
+	run func() string
+}
+
+func (t *ceTask) response() map[string]interface{} {
+	response := map[string]interface{}{
+		"id":           t.ID,
+		"type":         t.Type,
+		"componentKey": t.ComponentKey,
+		"status":       t.Status,
+		"submittedAt":  t.SubmittedAt,
+	}
+	if t.ExecutedAt != "" {
+		response["executedAt"] = t.ExecutedAt
+	}
+	if t.ErrorMessage != "" {
+		response["errorMessage"] = t.ErrorMessage
+	}
+	return response
+}
+
+func (s *Server) registerCE() {
+	s.handle(http.MethodPost, "api/ce/submit", s.submitAnalysis)
+	s.handle(http.MethodGet, "api/ce/task", s.showCETask)
+}
+
+// execute runs a task
+func (t *ceTask) execute() {
+	t.Status, t.ExecutedAt = "SUCCESS", now()
+	if t.ErrorMessage = t.run(); t.ErrorMessage != "" {
+		t.Status = "FAILED"
+	}
+}
+
+// submitCETask queues a task, run is called once the task is executed
+func (s *Server) submitCETask(taskType string, componentKey string, run func() string) *ceTask {
+	task := &ceTask{ID: s.newID(), Type: taskType, ComponentKey: componentKey, Status: "PENDING", SubmittedAt: now(), run: run}
+	s.ceTasks = append(s.ceTasks, task)
+	return task
+}
+
+func (s *Server) showCETask(r *request) (interface{}, error) {
+	id, err := r.required("id")
+	if err != nil {
+		return nil, err
+	}
+	var task *ceTask
+	for _, t := range s.ceTasks {
+		if t.ID == id {
+			task = t
+		}
+	}
+	if task == nil {
+		return nil, notFound("No activity found for task '%s'", id)
+	}
+
+	switch task.Status {
+	case "PENDING":
+		task.Status = "IN_PROGRESS"
+	case "IN_PROGRESS":
+		task.execute()
+	}
+	return map[string]interface{}{"task": task.response()}, nil
+}
+
+// submitAnalysis stands for the upload of an analysis report by a scanner. The fake does not require the report: the
+// analysis of the branch set with the "branch=<name>" characteristic, or of the main branch, runs at once. A new branch
+// is kept when inactive if its name matches sonar.dbcleaner.branchesToKeepWhenInactive, like on SonarQube.
+func (s *Server) submitAnalysis(r *request) (interface{}, error) {
+	key, err := r.required("projectKey")
+	if err != nil {
+		return nil, err
+	}
+	p, err := s.existingProject(key)
+	if err != nil {
+		return nil, err
+	}
+	name := p.branches[0].Name
+	for _, characteristic := range r.params("characteristic") {
+		if value, ok := strings.CutPrefix(characteristic, "branch="); ok {
+			name = value
+		}
+	}
+
+	task := s.submitCETask("REPORT", p.Key, func() string {
+		b := p.branch(name)
+		if b == nil {
+			b = &branch{Name: name, ExcludedFromPurge: s.keepWhenInactive(p.Key, name)}
+			p.branches = append(p.branches, b)
+		}
+		b.AnalysisDate = now()
+		return ""
+	})
+	task.execute()
+	return map[string]interface{}{"taskId": task.ID, "projectId": p.Key}, nil
+}
+
+// keepWhenInactive reports whether a new branch is excluded from the automatic deletion of inactive branches
+func (s *Server) keepWhenInactive(project string, name string) bool {
+	patterns := defaultBranchesToKeepWhenInactive
+	if setting := s.findSetting("sonar.dbcleaner.branchesToKeepWhenInactive", project); setting != nil {
+		patterns = setting.Values
+	} else if setting := s.findSetting("sonar.dbcleaner.branchesToKeepWhenInactive", ""); setting != nil {
+		patterns = setting.Values
+	}
+	for _, pattern := range patterns {
+		if matched, err := regexp.MatchString("^(?:"+pattern+")$", name); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
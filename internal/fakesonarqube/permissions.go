@@ -0,0 +1,411 @@
+package fakesonarqube
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+)
+
+var (
+	globalPermissions  = []string{"admin", "gateadmin", "profileadmin", "provisioning", "scan", "applicationcreator", "portfoliocreator"}
+	projectPermissions = []string{"admin", "codeviewer", "issueadmin", "securityhotspotadmin", "scan", "user"}
+)
+
+// permissionScope identifies where permissions are granted: globally when both fields are empty, on a project or
+// in a permission template
+type permissionScope struct {
+	project  string
+	template string
+}
+
+// permissionHolders maps the logins of users and the names of groups to the permissions they are granted
+type permissionHolders struct {
+	users  map[string]map[string]bool
+	groups map[string]map[string]bool
+}
+
+type permissionTemplate struct {
+	ID                string `json:"id"`
+	Name              string `json:"name"`
+	Description       string `json:"description,omitempty"`
+	ProjectKeyPattern string `json:"projectKeyPattern,omitempty"`
+	CreatedAt         string `json:"createdAt"`
+	UpdatedAt         string `json:"updatedAt"`
+}
+
+func (s *Server) registerPermissions() {
+	s.grant(permissionScope{}, true, s.options.Login, "admin")
+	for _, permission := range globalPermissions {
+		s.grant(permissionScope{}, false, "sonar-administrators", permission)
+	}
+
+	defaultTemplate := &permissionTemplate{
+		ID:          "default_template",
+		Name:        "Default template",
+		Description: "This permission template will be used as default when no other permission configuration is available",
+		CreatedAt:   now(),
+		UpdatedAt:   now(),
+	}
+	s.permissionTemplates = []*permissionTemplate{defaultTemplate}
+	s.defaultTemplate = defaultTemplate.ID
+	scope := permissionScope{template: defaultTemplate.ID}
+	s.grant(scope, false, "sonar-administrators", "admin")
+	for _, permission := range []string{"codeviewer", "user"} {
+		s.grant(scope, false, "sonar-users", permission)
+	}
+
+	s.handle(http.MethodPost, "api/permissions/add_user", s.addPermission(true))
+	s.handle(http.MethodPost, "api/permissions/add_group", s.addPermission(false))
+	s.handle(http.MethodPost, "api/permissions/remove_user", s.removePermission(true))
+	s.handle(http.MethodPost, "api/permissions/remove_group", s.removePermission(false))
+	s.handle(http.MethodPost, "api/permissions/add_user_to_template", s.addPermission(true))
+	s.handle(http.MethodPost, "api/permissions/add_group_to_template", s.addPermission(false))
+	s.handle(http.MethodPost, "api/permissions/remove_user_from_template", s.removePermission(true))
+	s.handle(http.MethodPost, "api/permissions/remove_group_from_template", s.removePermission(false))
+	s.handle(http.MethodGet, "api/permissions/users", s.permissionUsers)
+	s.handle(http.MethodGet, "api/permissions/groups", s.permissionGroups)
+	s.handle(http.MethodGet, "api/permissions/template_users", s.permissionUsers)
+	s.handle(http.MethodGet, "api/permissions/template_groups", s.permissionGroups)
+	s.handle(http.MethodPost, "api/permissions/create_template", s.createPermissionTemplate)
+	s.handle(http.MethodGet, "api/permissions/search_templates", s.searchPermissionTemplates)
+	s.handle(http.MethodPost, "api/permissions/update_template", s.updatePermissionTemplate)
+	s.handle(http.MethodPost, "api/permissions/delete_template", s.deletePermissionTemplate)
+	s.handle(http.MethodPost, "api/permissions/set_default_template", s.setDefaultPermissionTemplate)
+}
+
+func (s *Server) grant(scope permissionScope, isUser bool, name string, permission string) {
+	holders, ok := s.permissions[scope]
+	if !ok {
+		holders = permissionHolders{users: map[string]map[string]bool{}, groups: map[string]map[string]bool{}}
+		s.permissions[scope] = holders
+	}
+	granted := holders.groups
+	if isUser {
+		granted = holders.users
+	}
+	if granted[name] == nil {
+		granted[name] = map[string]bool{}
+	}
+	granted[name][permission] = true
+}
+
+func (s *Server) revoke(scope permissionScope, isUser bool, name string, permission string) {
+	holders := s.permissions[scope]
+	granted := holders.groups
+	if isUser {
+		granted = holders.users
+	}
+	delete(granted[name], permission)
+	if len(granted[name]) == 0 {
+		delete(granted, name)
+	}
+}
+
+// granted returns the sorted permissions of a user or a group in scope
+func (s *Server) granted(scope permissionScope, isUser bool, name string) []string {
+	holders := s.permissions[scope]
+	granted := holders.groups
+	if isUser {
+		granted = holders.users
+	}
+	permissions := []string{}
+	for permission := range granted[name] {
+		permissions = append(permissions, permission)
+	}
+	sort.Strings(permissions)
+	return permissions
+}
+
+// renameGroupPermissions moves the permissions of a group to its new name, or drops them when newName is empty
+func (s *Server) renameGroupPermissions(name string, newName string) {
+	for _, holders := range s.permissions {
+		if permissions, ok := holders.groups[name]; ok {
+			delete(holders.groups, name)
+			if newName != "" {
+				holders.groups[newName] = permissions
+			}
+		}
+	}
+}
+
+// permissionScope returns the scope of a request from its projectKey, templateId and templateName parameters
+func (s *Server) permissionScope(r *request) (permissionScope, error) {
+	isTemplate := strings.Contains(r.URL.Path, "template")
+	if !isTemplate {
+		if projectKey := r.param("projectKey"); projectKey != "" {
+			c, err := s.component(projectKey)
+			if err != nil {
+				return permissionScope{}, err
+			}
+			return permissionScope{project: c.Key}, nil
+		}
+		return permissionScope{}, nil
+	}
+
+	t, err := s.permissionTemplate(r)
+	if err != nil {
+		return permissionScope{}, err
+	}
+	return permissionScope{template: t.ID}, nil
+}
+
+func (s *Server) permissionTemplate(r *request) (*permissionTemplate, error) {
+	id, name := r.param("templateId"), r.param("templateName")
+	if id == "" && name == "" {
+		return nil, badRequest("Template name or template id must be provided, not both.")
+	}
+	for _, t := range s.permissionTemplates {
+		if (id != "" && t.ID == id) || (id == "" && strings.EqualFold(t.Name, name)) {
+			return t, nil
+		}
+	}
+	if id != "" {
+		return nil, notFound("Permission template with id '%s' is not found", id)
+	}
+	return nil, notFound("Permission template with name '%s' is not found (case insensitive search)", name)
+}
+
+// permissionHolder returns the login of the user or the name of the group a permission is granted to
+func (s *Server) permissionHolder(r *request, isUser bool) (string, error) {
+	if isUser {
+		login, err := r.required("login")
+		if err != nil {
+			return "", err
+		}
+		if _, err := s.activeUser(login); err != nil {
+			return "", err
+		}
+		return login, nil
+	}
+
+	name, err := r.required("groupName")
+	if err != nil {
+		return "", err
+	}
+	if name == "Anyone" {
+		return name, nil
+	}
+	g, err := s.existingGroup(name)
+	if err != nil {
+		return "", err
+	}
+	return g.Name, nil
+}
+
+func (s *Server) validPermission(r *request, scope permissionScope) (string, error) {
+	permission, err := r.required("permission")
+	if err != nil {
+		return "", err
+	}
+	valid := globalPermissions
+	if scope != (permissionScope{}) {
+		valid = projectPermissions
+	}
+	for _, v := range valid {
+		if permission == v {
+			return permission, nil
+		}
+	}
+	return "", badRequest("Value of parameter 'permission' (%s) must be one of: [%s]", permission, strings.Join(valid, ", "))
+}
+
+func (s *Server) addPermission(isUser bool) func(r *request) (interface{}, error) {
+	return func(r *request) (interface{}, error) {
+		scope, err := s.permissionScope(r)
+		if err != nil {
+			return nil, err
+		}
+		permission, err := s.validPermission(r, scope)
+		if err != nil {
+			return nil, err
+		}
+		name, err := s.permissionHolder(r, isUser)
+		if err != nil {
+			return nil, err
+		}
+		s.grant(scope, isUser, name, permission)
+		return nil, nil
+	}
+}
+
+func (s *Server) removePermission(isUser bool) func(r *request) (interface{}, error) {
+	return func(r *request) (interface{}, error) {
+		scope, err := s.permissionScope(r)
+		if err != nil {
+			return nil, err
+		}
+		permission, err := s.validPermission(r, scope)
+		if err != nil {
+			return nil, err
+		}
+		name, err := s.permissionHolder(r, isUser)
+		if err != nil {
+			return nil, err
+		}
+		if scope == (permissionScope{}) && permission == "admin" && !isUser && name == "sonar-administrators" {
+			return nil, badRequest("Last group with permission 'admin'. Permission cannot be removed.")
+		}
+		s.revoke(scope, isUser, name, permission)
+		return nil, nil
+	}
+}
+
+// permissionUsers lists the permissions of users. Like SonarQube, only users having a permission are listed unless
+// a query is given.
+func (s *Server) permissionUsers(r *request) (interface{}, error) {
+	scope, err := s.permissionScope(r)
+	if err != nil {
+		return nil, err
+	}
+
+	users := []map[string]interface{}{}
+	for _, u := range s.users {
+		permissions := s.granted(scope, true, u.Login)
+		if !u.Active || (r.param("q") == "" && len(permissions) == 0) || !matches(r.param("q"), u.Login, u.Name, u.Email) {
+			continue
+		}
+		users = append(users, map[string]interface{}{"login": u.Login, "name": u.Name, "email": u.Email, "permissions": permissions})
+	}
+
+	page, p, err := paginate(r, users, 20)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"paging": p, "users": page}, nil
+}
+
+// permissionGroups lists the permissions of groups, including the Anyone group
+func (s *Server) permissionGroups(r *request) (interface{}, error) {
+	scope, err := s.permissionScope(r)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := []map[string]interface{}{}
+	anyone := &group{Name: "Anyone", Description: ""}
+	for _, g := range append([]*group{anyone}, s.groups...) {
+		permissions := s.granted(scope, false, g.Name)
+		if (r.param("q") == "" && len(permissions) == 0 && g != anyone) || !matches(r.param("q"), g.Name) {
+			continue
+		}
+		response := map[string]interface{}{"name": g.Name, "description": g.Description, "permissions": permissions}
+		if g.ID != "" {
+			response["id"] = g.ID
+		}
+		groups = append(groups, response)
+	}
+
+	page, p, err := paginate(r, groups, 20)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"paging": p, "groups": page}, nil
+}
+
+func (s *Server) findPermissionTemplate(name string) *permissionTemplate {
+	for _, t := range s.permissionTemplates {
+		if strings.EqualFold(t.Name, name) {
+			return t
+		}
+	}
+	return nil
+}
+
+func (s *Server) createPermissionTemplate(r *request) (interface{}, error) {
+	name, err := r.required("name")
+	if err != nil {
+		return nil, err
+	}
+	if s.findPermissionTemplate(name) != nil {
+		return nil, badRequest("A template with the name '%s' already exists (case insensitive).", name)
+	}
+
+	t := &permissionTemplate{
+		ID:                s.newID(),
+		Name:              name,
+		Description:       r.param("description"),
+		ProjectKeyPattern: r.param("projectKeyPattern"),
+		CreatedAt:         now(),
+		UpdatedAt:         now(),
+	}
+	s.permissionTemplates = append(s.permissionTemplates, t)
+	return map[string]interface{}{"permissionTemplate": t}, nil
+}
+
+func (s *Server) searchPermissionTemplates(r *request) (interface{}, error) {
+	templates := []*permissionTemplate{}
+	for _, t := range s.permissionTemplates {
+		if matches(r.param("q"), t.Name) {
+			templates = append(templates, t)
+		}
+	}
+
+	defaultTemplates := []map[string]string{{"templateId": s.defaultTemplate, "qualifier": "TRK"}}
+	if s.hasPortfolios() {
+		defaultTemplates = append(defaultTemplates, map[string]string{"templateId": s.defaultTemplate, "qualifier": "VW"})
+	}
+	return map[string]interface{}{
+		"paging":              paging{PageIndex: 1, PageSize: len(templates), Total: len(templates)},
+		"permissionTemplates": templates,
+		"defaultTemplates":    defaultTemplates,
+	}, nil
+}
+
+func (s *Server) updatePermissionTemplate(r *request) (interface{}, error) {
+	id, err := r.required("id")
+	if err != nil {
+		return nil, err
+	}
+	var t *permissionTemplate
+	for _, candidate := range s.permissionTemplates {
+		if candidate.ID == id {
+			t = candidate
+		}
+	}
+	if t == nil {
+		return nil, notFound("Permission template with id '%s' is not found", id)
+	}
+
+	if name := r.param("name"); name != "" && !strings.EqualFold(name, t.Name) {
+		if s.findPermissionTemplate(name) != nil {
+			return nil, badRequest("A template with the name '%s' already exists (case insensitive).", name)
+		}
+		t.Name = name
+	}
+	if r.has("description") {
+		t.Description = r.param("description")
+	}
+	if r.has("projectKeyPattern") {
+		t.ProjectKeyPattern = r.param("projectKeyPattern")
+	}
+	t.UpdatedAt = now()
+	return map[string]interface{}{"permissionTemplate": t}, nil
+}
+
+func (s *Server) deletePermissionTemplate(r *request) (interface{}, error) {
+	t, err := s.permissionTemplate(r)
+	if err != nil {
+		return nil, err
+	}
+	if t.ID == s.defaultTemplate {
+		return nil, badRequest("It is not possible to delete the default permission template for projects")
+	}
+
+	for i := range s.permissionTemplates {
+		if s.permissionTemplates[i] == t {
+			s.permissionTemplates = append(s.permissionTemplates[:i], s.permissionTemplates[i+1:]...)
+			break
+		}
+	}
+	delete(s.permissions, permissionScope{template: t.ID})
+	return nil, nil
+}
+
+func (s *Server) setDefaultPermissionTemplate(r *request) (interface{}, error) {
+	t, err := s.permissionTemplate(r)
+	if err != nil {
+		return nil, err
+	}
+	s.defaultTemplate = t.ID
+	return nil, nil
+}
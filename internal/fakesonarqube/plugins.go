@@ -0,0 +1,74 @@
+package fakesonarqube
+
+import (
+	"net/http"
+	"sort"
+)
+
+// plugin is an installed plugin. Unlike SonarQube, the fake does not need a restart to complete an installation.
+type plugin struct {
+	Key         string `json:"key"`
+	Name        string `json:"name"`
+	Version     string `json:"version"`
+	UpdatedAt   int64  `json:"updatedAt"`
+	Filename    string `json:"filename"`
+	Description string `json:"description"`
+}
+
+func (s *Server) registerPlugins() {
+	s.availablePluginNames = map[string]string{
+		"authaad":       "Azure Active Directory (AAD) Authentication Plug-in for SonarQube",
+		"checkstyle":    "Checkstyle",
+		"communityrust": "Rust language analyzer",
+		"findbugs":      "SpotBugs",
+		"ldap":          "LDAP",
+		"pmd":           "PMD",
+		"shellcheck":    "ShellCheck Analyzer",
+	}
+
+	s.handle(http.MethodPost, "api/plugins/install", s.installPlugin)
+	s.handle(http.MethodGet, "api/plugins/installed", s.installedPlugins)
+	s.handle(http.MethodPost, "api/plugins/uninstall", s.uninstallPlugin)
+}
+
+func (s *Server) installPlugin(r *request) (interface{}, error) {
+	key, err := r.required("key")
+	if err != nil {
+		return nil, err
+	}
+	name, ok := s.availablePluginNames[key]
+	if !ok {
+		return nil, badRequest("No plugin with key '%s' or plugin '%s' is already installed in latest version", key, key)
+	}
+	for _, p := range s.plugins {
+		if p.Key == key {
+			return nil, badRequest("No plugin with key '%s' or plugin '%s' is already installed in latest version", key, key)
+		}
+	}
+
+	s.plugins = append(s.plugins, &plugin{Key: key, Name: name, Version: "1.0", Filename: "sonar-" + key + "-plugin-1.0.jar"})
+	sort.Slice(s.plugins, func(i, j int) bool { return s.plugins[i].Key < s.plugins[j].Key })
+	return nil, nil
+}
+
+func (s *Server) installedPlugins(r *request) (interface{}, error) {
+	plugins := s.plugins
+	if plugins == nil {
+		plugins = []*plugin{}
+	}
+	return map[string]interface{}{"plugins": plugins}, nil
+}
+
+func (s *Server) uninstallPlugin(r *request) (interface{}, error) {
+	key, err := r.required("key")
+	if err != nil {
+		return nil, err
+	}
+	for i, p := range s.plugins {
+		if p.Key == key {
+			s.plugins = append(s.plugins[:i], s.plugins[i+1:]...)
+			return nil, nil
+		}
+	}
+	return nil, badRequest("Plugin [%s] is not installed", key)
+}
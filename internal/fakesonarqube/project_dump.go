@@ -0,0 +1,31 @@
+package fakesonarqube
+
+import (
+	"net/http"
+)
+
+// registerProjectDump registers api/project_dump, which is only available in the Enterprise and Datacenter editions
+func (s *Server) registerProjectDump() {
+	s.handle(http.MethodPost, "api/project_dump/export", s.exportProjectDump)
+}
+
+// exportProjectDump submits the export of a project. The fake does not write the dump, the task only fails when the
+// project is deleted before it runs.
+func (s *Server) exportProjectDump(r *request) (interface{}, error) {
+	key, err := r.required("key")
+	if err != nil {
+		return nil, err
+	}
+	p, err := s.existingProject(key)
+	if err != nil {
+		return nil, err
+	}
+
+	task := s.submitCETask("PROJECT_EXPORT", p.Key, func() string {
+		if _, err := s.existingProject(key); err != nil {
+			return "Project '" + key + "' was deleted before it was exported"
+		}
+		return ""
+	})
+	return map[string]interface{}{"taskId": task.ID, "projectKey": p.Key, "projectName": p.Name}, nil
+}
@@ -0,0 +1,93 @@
+package fakesonarqube
+
+import (
+	"net/http"
+	"strings"
+)
+
+// providedLinkTypes are the types of the links named after them, other links are custom links
+var providedLinkTypes = []string{"homepage", "ci", "issue", "scm"}
+
+// projectLink is a link shown on the page of a project
+type projectLink struct {
+	ID   string
+	Name string
+	Type string
+	URL  string
+}
+
+func (l *projectLink) response() map[string]interface{} {
+	return map[string]interface{}{"id": l.ID, "name": l.Name, "type": l.Type, "url": l.URL}
+}
+
+func (s *Server) registerProjectLinks() {
+	s.handle(http.MethodPost, "api/project_links/create", s.createProjectLink)
+	s.handle(http.MethodPost, "api/project_links/delete", s.deleteProjectLink)
+	s.handle(http.MethodGet, "api/project_links/search", s.searchProjectLinks)
+}
+
+func (s *Server) createProjectLink(r *request) (interface{}, error) {
+	key, err := r.required("projectKey")
+	if err != nil {
+		return nil, err
+	}
+	name, err := r.required("name")
+	if err != nil {
+		return nil, err
+	}
+	url, err := r.required("url")
+	if err != nil {
+		return nil, err
+	}
+	if len(name) > 128 {
+		return nil, badRequest("'name' length (%d) is longer than the maximum authorized (128)", len(name))
+	}
+	if len(url) > 2048 {
+		return nil, badRequest("'url' length (%d) is longer than the maximum authorized (2048)", len(url))
+	}
+	p, err := s.existingProject(key)
+	if err != nil {
+		return nil, err
+	}
+
+	linkType := "custom"
+	if contains(providedLinkTypes, strings.ToLower(name)) {
+		linkType = strings.ToLower(name)
+	}
+	link := &projectLink{ID: s.newID(), Name: name, Type: linkType, URL: url}
+	p.links = append(p.links, link)
+	return map[string]interface{}{"link": link.response()}, nil
+}
+
+func (s *Server) deleteProjectLink(r *request) (interface{}, error) {
+	id, err := r.required("id")
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range s.projects {
+		for i, link := range p.links {
+			if link.ID == id {
+				p.links = append(p.links[:i], p.links[i+1:]...)
+				return nil, nil
+			}
+		}
+	}
+	return nil, notFound("Link with id '%s' not found", id)
+}
+
+func (s *Server) searchProjectLinks(r *request) (interface{}, error) {
+	key, err := r.required("projectKey")
+	if err != nil {
+		return nil, err
+	}
+	p, err := s.existingProject(key)
+	if err != nil {
+		return nil, err
+	}
+
+	links := []map[string]interface{}{}
+	for _, link := range p.links {
+		links = append(links, link.response())
+	}
+	return map[string]interface{}{"links": links}, nil
+}
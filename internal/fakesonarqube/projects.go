@@ -0,0 +1,693 @@
+package fakesonarqube
+
+import (
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+var (
+	projectKeyPattern = regexp.MustCompile(`^[\w\-.:]*[a-zA-Z\-_.:][\w\-.:]*$`)
+	tagPattern        = regexp.MustCompile(`^[a-z0-9+#\-.]+$`)
+)
+
+// project is a component: a project, or the root component of a portfolio
+type project struct {
+	Key         string
+	Name        string
+	Description string
+	Qualifier   string
+	Visibility  string
+	Tags        []string
+	CreatedAt   string
+
+	// branches starts with the main branch
+	branches []*branch
+	links    []*projectLink
+}
+
+// branch is a branch of a project. Only the main branch exists until an analysis creates another one.
+type branch struct {
+	Name              string
+	ExcludedFromPurge bool
+	AnalysisDate      string
+}
+
+// branch returns the branch of the project with the given name, or nil
+func (p *project) branch(name string) *branch {
+	for _, b := range p.branches {
+		if b.Name == name {
+			return b
+		}
+	}
+	return nil
+}
+
+func (p *project) response() map[string]interface{} {
+	tags := p.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	response := map[string]interface{}{
+		"key":        p.Key,
+		"name":       p.Name,
+		"qualifier":  p.Qualifier,
+		"visibility": p.Visibility,
+		"tags":       tags,
+	}
+	if p.Description != "" {
+		response["description"] = p.Description
+	}
+	return response
+}
+
+type newCodePeriod struct {
+	project string
+	branch  string
+	Type    string
+	Value   string
+}
+
+func (s *Server) registerProjects() {
+	s.handle(http.MethodPost, "api/projects/create", s.createProject)
+	s.handle(http.MethodPost, "api/projects/delete", s.deleteProject)
+	s.handle(http.MethodGet, "api/projects/search", s.searchProjects)
+	s.handle(http.MethodPost, "api/projects/update_key", s.updateProjectKey)
+	s.handle(http.MethodPost, "api/projects/update_visibility", s.updateProjectVisibility)
+	s.handle(http.MethodPost, "api/project_tags/set", s.setProjectTags)
+	s.handle(http.MethodGet, "api/project_branches/list", s.listBranches)
+	s.handle(http.MethodPost, "api/project_branches/rename", s.renameMainBranch)
+	s.handle(http.MethodPost, "api/project_branches/delete", s.deleteBranch)
+	s.handle(http.MethodPost, "api/project_branches/set_automatic_deletion_protection", s.setBranchDeletionProtection)
+	s.handle(http.MethodGet, "api/components/show", s.showComponent)
+}
+
+func (s *Server) registerNewCodePeriods() {
+	s.newCodePeriods = []*newCodePeriod{{Type: "PREVIOUS_VERSION"}}
+
+	s.handle(http.MethodPost, "api/new_code_periods/set", s.setNewCodePeriod)
+	s.handle(http.MethodGet, "api/new_code_periods/show", s.showNewCodePeriod)
+	s.handle(http.MethodPost, "api/new_code_periods/unset", s.unsetNewCodePeriod)
+}
+
+// component returns the project, portfolio or application with the given key
+func (s *Server) component(key string) (*project, error) {
+	for _, p := range s.projects {
+		if p.Key == key {
+			return p, nil
+		}
+	}
+	for _, p := range s.portfolios {
+		if p.Key == key {
+			return p.project, nil
+		}
+	}
+	for _, a := range s.applications {
+		if a.Key == key {
+			return a.project, nil
+		}
+	}
+	return nil, notFound("Component key '%s' not found", key)
+}
+
+// existingProject returns the project with the given key, portfolios are not projects
+func (s *Server) existingProject(key string) (*project, error) {
+	for _, p := range s.projects {
+		if p.Key == key {
+			return p, nil
+		}
+	}
+	return nil, notFound("Project '%s' not found", key)
+}
+
+func (s *Server) createProject(r *request) (interface{}, error) {
+	key, err := r.required("project")
+	if err != nil {
+		return nil, err
+	}
+	name, err := r.required("name")
+	if err != nil {
+		return nil, err
+	}
+	visibility, err := r.oneOf("visibility", "public", "private", "public")
+	if err != nil {
+		return nil, err
+	}
+	if !projectKeyPattern.MatchString(key) {
+		return nil, badRequest("Malformed key for Project: '%s'. Allowed characters are alphanumeric, '-', '_', '.' and ':', with at least one non-digit.", key)
+	}
+	if len(name) > 500 {
+		return nil, badRequest("'name' length (%d) is longer than the maximum authorized (500)", len(name))
+	}
+	if _, err := s.component(key); err == nil {
+		return nil, badRequest("Could not create Project with key: \"%s\". A similar key already exists: \"%s\"", key, key)
+	}
+
+	mainBranch := r.param("mainBranch")
+	if mainBranch == "" {
+		mainBranch = "main"
+	}
+	p := &project{Key: key, Name: name, Qualifier: "TRK", Visibility: visibility, CreatedAt: now(), branches: []*branch{{Name: mainBranch, ExcludedFromPurge: true}}}
+	s.projects = append(s.projects, p)
+	s.applyPermissionTemplate(p)
+
+	return map[string]interface{}{"project": p.response()}, nil
+}
+
+// applyPermissionTemplate grants the permissions of the default permission template, or of the first template whose
+// project key pattern matches, on a new project
+func (s *Server) applyPermissionTemplate(p *project) {
+	templateID := s.defaultTemplate
+	for _, t := range s.permissionTemplates {
+		if t.ProjectKeyPattern != "" {
+			if pattern, err := regexp.Compile("^(?:" + t.ProjectKeyPattern + ")$"); err == nil && pattern.MatchString(p.Key) {
+				templateID = t.ID
+				break
+			}
+		}
+	}
+
+	template := s.permissions[permissionScope{template: templateID}]
+	scope := permissionScope{project: p.Key}
+	for login, permissions := range template.users {
+		for permission := range permissions {
+			s.grant(scope, true, login, permission)
+		}
+	}
+	for name, permissions := range template.groups {
+		for permission := range permissions {
+			s.grant(scope, false, name, permission)
+		}
+	}
+}
+
+func (s *Server) deleteProject(r *request) (interface{}, error) {
+	key, err := r.required("project")
+	if err != nil {
+		return nil, err
+	}
+	p, err := s.existingProject(key)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range s.projects {
+		if s.projects[i] == p {
+			s.projects = append(s.projects[:i], s.projects[i+1:]...)
+			break
+		}
+	}
+	s.renameComponent(p.Key, "")
+	return nil, nil
+}
+
+// renameComponent updates the references to a component after its key changed, or drops them when newKey is empty
+func (s *Server) renameComponent(key string, newKey string) {
+	if holders, ok := s.permissions[permissionScope{project: key}]; ok {
+		delete(s.permissions, permissionScope{project: key})
+		if newKey != "" {
+			s.permissions[permissionScope{project: newKey}] = holders
+		}
+	}
+
+	settings := s.settings[:0]
+	for _, setting := range s.settings {
+		if setting.component == key {
+			if newKey == "" {
+				continue
+			}
+			setting.component = newKey
+		}
+		settings = append(settings, setting)
+	}
+	s.settings = settings
+
+	periods := s.newCodePeriods[:0]
+	for _, period := range s.newCodePeriods {
+		if period.project == key {
+			if newKey == "" {
+				continue
+			}
+			period.project = newKey
+		}
+		periods = append(periods, period)
+	}
+	s.newCodePeriods = periods
+
+	webhooks := s.webhooks[:0]
+	for _, w := range s.webhooks {
+		if w.project == key {
+			if newKey == "" {
+				continue
+			}
+			w.project = newKey
+		}
+		webhooks = append(webhooks, w)
+	}
+	s.webhooks = webhooks
+
+	tokens := s.tokens[:0]
+	for _, t := range s.tokens {
+		if t.Project != nil && t.Project.Key == key {
+			if newKey == "" {
+				continue
+			}
+			t.Project.Key = newKey
+		}
+		tokens = append(tokens, t)
+	}
+	s.tokens = tokens
+
+	for _, gate := range s.qualityGates {
+		if gate.projects[key] {
+			delete(gate.projects, key)
+			if newKey != "" {
+				gate.projects[newKey] = true
+			}
+		}
+	}
+	for _, profile := range s.qualityProfiles {
+		if profile.projects[key] {
+			delete(profile.projects, key)
+			if newKey != "" {
+				profile.projects[newKey] = true
+			}
+		}
+	}
+	if binding, ok := s.almBindings[key]; ok {
+		delete(s.almBindings, key)
+		if newKey != "" {
+			s.almBindings[newKey] = binding
+		}
+	}
+	for _, a := range s.applications {
+		if contains(a.projects, key) {
+			if newKey == "" {
+				a.removeProject(key)
+				continue
+			}
+			for i := range a.projects {
+				if a.projects[i] == key {
+					a.projects[i] = newKey
+				}
+			}
+			for _, b := range a.branches {
+				if branch, ok := b.projects[key]; ok {
+					delete(b.projects, key)
+					b.projects[newKey] = branch
+				}
+			}
+		}
+	}
+	for _, portfolio := range s.portfolios {
+		if branches, ok := portfolio.selectedProjects[key]; ok {
+			delete(portfolio.selectedProjects, key)
+			if newKey != "" {
+				portfolio.selectedProjects[newKey] = branches
+			}
+		}
+		subViews := portfolio.subViews[:0]
+		for _, child := range portfolio.subViews {
+			if child.key == key {
+				if newKey == "" {
+					continue
+				}
+				child.key = newKey
+			}
+			subViews = append(subViews, child)
+		}
+		portfolio.subViews = subViews
+	}
+}
+
+func (s *Server) searchProjects(r *request) (interface{}, error) {
+	keys := map[string]bool{}
+	for _, key := range r.commaSeparated("projects") {
+		keys[key] = true
+	}
+
+	components := []map[string]interface{}{}
+	for _, p := range s.projects {
+		if (len(keys) == 0 || keys[p.Key]) && matches(r.param("q"), p.Key, p.Name) {
+			components = append(components, p.response())
+		}
+	}
+
+	page, pg, err := paginate(r, components, 100)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"paging": pg, "components": page}, nil
+}
+
+func (s *Server) updateProjectKey(r *request) (interface{}, error) {
+	from, err := r.required("from")
+	if err != nil {
+		return nil, err
+	}
+	to, err := r.required("to")
+	if err != nil {
+		return nil, err
+	}
+	p, err := s.existingProject(from)
+	if err != nil {
+		return nil, err
+	}
+	if !projectKeyPattern.MatchString(to) {
+		return nil, badRequest("Malformed key for Project: '%s'. Allowed characters are alphanumeric, '-', '_', '.' and ':', with at least one non-digit.", to)
+	}
+	if _, err := s.component(to); err == nil {
+		return nil, badRequest("Impossible to update key: a component with key \"%s\" already exists.", to)
+	}
+
+	p.Key = to
+	s.renameComponent(from, to)
+	return nil, nil
+}
+
+func (s *Server) updateProjectVisibility(r *request) (interface{}, error) {
+	key, err := r.required("project")
+	if err != nil {
+		return nil, err
+	}
+	visibility, err := r.required("visibility")
+	if err != nil {
+		return nil, err
+	}
+	if visibility, err = r.oneOf("visibility", "", "private", "public"); err != nil {
+		return nil, err
+	}
+	p, err := s.component(key)
+	if err != nil {
+		return nil, err
+	}
+	p.Visibility = visibility
+	return nil, nil
+}
+
+func (s *Server) setProjectTags(r *request) (interface{}, error) {
+	key, err := r.required("project")
+	if err != nil {
+		return nil, err
+	}
+	if !r.has("tags") {
+		return nil, badRequest("The 'tags' parameter is missing")
+	}
+	p, err := s.existingProject(key)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := []string{}
+	seen := map[string]bool{}
+	for _, tag := range r.commaSeparated("tags") {
+		if !tagPattern.MatchString(tag) {
+			return nil, badRequest("Tag '%s' is invalid. Tags accept only the characters: a-z, 0-9, '+', '-', '#', '.'", tag)
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	sort.Strings(tags)
+	p.Tags = tags
+	return nil, nil
+}
+
+func (s *Server) showComponent(r *request) (interface{}, error) {
+	key, err := r.required("component")
+	if err != nil {
+		return nil, err
+	}
+	p, err := s.component(key)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"component": p.response(), "ancestors": []string{}}, nil
+}
+
+func (s *Server) listBranches(r *request) (interface{}, error) {
+	key, err := r.required("project")
+	if err != nil {
+		return nil, err
+	}
+	p, err := s.existingProject(key)
+	if err != nil {
+		return nil, err
+	}
+
+	branches := []map[string]interface{}{}
+	for i, b := range p.branches {
+		response := map[string]interface{}{
+			"name":              b.Name,
+			"isMain":            i == 0,
+			"type":              "BRANCH",
+			"status":            map[string]string{},
+			"excludedFromPurge": b.ExcludedFromPurge,
+		}
+		if b.AnalysisDate != "" {
+			response["analysisDate"] = b.AnalysisDate
+			response["status"] = map[string]string{"qualityGateStatus": "OK"}
+		}
+		branches = append(branches, response)
+	}
+	return map[string]interface{}{"branches": branches}, nil
+}
+
+// existingBranch returns a project and one of its branches
+func (s *Server) existingBranch(r *request) (*project, *branch, error) {
+	key, err := r.required("project")
+	if err != nil {
+		return nil, nil, err
+	}
+	name, err := r.required("branch")
+	if err != nil {
+		return nil, nil, err
+	}
+	p, err := s.existingProject(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	b := p.branch(name)
+	if b == nil {
+		return nil, nil, notFound("Branch '%s' not found for project '%s'", name, p.Key)
+	}
+	return p, b, nil
+}
+
+func (s *Server) deleteBranch(r *request) (interface{}, error) {
+	p, b, err := s.existingBranch(r)
+	if err != nil {
+		return nil, err
+	}
+	if b == p.branches[0] {
+		return nil, badRequest("Only non-main branches can be deleted")
+	}
+
+	for i := range p.branches {
+		if p.branches[i] == b {
+			p.branches = append(p.branches[:i], p.branches[i+1:]...)
+			break
+		}
+	}
+	periods := s.newCodePeriods[:0]
+	for _, period := range s.newCodePeriods {
+		if period.project != p.Key || period.branch != b.Name {
+			periods = append(periods, period)
+		}
+	}
+	s.newCodePeriods = periods
+	for _, a := range s.applications {
+		for _, applicationBranch := range a.branches {
+			if applicationBranch.projects[p.Key] == b.Name {
+				delete(applicationBranch.projects, p.Key)
+			}
+		}
+	}
+	return nil, nil
+}
+
+func (s *Server) setBranchDeletionProtection(r *request) (interface{}, error) {
+	p, b, err := s.existingBranch(r)
+	if err != nil {
+		return nil, err
+	}
+	if !r.has("value") {
+		return nil, badRequest("The 'value' parameter is missing")
+	}
+	value, err := r.boolean("value", false)
+	if err != nil {
+		return nil, err
+	}
+	if b == p.branches[0] && !value {
+		return nil, badRequest("Main branch of the project is always excluded from automatic deletion.")
+	}
+	b.ExcludedFromPurge = value
+	return nil, nil
+}
+
+// renameMainBranch renames the main branch of a project
+func (s *Server) renameMainBranch(r *request) (interface{}, error) {
+	key, err := r.required("project")
+	if err != nil {
+		return nil, err
+	}
+	name, err := r.required("name")
+	if err != nil {
+		return nil, err
+	}
+	p, err := s.existingProject(key)
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range p.branches[1:] {
+		if b.Name == name {
+			return nil, badRequest("Impossible to update branch name: a branch with name \"%s\" already exists in the project.", name)
+		}
+	}
+
+	for _, period := range s.newCodePeriods {
+		if period.project == p.Key && period.branch == p.branches[0].Name {
+			period.branch = name
+		}
+	}
+	for _, a := range s.applications {
+		for _, applicationBranch := range a.branches {
+			if applicationBranch.projects[p.Key] == p.branches[0].Name {
+				applicationBranch.projects[p.Key] = name
+			}
+		}
+	}
+	p.branches[0].Name = name
+	return nil, nil
+}
+
+func (s *Server) findNewCodePeriod(project string, branch string) *newCodePeriod {
+	for _, period := range s.newCodePeriods {
+		if period.project == project && period.branch == branch {
+			return period
+		}
+	}
+	return nil
+}
+
+// newCodePeriodScope returns the project and branch a new code period request applies to
+func (s *Server) newCodePeriodScope(r *request) (string, string, error) {
+	projectKey, branch := r.param("project"), r.param("branch")
+	if projectKey == "" {
+		if branch != "" {
+			return "", "", badRequest("If branch key is specified, project key needs to be specified too")
+		}
+		return "", "", nil
+	}
+	p, err := s.existingProject(projectKey)
+	if err != nil {
+		return "", "", err
+	}
+	if branch != "" {
+		if p.branch(branch) == nil {
+			return "", "", notFound("Branch '%s' in project '%s' not found", branch, projectKey)
+		}
+	}
+	return p.Key, branch, nil
+}
+
+func (s *Server) setNewCodePeriod(r *request) (interface{}, error) {
+	project, branch, err := s.newCodePeriodScope(r)
+	if err != nil {
+		return nil, err
+	}
+	periodType, err := r.required("type")
+	if err != nil {
+		return nil, err
+	}
+
+	valid := []string{"PREVIOUS_VERSION", "NUMBER_OF_DAYS"}
+	switch {
+	case branch != "":
+		valid = append(valid, "SPECIFIC_ANALYSIS", "REFERENCE_BRANCH")
+	case project != "":
+		valid = append(valid, "REFERENCE_BRANCH")
+	}
+	if _, err := r.oneOf("type", "", valid...); err != nil {
+		return nil, badRequest("Invalid type '%s'. Overall setting can only be set with types: %v", periodType, valid)
+	}
+
+	value := r.param("value")
+	switch periodType {
+	case "PREVIOUS_VERSION":
+		if value != "" {
+			return nil, badRequest("Unexpected value for type '%s'", periodType)
+		}
+	case "NUMBER_OF_DAYS":
+		if days, err := strconv.Atoi(value); err != nil || days < 1 || days > 90 {
+			return nil, badRequest("Failed to parse number of days: %s", value)
+		}
+	default:
+		if value == "" {
+			return nil, badRequest("New code definition type '%s' requires a value", periodType)
+		}
+	}
+
+	period := s.findNewCodePeriod(project, branch)
+	if period == nil {
+		period = &newCodePeriod{project: project, branch: branch}
+		s.newCodePeriods = append(s.newCodePeriods, period)
+	}
+	period.Type, period.Value = periodType, value
+	return nil, nil
+}
+
+func (s *Server) showNewCodePeriod(r *request) (interface{}, error) {
+	project, branch, err := s.newCodePeriodScope(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// A branch inherits the period of its project, which inherits the global period
+	period, inherited := s.findNewCodePeriod(project, branch), false
+	if period == nil && branch != "" {
+		period, inherited = s.findNewCodePeriod(project, ""), true
+	}
+	if period == nil {
+		period, inherited = s.findNewCodePeriod("", ""), true
+	}
+
+	response := map[string]interface{}{
+		"type":      period.Type,
+		"inherited": inherited && project != "",
+	}
+	if project != "" {
+		response["projectKey"] = project
+	}
+	if branch != "" {
+		response["branchKey"] = branch
+	}
+	if period.Value != "" {
+		response["value"] = period.Value
+		response["effectiveValue"] = period.Value
+	}
+	return response, nil
+}
+
+func (s *Server) unsetNewCodePeriod(r *request) (interface{}, error) {
+	project, branch, err := s.newCodePeriodScope(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if project == "" {
+		// The global period is reset to its default value
+		global := s.findNewCodePeriod("", "")
+		global.Type, global.Value = "PREVIOUS_VERSION", ""
+		return nil, nil
+	}
+	for i, period := range s.newCodePeriods {
+		if period.project == project && period.branch == branch {
+			s.newCodePeriods = append(s.newCodePeriods[:i], s.newCodePeriods[i+1:]...)
+			break
+		}
+	}
+	return nil, nil
+}
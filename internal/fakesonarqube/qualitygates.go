@@ -0,0 +1,569 @@
+package fakesonarqube
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+)
+
+// metrics maps the keys of the metrics quality gate conditions can use to their type
+var metrics = map[string]string{
+	"blocker_violations":             "INT",
+	"branch_coverage":                "PERCENT",
+	"bugs":                           "INT",
+	"code_smells":                    "INT",
+	"cognitive_complexity":           "INT",
+	"comment_lines_density":          "PERCENT",
+	"complexity":                     "INT",
+	"coverage":                       "PERCENT",
+	"critical_violations":            "INT",
+	"duplicated_blocks":              "INT",
+	"duplicated_lines":               "INT",
+	"duplicated_lines_density":       "PERCENT",
+	"info_violations":                "INT",
+	"line_coverage":                  "PERCENT",
+	"lines":                          "INT",
+	"lines_to_cover":                 "INT",
+	"major_violations":               "INT",
+	"minor_violations":               "INT",
+	"ncloc":                          "INT",
+	"new_blocker_violations":         "INT",
+	"new_branch_coverage":            "PERCENT",
+	"new_bugs":                       "INT",
+	"new_code_smells":                "INT",
+	"new_coverage":                   "PERCENT",
+	"new_critical_violations":        "INT",
+	"new_duplicated_lines_density":   "PERCENT",
+	"new_line_coverage":              "PERCENT",
+	"new_lines":                      "INT",
+	"new_maintainability_rating":     "RATING",
+	"new_reliability_rating":         "RATING",
+	"new_security_hotspots":          "INT",
+	"new_security_hotspots_reviewed": "PERCENT",
+	"new_security_rating":            "RATING",
+	"new_security_review_rating":     "RATING",
+	"new_sqale_debt_ratio":           "PERCENT",
+	"new_technical_debt":             "WORK_DUR",
+	"new_violations":                 "INT",
+	"new_vulnerabilities":            "INT",
+	"reliability_rating":             "RATING",
+	"security_hotspots":              "INT",
+	"security_hotspots_reviewed":     "PERCENT",
+	"security_rating":                "RATING",
+	"security_review_rating":         "RATING",
+	"skipped_tests":                  "INT",
+	"sqale_debt_ratio":               "PERCENT",
+	"sqale_index":                    "WORK_DUR",
+	"sqale_rating":                   "RATING",
+	"test_errors":                    "INT",
+	"test_failures":                  "INT",
+	"test_success_density":           "PERCENT",
+	"tests":                          "INT",
+	"violations":                     "INT",
+	"vulnerabilities":                "INT",
+}
+
+type qualityGate struct {
+	ID         string
+	Name       string
+	BuiltIn    bool
+	Conditions []*qualityGateCondition
+
+	projects map[string]bool
+	users    map[string]bool
+	groups   map[string]bool
+}
+
+type qualityGateCondition struct {
+	ID     string `json:"id"`
+	Metric string `json:"metric"`
+	OP     string `json:"op"`
+	Error  string `json:"error"`
+}
+
+func (s *Server) registerQualityGates() {
+	sonarWay := &qualityGate{ID: s.newID(), Name: "Sonar way", BuiltIn: true, projects: map[string]bool{}, users: map[string]bool{}, groups: map[string]bool{}}
+	for _, c := range []qualityGateCondition{
+		{Metric: "new_coverage", OP: "LT", Error: "80"},
+		{Metric: "new_duplicated_lines_density", OP: "GT", Error: "3"},
+		{Metric: "new_security_hotspots_reviewed", OP: "LT", Error: "100"},
+		{Metric: "new_violations", OP: "GT", Error: "0"},
+	} {
+		c.ID = s.newID()
+		condition := c
+		sonarWay.Conditions = append(sonarWay.Conditions, &condition)
+	}
+	s.qualityGates = []*qualityGate{sonarWay}
+
+	s.handle(http.MethodPost, "api/qualitygates/create", s.createQualityGate)
+	s.handle(http.MethodPost, "api/qualitygates/copy", s.copyQualityGate)
+	s.handle(http.MethodGet, "api/qualitygates/list", s.listQualityGates)
+	s.handle(http.MethodGet, "api/qualitygates/show", s.showQualityGate)
+	s.handle(http.MethodPost, "api/qualitygates/destroy", s.destroyQualityGate)
+	s.handle(http.MethodPost, "api/qualitygates/rename", s.renameQualityGate)
+	s.handle(http.MethodPost, "api/qualitygates/set_as_default", s.setDefaultQualityGate)
+	s.handle(http.MethodPost, "api/qualitygates/create_condition", s.createCondition)
+	s.handle(http.MethodPost, "api/qualitygates/update_condition", s.updateCondition)
+	s.handle(http.MethodPost, "api/qualitygates/delete_condition", s.deleteCondition)
+	s.handle(http.MethodPost, "api/qualitygates/select", s.selectQualityGate)
+	s.handle(http.MethodPost, "api/qualitygates/deselect", s.deselectQualityGate)
+	s.handle(http.MethodGet, "api/qualitygates/get_by_project", s.qualityGateOfProject)
+	s.handle(http.MethodPost, "api/qualitygates/add_user", s.addQualityGateEditor(true))
+	s.handle(http.MethodPost, "api/qualitygates/add_group", s.addQualityGateEditor(false))
+	s.handle(http.MethodPost, "api/qualitygates/remove_user", s.removeQualityGateEditor(true))
+	s.handle(http.MethodPost, "api/qualitygates/remove_group", s.removeQualityGateEditor(false))
+	s.handle(http.MethodGet, "api/qualitygates/search_users", s.qualityGateEditors(true))
+	s.handle(http.MethodGet, "api/qualitygates/search_groups", s.qualityGateEditors(false))
+}
+
+// defaultQualityGate is the first quality gate, SonarQube always has exactly one default quality gate
+func (s *Server) defaultQualityGate() *qualityGate {
+	return s.qualityGates[0]
+}
+
+func (s *Server) findQualityGate(name string) *qualityGate {
+	for _, g := range s.qualityGates {
+		if g.Name == name {
+			return g
+		}
+	}
+	return nil
+}
+
+// existingQualityGate returns the quality gate named by the parameter param
+func (s *Server) existingQualityGate(r *request, param string) (*qualityGate, error) {
+	name, err := r.required(param)
+	if err != nil {
+		return nil, err
+	}
+	if g := s.findQualityGate(name); g != nil {
+		return g, nil
+	}
+	return nil, notFound("No quality gate has been found for name %s", name)
+}
+
+// gateOfRequest returns the quality gate of a create_condition, select or deselect request. The gate is identified by
+// gateName, or by gateId which SonarQube 8.4 deprecated and SonarQube 10.0 removed.
+func (s *Server) gateOfRequest(r *request) (*qualityGate, error) {
+	if !s.atLeast("10.0") && r.has("gateId") {
+		for _, g := range s.qualityGates {
+			if g.ID == r.param("gateId") {
+				return g, nil
+			}
+		}
+		return nil, notFound("No quality gate has been found for id %s", r.param("gateId"))
+	}
+	if !s.atLeast("8.4") {
+		return nil, badRequest("The 'gateId' parameter is missing")
+	}
+	return s.existingQualityGate(r, "gateName")
+}
+
+func (s *Server) newQualityGate(r *request) (*qualityGate, error) {
+	name, err := r.required("name")
+	if err != nil {
+		return nil, err
+	}
+	if len(name) > 100 {
+		return nil, badRequest("'name' length (%d) is longer than the maximum authorized (100)", len(name))
+	}
+	if s.findQualityGate(name) != nil {
+		return nil, badRequest("Name has already been taken")
+	}
+	g := &qualityGate{ID: s.newID(), Name: name, projects: map[string]bool{}, users: map[string]bool{}, groups: map[string]bool{}}
+	s.qualityGates = append(s.qualityGates, g)
+	return g, nil
+}
+
+func (s *Server) createQualityGate(r *request) (interface{}, error) {
+	g, err := s.newQualityGate(r)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{"id": g.ID, "name": g.Name}, nil
+}
+
+func (s *Server) copyQualityGate(r *request) (interface{}, error) {
+	source, err := s.existingQualityGate(r, "sourceName")
+	if err != nil {
+		return nil, err
+	}
+	g, err := s.newQualityGate(r)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range source.Conditions {
+		condition := *c
+		condition.ID = s.newID()
+		g.Conditions = append(g.Conditions, &condition)
+	}
+	return map[string]string{"id": g.ID, "name": g.Name}, nil
+}
+
+func (s *Server) listQualityGates(r *request) (interface{}, error) {
+	gates := []map[string]interface{}{}
+	for _, g := range s.qualityGates {
+		gates = append(gates, map[string]interface{}{
+			"id":        g.ID,
+			"name":      g.Name,
+			"isBuiltIn": g.BuiltIn,
+			"isDefault": g == s.defaultQualityGate(),
+		})
+	}
+	return map[string]interface{}{"qualitygates": gates}, nil
+}
+
+func (s *Server) showQualityGate(r *request) (interface{}, error) {
+	g, err := s.existingQualityGate(r, "name")
+	if err != nil {
+		return nil, err
+	}
+
+	isDefault := g == s.defaultQualityGate()
+	conditions := g.Conditions
+	if conditions == nil {
+		conditions = []*qualityGateCondition{}
+	}
+	return map[string]interface{}{
+		"id":         g.ID,
+		"name":       g.Name,
+		"conditions": conditions,
+		"isBuiltIn":  g.BuiltIn,
+		"isDefault":  isDefault,
+		"actions": map[string]bool{
+			"rename":            !g.BuiltIn,
+			"setAsDefault":      !isDefault,
+			"copy":              true,
+			"associateProjects": !isDefault,
+			"delete":            !isDefault && !g.BuiltIn,
+			"manageConditions":  !g.BuiltIn,
+			"delegate":          !g.BuiltIn,
+		},
+	}, nil
+}
+
+func (s *Server) destroyQualityGate(r *request) (interface{}, error) {
+	g, err := s.existingQualityGate(r, "name")
+	if err != nil {
+		return nil, err
+	}
+	if g.BuiltIn {
+		return nil, badRequest("Operation forbidden for built-in Quality Gate '%s'", g.Name)
+	}
+	if g == s.defaultQualityGate() {
+		return nil, badRequest("The default quality gate cannot be removed")
+	}
+
+	for i := range s.qualityGates {
+		if s.qualityGates[i] == g {
+			s.qualityGates = append(s.qualityGates[:i], s.qualityGates[i+1:]...)
+			break
+		}
+	}
+	return nil, nil
+}
+
+func (s *Server) renameQualityGate(r *request) (interface{}, error) {
+	g, err := s.existingQualityGate(r, "currentName")
+	if err != nil {
+		return nil, err
+	}
+	name, err := r.required("name")
+	if err != nil {
+		return nil, err
+	}
+	if g.BuiltIn {
+		return nil, badRequest("Operation forbidden for built-in Quality Gate '%s'", g.Name)
+	}
+	if other := s.findQualityGate(name); other != nil && other != g {
+		return nil, badRequest("Name '%s' has already been taken", name)
+	}
+	g.Name = name
+	return nil, nil
+}
+
+func (s *Server) setDefaultQualityGate(r *request) (interface{}, error) {
+	g, err := s.existingQualityGate(r, "name")
+	if err != nil {
+		return nil, err
+	}
+	for i := range s.qualityGates {
+		if s.qualityGates[i] == g {
+			s.qualityGates[0], s.qualityGates[i] = s.qualityGates[i], s.qualityGates[0]
+			break
+		}
+	}
+	return nil, nil
+}
+
+// validCondition validates the metric, operator and threshold of a condition
+func validCondition(r *request) (*qualityGateCondition, error) {
+	metric, err := r.required("metric")
+	if err != nil {
+		return nil, err
+	}
+	threshold, err := r.required("error")
+	if err != nil {
+		return nil, err
+	}
+	metricType, ok := metrics[metric]
+	if !ok {
+		return nil, notFound("There is no metric with key=%s", metric)
+	}
+
+	op, err := r.oneOf("op", "GT", "LT", "GT")
+	if err != nil {
+		return nil, err
+	}
+	switch metricType {
+	case "RATING":
+		if op != "GT" {
+			return nil, badRequest("Operator %s is not allowed for this metric.", op)
+		}
+		if value, err := strconv.Atoi(threshold); err != nil || value < 1 || value > 4 {
+			return nil, badRequest("'%s' is not a valid rating", threshold)
+		}
+	case "INT", "WORK_DUR":
+		if _, err := strconv.Atoi(threshold); err != nil {
+			return nil, badRequest("Invalid value '%s' for metric '%s'", threshold, metric)
+		}
+	default:
+		if _, err := strconv.ParseFloat(threshold, 64); err != nil {
+			return nil, badRequest("Invalid value '%s' for metric '%s'", threshold, metric)
+		}
+	}
+	return &qualityGateCondition{Metric: metric, OP: op, Error: threshold}, nil
+}
+
+func (s *Server) createCondition(r *request) (interface{}, error) {
+	g, err := s.gateOfRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	if g.BuiltIn {
+		return nil, badRequest("Operation forbidden for built-in Quality Gate '%s'", g.Name)
+	}
+	condition, err := validCondition(r)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range g.Conditions {
+		if c.Metric == condition.Metric {
+			return nil, badRequest("Condition on metric '%s' already exists.", c.Metric)
+		}
+	}
+
+	condition.ID = s.newID()
+	g.Conditions = append(g.Conditions, condition)
+	return condition, nil
+}
+
+// findCondition returns the condition with the id parameter and its quality gate
+func (s *Server) findCondition(r *request) (*qualityGate, int, error) {
+	id, err := r.required("id")
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, g := range s.qualityGates {
+		for i, c := range g.Conditions {
+			if c.ID == id {
+				if g.BuiltIn {
+					return nil, 0, badRequest("Operation forbidden for built-in Quality Gate '%s'", g.Name)
+				}
+				return g, i, nil
+			}
+		}
+	}
+	return nil, 0, notFound("No quality gate condition with uuid '%s'", id)
+}
+
+func (s *Server) updateCondition(r *request) (interface{}, error) {
+	g, i, err := s.findCondition(r)
+	if err != nil {
+		return nil, err
+	}
+	condition, err := validCondition(r)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range g.Conditions {
+		if c.Metric == condition.Metric && c != g.Conditions[i] {
+			return nil, badRequest("Condition on metric '%s' already exists.", c.Metric)
+		}
+	}
+
+	condition.ID = g.Conditions[i].ID
+	g.Conditions[i] = condition
+	return nil, nil
+}
+
+func (s *Server) deleteCondition(r *request) (interface{}, error) {
+	g, i, err := s.findCondition(r)
+	if err != nil {
+		return nil, err
+	}
+	g.Conditions = append(g.Conditions[:i], g.Conditions[i+1:]...)
+	return nil, nil
+}
+
+// qualityGateProject returns the quality gate and the project of a select or deselect request
+func (s *Server) qualityGateProject(r *request) (*qualityGate, *project, error) {
+	g, err := s.gateOfRequest(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	key, err := r.required("projectKey")
+	if err != nil {
+		return nil, nil, err
+	}
+	p, err := s.existingProject(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return g, p, nil
+}
+
+func (s *Server) selectQualityGate(r *request) (interface{}, error) {
+	g, p, err := s.qualityGateProject(r)
+	if err != nil {
+		return nil, err
+	}
+	for _, other := range s.qualityGates {
+		delete(other.projects, p.Key)
+	}
+	g.projects[p.Key] = true
+	return nil, nil
+}
+
+func (s *Server) deselectQualityGate(r *request) (interface{}, error) {
+	_, p, err := s.qualityGateProject(r)
+	if err != nil {
+		return nil, err
+	}
+	for _, g := range s.qualityGates {
+		delete(g.projects, p.Key)
+	}
+	return nil, nil
+}
+
+func (s *Server) qualityGateOfProject(r *request) (interface{}, error) {
+	key, err := r.required("project")
+	if err != nil {
+		return nil, err
+	}
+	p, err := s.existingProject(key)
+	if err != nil {
+		return nil, err
+	}
+
+	// Projects without an explicit quality gate use the default one
+	g := s.defaultQualityGate()
+	for _, candidate := range s.qualityGates {
+		if candidate.projects[p.Key] {
+			g = candidate
+		}
+	}
+	return map[string]interface{}{
+		"qualityGate": map[string]interface{}{"id": g.ID, "name": g.Name, "default": g == s.defaultQualityGate()},
+	}, nil
+}
+
+// qualityGateEditor returns the quality gate and the user login or group name of a permission request
+func (s *Server) qualityGateEditor(r *request, isUser bool) (*qualityGate, string, error) {
+	g, err := s.existingQualityGate(r, "gateName")
+	if err != nil {
+		return nil, "", err
+	}
+	if g.BuiltIn {
+		return nil, "", badRequest("Operation forbidden for built-in Quality Gate '%s'", g.Name)
+	}
+	if isUser {
+		login, err := r.required("login")
+		if err != nil {
+			return nil, "", err
+		}
+		_, err = s.activeUser(login)
+		return g, login, err
+	}
+	name, err := r.required("groupName")
+	if err != nil {
+		return nil, "", err
+	}
+	_, err = s.existingGroup(name)
+	return g, name, err
+}
+
+func (s *Server) addQualityGateEditor(isUser bool) func(r *request) (interface{}, error) {
+	return func(r *request) (interface{}, error) {
+		g, name, err := s.qualityGateEditor(r, isUser)
+		if err != nil {
+			return nil, err
+		}
+		if isUser {
+			g.users[name] = true
+		} else {
+			g.groups[name] = true
+		}
+		return nil, nil
+	}
+}
+
+func (s *Server) removeQualityGateEditor(isUser bool) func(r *request) (interface{}, error) {
+	return func(r *request) (interface{}, error) {
+		g, name, err := s.qualityGateEditor(r, isUser)
+		if err != nil {
+			return nil, err
+		}
+		if isUser {
+			delete(g.users, name)
+		} else {
+			delete(g.groups, name)
+		}
+		return nil, nil
+	}
+}
+
+func (s *Server) qualityGateEditors(isUser bool) func(r *request) (interface{}, error) {
+	return func(r *request) (interface{}, error) {
+		g, err := s.existingQualityGate(r, "gateName")
+		if err != nil {
+			return nil, err
+		}
+
+		editors := []map[string]interface{}{}
+		if isUser {
+			for _, u := range s.users {
+				if !u.Active || !matches(r.param("q"), u.Login, u.Name) {
+					continue
+				}
+				if include, err := selected(r, g.users[u.Login]); err != nil {
+					return nil, err
+				} else if include {
+					editors = append(editors, map[string]interface{}{"login": u.Login, "name": u.Name, "selected": g.users[u.Login]})
+				}
+			}
+		} else {
+			for _, group := range s.groups {
+				if !matches(r.param("q"), group.Name) {
+					continue
+				}
+				if include, err := selected(r, g.groups[group.Name]); err != nil {
+					return nil, err
+				} else if include {
+					editors = append(editors, map[string]interface{}{"name": group.Name, "description": group.Description, "selected": g.groups[group.Name]})
+				}
+			}
+		}
+		sort.SliceStable(editors, func(i, j int) bool { return editors[i]["name"].(string) < editors[j]["name"].(string) })
+
+		page, p, err := paginate(r, editors, 25)
+		if err != nil {
+			return nil, err
+		}
+		field := "groups"
+		if isUser {
+			field = "users"
+		}
+		return map[string]interface{}{"paging": p, field: page}, nil
+	}
+}
@@ -0,0 +1,435 @@
+package fakesonarqube
+
+import (
+	"net/http"
+	"sort"
+)
+
+// languages maps the keys of the languages known to the fake to their name
+var languages = map[string]string{
+	"cloudformation": "CloudFormation",
+	"cs":             "C#",
+	"css":            "CSS",
+	"docker":         "Docker",
+	"go":             "Go",
+	"java":           "Java",
+	"js":             "JavaScript",
+	"json":           "JSON",
+	"kotlin":         "Kotlin",
+	"php":            "PHP",
+	"py":             "Python",
+	"ruby":           "Ruby",
+	"scala":          "Scala",
+	"terraform":      "Terraform",
+	"ts":             "TypeScript",
+	"web":            "HTML",
+	"xml":            "XML",
+	"yaml":           "YAML",
+}
+
+type qualityProfile struct {
+	Key       string
+	Name      string
+	Language  string
+	BuiltIn   bool
+	IsDefault bool
+	Parent    *qualityProfile
+
+	projects    map[string]bool
+	activeRules map[string]*activeRule
+}
+
+type activeRule struct {
+	severity string
+	params   map[string]string
+}
+
+func (a *activeRule) response(profileKey string) map[string]interface{} {
+	params := []map[string]string{}
+	for _, key := range sortedKeys(a.params) {
+		params = append(params, map[string]string{"key": key, "value": a.params[key]})
+	}
+	return map[string]interface{}{
+		"qProfile": profileKey,
+		"inherit":  "NONE",
+		"severity": a.severity,
+		"params":   params,
+	}
+}
+
+func (p *qualityProfile) response() map[string]interface{} {
+	response := map[string]interface{}{
+		"key":             p.Key,
+		"name":            p.Name,
+		"language":        p.Language,
+		"languageName":    languages[p.Language],
+		"isInherited":     p.Parent != nil,
+		"isBuiltIn":       p.BuiltIn,
+		"isDefault":       p.IsDefault,
+		"activeRuleCount": len(p.activeRules),
+		"projectCount":    len(p.projects),
+		"actions": map[string]bool{
+			"edit":              !p.BuiltIn,
+			"setAsDefault":      !p.IsDefault,
+			"copy":              true,
+			"delete":            !p.BuiltIn && !p.IsDefault,
+			"associateProjects": !p.IsDefault,
+		},
+	}
+	if p.Parent != nil {
+		response["parentKey"], response["parentName"] = p.Parent.Key, p.Parent.Name
+	}
+	return response
+}
+
+func (s *Server) registerQualityProfiles() {
+	for _, language := range sortedKeys(languages) {
+		s.qualityProfiles = append(s.qualityProfiles, &qualityProfile{
+			Key:         s.newID(),
+			Name:        "Sonar way",
+			Language:    language,
+			BuiltIn:     true,
+			IsDefault:   true,
+			projects:    map[string]bool{},
+			activeRules: map[string]*activeRule{},
+		})
+	}
+
+	s.handle(http.MethodPost, "api/qualityprofiles/create", s.createQualityProfile)
+	s.handle(http.MethodGet, "api/qualityprofiles/search", s.searchQualityProfiles)
+	s.handle(http.MethodPost, "api/qualityprofiles/delete", s.deleteQualityProfile)
+	s.handle(http.MethodPost, "api/qualityprofiles/set_default", s.setDefaultQualityProfile)
+	s.handle(http.MethodPost, "api/qualityprofiles/change_parent", s.changeQualityProfileParent)
+	s.handle(http.MethodPost, "api/qualityprofiles/add_project", s.addQualityProfileProject)
+	s.handle(http.MethodPost, "api/qualityprofiles/remove_project", s.removeQualityProfileProject)
+	s.handle(http.MethodGet, "api/qualityprofiles/projects", s.qualityProfileProjects)
+	s.handle(http.MethodPost, "api/qualityprofiles/activate_rule", s.activateRule)
+	s.handle(http.MethodPost, "api/qualityprofiles/deactivate_rule", s.deactivateRule)
+}
+
+func (s *Server) findQualityProfile(name string, language string) *qualityProfile {
+	for _, p := range s.qualityProfiles {
+		if p.Name == name && p.Language == language {
+			return p
+		}
+	}
+	return nil
+}
+
+// validLanguage returns the language parameter of a request
+func validLanguage(r *request) (string, error) {
+	language, err := r.required("language")
+	if err != nil {
+		return "", err
+	}
+	if _, ok := languages[language]; !ok {
+		return "", badRequest("Value of parameter 'language' (%s) must be one of: %v", language, sortedKeys(languages))
+	}
+	return language, nil
+}
+
+// existingQualityProfile returns the quality profile identified by the qualityProfile and language parameters
+func (s *Server) existingQualityProfile(r *request) (*qualityProfile, error) {
+	name, err := r.required("qualityProfile")
+	if err != nil {
+		return nil, err
+	}
+	language, err := validLanguage(r)
+	if err != nil {
+		return nil, err
+	}
+	if p := s.findQualityProfile(name, language); p != nil {
+		return p, nil
+	}
+	return nil, notFound("Quality Profile for language '%s' and name '%s' does not exist", language, name)
+}
+
+func (s *Server) qualityProfileByKey(key string) (*qualityProfile, error) {
+	for _, p := range s.qualityProfiles {
+		if p.Key == key {
+			return p, nil
+		}
+	}
+	return nil, notFound("Quality Profile with key '%s' does not exist", key)
+}
+
+func (s *Server) createQualityProfile(r *request) (interface{}, error) {
+	name, err := r.required("name")
+	if err != nil {
+		return nil, err
+	}
+	language, err := validLanguage(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(name) > 100 {
+		return nil, badRequest("'name' length (%d) is longer than the maximum authorized (100)", len(name))
+	}
+	if s.findQualityProfile(name, language) != nil {
+		return nil, badRequest("Quality profile already exists: {lang=%s, name=%s}", language, name)
+	}
+
+	p := &qualityProfile{Key: s.newID(), Name: name, Language: language, projects: map[string]bool{}, activeRules: map[string]*activeRule{}}
+	s.qualityProfiles = append(s.qualityProfiles, p)
+	return map[string]interface{}{"profile": p.response(), "warnings": []string{}}, nil
+}
+
+func (s *Server) searchQualityProfiles(r *request) (interface{}, error) {
+	defaults, err := r.boolean("defaults", false)
+	if err != nil {
+		return nil, err
+	}
+	profiles := []map[string]interface{}{}
+	for _, p := range s.qualityProfiles {
+		if language := r.param("language"); language != "" && p.Language != language {
+			continue
+		}
+		if name := r.param("qualityProfile"); name != "" && p.Name != name {
+			continue
+		}
+		if defaults && !p.IsDefault {
+			continue
+		}
+		if project := r.param("project"); project != "" && !s.usesQualityProfile(project, p) {
+			continue
+		}
+		profiles = append(profiles, p.response())
+	}
+	sort.SliceStable(profiles, func(i, j int) bool {
+		return profiles[i]["language"].(string) < profiles[j]["language"].(string)
+	})
+	return map[string]interface{}{"profiles": profiles}, nil
+}
+
+// usesQualityProfile reports whether a project uses a quality profile, explicitly or because it is the default one
+func (s *Server) usesQualityProfile(projectKey string, p *qualityProfile) bool {
+	if p.projects[projectKey] {
+		return true
+	}
+	if !p.IsDefault {
+		return false
+	}
+	for _, other := range s.qualityProfiles {
+		if other.Language == p.Language && other.projects[projectKey] {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Server) deleteQualityProfile(r *request) (interface{}, error) {
+	p, err := s.existingQualityProfile(r)
+	if err != nil {
+		return nil, err
+	}
+	if p.BuiltIn {
+		return nil, badRequest("Operation forbidden for built-in Quality Profile '%s' with language '%s'", p.Name, p.Language)
+	}
+	if p.IsDefault {
+		return nil, badRequest("Profile '%s' cannot be deleted because it is marked as default", p.Name)
+	}
+
+	// Like SonarQube, the descendants of a quality profile are deleted with it
+	deleted := map[*qualityProfile]bool{p: true}
+	for changed := true; changed; {
+		changed = false
+		for _, candidate := range s.qualityProfiles {
+			if candidate.Parent != nil && deleted[candidate.Parent] && !deleted[candidate] {
+				deleted[candidate], changed = true, true
+			}
+		}
+	}
+	for candidate := range deleted {
+		if candidate.IsDefault {
+			return nil, badRequest("Profile '%s' cannot be deleted because its descendant named '%s' is marked as default", p.Name, candidate.Name)
+		}
+	}
+	profiles := s.qualityProfiles[:0]
+	for _, candidate := range s.qualityProfiles {
+		if !deleted[candidate] {
+			profiles = append(profiles, candidate)
+		}
+	}
+	s.qualityProfiles = profiles
+	return nil, nil
+}
+
+func (s *Server) setDefaultQualityProfile(r *request) (interface{}, error) {
+	p, err := s.existingQualityProfile(r)
+	if err != nil {
+		return nil, err
+	}
+	for _, other := range s.qualityProfiles {
+		if other.Language == p.Language {
+			other.IsDefault = other == p
+		}
+	}
+	return nil, nil
+}
+
+func (s *Server) changeQualityProfileParent(r *request) (interface{}, error) {
+	p, err := s.existingQualityProfile(r)
+	if err != nil {
+		return nil, err
+	}
+	if p.BuiltIn {
+		return nil, badRequest("Operation forbidden for built-in Quality Profile '%s' with language '%s'", p.Name, p.Language)
+	}
+
+	parentName := r.param("parentQualityProfile")
+	if parentName == "" {
+		p.Parent = nil
+		return nil, nil
+	}
+	parent := s.findQualityProfile(parentName, p.Language)
+	if parent == nil {
+		return nil, notFound("Quality Profile for language '%s' and name '%s' does not exist", p.Language, parentName)
+	}
+	for ancestor := parent; ancestor != nil; ancestor = ancestor.Parent {
+		if ancestor == p {
+			return nil, badRequest("Descendant profile '%s' can not be selected as parent of '%s'", parent.Name, p.Name)
+		}
+	}
+	p.Parent = parent
+	return nil, nil
+}
+
+// qualityProfileProject returns the quality profile and the project of an add_project or remove_project request
+func (s *Server) qualityProfileProject(r *request) (*qualityProfile, *project, error) {
+	p, err := s.existingQualityProfile(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	key, err := r.required("project")
+	if err != nil {
+		return nil, nil, err
+	}
+	pr, err := s.existingProject(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return p, pr, nil
+}
+
+func (s *Server) addQualityProfileProject(r *request) (interface{}, error) {
+	p, pr, err := s.qualityProfileProject(r)
+	if err != nil {
+		return nil, err
+	}
+	for _, other := range s.qualityProfiles {
+		if other.Language == p.Language {
+			delete(other.projects, pr.Key)
+		}
+	}
+	p.projects[pr.Key] = true
+	return nil, nil
+}
+
+func (s *Server) removeQualityProfileProject(r *request) (interface{}, error) {
+	p, pr, err := s.qualityProfileProject(r)
+	if err != nil {
+		return nil, err
+	}
+	delete(p.projects, pr.Key)
+	return nil, nil
+}
+
+func (s *Server) qualityProfileProjects(r *request) (interface{}, error) {
+	key, err := r.required("key")
+	if err != nil {
+		return nil, err
+	}
+	p, err := s.qualityProfileByKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []map[string]interface{}{}
+	for _, pr := range s.projects {
+		if !matches(r.param("q"), pr.Key, pr.Name) {
+			continue
+		}
+		if include, err := selected(r, p.projects[pr.Key]); err != nil {
+			return nil, err
+		} else if include {
+			results = append(results, map[string]interface{}{"key": pr.Key, "name": pr.Name, "selected": p.projects[pr.Key]})
+		}
+	}
+
+	page, pg, err := paginate(r, results, 100)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"paging": pg, "results": page, "more": pg.PageIndex*pg.PageSize < pg.Total}, nil
+}
+
+// profileRule returns the quality profile and the rule of an activate_rule or deactivate_rule request
+func (s *Server) profileRule(r *request) (*qualityProfile, *rule, error) {
+	key, err := r.required("key")
+	if err != nil {
+		return nil, nil, err
+	}
+	ruleKey, err := r.required("rule")
+	if err != nil {
+		return nil, nil, err
+	}
+	p, err := s.qualityProfileByKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	if p.BuiltIn {
+		return nil, nil, badRequest("Operation forbidden for built-in Quality Profile '%s' with language '%s'", p.Name, p.Language)
+	}
+	found := s.findRule(ruleKey)
+	if found == nil {
+		return nil, nil, notFound("Rule not found: %s", ruleKey)
+	}
+	if found.Lang != p.Language {
+		return nil, nil, badRequest("%s rule %s cannot be activated on %s profile %s", found.LangName, found.Key, languages[p.Language], p.Name)
+	}
+	return p, found, nil
+}
+
+func (s *Server) activateRule(r *request) (interface{}, error) {
+	p, found, err := s.profileRule(r)
+	if err != nil {
+		return nil, err
+	}
+	if found.IsTemplate {
+		return nil, badRequest("Rule template can't be activated on a Quality profile: %s", found.Key)
+	}
+
+	reset, err := r.boolean("reset", false)
+	if err != nil {
+		return nil, err
+	}
+	severity := found.Severity
+	if !reset {
+		if severity, err = r.oneOf("severity", found.Severity, "INFO", "MINOR", "MAJOR", "CRITICAL", "BLOCKER"); err != nil {
+			return nil, err
+		}
+	}
+	params := map[string]string{}
+	for _, param := range found.Params {
+		if param.DefaultValue != "" {
+			params[param.Key] = param.DefaultValue
+		}
+	}
+	if !reset {
+		for key, value := range parseRuleParams(r.param("params")) {
+			params[key] = value
+		}
+	}
+
+	p.activeRules[found.Key] = &activeRule{severity: severity, params: params}
+	return nil, nil
+}
+
+func (s *Server) deactivateRule(r *request) (interface{}, error) {
+	p, found, err := s.profileRule(r)
+	if err != nil {
+		return nil, err
+	}
+	delete(p.activeRules, found.Key)
+	return nil, nil
+}
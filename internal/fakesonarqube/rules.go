@@ -0,0 +1,319 @@
+package fakesonarqube
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+var ruleCustomKeyPattern = regexp.MustCompile(`^[a-zA-Z0-9_]+$`)
+
+type rule struct {
+	Key         string      `json:"key"`
+	Repo        string      `json:"repo"`
+	Name        string      `json:"name"`
+	CreatedAt   string      `json:"createdAt"`
+	UpdatedAt   string      `json:"updatedAt"`
+	MdDesc      string      `json:"mdDesc,omitempty"`
+	HtmlDesc    string      `json:"htmlDesc,omitempty"`
+	Severity    string      `json:"severity"`
+	Status      string      `json:"status"`
+	IsTemplate  bool        `json:"isTemplate"`
+	TemplateKey string      `json:"templateKey,omitempty"`
+	Tags        []string    `json:"tags"`
+	SysTags     []string    `json:"sysTags"`
+	Lang        string      `json:"lang"`
+	LangName    string      `json:"langName"`
+	Scope       string      `json:"scope"`
+	IsExternal  bool        `json:"isExternal"`
+	Type        string      `json:"type"`
+	Params      []ruleParam `json:"params"`
+}
+
+type ruleParam struct {
+	Key          string `json:"key"`
+	HtmlDesc     string `json:"htmlDesc,omitempty"`
+	DefaultValue string `json:"defaultValue,omitempty"`
+	Type         string `json:"type"`
+}
+
+func (s *Server) registerRules() {
+	for _, r := range []rule{
+		{Key: "xml:XPathCheck", Name: "Track breaches of an XPath rule", Severity: "MAJOR", Type: "CODE_SMELL", IsTemplate: true, Params: []ruleParam{
+			{Key: "expression", HtmlDesc: "The XPath query", Type: "TEXT"},
+			{Key: "filePattern", HtmlDesc: "The files to be validated using Ant-style matching patterns.", Type: "STRING"},
+			{Key: "message", HtmlDesc: "The issue message", DefaultValue: "Change this XML node to not match: ", Type: "STRING"},
+		}},
+		{Key: "xml:S1135", Name: "Track uses of \"TODO\" tags", Severity: "INFO", Type: "CODE_SMELL"},
+		{Key: "java:S124", Name: "Track comments matching a regular expression", Severity: "MAJOR", Type: "CODE_SMELL", IsTemplate: true, Params: []ruleParam{
+			{Key: "regularExpression", HtmlDesc: "The regular expression", Type: "STRING"},
+			{Key: "message", HtmlDesc: "The violation message", DefaultValue: "The regular expression matches this comment.", Type: "STRING"},
+		}},
+		{Key: "java:S1135", Name: "Track uses of \"TODO\" tags", Severity: "INFO", Type: "CODE_SMELL"},
+		{Key: "java:S2068", Name: "Hard-coded credentials are security-sensitive", Severity: "BLOCKER", Type: "SECURITY_HOTSPOT", Params: []ruleParam{
+			{Key: "credentialWords", HtmlDesc: "Comma separated list of words identifying potential credentials", DefaultValue: "password,passwd,pwd,passphrase", Type: "STRING"},
+		}},
+		{Key: "javascript:CommentRegularExpression", Name: "Track comments matching a regular expression", Severity: "MAJOR", Type: "CODE_SMELL", IsTemplate: true, Params: []ruleParam{
+			{Key: "regularExpression", HtmlDesc: "The regular expression", Type: "STRING"},
+			{Key: "message", HtmlDesc: "The issue message", DefaultValue: "The regular expression matches this comment.", Type: "STRING"},
+		}},
+		{Key: "javascript:S1135", Name: "Track uses of \"TODO\" tags", Severity: "INFO", Type: "CODE_SMELL"},
+		{Key: "python:S1135", Name: "Track uses of \"TODO\" tags", Severity: "INFO", Type: "CODE_SMELL"},
+	} {
+		r := r
+		r.Repo, _, _ = strings.Cut(r.Key, ":")
+		r.Lang = map[string]string{"javascript": "js", "python": "py"}[r.Repo]
+		if r.Lang == "" {
+			r.Lang = r.Repo
+		}
+		r.LangName = languages[r.Lang]
+		r.Status, r.Scope, r.CreatedAt, r.UpdatedAt = "READY", "MAIN", now(), now()
+		r.Tags, r.SysTags = []string{}, []string{}
+		s.rules = append(s.rules, &r)
+	}
+
+	s.handle(http.MethodPost, "api/rules/create", s.createRule)
+	s.handle(http.MethodGet, "api/rules/search", s.searchRules)
+	s.handle(http.MethodGet, "api/rules/show", s.showRule)
+	s.handle(http.MethodPost, "api/rules/update", s.updateRule)
+	s.handle(http.MethodPost, "api/rules/delete", s.deleteRule)
+}
+
+func (s *Server) findRule(key string) *rule {
+	for _, r := range s.rules {
+		if r.Key == key {
+			return r
+		}
+	}
+	return nil
+}
+
+func (s *Server) existingRule(r *request) (*rule, error) {
+	key, err := r.required("key")
+	if err != nil {
+		return nil, err
+	}
+	if found := s.findRule(key); found != nil {
+		return found, nil
+	}
+	return nil, notFound("Rule not found: %s", key)
+}
+
+// parseRuleParams parses parameters given as "key1=value1;key2=value2"
+func parseRuleParams(encoded string) map[string]string {
+	params := map[string]string{}
+	for _, pair := range strings.Split(encoded, ";") {
+		if key, value, ok := strings.Cut(pair, "="); ok {
+			params[strings.TrimSpace(key)] = strings.Trim(value, `"`)
+		}
+	}
+	return params
+}
+
+func (s *Server) createRule(r *request) (interface{}, error) {
+	customKey, err := r.required("customKey")
+	if err != nil {
+		return nil, err
+	}
+	name, err := r.required("name")
+	if err != nil {
+		return nil, err
+	}
+	templateKey, err := r.required("templateKey")
+	if err != nil {
+		return nil, err
+	}
+	description, err := r.required("markdownDescription")
+	if err != nil {
+		return nil, err
+	}
+	if !ruleCustomKeyPattern.MatchString(customKey) {
+		return nil, badRequest("The rule key \"%s\" is invalid, it should only contain: a-z, 0-9, \"_\"", customKey)
+	}
+	template := s.findRule(templateKey)
+	if template == nil {
+		return nil, badRequest("The template key doesn't exist: %s", templateKey)
+	}
+	if !template.IsTemplate {
+		return nil, badRequest("This rule is not a template rule: %s", templateKey)
+	}
+	severity, err := r.oneOf("severity", template.Severity, "INFO", "MINOR", "MAJOR", "CRITICAL", "BLOCKER")
+	if err != nil {
+		return nil, err
+	}
+	status, err := r.oneOf("status", "READY", "BETA", "DEPRECATED", "READY", "REMOVED")
+	if err != nil {
+		return nil, err
+	}
+	ruleType, err := r.oneOf("type", template.Type, "CODE_SMELL", "BUG", "VULNERABILITY", "SECURITY_HOTSPOT")
+	if err != nil {
+		return nil, err
+	}
+
+	key := template.Repo + ":" + customKey
+	if s.findRule(key) != nil {
+		return nil, badRequest("A rule with the key '%s' already exists", customKey)
+	}
+
+	custom := &rule{
+		Key:         key,
+		Repo:        template.Repo,
+		Name:        name,
+		CreatedAt:   now(),
+		UpdatedAt:   now(),
+		MdDesc:      description,
+		HtmlDesc:    description,
+		Severity:    severity,
+		Status:      status,
+		TemplateKey: template.Key,
+		Tags:        []string{},
+		SysTags:     []string{},
+		Lang:        template.Lang,
+		LangName:    template.LangName,
+		Scope:       template.Scope,
+		Type:        ruleType,
+	}
+	values := parseRuleParams(r.param("params"))
+	for _, param := range template.Params {
+		param.DefaultValue = values[param.Key]
+		custom.Params = append(custom.Params, param)
+	}
+	s.rules = append(s.rules, custom)
+	return map[string]interface{}{"rule": custom}, nil
+}
+
+func (s *Server) searchRules(r *request) (interface{}, error) {
+	languages := r.commaSeparated("languages")
+	repositories := r.commaSeparated("repositories")
+
+	// qprofile and activation restrict the search to the rules activated (or not) in a quality profile
+	var profile *qualityProfile
+	if key := r.param("qprofile"); key != "" {
+		p, err := s.qualityProfileByKey(key)
+		if err != nil {
+			return nil, err
+		}
+		profile = p
+	}
+	activation, err := r.boolean("activation", true)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := []*rule{}
+	for _, candidate := range s.rules {
+		if key := r.param("rule_key"); key != "" && candidate.Key != key {
+			continue
+		}
+		if key := r.param("template_key"); key != "" && candidate.TemplateKey != key {
+			continue
+		}
+		if len(languages) > 0 && !contains(languages, candidate.Lang) {
+			continue
+		}
+		if len(repositories) > 0 && !contains(repositories, candidate.Repo) {
+			continue
+		}
+		if isTemplate := r.param("is_template"); isTemplate != "" && (isTemplate == "true") != candidate.IsTemplate {
+			continue
+		}
+		if profile != nil {
+			if _, active := profile.activeRules[candidate.Key]; active != activation {
+				continue
+			}
+		}
+		if matches(r.param("q"), candidate.Name) {
+			rules = append(rules, candidate)
+		}
+	}
+
+	page, p, err := paginate(r, rules, 100)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"total": p.Total, "p": p.PageIndex, "ps": p.PageSize, "rules": page}, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) showRule(r *request) (interface{}, error) {
+	found, err := s.existingRule(r)
+	if err != nil {
+		return nil, err
+	}
+
+	response := map[string]interface{}{"rule": found}
+	if actives, _ := r.boolean("actives", false); actives {
+		activeRules := []map[string]interface{}{}
+		for _, profile := range s.qualityProfiles {
+			if active, ok := profile.activeRules[found.Key]; ok {
+				activeRules = append(activeRules, active.response(profile.Key))
+			}
+		}
+		response["actives"] = activeRules
+	}
+	return response, nil
+}
+
+func (s *Server) updateRule(r *request) (interface{}, error) {
+	found, err := s.existingRule(r)
+	if err != nil {
+		return nil, err
+	}
+	if found.TemplateKey == "" && (r.param("name") != "" || r.param("markdown_description") != "" || r.param("params") != "") {
+		return nil, badRequest("Only custom rules can be updated")
+	}
+
+	if name := r.param("name"); name != "" {
+		found.Name = name
+	}
+	if description := r.param("markdown_description"); description != "" {
+		found.MdDesc, found.HtmlDesc = description, description
+	}
+	if r.param("severity") != "" {
+		if found.Severity, err = r.oneOf("severity", "", "INFO", "MINOR", "MAJOR", "CRITICAL", "BLOCKER"); err != nil {
+			return nil, err
+		}
+	}
+	if r.param("status") != "" {
+		if found.Status, err = r.oneOf("status", "", "BETA", "DEPRECATED", "READY", "REMOVED"); err != nil {
+			return nil, err
+		}
+	}
+	if r.has("params") {
+		values := parseRuleParams(r.param("params"))
+		for i := range found.Params {
+			found.Params[i].DefaultValue = values[found.Params[i].Key]
+		}
+	}
+	found.UpdatedAt = now()
+	return map[string]interface{}{"rule": found}, nil
+}
+
+func (s *Server) deleteRule(r *request) (interface{}, error) {
+	found, err := s.existingRule(r)
+	if err != nil {
+		return nil, err
+	}
+	if found.TemplateKey == "" {
+		return nil, badRequest("Rule '%s' is not a custom rule", found.Key)
+	}
+
+	for i := range s.rules {
+		if s.rules[i] == found {
+			s.rules = append(s.rules[:i], s.rules[i+1:]...)
+			break
+		}
+	}
+	for _, profile := range s.qualityProfiles {
+		delete(profile.activeRules, found.Key)
+	}
+	return nil, nil
+}
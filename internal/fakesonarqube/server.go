@@ -0,0 +1,498 @@
+// Package fakesonarqube implements an in-memory fake of the SonarQube web API for tests.
+//
+// The fake keeps its state in memory and implements the subset of the web API used by the provider, with the
+// validation and error responses of a real instance where the provider depends on them. It reports the version
+// and edition it is configured with, so edition specific features can be tested without a licensed instance.
+package fakesonarqube
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultVersion is the version reported when Options.Version is empty
+	DefaultVersion = "10.4.1.88267"
+	// DefaultEdition is the edition reported when Options.Edition is empty
+	DefaultEdition = "Enterprise"
+	// DefaultLogin and DefaultPassword are the credentials of the administrator when Options does not set them
+	DefaultLogin    = "admin"
+	DefaultPassword = "admin"
+)
+
+// Options configures a fake server
+type Options struct {
+	// Version is the SonarQube version reported by api/system/info, e.g. "9.9.4.87374"
+	Version string
+	// Edition is the SonarQube edition reported by api/system/info: Community, Developer, Enterprise or Data Center.
+	// The api/views and api/project_dump web services are only available in the Enterprise and Datacenter editions.
+	Edition string
+	// Login and Password are the credentials of the administrator
+	Login    string
+	Password string
+	// Organization makes the server behave like SonarCloud when set: api/system/info, api/plugins, api/views and
+	// api/project_dump are not available, and the actions of organization scoped web services require this organization.
+	Organization string
+}
+
+// Server is a fake SonarQube instance listening on a local port. Its state is shared by all clients and lives as
+// long as the server.
+type Server struct {
+	*httptest.Server
+
+	options  Options
+	handlers map[string]handler
+
+	mu     sync.Mutex
+	nextID int
+
+	users                []*user
+	tokens               []*token
+	groups               []*group
+	projects             []*project
+	settings             []*setting
+	newCodePeriods       []*newCodePeriod
+	permissions          map[permissionScope]permissionHolders
+	permissionTemplates  []*permissionTemplate
+	defaultTemplate      string
+	qualityGates         []*qualityGate
+	qualityProfiles      []*qualityProfile
+	rules                []*rule
+	webhooks             []*webhook
+	almSettings          []*almSetting
+	almBindings          map[string]*almBinding
+	ceTasks              []*ceTask
+	portfolios           []*portfolio
+	applications         []*application
+	plugins              []*plugin
+	availablePluginNames map[string]string
+}
+
+// NewServer starts a fake SonarQube instance. The caller must Close it.
+func NewServer(options Options) *Server {
+	if options.Version == "" {
+		options.Version = DefaultVersion
+	}
+	if options.Edition == "" {
+		options.Edition = DefaultEdition
+	}
+	if options.Login == "" {
+		options.Login = DefaultLogin
+	}
+	if options.Password == "" {
+		options.Password = DefaultPassword
+	}
+
+	s := &Server{
+		options:     options,
+		handlers:    map[string]handler{},
+		permissions: map[permissionScope]permissionHolders{},
+		almBindings: map[string]*almBinding{},
+	}
+	s.registerSystem()
+	s.registerUsers()
+	s.registerUsersManagement()
+	s.registerUserTokens()
+	s.registerUserGroups()
+	s.registerProjects()
+	s.registerProjectLinks()
+	s.registerSettings()
+	s.registerNewCodePeriods()
+	s.registerPermissions()
+	s.registerQualityGates()
+	s.registerQualityProfiles()
+	s.registerRules()
+	s.registerWebhooks()
+	s.registerAlmSettings()
+	s.registerCE()
+	if s.options.Organization == "" {
+		s.registerPlugins()
+	}
+	if s.hasApplications() {
+		s.registerApplications()
+	}
+	if s.hasPortfolios() {
+		s.registerViews()
+		s.registerProjectDump()
+	}
+	s.registerWebservices()
+
+	s.Server = httptest.NewServer(s)
+	return s
+}
+
+// hasPortfolios reports whether the edition includes portfolios, and the other features of the Enterprise edition
+func (s *Server) hasPortfolios() bool {
+	if s.options.Organization != "" {
+		return false
+	}
+	edition := strings.ToLower(strings.ReplaceAll(s.options.Edition, " ", ""))
+	return edition == "enterprise" || edition == "datacenter"
+}
+
+// handler implements a web service action. It returns the value encoded as the JSON response, or nil for actions
+// answering with 204 No Content.
+type handler struct {
+	method    string
+	anonymous bool
+	serve     func(r *request) (interface{}, error)
+}
+
+func (s *Server) handle(method string, endpoint string, serve func(r *request) (interface{}, error)) {
+	s.handlers[endpoint] = handler{method: method, serve: serve}
+}
+
+// handleAnonymous registers an action which does not require authentication
+func (s *Server) handleAnonymous(method string, endpoint string, serve func(r *request) (interface{}, error)) {
+	s.handlers[endpoint] = handler{method: method, anonymous: true, serve: serve}
+}
+
+// route returns the handler of a path. Paths of the v2 web API may contain variables, e.g.
+// "api/v2/users-management/users/{id}", whose values are set as path values of the request.
+func (s *Server) route(r *http.Request) (handler, bool) {
+	path := strings.TrimPrefix(r.URL.Path, "/")
+	if h, ok := s.handlers[path]; ok {
+		return h, true
+	}
+	segments := strings.Split(path, "/")
+	for endpoint, h := range s.handlers {
+		if !strings.Contains(endpoint, "{") {
+			continue
+		}
+		patterns := strings.Split(endpoint, "/")
+		if len(patterns) != len(segments) {
+			continue
+		}
+		values := map[string]string{}
+		for i, pattern := range patterns {
+			if strings.HasPrefix(pattern, "{") && strings.HasSuffix(pattern, "}") {
+				values[strings.Trim(pattern, "{}")] = segments[i]
+			} else if pattern != segments[i] {
+				values = nil
+				break
+			}
+		}
+		if values != nil {
+			for name, value := range values {
+				r.SetPathValue(name, value)
+			}
+			return h, true
+		}
+	}
+	return handler{}, false
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h, ok := s.route(r)
+	if !ok {
+		writeError(w, r, notFound("Unknown url : %s", r.URL.Path))
+		return
+	}
+	if r.Method != h.method && !(h.method == http.MethodGet && r.Method == http.MethodPost) {
+		writeError(w, r, &apiError{status: http.StatusMethodNotAllowed})
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		writeError(w, r, badRequest("%s", err))
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	login, ok := s.authenticate(r)
+	if !ok && !h.anonymous {
+		writeError(w, r, &apiError{status: http.StatusUnauthorized})
+		return
+	}
+	if err := s.checkOrganization(r); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	response, err := h.serve(&request{Request: r, login: login})
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if response == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// organizationScopedServices are the web services whose actions require an organization on SonarCloud
+var organizationScopedServices = []string{
+	"api/permissions/",
+	"api/projects/",
+	"api/qualitygates/",
+	"api/qualityprofiles/",
+	"api/rules/",
+	"api/user_groups/",
+	"api/webhooks/",
+}
+
+// checkOrganization returns an error when the server behaves like SonarCloud and a request to an organization
+// scoped web service does not send its organization
+func (s *Server) checkOrganization(r *http.Request) error {
+	if s.options.Organization == "" {
+		return nil
+	}
+	for _, prefix := range organizationScopedServices {
+		if strings.HasPrefix(strings.TrimPrefix(r.URL.Path, "/"), prefix) {
+			switch organization := r.Form.Get("organization"); organization {
+			case s.options.Organization:
+				return nil
+			case "":
+				return badRequest("The 'organization' parameter is missing")
+			default:
+				return notFound("No organization with key '%s'", organization)
+			}
+		}
+	}
+	return nil
+}
+
+// authenticate returns the login of the user making the request. Like SonarQube, it accepts the credentials of a
+// user or a token with basic authentication, and a token with bearer authentication.
+func (s *Server) authenticate(r *http.Request) (string, bool) {
+	scheme, credentials, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+	switch strings.ToLower(scheme) {
+	case "basic":
+		decoded, err := base64.StdEncoding.DecodeString(credentials)
+		if err != nil {
+			return "", false
+		}
+		login, password, _ := strings.Cut(string(decoded), ":")
+		if password == "" {
+			return s.tokenLogin(login)
+		}
+		u := s.findUser(login)
+		return login, u != nil && u.Active && u.password == password
+	case "bearer":
+		return s.tokenLogin(credentials)
+	}
+	return "", false
+}
+
+func (s *Server) tokenLogin(value string) (string, bool) {
+	for _, t := range s.tokens {
+		if t.value == value {
+			return t.Login, true
+		}
+	}
+	return "", false
+}
+
+// newID returns a unique identifier shaped like the uuids of SonarQube
+func (s *Server) newID() string {
+	s.nextID++
+	return fmt.Sprintf("AY%018d", s.nextID)
+}
+
+// now returns the current time in the format of the dates returned by SonarQube
+func now() string {
+	return time.Now().Format("2006-01-02T15:04:05-0700")
+}
+
+// request gives access to the parameters of a request, either in the query string or form encoded in the body
+type request struct {
+	*http.Request
+	login string
+}
+
+func (r *request) param(name string) string {
+	return r.Form.Get(name)
+}
+
+func (r *request) params(name string) []string {
+	return r.Form[name]
+}
+
+// has reports whether the parameter was sent, even with an empty value
+func (r *request) has(name string) bool {
+	_, ok := r.Form[name]
+	return ok
+}
+
+func (r *request) required(name string) (string, error) {
+	value := r.param(name)
+	if value == "" {
+		return "", badRequest("The '%s' parameter is missing", name)
+	}
+	return value, nil
+}
+
+func (r *request) boolean(name string, defaultValue bool) (bool, error) {
+	value := r.param(name)
+	if value == "" {
+		return defaultValue, nil
+	}
+	switch strings.ToLower(value) {
+	case "true", "yes":
+		return true, nil
+	case "false", "no":
+		return false, nil
+	}
+	return false, badRequest("Value of parameter '%s' (%s) must be one of: [true, false, yes, no]", name, value)
+}
+
+func (r *request) oneOf(name string, defaultValue string, values ...string) (string, error) {
+	value := r.param(name)
+	if value == "" {
+		return defaultValue, nil
+	}
+	for _, v := range values {
+		if value == v {
+			return value, nil
+		}
+	}
+	return "", badRequest("Value of parameter '%s' (%s) must be one of: [%s]", name, value, strings.Join(values, ", "))
+}
+
+// commaSeparated returns the values of a parameter given as a comma separated list
+func (r *request) commaSeparated(name string) []string {
+	var values []string
+	for _, value := range strings.Split(r.param(name), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+type paging struct {
+	PageIndex int `json:"pageIndex"`
+	PageSize  int `json:"pageSize"`
+	Total     int `json:"total"`
+}
+
+// paginate returns the page of items requested with the p and ps parameters
+func paginate[T any](r *request, items []T, defaultPageSize int) ([]T, paging, error) {
+	page, pageSize := 1, defaultPageSize
+	if value := r.param("p"); value != "" {
+		p, err := strconv.Atoi(value)
+		if err != nil || p < 1 {
+			return nil, paging{}, badRequest("'%s' value (p) must be greater than 0", value)
+		}
+		page = p
+	}
+	if value := r.param("ps"); value != "" {
+		ps, err := strconv.Atoi(value)
+		if err != nil || ps < 1 || ps > 500 {
+			return nil, paging{}, badRequest("'ps' value (%s) must be between 1 and 500", value)
+		}
+		pageSize = ps
+	}
+
+	start := (page - 1) * pageSize
+	if start > len(items) {
+		start = len(items)
+	}
+	end := start + pageSize
+	if end > len(items) {
+		end = len(items)
+	}
+	return items[start:end], paging{PageIndex: page, PageSize: pageSize, Total: len(items)}, nil
+}
+
+// matches implements the q parameter of search actions, a case insensitive partial match on any of the values
+func matches(query string, values ...string) bool {
+	if query == "" {
+		return true
+	}
+	for _, value := range values {
+		if strings.Contains(strings.ToLower(value), strings.ToLower(query)) {
+			return true
+		}
+	}
+	return false
+}
+
+// selected implements the selected parameter of actions listing the members of a set: selected, deselected or all
+func selected(r *request, isSelected bool) (bool, error) {
+	switch value, err := r.oneOf("selected", "selected", "selected", "deselected", "all"); {
+	case err != nil:
+		return false, err
+	case value == "all":
+		return true, nil
+	case value == "deselected":
+		return !isSelected, nil
+	default:
+		return isSelected, nil
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// apiError is an error response of the web API
+type apiError struct {
+	status   int
+	messages []string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("%d: %s", e.status, strings.Join(e.messages, "; "))
+}
+
+func badRequest(format string, args ...interface{}) error {
+	return &apiError{status: http.StatusBadRequest, messages: []string{fmt.Sprintf(format, args...)}}
+}
+
+func notFound(format string, args ...interface{}) error {
+	return &apiError{status: http.StatusNotFound, messages: []string{fmt.Sprintf(format, args...)}}
+}
+
+func forbidden() error {
+	return &apiError{status: http.StatusForbidden, messages: []string{"Insufficient privileges"}}
+}
+
+// writeError writes an error response in the format of the web API the request was sent to
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	e, ok := err.(*apiError)
+	if !ok {
+		e = &apiError{status: http.StatusInternalServerError, messages: []string{err.Error()}}
+	}
+
+	if strings.HasPrefix(strings.TrimPrefix(r.URL.Path, "/"), "api/v2/") {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(e.status)
+		if len(e.messages) > 0 {
+			json.NewEncoder(w).Encode(map[string]string{"message": strings.Join(e.messages, "; ")})
+		}
+		return
+	}
+
+	type message struct {
+		Msg string `json:"msg"`
+	}
+	response := struct {
+		Errors []message `json:"errors"`
+	}{}
+	for _, m := range e.messages {
+		response.Errors = append(response.Errors, message{Msg: m})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(e.status)
+	if len(response.Errors) > 0 {
+		json.NewEncoder(w).Encode(response)
+	}
+}
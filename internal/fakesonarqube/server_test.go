@@ -0,0 +1,170 @@
+package fakesonarqube
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/jdamata/terraform-provider-sonarqube/internal/client"
+)
+
+func newTestClient(t *testing.T, s *Server, authenticator client.Authenticator) *client.Client {
+	t.Helper()
+
+	httpClient := retryablehttp.NewClient()
+	httpClient.Logger = nil
+	httpClient.RetryMax = 0
+
+	baseURL, err := url.Parse(s.URL + "/")
+	if err != nil {
+		t.Fatalf("failed to parse fake server url: %+v", err)
+	}
+	return client.New(httpClient, *baseURL, authenticator)
+}
+
+func newTestServer(t *testing.T, options Options) *Server {
+	t.Helper()
+
+	s := NewServer(options)
+	t.Cleanup(s.Close)
+	return s
+}
+
+func TestServerReportsVersionAndEdition(t *testing.T) {
+	s := newTestServer(t, Options{Version: "9.9.4.87374", Edition: "Community"})
+	c := newTestClient(t, s, client.BasicAuth(DefaultLogin, DefaultPassword))
+
+	info, err := c.System.Info(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	if info.System.Version != "9.9.4.87374" || info.System.Edition != "Community" {
+		t.Errorf("info = %+v, want version 9.9.4.87374 and edition Community", info.System)
+	}
+
+	// Portfolios are not available in the Community edition
+	if _, err := c.Views.Show(context.Background(), "portfolio"); !client.IsNotFound(err) {
+		t.Errorf("IsNotFound(%v) = false, want true", err)
+	}
+}
+
+func TestServerAuthentication(t *testing.T) {
+	s := newTestServer(t, Options{})
+
+	var apiError *client.APIError
+	_, err := newTestClient(t, s, client.BasicAuth(DefaultLogin, "wrong")).System.Info(context.Background())
+	if !errors.As(err, &apiError) || apiError.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("err = %v, want status code %d", err, http.StatusUnauthorized)
+	}
+
+	token, err := newTestClient(t, s, client.BasicAuth(DefaultLogin, DefaultPassword)).UserTokens.Generate(context.Background(), client.UserTokensGenerateRequest{Name: "test", Type: "USER_TOKEN"})
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	for _, authenticator := range []client.Authenticator{client.BasicAuth(token.Token, ""), client.BearerAuth(token.Token)} {
+		if _, err := newTestClient(t, s, authenticator).System.Info(context.Background()); err != nil {
+			t.Errorf("unexpected error: %+v", err)
+		}
+	}
+}
+
+func TestServerProjectLifecycle(t *testing.T) {
+	s := newTestServer(t, Options{})
+	c := newTestClient(t, s, client.BasicAuth(DefaultLogin, DefaultPassword))
+	ctx := context.Background()
+
+	if _, err := c.Projects.Create(ctx, client.ProjectsCreateRequest{Name: "Project", Project: "project"}); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	if _, err := c.Projects.Create(ctx, client.ProjectsCreateRequest{Name: "Project", Project: "project"}); err == nil {
+		t.Errorf("creating a project twice succeeded, want an error")
+	}
+	if err := c.Projects.UpdateKey(ctx, "project", "renamed"); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	if err := c.Projects.Delete(ctx, "project"); !client.IsNotFound(err) {
+		t.Errorf("IsNotFound(%v) = false, want true", err)
+	}
+	if err := c.Projects.Delete(ctx, "renamed"); err != nil {
+		t.Errorf("unexpected error: %+v", err)
+	}
+}
+
+func TestServerWebServices(t *testing.T) {
+	s := newTestServer(t, Options{})
+	c := newTestClient(t, s, client.BasicAuth(DefaultLogin, DefaultPassword))
+	ctx := context.Background()
+
+	webServices, err := c.Webservices.List(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	found := false
+	for _, webService := range webServices {
+		for _, action := range webService.Actions {
+			if webService.Path == "api/users" && action.Key == "deactivate" {
+				found = action.Post && len(action.Params) == 2
+			}
+		}
+	}
+	if !found {
+		t.Errorf("webServices = %+v, want api/users/deactivate with its parameters", webServices)
+	}
+
+	document, err := c.Webservices.APIDocs(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	if _, ok := document.Paths["/api/v2/users-management/users/{id}"]["delete"]; !ok {
+		t.Errorf("paths = %v, want DELETE /api/v2/users-management/users/{id}", document.Paths)
+	}
+
+	// The v2 web API is not available before SonarQube 10
+	old := newTestClient(t, newTestServer(t, Options{Version: "9.9.4.87374"}), client.BasicAuth(DefaultLogin, DefaultPassword))
+	if _, err := old.Webservices.APIDocs(ctx); !client.IsNotFound(err) {
+		t.Errorf("IsNotFound(%v) = false, want true", err)
+	}
+}
+
+func TestServerUsersManagement(t *testing.T) {
+	s := newTestServer(t, Options{})
+	c := newTestClient(t, s, client.BasicAuth(DefaultLogin, DefaultPassword))
+	ctx := context.Background()
+
+	if _, err := c.Users.Create(ctx, client.UsersCreateRequest{Login: "john", Name: "John", Password: "secret-password", Local: true}); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	user, err := c.UsersManagement.FindUser(ctx, "john")
+	if err != nil || user == nil {
+		t.Fatalf("FindUser() = %+v, %v, want the user", user, err)
+	}
+	if err := c.UsersManagement.DeactivateUser(ctx, user.ID, true); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	if user, err := c.UsersManagement.FindUser(ctx, "john"); err != nil || user != nil {
+		t.Errorf("FindUser() = %+v, %v, want no user", user, err)
+	}
+	if err := c.UsersManagement.DeactivateUser(ctx, user.ID, true); !client.IsNotFound(err) {
+		t.Errorf("IsNotFound(%v) = false, want true", err)
+	}
+}
+
+func TestServerOrganization(t *testing.T) {
+	s := newTestServer(t, Options{Organization: "my-org"})
+	c := newTestClient(t, s, client.BasicAuth(DefaultLogin, DefaultPassword))
+	ctx := context.Background()
+
+	if _, err := c.System.Info(ctx); !client.IsNotFound(err) {
+		t.Errorf("IsNotFound(%v) = false, want true", err)
+	}
+	if _, err := c.Projects.Create(ctx, client.ProjectsCreateRequest{Name: "Project", Project: "project"}); err == nil {
+		t.Errorf("creating a project without organization succeeded, want an error")
+	}
+	c.SetOrganization("my-org")
+	if _, err := c.Projects.Create(ctx, client.ProjectsCreateRequest{Name: "Project", Project: "project"}); err != nil {
+		t.Errorf("unexpected error: %+v", err)
+	}
+}
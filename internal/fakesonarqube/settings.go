@@ -0,0 +1,167 @@
+package fakesonarqube
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// setting is the value of a setting, either global or set on a component. Only one of Value, Values and
+// FieldValues is set.
+type setting struct {
+	Key         string
+	Value       string
+	Values      []string
+	FieldValues []map[string]string
+
+	component string
+}
+
+func (s *Server) registerSettings() {
+	s.handle(http.MethodGet, "api/settings/values", s.settingValues)
+	s.handle(http.MethodPost, "api/settings/set", s.setSetting)
+	s.handle(http.MethodPost, "api/settings/reset", s.resetSettings)
+}
+
+func (s *Server) findSetting(key string, component string) *setting {
+	for _, setting := range s.settings {
+		if setting.Key == key && setting.component == component {
+			return setting
+		}
+	}
+	return nil
+}
+
+// settingComponent returns the key of the component a request applies to, or an empty string for global settings
+func (s *Server) settingComponent(r *request) (string, error) {
+	key := r.param("component")
+	if key == "" {
+		return "", nil
+	}
+	c, err := s.component(key)
+	if err != nil {
+		return "", err
+	}
+	return c.Key, nil
+}
+
+// settingValues returns the settings of a component, including the global settings it inherits. Like SonarQube,
+// secured settings are left out of settings and only their keys are listed in setSecuredSettings.
+func (s *Server) settingValues(r *request) (interface{}, error) {
+	component, err := s.settingComponent(r)
+	if err != nil {
+		return nil, err
+	}
+
+	effective := map[string]*setting{}
+	inherited := map[string]bool{}
+	for _, setting := range s.settings {
+		if setting.component == "" && effective[setting.Key] == nil {
+			effective[setting.Key], inherited[setting.Key] = setting, component != ""
+		}
+		if component != "" && setting.component == component {
+			effective[setting.Key], inherited[setting.Key] = setting, false
+		}
+	}
+
+	keys := r.commaSeparated("keys")
+	if len(keys) == 0 {
+		keys = sortedKeys(effective)
+	}
+
+	settings := []map[string]interface{}{}
+	secured := []string{}
+	for _, key := range keys {
+		setting, ok := effective[key]
+		if !ok {
+			continue
+		}
+		// Secured settings are only listed by key in setSecuredSettings
+		if strings.HasSuffix(key, ".secured") {
+			secured = append(secured, key)
+			continue
+		}
+		response := map[string]interface{}{"key": key, "inherited": inherited[key]}
+		switch {
+		case setting.Value != "":
+			response["value"] = setting.Value
+		case setting.Values != nil:
+			response["values"] = setting.Values
+		default:
+			response["fieldValues"] = setting.FieldValues
+		}
+		settings = append(settings, response)
+	}
+	return map[string]interface{}{"settings": settings, "setSecuredSettings": secured}, nil
+}
+
+func (s *Server) setSetting(r *request) (interface{}, error) {
+	key, err := r.required("key")
+	if err != nil {
+		return nil, err
+	}
+	component, err := s.settingComponent(r)
+	if err != nil {
+		return nil, err
+	}
+
+	value := &setting{Key: key, component: component}
+	provided := 0
+	if r.has("value") {
+		value.Value = r.param("value")
+		provided++
+		if value.Value == "" {
+			return nil, badRequest("A non empty value must be provided")
+		}
+	}
+	if r.has("values") {
+		value.Values = r.params("values")
+		provided++
+	}
+	if r.has("fieldValues") {
+		provided++
+		for _, encoded := range r.params("fieldValues") {
+			fields := map[string]string{}
+			if err := json.Unmarshal([]byte(encoded), &fields); err != nil {
+				return nil, badRequest("JSON '%s' does not respect expected format for setting '%s'. Ex: {\"field1\":\"value1\", \"field2\":\"value2\"}", encoded, key)
+			}
+			value.FieldValues = append(value.FieldValues, fields)
+		}
+	}
+	if provided != 1 {
+		return nil, badRequest("Either 'value', 'values' or 'fieldValues' must be provided")
+	}
+
+	if existing := s.findSetting(key, component); existing != nil {
+		*existing = *value
+		return nil, nil
+	}
+	s.settings = append(s.settings, value)
+	sort.SliceStable(s.settings, func(i, j int) bool { return s.settings[i].Key < s.settings[j].Key })
+	return nil, nil
+}
+
+func (s *Server) resetSettings(r *request) (interface{}, error) {
+	keys := r.commaSeparated("keys")
+	if len(keys) == 0 {
+		return nil, badRequest("The 'keys' parameter is missing")
+	}
+	component, err := s.settingComponent(r)
+	if err != nil {
+		return nil, err
+	}
+
+	reset := map[string]bool{}
+	for _, key := range keys {
+		reset[key] = true
+	}
+	settings := s.settings[:0]
+	for _, setting := range s.settings {
+		if setting.component != component || !reset[setting.Key] {
+			settings = append(settings, setting)
+		}
+	}
+	s.settings = settings
+	return nil, nil
+}
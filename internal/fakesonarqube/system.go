@@ -0,0 +1,38 @@
+package fakesonarqube
+
+import (
+	"net/http"
+)
+
+func (s *Server) registerSystem() {
+	s.handleAnonymous(http.MethodGet, "api/system/status", s.systemStatus)
+	s.handle(http.MethodGet, "api/system/health", s.systemHealth)
+	// SonarCloud does not report its version and edition
+	if s.options.Organization == "" {
+		s.handle(http.MethodGet, "api/system/info", s.systemInfo)
+	}
+}
+
+func (s *Server) systemStatus(r *request) (interface{}, error) {
+	return map[string]string{
+		"id":      "fake-sonarqube",
+		"version": s.options.Version,
+		"status":  "UP",
+	}, nil
+}
+
+func (s *Server) systemHealth(r *request) (interface{}, error) {
+	return map[string]interface{}{
+		"health": "GREEN",
+		"causes": []string{},
+	}, nil
+}
+
+func (s *Server) systemInfo(r *request) (interface{}, error) {
+	return map[string]interface{}{
+		"System": map[string]interface{}{
+			"Version": s.options.Version,
+			"Edition": s.options.Edition,
+		},
+	}, nil
+}
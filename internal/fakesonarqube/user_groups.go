@@ -0,0 +1,216 @@
+package fakesonarqube
+
+import (
+	"net/http"
+	"sort"
+)
+
+type group struct {
+	ID          string
+	Name        string
+	Description string
+	Default     bool
+
+	members map[string]bool
+}
+
+func (g *group) response() map[string]interface{} {
+	return map[string]interface{}{
+		"id":           g.ID,
+		"name":         g.Name,
+		"description":  g.Description,
+		"membersCount": len(g.members),
+		"default":      g.Default,
+	}
+}
+
+func (s *Server) registerUserGroups() {
+	s.groups = []*group{
+		{ID: s.newID(), Name: "sonar-administrators", Description: "System administrators", members: map[string]bool{s.options.Login: true}},
+		{ID: s.newID(), Name: "sonar-users", Description: "Every authenticated user automatically belongs to this group", Default: true, members: map[string]bool{s.options.Login: true}},
+	}
+
+	s.handle(http.MethodPost, "api/user_groups/create", s.createGroup)
+	s.handle(http.MethodGet, "api/user_groups/search", s.searchGroups)
+	s.handle(http.MethodPost, "api/user_groups/update", s.updateGroup)
+	s.handle(http.MethodPost, "api/user_groups/delete", s.deleteGroup)
+	s.handle(http.MethodPost, "api/user_groups/add_user", s.addGroupMember)
+	s.handle(http.MethodPost, "api/user_groups/remove_user", s.removeGroupMember)
+	s.handle(http.MethodGet, "api/user_groups/users", s.groupMembers)
+}
+
+func (s *Server) findGroup(name string) *group {
+	for _, g := range s.groups {
+		if g.Name == name {
+			return g
+		}
+	}
+	return nil
+}
+
+func (s *Server) existingGroup(name string) (*group, error) {
+	if g := s.findGroup(name); g != nil {
+		return g, nil
+	}
+	return nil, notFound("No group with name '%s'", name)
+}
+
+func (s *Server) createGroup(r *request) (interface{}, error) {
+	name, err := r.required("name")
+	if err != nil {
+		return nil, err
+	}
+	if name == "Anyone" {
+		return nil, badRequest("Anyone group cannot be used")
+	}
+	if s.findGroup(name) != nil {
+		return nil, badRequest("Group '%s' already exists", name)
+	}
+
+	g := &group{ID: s.newID(), Name: name, Description: r.param("description"), members: map[string]bool{}}
+	s.groups = append(s.groups, g)
+	return map[string]interface{}{"group": g.response()}, nil
+}
+
+func (s *Server) searchGroups(r *request) (interface{}, error) {
+	groups := []map[string]interface{}{}
+	for _, g := range s.groups {
+		if matches(r.param("q"), g.Name) {
+			groups = append(groups, g.response())
+		}
+	}
+
+	page, p, err := paginate(r, groups, 100)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"paging": p, "groups": page}, nil
+}
+
+func (s *Server) updateGroup(r *request) (interface{}, error) {
+	currentName, err := r.required("currentName")
+	if err != nil {
+		return nil, err
+	}
+	g, err := s.existingGroup(currentName)
+	if err != nil {
+		return nil, err
+	}
+
+	if name := r.param("name"); name != "" && name != g.Name {
+		if g.Default {
+			return nil, badRequest("Default group '%s' cannot be used to perform this action", g.Name)
+		}
+		if s.findGroup(name) != nil {
+			return nil, badRequest("Group '%s' already exists", name)
+		}
+		s.renameGroupPermissions(g.Name, name)
+		g.Name = name
+	}
+	if r.has("description") {
+		g.Description = r.param("description")
+	}
+	return map[string]interface{}{"group": g.response()}, nil
+}
+
+func (s *Server) deleteGroup(r *request) (interface{}, error) {
+	name, err := r.required("name")
+	if err != nil {
+		return nil, err
+	}
+	g, err := s.existingGroup(name)
+	if err != nil {
+		return nil, err
+	}
+	if g.Default {
+		return nil, badRequest("Default group '%s' cannot be used to perform this action", g.Name)
+	}
+	if g.Name == "sonar-administrators" {
+		return nil, badRequest("The last system admin group cannot be deleted")
+	}
+
+	for i := range s.groups {
+		if s.groups[i] == g {
+			s.groups = append(s.groups[:i], s.groups[i+1:]...)
+			break
+		}
+	}
+	s.renameGroupPermissions(g.Name, "")
+	for _, gate := range s.qualityGates {
+		delete(gate.groups, g.Name)
+	}
+	return nil, nil
+}
+
+func (s *Server) addGroupMember(r *request) (interface{}, error) {
+	g, u, err := s.groupMembership(r)
+	if err != nil {
+		return nil, err
+	}
+	g.members[u.Login] = true
+	return nil, nil
+}
+
+func (s *Server) removeGroupMember(r *request) (interface{}, error) {
+	g, u, err := s.groupMembership(r)
+	if err != nil {
+		return nil, err
+	}
+	if g.Default {
+		return nil, badRequest("Default group '%s' cannot be used to perform this action", g.Name)
+	}
+	delete(g.members, u.Login)
+	return nil, nil
+}
+
+func (s *Server) groupMembership(r *request) (*group, *user, error) {
+	name, err := r.required("name")
+	if err != nil {
+		return nil, nil, err
+	}
+	login, err := r.required("login")
+	if err != nil {
+		return nil, nil, err
+	}
+	g, err := s.existingGroup(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	u, err := s.activeUser(login)
+	if err != nil {
+		return nil, nil, err
+	}
+	return g, u, nil
+}
+
+func (s *Server) groupMembers(r *request) (interface{}, error) {
+	name, err := r.required("name")
+	if err != nil {
+		return nil, err
+	}
+	g, err := s.existingGroup(name)
+	if err != nil {
+		return nil, err
+	}
+
+	members := []map[string]interface{}{}
+	for _, u := range s.users {
+		if !u.Active || !matches(r.param("q"), u.Login, u.Name) {
+			continue
+		}
+		include, err := selected(r, g.members[u.Login])
+		if err != nil {
+			return nil, err
+		}
+		if include {
+			members = append(members, map[string]interface{}{"login": u.Login, "name": u.Name, "selected": g.members[u.Login]})
+		}
+	}
+	sort.Slice(members, func(i, j int) bool { return members[i]["login"].(string) < members[j]["login"].(string) })
+
+	page, p, err := paginate(r, members, 25)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"paging": p, "users": page}, nil
+}
@@ -0,0 +1,359 @@
+package fakesonarqube
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"time"
+)
+
+type user struct {
+	Login            string `json:"login"`
+	Name             string `json:"name"`
+	Email            string `json:"email,omitempty"`
+	Active           bool   `json:"active"`
+	Local            bool   `json:"local"`
+	ExternalIdentity string `json:"externalIdentity,omitempty"`
+	ExternalProvider string `json:"externalProvider,omitempty"`
+
+	// id identifies the user in the v2 web API
+	id       string
+	password string
+}
+
+type token struct {
+	Login          string        `json:"login"`
+	Name           string        `json:"name"`
+	Type           string        `json:"type"`
+	CreatedAt      string        `json:"createdAt"`
+	ExpirationDate string        `json:"expirationDate,omitempty"`
+	Project        *tokenProject `json:"project,omitempty"`
+
+	value string
+}
+
+type tokenProject struct {
+	Key  string `json:"key"`
+	Name string `json:"name"`
+}
+
+func (s *Server) registerUsers() {
+	s.users = []*user{{
+		Login:    s.options.Login,
+		Name:     "Administrator",
+		Active:   true,
+		Local:    true,
+		id:       s.newID(),
+		password: s.options.Password,
+	}}
+
+	s.handle(http.MethodPost, "api/users/create", s.createUser)
+	s.handle(http.MethodGet, "api/users/search", s.searchUsers)
+	s.handle(http.MethodPost, "api/users/update", s.updateUser)
+	s.handle(http.MethodPost, "api/users/change_password", s.changePassword)
+	s.handle(http.MethodPost, "api/users/deactivate", s.deactivateUser)
+	s.handle(http.MethodPost, "api/users/update_identity_provider", s.updateIdentityProvider)
+}
+
+func (s *Server) registerUserTokens() {
+	s.handle(http.MethodPost, "api/user_tokens/generate", s.generateToken)
+	s.handle(http.MethodGet, "api/user_tokens/search", s.searchTokens)
+	s.handle(http.MethodPost, "api/user_tokens/revoke", s.revokeToken)
+}
+
+func (s *Server) findUser(login string) *user {
+	for _, u := range s.users {
+		if u.Login == login {
+			return u
+		}
+	}
+	return nil
+}
+
+// activeUser returns the active user with the given login
+func (s *Server) activeUser(login string) (*user, error) {
+	if u := s.findUser(login); u != nil && u.Active {
+		return u, nil
+	}
+	return nil, notFound("User with login '%s' has not been found", login)
+}
+
+func (s *Server) createUser(r *request) (interface{}, error) {
+	login, err := r.required("login")
+	if err != nil {
+		return nil, err
+	}
+	name, err := r.required("name")
+	if err != nil {
+		return nil, err
+	}
+	local, err := r.boolean("local", true)
+	if err != nil {
+		return nil, err
+	}
+	password := r.param("password")
+	if local && password == "" {
+		return nil, badRequest("Password is mandatory and must not be empty")
+	}
+	if !local && password != "" {
+		return nil, badRequest("Password should only be set on local user")
+	}
+
+	u := s.findUser(login)
+	switch {
+	case u != nil && u.Active:
+		return nil, badRequest("An active user with login '%s' already exists", login)
+	case u != nil:
+		// Like SonarQube, creating a deactivated user reactivates it
+		u.Name, u.Email, u.Local, u.password, u.Active = name, r.param("email"), local, password, true
+	default:
+		u = &user{Login: login, Name: name, Email: r.param("email"), Active: true, Local: local, id: s.newID(), password: password}
+		s.users = append(s.users, u)
+	}
+	if group := s.findGroup("sonar-users"); group != nil {
+		group.members[login] = true
+	}
+
+	return map[string]interface{}{"user": u}, nil
+}
+
+func (s *Server) searchUsers(r *request) (interface{}, error) {
+	deactivated, err := r.boolean("deactivated", false)
+	if err != nil {
+		return nil, err
+	}
+
+	users := []*user{}
+	for _, u := range s.users {
+		if u.Active != deactivated && matches(r.param("q"), u.Login, u.Name, u.Email) {
+			users = append(users, u)
+		}
+	}
+
+	page, p, err := paginate(r, users, 50)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"paging": p, "users": page}, nil
+}
+
+func (s *Server) updateUser(r *request) (interface{}, error) {
+	login, err := r.required("login")
+	if err != nil {
+		return nil, err
+	}
+	u, err := s.activeUser(login)
+	if err != nil {
+		return nil, err
+	}
+	if r.has("name") {
+		u.Name = r.param("name")
+	}
+	if r.has("email") {
+		u.Email = r.param("email")
+	}
+	return map[string]interface{}{"user": u}, nil
+}
+
+func (s *Server) changePassword(r *request) (interface{}, error) {
+	login, err := r.required("login")
+	if err != nil {
+		return nil, err
+	}
+	password, err := r.required("password")
+	if err != nil {
+		return nil, err
+	}
+	u, err := s.activeUser(login)
+	if err != nil {
+		return nil, err
+	}
+	if !u.Local {
+		return nil, badRequest("Password cannot be changed when external authentication is used")
+	}
+	u.password = password
+	return nil, nil
+}
+
+func (s *Server) deactivateUser(r *request) (interface{}, error) {
+	login, err := r.required("login")
+	if err != nil {
+		return nil, err
+	}
+	if login == r.login {
+		return nil, badRequest("Self-deactivation is not possible")
+	}
+	u, err := s.activeUser(login)
+	if err != nil {
+		return nil, err
+	}
+	anonymize, err := r.boolean("anonymize", false)
+	if err != nil {
+		return nil, err
+	}
+
+	s.deactivate(u, anonymize)
+	return map[string]interface{}{"user": u}, nil
+}
+
+// deactivate removes a user from its groups, permissions and tokens, and anonymizes it if requested
+func (s *Server) deactivate(u *user, anonymize bool) {
+	login := u.Login
+	u.Active = false
+	for _, g := range s.groups {
+		delete(g.members, login)
+	}
+	for _, holders := range s.permissions {
+		delete(holders.users, login)
+	}
+	tokens := s.tokens[:0]
+	for _, t := range s.tokens {
+		if t.Login != login {
+			tokens = append(tokens, t)
+		}
+	}
+	s.tokens = tokens
+
+	if anonymize {
+		u.Login, u.Name, u.Email = "sq-removed-"+s.newID(), "", ""
+	}
+}
+
+func (s *Server) updateIdentityProvider(r *request) (interface{}, error) {
+	login, err := r.required("login")
+	if err != nil {
+		return nil, err
+	}
+	provider, err := r.required("newExternalProvider")
+	if err != nil {
+		return nil, err
+	}
+	u, err := s.activeUser(login)
+	if err != nil {
+		return nil, err
+	}
+
+	identity := r.param("newExternalIdentity")
+	if identity == "" {
+		identity = login
+	}
+	u.ExternalProvider, u.ExternalIdentity = provider, identity
+	return nil, nil
+}
+
+func (s *Server) generateToken(r *request) (interface{}, error) {
+	name, err := r.required("name")
+	if err != nil {
+		return nil, err
+	}
+	tokenType, err := r.oneOf("type", "USER_TOKEN", "USER_TOKEN", "GLOBAL_ANALYSIS_TOKEN", "PROJECT_ANALYSIS_TOKEN")
+	if err != nil {
+		return nil, err
+	}
+	login := r.param("login")
+	if login == "" {
+		login = r.login
+	}
+	if _, err := s.activeUser(login); err != nil {
+		return nil, err
+	}
+	for _, t := range s.tokens {
+		if t.Login == login && t.Name == name {
+			return nil, badRequest("A user token for login '%s' and name '%s' already exists", login, name)
+		}
+	}
+
+	t := &token{
+		Login:     login,
+		Name:      name,
+		Type:      tokenType,
+		CreatedAt: now(),
+		value:     newTokenValue(tokenType),
+	}
+	if value := r.param("expirationDate"); value != "" {
+		expirationDate, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return nil, badRequest("The date '%s' does not respect format 'yyyy-MM-dd'", value)
+		}
+		if !expirationDate.After(time.Now()) {
+			return nil, badRequest("The minimum value for parameter 'expirationDate' is %s.", time.Now().AddDate(0, 0, 1).Format("2006-01-02"))
+		}
+		t.ExpirationDate = expirationDate.Format("2006-01-02T15:04:05-0700")
+	}
+	if tokenType == "PROJECT_ANALYSIS_TOKEN" {
+		projectKey, err := r.required("projectKey")
+		if err != nil {
+			return nil, err
+		}
+		p, err := s.component(projectKey)
+		if err != nil {
+			return nil, err
+		}
+		t.Project = &tokenProject{Key: p.Key, Name: p.Name}
+	}
+	s.tokens = append(s.tokens, t)
+
+	response := map[string]interface{}{
+		"login":     t.Login,
+		"name":      t.Name,
+		"token":     t.value,
+		"type":      t.Type,
+		"createdAt": t.CreatedAt,
+	}
+	if t.ExpirationDate != "" {
+		response["expirationDate"] = t.ExpirationDate
+	}
+	if t.Project != nil {
+		response["projectKey"] = t.Project.Key
+	}
+	return response, nil
+}
+
+// newTokenValue returns a random token value with the prefix SonarQube uses for its type
+func newTokenValue(tokenType string) string {
+	prefix := map[string]string{
+		"USER_TOKEN":             "squ_",
+		"GLOBAL_ANALYSIS_TOKEN":  "sqa_",
+		"PROJECT_ANALYSIS_TOKEN": "sqp_",
+	}[tokenType]
+	b := make([]byte, 20)
+	rand.Read(b)
+	return prefix + hex.EncodeToString(b)
+}
+
+func (s *Server) searchTokens(r *request) (interface{}, error) {
+	login := r.param("login")
+	if login == "" {
+		login = r.login
+	}
+	if _, err := s.activeUser(login); err != nil {
+		return nil, err
+	}
+
+	tokens := []*token{}
+	for _, t := range s.tokens {
+		if t.Login == login {
+			tokens = append(tokens, t)
+		}
+	}
+	return map[string]interface{}{"login": login, "userTokens": tokens}, nil
+}
+
+func (s *Server) revokeToken(r *request) (interface{}, error) {
+	name, err := r.required("name")
+	if err != nil {
+		return nil, err
+	}
+	login := r.param("login")
+	if login == "" {
+		login = r.login
+	}
+
+	for i, t := range s.tokens {
+		if t.Login == login && t.Name == name {
+			s.tokens = append(s.tokens[:i], s.tokens[i+1:]...)
+			return nil, nil
+		}
+	}
+	return nil, notFound("User token with name '%s' doesn't exist for user '%s'", name, login)
+}
@@ -0,0 +1,76 @@
+package fakesonarqube
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// managedUser is a user as returned by api/v2/users-management
+type managedUser struct {
+	ID     string `json:"id"`
+	Login  string `json:"login"`
+	Name   string `json:"name"`
+	Email  string `json:"email,omitempty"`
+	Active bool   `json:"active"`
+	Local  bool   `json:"local"`
+}
+
+// registerUsersManagement registers the subset of api/v2/users-management used by the provider. Like the v2 web
+// API, it is only available on SonarQube 10 and newer.
+func (s *Server) registerUsersManagement() {
+	if !s.atLeast("10.0") {
+		return
+	}
+	s.handle(http.MethodGet, "api/v2/users-management/users", s.searchManagedUsers)
+	s.handle(http.MethodDelete, "api/v2/users-management/users/{id}", s.deactivateManagedUser)
+}
+
+func (s *Server) searchManagedUsers(r *request) (interface{}, error) {
+	active, err := r.boolean("active", true)
+	if err != nil {
+		return nil, err
+	}
+	pageSize := 50
+	if value := r.param("pageSize"); value != "" {
+		if pageSize, err = strconv.Atoi(value); err != nil || pageSize < 1 || pageSize > 500 {
+			return nil, badRequest("Value of parameter 'pageSize' (%s) must be between 1 and 500", value)
+		}
+	}
+
+	pageIndex := 1
+	if value := r.param("pageIndex"); value != "" {
+		if pageIndex, err = strconv.Atoi(value); err != nil || pageIndex < 1 {
+			return nil, badRequest("Value of parameter 'pageIndex' (%s) must be a strictly positive integer", value)
+		}
+	}
+
+	users := []managedUser{}
+	for _, u := range s.users {
+		if u.Active == active && matches(r.param("q"), u.Login, u.Name, u.Email) {
+			users = append(users, managedUser{ID: u.id, Login: u.Login, Name: u.Name, Email: u.Email, Active: u.Active, Local: u.Local})
+		}
+	}
+	total := len(users)
+	start := min((pageIndex-1)*pageSize, total)
+	return map[string]interface{}{
+		"users": users[start:min(start+pageSize, total)],
+		"page":  map[string]int{"pageIndex": pageIndex, "pageSize": pageSize, "total": total},
+	}, nil
+}
+
+func (s *Server) deactivateManagedUser(r *request) (interface{}, error) {
+	anonymize, err := r.boolean("anonymize", false)
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range s.users {
+		if u.id == r.PathValue("id") && u.Active {
+			if u.Login == r.login {
+				return nil, badRequest("Self-deactivation is not possible")
+			}
+			s.deactivate(u, anonymize)
+			return nil, nil
+		}
+	}
+	return nil, notFound("User '%s' not found", r.PathValue("id"))
+}
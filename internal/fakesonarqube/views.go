@@ -0,0 +1,390 @@
+package fakesonarqube
+
+import (
+	"net/http"
+	"regexp"
+	"sort"
+)
+
+// portfolio is a portfolio of the Enterprise edition. Its projects are selected according to its selection mode.
+type portfolio struct {
+	*project
+
+	selectionMode    string
+	tags             []string
+	regexp           string
+	branch           string
+	selectedProjects map[string][]string
+	subViews         []subView
+}
+
+// subView is a child of a portfolio: a reference to another portfolio (VW), a local view (SVW) or an application (APP)
+type subView struct {
+	key       string
+	qualifier string
+}
+
+func (p *portfolio) response() map[string]interface{} {
+	response := map[string]interface{}{
+		"key":           p.Key,
+		"name":          p.Name,
+		"desc":          p.Description,
+		"qualifier":     p.Qualifier,
+		"visibility":    p.Visibility,
+		"selectionMode": p.selectionMode,
+	}
+	switch p.selectionMode {
+	case "MANUAL":
+		selectedProjects := []map[string]interface{}{}
+		for _, key := range sortedKeys(p.selectedProjects) {
+			selected := map[string]interface{}{"projectKey": key}
+			if branches := p.selectedProjects[key]; len(branches) > 0 {
+				selected["selectedBranches"] = branches
+			}
+			selectedProjects = append(selectedProjects, selected)
+		}
+		response["selectedProjects"] = selectedProjects
+	case "TAGS":
+		response["tags"] = p.tags
+	case "REGEXP":
+		response["regexp"] = p.regexp
+	}
+	if p.branch != "" {
+		response["branch"] = p.branch
+	}
+	return response
+}
+
+func (s *Server) registerViews() {
+	s.handle(http.MethodPost, "api/views/create", s.createPortfolio)
+	s.handle(http.MethodGet, "api/views/show", s.showPortfolio)
+	s.handle(http.MethodPost, "api/views/update", s.updatePortfolio)
+	s.handle(http.MethodPost, "api/views/delete", s.deletePortfolio)
+	s.handle(http.MethodPost, "api/views/set_none_mode", s.setPortfolioMode("NONE"))
+	s.handle(http.MethodPost, "api/views/set_manual_mode", s.setPortfolioMode("MANUAL"))
+	s.handle(http.MethodPost, "api/views/set_tags_mode", s.setPortfolioMode("TAGS"))
+	s.handle(http.MethodPost, "api/views/set_regexp_mode", s.setPortfolioMode("REGEXP"))
+	s.handle(http.MethodPost, "api/views/set_remaining_projects_mode", s.setPortfolioMode("REST"))
+	s.handle(http.MethodPost, "api/views/add_project", s.addPortfolioProject)
+	s.handle(http.MethodPost, "api/views/remove_project", s.removePortfolioProject)
+	s.handle(http.MethodPost, "api/views/add_project_branch", s.addPortfolioProjectBranch)
+	s.handle(http.MethodPost, "api/views/remove_project_branch", s.removePortfolioProjectBranch)
+	s.handle(http.MethodPost, "api/views/add_portfolio", s.addSubView("portfolio", "reference", "VW"))
+	s.handle(http.MethodPost, "api/views/remove_portfolio", s.removeSubView("portfolio", "reference", "VW"))
+	s.handle(http.MethodPost, "api/views/add_local_view", s.addSubView("key", "ref_key", "SVW"))
+	s.handle(http.MethodPost, "api/views/remove_local_view", s.removeSubView("key", "ref_key", "SVW"))
+	s.handle(http.MethodPost, "api/views/add_application", s.addSubView("portfolio", "application", "APP"))
+	s.handle(http.MethodPost, "api/views/remove_application", s.removeSubView("portfolio", "application", "APP"))
+}
+
+func (s *Server) existingPortfolio(key string) (*portfolio, error) {
+	for _, p := range s.portfolios {
+		if p.Key == key {
+			return p, nil
+		}
+	}
+	return nil, notFound("Portfolio '%s' not found", key)
+}
+
+func (s *Server) createPortfolio(r *request) (interface{}, error) {
+	name, err := r.required("name")
+	if err != nil {
+		return nil, err
+	}
+	visibility, err := r.oneOf("visibility", "public", "private", "public")
+	if err != nil {
+		return nil, err
+	}
+	key := r.param("key")
+	if key == "" {
+		key = name
+	}
+	if !projectKeyPattern.MatchString(key) {
+		return nil, badRequest("Malformed key for Portfolio: '%s'. Allowed characters are alphanumeric, '-', '_', '.' and ':', with at least one non-digit.", key)
+	}
+	if _, err := s.component(key); err == nil {
+		return nil, badRequest("Could not create Portfolio with key: \"%s\". A similar key already exists: \"%s\"", key, key)
+	}
+
+	p := &portfolio{
+		project:          &project{Key: key, Name: name, Description: r.param("description"), Qualifier: "VW", Visibility: visibility, CreatedAt: now()},
+		selectionMode:    "NONE",
+		selectedProjects: map[string][]string{},
+	}
+	s.portfolios = append(s.portfolios, p)
+	return p.response(), nil
+}
+
+func (s *Server) showPortfolio(r *request) (interface{}, error) {
+	key, err := r.required("key")
+	if err != nil {
+		return nil, err
+	}
+	p, err := s.existingPortfolio(key)
+	if err != nil {
+		return nil, err
+	}
+
+	response := p.response()
+	subViews := []map[string]interface{}{}
+	for _, child := range p.subViews {
+		if component, err := s.component(child.key); err == nil {
+			subViews = append(subViews, map[string]interface{}{"key": child.key, "name": component.Name, "qualifier": child.qualifier})
+		}
+	}
+	response["subViews"] = subViews
+	return response, nil
+}
+
+func (s *Server) updatePortfolio(r *request) (interface{}, error) {
+	key, err := r.required("key")
+	if err != nil {
+		return nil, err
+	}
+	name, err := r.required("name")
+	if err != nil {
+		return nil, err
+	}
+	p, err := s.existingPortfolio(key)
+	if err != nil {
+		return nil, err
+	}
+	p.Name, p.Description = name, r.param("description")
+	return nil, nil
+}
+
+func (s *Server) deletePortfolio(r *request) (interface{}, error) {
+	key, err := r.required("key")
+	if err != nil {
+		return nil, err
+	}
+	p, err := s.existingPortfolio(key)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range s.portfolios {
+		if s.portfolios[i] == p {
+			s.portfolios = append(s.portfolios[:i], s.portfolios[i+1:]...)
+			break
+		}
+	}
+	s.renameComponent(p.Key, "")
+	return nil, nil
+}
+
+// setPortfolioMode returns the action setting the selection mode of a portfolio. Changing the mode clears the
+// selection of the previous mode.
+func (s *Server) setPortfolioMode(mode string) func(r *request) (interface{}, error) {
+	return func(r *request) (interface{}, error) {
+		key, err := r.required("portfolio")
+		if err != nil {
+			return nil, err
+		}
+		p, err := s.existingPortfolio(key)
+		if err != nil {
+			return nil, err
+		}
+
+		var tags []string
+		var expression string
+		switch mode {
+		case "TAGS":
+			if tags = r.commaSeparated("tags"); len(tags) == 0 {
+				return nil, badRequest("The 'tags' parameter is missing")
+			}
+		case "REGEXP":
+			if expression, err = r.required("regexp"); err != nil {
+				return nil, err
+			}
+			if _, err := regexp.Compile(expression); err != nil {
+				return nil, badRequest("Invalid regular expression: %s", expression)
+			}
+		}
+
+		if mode != "MANUAL" || p.selectionMode != "MANUAL" {
+			p.selectedProjects = map[string][]string{}
+		}
+		p.selectionMode, p.tags, p.regexp = mode, tags, expression
+		p.branch = ""
+		if mode == "TAGS" || mode == "REGEXP" || mode == "REST" {
+			p.branch = r.param("branch")
+		}
+		return nil, nil
+	}
+}
+
+// portfolioProject returns the manual portfolio and the project of a request
+func (s *Server) portfolioProject(r *request) (*portfolio, *project, error) {
+	key, err := r.required("key")
+	if err != nil {
+		return nil, nil, err
+	}
+	projectKey, err := r.required("project")
+	if err != nil {
+		return nil, nil, err
+	}
+	p, err := s.existingPortfolio(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	pr, err := s.existingProject(projectKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	if p.selectionMode != "MANUAL" {
+		return nil, nil, badRequest("Portfolio '%s' is not in manual selection mode", p.Key)
+	}
+	return p, pr, nil
+}
+
+func (s *Server) addPortfolioProject(r *request) (interface{}, error) {
+	p, pr, err := s.portfolioProject(r)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := p.selectedProjects[pr.Key]; ok {
+		return nil, badRequest("Project '%s' is already selected in portfolio '%s'", pr.Key, p.Key)
+	}
+	p.selectedProjects[pr.Key] = nil
+	return nil, nil
+}
+
+func (s *Server) removePortfolioProject(r *request) (interface{}, error) {
+	p, pr, err := s.portfolioProject(r)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := p.selectedProjects[pr.Key]; !ok {
+		return nil, badRequest("Project '%s' is not selected in portfolio '%s'", pr.Key, p.Key)
+	}
+	delete(p.selectedProjects, pr.Key)
+	return nil, nil
+}
+
+// portfolioProjectBranch returns the manual portfolio, the selected project and the branch of a request
+func (s *Server) portfolioProjectBranch(r *request) (*portfolio, *project, string, error) {
+	p, pr, err := s.portfolioProject(r)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	branch, err := r.required("branch")
+	if err != nil {
+		return nil, nil, "", err
+	}
+	if _, ok := p.selectedProjects[pr.Key]; !ok {
+		return nil, nil, "", badRequest("Project '%s' is not selected in portfolio '%s'", pr.Key, p.Key)
+	}
+	if pr.branch(branch) == nil {
+		return nil, nil, "", notFound("Branch '%s' not found for project '%s'", branch, pr.Key)
+	}
+	return p, pr, branch, nil
+}
+
+func (s *Server) addPortfolioProjectBranch(r *request) (interface{}, error) {
+	p, pr, branch, err := s.portfolioProjectBranch(r)
+	if err != nil {
+		return nil, err
+	}
+	if !contains(p.selectedProjects[pr.Key], branch) {
+		p.selectedProjects[pr.Key] = append(p.selectedProjects[pr.Key], branch)
+		sort.Strings(p.selectedProjects[pr.Key])
+	}
+	return nil, nil
+}
+
+func (s *Server) removePortfolioProjectBranch(r *request) (interface{}, error) {
+	p, pr, branch, err := s.portfolioProjectBranch(r)
+	if err != nil {
+		return nil, err
+	}
+	branches := []string{}
+	for _, selected := range p.selectedProjects[pr.Key] {
+		if selected != branch {
+			branches = append(branches, selected)
+		}
+	}
+	p.selectedProjects[pr.Key] = branches
+	return nil, nil
+}
+
+// subViewParams returns the parent portfolio and the key of the child of a request. Portfolios are looked up for the
+// VW and SVW qualifiers, applications for the APP qualifier.
+func (s *Server) subViewParams(r *request, parentParam string, childParam string, qualifier string) (*portfolio, string, error) {
+	key, err := r.required(parentParam)
+	if err != nil {
+		return nil, "", err
+	}
+	childKey, err := r.required(childParam)
+	if err != nil {
+		return nil, "", err
+	}
+	p, err := s.existingPortfolio(key)
+	if err != nil {
+		return nil, "", err
+	}
+	if qualifier == "APP" {
+		if _, err := s.existingApplication(r); err != nil {
+			return nil, "", err
+		}
+	} else if _, err := s.existingPortfolio(childKey); err != nil {
+		return nil, "", err
+	}
+	return p, childKey, nil
+}
+
+// descendant reports whether a portfolio is key, or contains key in its hierarchy
+func (s *Server) descendant(p *portfolio, key string) bool {
+	if p.Key == key {
+		return true
+	}
+	for _, child := range p.subViews {
+		if child.qualifier == "APP" {
+			continue
+		}
+		if c, err := s.existingPortfolio(child.key); err == nil && s.descendant(c, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// addSubView returns the action adding a child to a portfolio. A portfolio can not contain itself, directly or not.
+func (s *Server) addSubView(parentParam string, childParam string, qualifier string) func(r *request) (interface{}, error) {
+	return func(r *request) (interface{}, error) {
+		p, childKey, err := s.subViewParams(r, parentParam, childParam, qualifier)
+		if err != nil {
+			return nil, err
+		}
+		for _, child := range p.subViews {
+			if child.key == childKey {
+				return nil, badRequest("Component '%s' is already part of portfolio '%s'", childKey, p.Key)
+			}
+		}
+		if qualifier != "APP" {
+			c, _ := s.existingPortfolio(childKey)
+			if s.descendant(c, p.Key) {
+				return nil, badRequest("Portfolio '%s' can not be added to portfolio '%s', as it would create a cycle", childKey, p.Key)
+			}
+		}
+		p.subViews = append(p.subViews, subView{key: childKey, qualifier: qualifier})
+		return nil, nil
+	}
+}
+
+// removeSubView returns the action removing a child of the given qualifier from a portfolio
+func (s *Server) removeSubView(parentParam string, childParam string, qualifier string) func(r *request) (interface{}, error) {
+	return func(r *request) (interface{}, error) {
+		p, childKey, err := s.subViewParams(r, parentParam, childParam, qualifier)
+		if err != nil {
+			return nil, err
+		}
+		for i, child := range p.subViews {
+			if child.key == childKey && child.qualifier == qualifier {
+				p.subViews = append(p.subViews[:i], p.subViews[i+1:]...)
+				return nil, nil
+			}
+		}
+		return nil, badRequest("Component '%s' is not part of portfolio '%s'", childKey, p.Key)
+	}
+}
@@ -0,0 +1,150 @@
+package fakesonarqube
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// maxWebhooks is the number of webhooks allowed globally and per project
+const maxWebhooks = 10
+
+type webhook struct {
+	Key  string
+	Name string
+	URL  string
+
+	secret  string
+	project string
+}
+
+func (w *webhook) response() map[string]interface{} {
+	return map[string]interface{}{
+		"key":       w.Key,
+		"name":      w.Name,
+		"url":       w.URL,
+		"hasSecret": w.secret != "",
+	}
+}
+
+func (s *Server) registerWebhooks() {
+	s.handle(http.MethodPost, "api/webhooks/create", s.createWebhook)
+	s.handle(http.MethodGet, "api/webhooks/list", s.listWebhooks)
+	s.handle(http.MethodPost, "api/webhooks/update", s.updateWebhook)
+	s.handle(http.MethodPost, "api/webhooks/delete", s.deleteWebhook)
+}
+
+func (s *Server) existingWebhook(r *request) (*webhook, error) {
+	key, err := r.required("webhook")
+	if err != nil {
+		return nil, err
+	}
+	for _, w := range s.webhooks {
+		if w.Key == key {
+			return w, nil
+		}
+	}
+	return nil, notFound("No webhook with key '%s'", key)
+}
+
+// webhookProject returns the key of the project of a request, or an empty string for global webhooks
+func (s *Server) webhookProject(r *request) (string, error) {
+	key := r.param("project")
+	if key == "" {
+		return "", nil
+	}
+	p, err := s.existingProject(key)
+	if err != nil {
+		return "", err
+	}
+	return p.Key, nil
+}
+
+// webhookParams validates the name, url and secret parameters of create and update requests
+func webhookParams(r *request) (name string, address string, secret string, err error) {
+	if name, err = r.required("name"); err != nil {
+		return
+	}
+	if address, err = r.required("url"); err != nil {
+		return
+	}
+	if u, parseErr := url.Parse(address); parseErr != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		err = badRequest("Url parameter with value '%s' is not a valid url", address)
+		return
+	}
+	secret = r.param("secret")
+	if len(secret) > 0 && len(secret) < 16 {
+		err = badRequest("Secret length must be between 16 and 200 characters")
+	}
+	return
+}
+
+func (s *Server) createWebhook(r *request) (interface{}, error) {
+	name, address, secret, err := webhookParams(r)
+	if err != nil {
+		return nil, err
+	}
+	project, err := s.webhookProject(r)
+	if err != nil {
+		return nil, err
+	}
+
+	count := 0
+	for _, w := range s.webhooks {
+		if w.project == project {
+			count++
+		}
+	}
+	if count >= maxWebhooks {
+		return nil, badRequest("Maximum number of webhook reached for project '%s'", project)
+	}
+
+	w := &webhook{Key: s.newID(), Name: name, URL: address, secret: secret, project: project}
+	s.webhooks = append(s.webhooks, w)
+	return map[string]interface{}{"webhook": w.response()}, nil
+}
+
+func (s *Server) listWebhooks(r *request) (interface{}, error) {
+	project, err := s.webhookProject(r)
+	if err != nil {
+		return nil, err
+	}
+
+	webhooks := []map[string]interface{}{}
+	for _, w := range s.webhooks {
+		if w.project == project {
+			webhooks = append(webhooks, w.response())
+		}
+	}
+	return map[string]interface{}{"webhooks": webhooks}, nil
+}
+
+func (s *Server) updateWebhook(r *request) (interface{}, error) {
+	w, err := s.existingWebhook(r)
+	if err != nil {
+		return nil, err
+	}
+	name, address, secret, err := webhookParams(r)
+	if err != nil {
+		return nil, err
+	}
+
+	w.Name, w.URL = name, address
+	if r.has("secret") {
+		w.secret = secret
+	}
+	return nil, nil
+}
+
+func (s *Server) deleteWebhook(r *request) (interface{}, error) {
+	w, err := s.existingWebhook(r)
+	if err != nil {
+		return nil, err
+	}
+	for i := range s.webhooks {
+		if s.webhooks[i] == w {
+			s.webhooks = append(s.webhooks[:i], s.webhooks[i+1:]...)
+			break
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,98 @@
+package fakesonarqube
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+)
+
+// actionParams lists the parameters returned by api/webservices/list for the actions whose parameters the provider
+// checks. Other actions are listed without parameters.
+func (s *Server) actionParams(endpoint string) []string {
+	switch endpoint {
+	case "api/users/deactivate":
+		return []string{"login", "anonymize"}
+	case "api/qualitygates/create_condition", "api/qualitygates/select", "api/qualitygates/deselect":
+		// SonarQube 8.4 added gateName and deprecated gateId, which SonarQube 10.0 removed
+		params := []string{}
+		if !s.atLeast("10.0") {
+			params = append(params, "gateId")
+		}
+		if s.atLeast("8.4") {
+			params = append(params, "gateName")
+		}
+		return params
+	}
+	return nil
+}
+
+func (s *Server) registerWebservices() {
+	s.handle(http.MethodGet, "api/webservices/list", s.listWebservices)
+	if s.atLeast("10.0") {
+		s.handle(http.MethodGet, "api/v2/api-docs", s.apiDocs)
+	}
+}
+
+// atLeast reports whether the server reports the given version or a newer one
+func (s *Server) atLeast(minimum string) bool {
+	v, err := version.NewVersion(s.options.Version)
+	if err != nil {
+		return true
+	}
+	return !v.LessThan(version.Must(version.NewVersion(minimum)))
+}
+
+// listWebservices describes the actions of the v1 web API implemented by the fake
+func (s *Server) listWebservices(r *request) (interface{}, error) {
+	type param struct {
+		Key string `json:"key"`
+	}
+	type action struct {
+		Key    string  `json:"key"`
+		Post   bool    `json:"post"`
+		Params []param `json:"params"`
+	}
+	type webService struct {
+		Path    string   `json:"path"`
+		Actions []action `json:"actions"`
+	}
+
+	byPath := map[string]*webService{}
+	for _, endpoint := range sortedKeys(s.handlers) {
+		if strings.HasPrefix(endpoint, "api/v2/") {
+			continue
+		}
+		i := strings.LastIndex(endpoint, "/")
+		path, key := endpoint[:i], endpoint[i+1:]
+		if byPath[path] == nil {
+			byPath[path] = &webService{Path: path}
+		}
+		a := action{Key: key, Post: s.handlers[endpoint].method == http.MethodPost, Params: []param{}}
+		for _, p := range s.actionParams(endpoint) {
+			a.Params = append(a.Params, param{Key: p})
+		}
+		byPath[path].Actions = append(byPath[path].Actions, a)
+	}
+
+	webServices := []*webService{}
+	for _, path := range sortedKeys(byPath) {
+		webServices = append(webServices, byPath[path])
+	}
+	return map[string]interface{}{"webServices": webServices}, nil
+}
+
+// apiDocs returns the paths of the OpenAPI document of the v2 web API implemented by the fake
+func (s *Server) apiDocs(r *request) (interface{}, error) {
+	paths := map[string]map[string]interface{}{}
+	for endpoint, h := range s.handlers {
+		if !strings.HasPrefix(endpoint, "api/v2/") || endpoint == "api/v2/api-docs" {
+			continue
+		}
+		if paths["/"+endpoint] == nil {
+			paths["/"+endpoint] = map[string]interface{}{}
+		}
+		paths["/"+endpoint][strings.ToLower(h.method)] = map[string]interface{}{}
+	}
+	return map[string]interface{}{"openapi": "3.0.1", "paths": paths}, nil
+}
@@ -1,24 +1,88 @@
 package main
 
 import (
+	"context"
 	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"regexp"
+	"strings"
 
-	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6/tf6server"
 	"github.com/jdamata/terraform-provider-sonarqube/sonarqube"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "export" {
+		if err := export(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
 
 	var debug bool
 
 	flag.BoolVar(&debug, "debug", false, "set to true to run the provider with support for debuggers like delve")
 	flag.Parse()
 
-	plugin.Serve(
-		&plugin.ServeOpts{
-			Debug:        debug,
-			ProviderAddr: "registry.terraform.io/jdamata/sonarqube",
-			ProviderFunc: sonarqube.Provider,
-		},
-	)
+	providerServerFactory, err := sonarqube.ProtoV6ProviderServerFactory(context.Background(), sonarqube.Provider())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var serveOpts []tf6server.ServeOpt
+	if debug {
+		serveOpts = append(serveOpts, tf6server.WithManagedDebug())
+	}
+
+	err = tf6server.Serve("registry.terraform.io/jdamata/sonarqube", providerServerFactory, serveOpts...)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// export runs the export subcommand, which writes the configuration of an existing SonarQube instance
+func export(args []string) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	directory := flags.String("directory", ".", "directory the generated .tf files are written to")
+	resourceTypes := flags.String("resource-types", "", "comma separated list of the resource types to export, every supported type by default")
+	projects := flags.String("projects", "", "regular expression matching the keys of the projects to export, every project by default")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), `Usage: %s export [options]
+
+Writes the configuration of the resources of a SonarQube instance, along with the import blocks adopting them.
+The provider is configured from the environment variables, e.g. SONAR_HOST and SONAR_TOKEN.
+
+Options:
+`, os.Args[0])
+		flags.PrintDefaults()
+		fmt.Fprintf(flags.Output(), "\nSupported resource types:\n  %s\n", strings.Join(sonarqube.ExportResourceTypes(), "\n  "))
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	options := sonarqube.ExportOptions{
+		Directory: *directory,
+		Progress:  os.Stdout,
+	}
+	if *resourceTypes != "" {
+		options.ResourceTypes = strings.Split(*resourceTypes, ",")
+	}
+	if *projects != "" {
+		pattern, err := regexp.Compile(*projects)
+		if err != nil {
+			return fmt.Errorf("export: Invalid project key pattern: %+v", err)
+		}
+		options.ProjectKeyPattern = pattern
+	}
+
+	// The resources log to the standard logger, which is only useful when debugging
+	if os.Getenv("TF_LOG") == "" {
+		log.SetOutput(io.Discard)
+	}
+	return sonarqube.Export(context.Background(), options)
 }
@@ -0,0 +1,50 @@
+package sonarqube
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Returns the validate_on_apply argument shared by the DevOps platform integration resources
+func almSettingValidateOnApplySchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Default:     false,
+		Description: "Fails the apply when Sonarqube can not reach the DevOps platform with the given settings and credentials, e.g. because they expired. Defaults to `false`.",
+	}
+}
+
+// Fails the plan of a DevOps platform integration when Sonarqube does not support the integrations, or their validation
+// while validate_on_apply is set
+var almSettingCustomizeDiff = customdiff.All(
+	requireCapability(capabilityAlmSettings),
+	customdiff.If(func(_ context.Context, d *schema.ResourceDiff, _ interface{}) bool {
+		return d.Get("validate_on_apply").(bool)
+	}, requireCapability(capabilityAlmValidation)),
+)
+
+// Validates a DevOps platform integration after it was created or updated, when validate_on_apply is set
+func validateAlmSettingOnApply(ctx context.Context, d *schema.ResourceData, m interface{}, caller string) diag.Diagnostics {
+	if !d.Get("validate_on_apply").(bool) {
+		return nil
+	}
+	if err := m.(*ProviderConfiguration).checkCapability(capabilityAlmValidation); err != nil {
+		return diag.FromErr(err)
+	}
+
+	key := d.Get("key").(string)
+	validation, err := m.(*ProviderConfiguration).client.AlmSettings.Validate(ctx, key)
+	if err != nil {
+		return diag.Errorf("%s: Failed to validate alm setting '%s': %+v", caller, key, err)
+	}
+	if !validation.Valid {
+		return attributeDiagnostics("validate_on_apply", fmt.Sprintf("%s: Sonarqube can not reach the DevOps platform of alm setting '%s'", caller, key), errors.New(validation.Message))
+	}
+	return nil
+}
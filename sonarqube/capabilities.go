@@ -0,0 +1,174 @@
+package sonarqube
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Features of SonarQube which depend on the version or the edition of the server
+const (
+	capabilityAlmSettings            = "alm_settings"
+	capabilityAlmValidation          = "alm_validation"
+	capabilityAnonymizeUsers         = "anonymize_users"
+	capabilityApplications           = "applications"
+	capabilityAzureBindings          = "azure_bindings"
+	capabilityBitbucketBindings      = "bitbucket_bindings"
+	capabilityBitbucketCloudBindings = "bitbucket_cloud_bindings"
+	capabilityGithubBindings         = "github_bindings"
+	capabilityGitlabBindings         = "gitlab_bindings"
+	capabilityPlugins                = "plugins"
+	capabilityPortfolios             = "portfolios"
+	capabilityProjectDump            = "project_dump"
+	capabilityQualityGatePermissions = "quality_gate_permissions"
+)
+
+// Editions as normalized by normalizeEdition
+const (
+	editionCommunity  = "community"
+	editionDeveloper  = "developer"
+	editionEnterprise = "enterprise"
+	editionDatacenter = "datacenter"
+)
+
+// capability describes the versions and editions of SonarQube supporting a feature
+type capability struct {
+	// description names the feature in error messages, e.g. "portfolios"
+	description string
+	// minimumVersion is the first version supporting the feature, empty when every supported version does
+	minimumVersion string
+	// editions lists the editions supporting the feature, empty when every edition does
+	editions []string
+	// excludedEditions lists the editions which do not support the feature, any other edition does
+	excludedEditions []string
+	// action and param identify the feature in the web API, e.g. "api/views/create". When the web API of the server
+	// was discovered they are checked instead of the minimum version.
+	action string
+	param  string
+	// sonarCloud reports whether SonarCloud supports the feature. Versions and editions are not checked on SonarCloud.
+	sonarCloud bool
+}
+
+var capabilities = map[string]capability{
+	capabilityAlmSettings: {
+		description: "DevOps platform integrations",
+		sonarCloud:  true,
+	},
+	capabilityAlmValidation: {
+		description: "the validation of DevOps platform integrations",
+		action:      "api/alm_settings/validate",
+		sonarCloud:  true,
+	},
+	capabilityAnonymizeUsers: {
+		description:    "the anonymization of deactivated users",
+		minimumVersion: "9.7",
+		action:         "api/users/deactivate",
+		param:          "anonymize",
+	},
+	capabilityApplications: {
+		description: "applications",
+		editions:    []string{editionDeveloper, editionEnterprise, editionDatacenter},
+		action:      "api/applications/create",
+	},
+	capabilityAzureBindings: {
+		description:      "Azure DevOps bindings",
+		excludedEditions: []string{editionCommunity},
+		sonarCloud:       true,
+	},
+	capabilityBitbucketBindings: {
+		description:      "Bitbucket Server bindings",
+		excludedEditions: []string{editionCommunity},
+	},
+	capabilityBitbucketCloudBindings: {
+		description:      "Bitbucket Cloud bindings",
+		excludedEditions: []string{editionCommunity},
+		sonarCloud:       true,
+	},
+	capabilityGithubBindings: {
+		description:      "GitHub bindings",
+		excludedEditions: []string{editionCommunity},
+		sonarCloud:       true,
+	},
+	capabilityGitlabBindings: {
+		description:      "GitLab bindings",
+		excludedEditions: []string{editionCommunity},
+		sonarCloud:       true,
+	},
+	capabilityPlugins: {
+		description: "the installation of plugins",
+		action:      "api/plugins/install",
+	},
+	capabilityPortfolios: {
+		description: "portfolios",
+		editions:    []string{editionEnterprise, editionDatacenter},
+		action:      "api/views/create",
+	},
+	capabilityProjectDump: {
+		description: "project exports",
+		editions:    []string{editionEnterprise, editionDatacenter},
+		action:      "api/project_dump/export",
+	},
+	capabilityQualityGatePermissions: {
+		description:    "quality gate permissions",
+		minimumVersion: "9.2",
+		action:         "api/qualitygates/add_group",
+	},
+}
+
+// Normalizes the edition reported by SonarQube, e.g. "Data Center" becomes "datacenter"
+func normalizeEdition(edition string) string {
+	return strings.ToLower(strings.ReplaceAll(edition, " ", ""))
+}
+
+// Returns an error when the configured SonarQube version or edition, or SonarCloud, does not support a feature
+func (conf *ProviderConfiguration) checkCapability(name string) error {
+	c, ok := capabilities[name]
+	if !ok {
+		return fmt.Errorf("unknown capability %s", name)
+	}
+
+	if conf.sonarCloud {
+		if !c.sonarCloud {
+			return fmt.Errorf("SonarCloud does not support %s", c.description)
+		}
+		return nil
+	}
+	if conf.sonarQubeAPI != nil && c.action != "" {
+		if !conf.sonarQubeAPI.hasAction(c.action) || (c.param != "" && !conf.sonarQubeAPI.hasParam(c.action, c.param)) {
+			return fmt.Errorf("SonarQube %s version %s does not support %s: the server does not expose %s", conf.sonarQubeEdition, conf.sonarQubeVersion, c.description, strings.TrimSuffix(c.action+" "+c.param, " "))
+		}
+	} else if c.minimumVersion != "" {
+		minimumVersion := version.Must(version.NewVersion(c.minimumVersion))
+		if conf.sonarQubeVersion.LessThan(minimumVersion) {
+			return fmt.Errorf("SonarQube %s version %s does not support %s: SonarQube %s or newer is required", conf.sonarQubeEdition, conf.sonarQubeVersion, c.description, minimumVersion)
+		}
+	}
+	edition := normalizeEdition(conf.sonarQubeEdition)
+	if slices.Contains(c.excludedEditions, edition) {
+		return fmt.Errorf("SonarQube %s version %s does not support %s", conf.sonarQubeEdition, conf.sonarQubeVersion, c.description)
+	}
+	if len(c.editions) > 0 && !slices.Contains(c.editions, edition) {
+		editions := make([]string, len(c.editions))
+		for i, edition := range c.editions {
+			editions[i] = strings.ToUpper(edition[:1]) + edition[1:]
+		}
+		return fmt.Errorf("SonarQube %s version %s does not support %s: one of the %s editions is required", conf.sonarQubeEdition, conf.sonarQubeVersion, c.description, strings.Join(editions, ", "))
+	}
+	return nil
+}
+
+// Returns a CustomizeDiff function failing the plan when the SonarQube server or SonarCloud does not support a feature
+func requireCapability(name string) schema.CustomizeDiffFunc {
+	return func(_ context.Context, _ *schema.ResourceDiff, meta interface{}) error {
+		conf, ok := meta.(*ProviderConfiguration)
+		if !ok || conf == nil {
+			// The provider is not configured yet, e.g. while validating the configuration
+			return nil
+		}
+		return conf.checkCapability(name)
+	}
+}
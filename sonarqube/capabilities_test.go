@@ -0,0 +1,79 @@
+package sonarqube
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/go-version"
+)
+
+func TestCheckCapability(t *testing.T) {
+	tests := []struct {
+		version    string
+		edition    string
+		capability string
+		supported  bool
+	}{
+		{"9.9.0", "Community", capabilityAnonymizeUsers, true},
+		{"9.9.0", "Community", capabilityGithubBindings, false},
+		{"9.9.0", "Developer", capabilityGithubBindings, true},
+		{"9.9.0", "Developer", capabilityPortfolios, false},
+		{"10.4.1", "Enterprise", capabilityPortfolios, true},
+		{"10.4.1", "Data Center", capabilityPortfolios, true},
+		{"10.4.1", "Datacenter", capabilityAzureBindings, true},
+		{"9.1", "Enterprise", capabilityQualityGatePermissions, false},
+		{"9.2", "Enterprise", capabilityQualityGatePermissions, true},
+		// Editions which are not known pass the features which exclude editions, not those which require one
+		{"10.4.1", "", capabilityGithubBindings, true},
+		{"10.4.1", "Team", capabilityBitbucketBindings, true},
+		{"10.4.1", "", capabilityPortfolios, false},
+		{"10.4.1", "Entreprise", capabilityPortfolios, false},
+		{"10.4.1", "Team", capabilityApplications, false},
+		{"10.4.1", "Community", capabilityBitbucketCloudBindings, false},
+	}
+	for _, test := range tests {
+		conf := &ProviderConfiguration{
+			sonarQubeVersion: version.Must(version.NewVersion(test.version)),
+			sonarQubeEdition: test.edition,
+		}
+		err := conf.checkCapability(test.capability)
+		if supported := err == nil; supported != test.supported {
+			t.Errorf("checkCapability(%s) on SonarQube %s %s = %v, want supported %v", test.capability, test.edition, test.version, err, test.supported)
+		}
+	}
+}
+
+func TestCheckUnknownCapability(t *testing.T) {
+	conf := &ProviderConfiguration{sonarQubeVersion: version.Must(version.NewVersion("10.4")), sonarQubeEdition: "Enterprise"}
+	if err := conf.checkCapability("unknown"); err == nil {
+		t.Errorf("checkCapability(unknown) = nil, want an error")
+	}
+}
+
+func TestRequireCapabilityWithoutConfiguration(t *testing.T) {
+	// The provider is not configured while Terraform validates the configuration
+	if err := requireCapability(capabilityPortfolios)(context.Background(), nil, nil); err != nil {
+		t.Errorf("unexpected error: %+v", err)
+	}
+}
+
+func TestCheckCapabilityOnSonarCloud(t *testing.T) {
+	conf := &ProviderConfiguration{sonarQubeEdition: "SonarCloud", sonarCloud: true}
+	for name, supported := range map[string]bool{
+		capabilityAlmSettings:            true,
+		capabilityAlmValidation:          true,
+		capabilityAzureBindings:          true,
+		capabilityBitbucketCloudBindings: true,
+		capabilityGithubBindings:         true,
+		capabilityGitlabBindings:         true,
+		capabilityBitbucketBindings:      false,
+		capabilityApplications:           false,
+		capabilityPlugins:                false,
+		capabilityPortfolios:             false,
+	} {
+		err := conf.checkCapability(name)
+		if (err == nil) != supported {
+			t.Errorf("checkCapability(%s) on SonarCloud = %v, want supported %v", name, err, supported)
+		}
+	}
+}
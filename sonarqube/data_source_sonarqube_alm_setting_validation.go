@@ -0,0 +1,76 @@
+package sonarqube
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/jdamata/terraform-provider-sonarqube/internal/client"
+)
+
+func dataSourceSonarqubeAlmSettingValidation() *schema.Resource {
+	return &schema.Resource{
+		Description: `Use this data source to check that Sonarqube reaches a DevOps platform with the settings and credentials of an
+Alm/Devops Platform Integration, or reaches the repository bound to a project. The data source is read on every plan, so
+expired credentials show up before pull request decoration stops working.`,
+		ReadContext: dataSourceSonarqubeAlmSettingValidationRead,
+		Schema: map[string]*schema.Schema{
+			"key": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"key", "project"},
+				Description:  "The key of the Alm/Devops Platform Integration to validate. Either `key` or `project` should be provided.",
+			},
+			"project": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: []string{"key", "project"},
+				Description:  "The key of a project whose binding is validated. `key` is then set to the integration the project is bound to. Either `key` or `project` should be provided.",
+			},
+			"valid": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether Sonarqube reached the DevOps platform.",
+			},
+			"error_message": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The reason Sonarqube gave when the validation failed, empty otherwise.",
+			},
+		},
+	}
+}
+
+func dataSourceSonarqubeAlmSettingValidationRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if err := m.(*ProviderConfiguration).checkCapability(capabilityAlmValidation); err != nil {
+		return diag.FromErr(err)
+	}
+
+	almSettingsService := m.(*ProviderConfiguration).client.AlmSettings
+	var validation *client.AlmValidation
+	var err error
+	if project, ok := d.GetOk("project"); ok {
+		var binding *client.AlmBinding
+		if binding, err = almSettingsService.GetBinding(ctx, project.(string)); err != nil {
+			return diag.Errorf("dataSourceSonarqubeAlmSettingValidationRead: Failed to read the binding of project '%s': %+v", project.(string), err)
+		}
+		d.Set("key", binding.Key)
+		d.SetId(project.(string))
+		validation, err = almSettingsService.ValidateBinding(ctx, project.(string))
+		if err != nil {
+			return diag.Errorf("dataSourceSonarqubeAlmSettingValidationRead: Failed to validate the binding of project '%s': %+v", project.(string), err)
+		}
+	} else {
+		key := d.Get("key").(string)
+		d.SetId(key)
+		validation, err = almSettingsService.Validate(ctx, key)
+		if err != nil {
+			return diag.Errorf("dataSourceSonarqubeAlmSettingValidationRead: Failed to validate alm setting '%s': %+v", key, err)
+		}
+	}
+
+	d.Set("valid", validation.Valid)
+	d.Set("error_message", validation.Message)
+	return nil
+}
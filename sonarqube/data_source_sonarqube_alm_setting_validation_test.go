@@ -0,0 +1,97 @@
+package sonarqube
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func testAccSonarqubeAlmSettingValidationDataSourceConfig(rnd string) string {
+	return fmt.Sprintf(`
+		resource "sonarqube_alm_gitlab" "%[1]s_valid" {
+			key                   = "%[1]s-valid"
+			personal_access_token = "123456"
+			url                   = "https://gitlab.com/api/v4"
+		}
+		resource "sonarqube_alm_gitlab" "%[1]s_invalid" {
+			key                   = "%[1]s-invalid"
+			personal_access_token = "123456"
+			url                   = "https://gitlab.example.invalid/api/v4"
+		}
+		data "sonarqube_alm_setting_validation" "%[1]s_valid" {
+			key = sonarqube_alm_gitlab.%[1]s_valid.key
+		}
+		data "sonarqube_alm_setting_validation" "%[1]s_invalid" {
+			key = sonarqube_alm_gitlab.%[1]s_invalid.key
+		}
+		`, rnd)
+}
+
+func TestAccSonarqubeAlmSettingValidationDataSource(t *testing.T) {
+	rnd := generateRandomResourceName()
+	name := "data.sonarqube_alm_setting_validation." + rnd
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t); testAccPreCheckCapability(t, capabilityAlmValidation) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSonarqubeAlmSettingValidationDataSourceConfig(rnd),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(name+"_valid", "valid", "true"),
+					resource.TestCheckResourceAttr(name+"_valid", "error_message", ""),
+					resource.TestCheckResourceAttr(name+"_invalid", "valid", "false"),
+					resource.TestMatchResourceAttr(name+"_invalid", "error_message", regexp.MustCompile("gitlab.example.invalid")),
+				),
+			},
+		},
+	})
+}
+
+func testAccSonarqubeAlmSettingValidationDataSourceBindingConfig(rnd string) string {
+	return fmt.Sprintf(`
+		resource "sonarqube_alm_gitlab" "%[1]s" {
+			key                   = "%[1]s"
+			personal_access_token = "123456"
+			url                   = "https://gitlab.com/api/v4"
+		}
+		resource "sonarqube_project" "%[1]s" {
+			name       = "%[1]s"
+			project    = "%[1]s"
+			visibility = "public"
+		}
+		resource "sonarqube_gitlab_binding" "%[1]s" {
+			alm_setting = sonarqube_alm_gitlab.%[1]s.key
+			project     = sonarqube_project.%[1]s.project
+			repository  = "123"
+		}
+		data "sonarqube_alm_setting_validation" "%[1]s" {
+			project = sonarqube_gitlab_binding.%[1]s.project
+		}
+		`, rnd)
+}
+
+func TestAccSonarqubeAlmSettingValidationDataSourceBinding(t *testing.T) {
+	rnd := generateRandomResourceName()
+	name := "data.sonarqube_alm_setting_validation." + rnd
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckCapability(t, capabilityAlmValidation)
+			testAccPreCheckGitlabBindingSupport(t)
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSonarqubeAlmSettingValidationDataSourceBindingConfig(rnd),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(name, "key", rnd),
+					resource.TestCheckResourceAttr(name, "valid", "true"),
+				),
+			},
+		},
+	})
+}
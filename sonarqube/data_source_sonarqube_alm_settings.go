@@ -0,0 +1,90 @@
+package sonarqube
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceSonarqubeAlmSettings() *schema.Resource {
+	return &schema.Resource{
+		Description: `Use this data source to list every Alm/Devops Platform Integration of Sonarqube along with the number of projects
+bound to it, e.g. to find unused integrations before rotating their credentials or deleting them. Secrets are never returned.`,
+		ReadContext: dataSourceSonarqubeAlmSettingsRead,
+		Schema: map[string]*schema.Schema{
+			"alm_settings": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"key": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The key of the integration.",
+						},
+						"type": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The DevOps platform of the integration, one of `azure`, `bitbucket`, `bitbucketcloud`, `github` or `gitlab`.",
+						},
+						"url": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The URL of the DevOps platform. Empty for Bitbucket Cloud, which is identified by its workspace.",
+						},
+						"projects": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The number of projects bound to the integration.",
+						},
+					},
+				},
+				Description: "List of the Alm/Devops Platform Integrations.",
+			},
+		},
+	}
+}
+
+func dataSourceSonarqubeAlmSettingsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if err := m.(*ProviderConfiguration).checkCapability(capabilityAlmSettings); err != nil {
+		return diag.FromErr(err)
+	}
+
+	almSettingsService := m.(*ProviderConfiguration).client.AlmSettings
+	definitions, err := almSettingsService.ListDefinitions(ctx)
+	if err != nil {
+		return diag.Errorf("dataSourceSonarqubeAlmSettingsRead: Failed to list alm definitions: %+v", err)
+	}
+
+	almSettings := []map[string]interface{}{}
+	for _, definition := range definitions.Azure {
+		almSettings = append(almSettings, map[string]interface{}{"key": definition.Key, "type": "azure", "url": definition.URL})
+	}
+	for _, definition := range definitions.Bitbucket {
+		almSettings = append(almSettings, map[string]interface{}{"key": definition.Key, "type": "bitbucket", "url": definition.URL})
+	}
+	// Bitbucket Cloud integrations have a workspace instead of a URL
+	for _, definition := range definitions.BitbucketCloud {
+		almSettings = append(almSettings, map[string]interface{}{"key": definition.Key, "type": "bitbucketcloud", "url": ""})
+	}
+	for _, definition := range definitions.Github {
+		almSettings = append(almSettings, map[string]interface{}{"key": definition.Key, "type": "github", "url": definition.URL})
+	}
+	for _, definition := range definitions.Gitlab {
+		almSettings = append(almSettings, map[string]interface{}{"key": definition.Key, "type": "gitlab", "url": definition.URL})
+	}
+
+	for _, almSetting := range almSettings {
+		key := almSetting["key"].(string)
+		count, err := almSettingsService.CountBinding(ctx, key)
+		if err != nil {
+			return diag.Errorf("dataSourceSonarqubeAlmSettingsRead: Failed to count the bindings of alm setting '%s': %+v", key, err)
+		}
+		almSetting["projects"] = count.Projects
+	}
+
+	d.SetId("alm_settings")
+	d.Set("alm_settings", almSettings)
+	return nil
+}
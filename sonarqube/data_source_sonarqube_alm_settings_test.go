@@ -0,0 +1,66 @@
+package sonarqube
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func testAccSonarqubeAlmSettingsDataSourceConfig(rnd string) string {
+	return fmt.Sprintf(`
+		resource "sonarqube_alm_gitlab" "%[1]s" {
+			key                   = "%[1]s-gitlab"
+			personal_access_token = "123456"
+			url                   = "https://gitlab.com/api/v4"
+		}
+		resource "sonarqube_alm_bitbucket_cloud" "%[1]s" {
+			key           = "%[1]s-bitbucket-cloud"
+			client_id     = "client"
+			client_secret = "secret"
+			workspace     = "workspace"
+		}
+		resource "sonarqube_project" "%[1]s" {
+			name       = "%[1]s"
+			project    = "%[1]s"
+			visibility = "public"
+		}
+		resource "sonarqube_gitlab_binding" "%[1]s" {
+			alm_setting = sonarqube_alm_gitlab.%[1]s.key
+			project     = sonarqube_project.%[1]s.project
+			repository  = "123"
+		}
+		data "sonarqube_alm_settings" "%[1]s" {
+			depends_on = [sonarqube_gitlab_binding.%[1]s, sonarqube_alm_bitbucket_cloud.%[1]s]
+		}
+		`, rnd)
+}
+
+func TestAccSonarqubeAlmSettingsDataSource(t *testing.T) {
+	rnd := generateRandomResourceName()
+	name := "data.sonarqube_alm_settings." + rnd
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t); testAccPreCheckGitlabBindingSupport(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSonarqubeAlmSettingsDataSourceConfig(rnd),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckTypeSetElemNestedAttrs(name, "alm_settings.*", map[string]string{
+						"key":      rnd + "-gitlab",
+						"type":     "gitlab",
+						"url":      "https://gitlab.com/api/v4",
+						"projects": "1",
+					}),
+					resource.TestCheckTypeSetElemNestedAttrs(name, "alm_settings.*", map[string]string{
+						"key":      rnd + "-bitbucket-cloud",
+						"type":     "bitbucketcloud",
+						"url":      "",
+						"projects": "0",
+					}),
+				),
+			},
+		},
+	})
+}
@@ -1,13 +1,16 @@
 package sonarqube
 
 import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func dataSourceSonarqubeGroup() *schema.Resource {
 	return &schema.Resource{
 		Description: "Use this data source to get a Sonarqube Group resource",
-		Read:        dataSourceSonarqubeGroupRead,
+		ReadContext: dataSourceSonarqubeGroupRead,
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:        schema.TypeString,
@@ -23,7 +26,13 @@ func dataSourceSonarqubeGroup() *schema.Resource {
 	}
 }
 
-func dataSourceSonarqubeGroupRead(d *schema.ResourceData, m interface{}) error {
+func dataSourceSonarqubeGroupRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	d.SetId(d.Get("name").(string))
-	return resourceSonarqubeGroupRead(d, m)
+	if diags := resourceSonarqubeGroupRead(ctx, d, m); diags.HasError() {
+		return diags
+	}
+	if d.Id() == "" {
+		return diag.Errorf("dataSourceSonarqubeGroupRead: Failed to find group: %+v", d.Get("name").(string))
+	}
+	return nil
 }
@@ -24,8 +24,8 @@ func TestAccSonarqubeGroupDataSource(t *testing.T) {
 	name := "data.sonarqube_group." + rnd
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubeGroupDataSourceConfig(rnd, "testAccSonarqubeGroupDataSource", "Terraform Test Group Data-source"),
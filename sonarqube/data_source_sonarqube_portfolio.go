@@ -1,13 +1,16 @@
 package sonarqube
 
 import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func dataSourceSonarqubePortfolio() *schema.Resource {
 	return &schema.Resource{
 		Description: "Use this data source to get a Sonarqube portfolio resource",
-		Read:        dataSourceSonarqubePortfolioRead,
+		ReadContext: dataSourceSonarqubePortfolioRead,
 		Schema: map[string]*schema.Schema{
 			"key": {
 				Type:        schema.TypeString,
@@ -57,11 +60,36 @@ func dataSourceSonarqubePortfolio() *schema.Resource {
 				Computed:    true,
 				Description: "The regular expression used to populate the portfolio. Only active when `selection_mode` is `REGEXP`",
 			},
+			"children": {
+				Type:        schema.TypeSet,
+				Computed:    true,
+				Description: "The portfolios and applications of the portfolio",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"key": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The key of the portfolio or the application",
+						},
+						"type": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "How the child is added to the portfolio: `portfolio`, `local_view` or `application`",
+						},
+					},
+				},
+			},
 		},
 	}
 }
 
-func dataSourceSonarqubePortfolioRead(d *schema.ResourceData, m interface{}) error {
+func dataSourceSonarqubePortfolioRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	d.SetId(d.Get("key").(string))
-	return resourceSonarqubePortfolioRead(d, m)
+	if diags := resourceSonarqubePortfolioRead(ctx, d, m); diags.HasError() {
+		return diags
+	}
+	if d.Id() == "" {
+		return diag.Errorf("dataSourceSonarqubePortfolioRead: Failed to find portfolio: %+v", d.Get("key").(string))
+	}
+	return nil
 }
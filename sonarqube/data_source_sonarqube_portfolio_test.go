@@ -59,8 +59,8 @@ func TestAccSonarqubePortfolioDataSource(t *testing.T) {
 	name := "data.sonarqube_portfolio." + rnd
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t); testAccPreCheckPortfolioSupport(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t); testAccPreCheckPortfolioSupport(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubePortfolioDataSourceConfig(rnd, "testAccSonarqubePortfolioDataSourceKey", "testAccSonarqubePortfolioDataSourceName", "testAccSonarqubePortfolioDataSourceDescription", "public"),
@@ -81,8 +81,8 @@ func TestAccSonarqubePortfolioDataSourceTags(t *testing.T) {
 	tags := []string{"tag1", "tag2"}
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t); testAccPreCheckPortfolioSupport(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t); testAccPreCheckPortfolioSupport(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubePortfolioDataSourceConfigTags(rnd, "testAccSonarqubePortfolioDataSourceKey", "testAccSonarqubePortfolioDataSourceName", "testAccSonarqubePortfolioDataSourceDescription", "public", tags),
@@ -103,8 +103,8 @@ func TestAccSonarqubePortfolioDataSourceRegex(t *testing.T) {
 	exampleRegex := "myExampleRegex"
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t); testAccPreCheckPortfolioSupport(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t); testAccPreCheckPortfolioSupport(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubePortfolioDataSourceConfigRegex(rnd, "testAccSonarqubePortfolioDataSourceKey", "testAccSonarqubePortfolioDataSourceName", "testAccSonarqubePortfolioDataSourceDescription", "public", exampleRegex),
@@ -1,13 +1,16 @@
 package sonarqube
 
 import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func dataSourceSonarqubeProject() *schema.Resource {
 	return &schema.Resource{
 		Description: "Use this data source to get a Sonarqube project resource",
-		Read:        dataSourceSonarqubeProjectRead,
+		ReadContext: dataSourceSonarqubeProjectRead,
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:        schema.TypeString,
@@ -28,7 +31,13 @@ func dataSourceSonarqubeProject() *schema.Resource {
 	}
 }
 
-func dataSourceSonarqubeProjectRead(d *schema.ResourceData, m interface{}) error {
+func dataSourceSonarqubeProjectRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	d.SetId(d.Get("project").(string))
-	return resourceSonarqubeProjectRead(d, m)
+	if diags := readProject(ctx, d, m); diags.HasError() {
+		return diags
+	}
+	if d.Id() == "" {
+		return diag.Errorf("dataSourceSonarqubeProjectRead: Failed to find project: %+v", d.Get("project").(string))
+	}
+	return nil
 }
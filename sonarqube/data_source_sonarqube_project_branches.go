@@ -0,0 +1,91 @@
+package sonarqube
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceSonarqubeProjectBranches() *schema.Resource {
+	return &schema.Resource{
+		Description: "Use this data source to list the branches of a Sonarqube project",
+		ReadContext: dataSourceSonarqubeProjectBranchesRead,
+		Schema: map[string]*schema.Schema{
+			"project": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The project key of the project",
+			},
+			"branches": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the branch.",
+						},
+						"is_main": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the branch is the main branch of the project.",
+						},
+						"type": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The type of the branch, e.g. `BRANCH`.",
+						},
+						"quality_gate_status": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The quality gate status of the last analysis of the branch, empty when the branch was not analyzed.",
+						},
+						"analysis_date": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The date of the last analysis of the branch, empty when the branch was not analyzed.",
+						},
+						"excluded_from_purge": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the branch is kept when it is inactive.",
+						},
+					},
+				},
+				Description: "List of the branches of the project, starting with the main branch.",
+			},
+		},
+	}
+}
+
+func dataSourceSonarqubeProjectBranchesRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	project := d.Get("project").(string)
+	branches, err := m.(*ProviderConfiguration).client.ProjectBranches.List(ctx, project)
+	if err != nil {
+		return diag.Errorf("dataSourceSonarqubeProjectBranchesRead: Failed to list project branches: %+v", err)
+	}
+
+	// Sonarqube lists the branches by name, the main branch comes first
+	projectBranches := []map[string]interface{}{}
+	for _, branch := range branches {
+		projectBranch := map[string]interface{}{
+			"name":                branch.Name,
+			"is_main":             branch.IsMain,
+			"type":                branch.Type,
+			"quality_gate_status": branch.Status.QualityGateStatus,
+			"analysis_date":       branch.AnalysisDate,
+			"excluded_from_purge": branch.ExcludedFromPurge,
+		}
+		if branch.IsMain {
+			projectBranches = append([]map[string]interface{}{projectBranch}, projectBranches...)
+		} else {
+			projectBranches = append(projectBranches, projectBranch)
+		}
+	}
+
+	d.SetId(project)
+	d.Set("branches", projectBranches)
+	return nil
+}
@@ -0,0 +1,61 @@
+package sonarqube
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func testAccSonarqubeProjectBranchesDataSourceConfig(rnd string, project string) string {
+	return fmt.Sprintf(`
+		resource "sonarqube_project" "%[1]s" {
+		  name    = "%[2]s"
+		  project = "%[2]s"
+		}
+		data "sonarqube_project_branches" "%[1]s" {
+			project = sonarqube_project.%[1]s.id
+		}
+		`, rnd, project)
+}
+
+func TestAccSonarqubeProjectBranchesDataSource(t *testing.T) {
+	rnd := generateRandomResourceName()
+	name := "data.sonarqube_project_branches." + rnd
+	project := "testAccSonarqubeProjectBranchesDataSource"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckFakeServer(t)
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSonarqubeProjectBranchesDataSourceConfig(rnd, project),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(name, "branches.#", "1"),
+					resource.TestCheckResourceAttr(name, "branches.0.name", "main"),
+					resource.TestCheckResourceAttr(name, "branches.0.is_main", "true"),
+					resource.TestCheckResourceAttr(name, "branches.0.analysis_date", ""),
+				),
+			},
+			{
+				PreConfig: func() {
+					testAccAnalyzeBranch(t, project, "release-2.0")
+				},
+				Config: testAccSonarqubeProjectBranchesDataSourceConfig(rnd, project),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(name, "branches.#", "2"),
+					resource.TestCheckResourceAttr(name, "branches.0.name", "main"),
+					resource.TestCheckResourceAttr(name, "branches.1.name", "release-2.0"),
+					resource.TestCheckResourceAttr(name, "branches.1.is_main", "false"),
+					resource.TestCheckResourceAttr(name, "branches.1.type", "BRANCH"),
+					resource.TestCheckResourceAttr(name, "branches.1.quality_gate_status", "OK"),
+					resource.TestCheckResourceAttrSet(name, "branches.1.analysis_date"),
+					resource.TestCheckResourceAttr(name, "branches.1.excluded_from_purge", "true"),
+				),
+			},
+		},
+	})
+}
@@ -25,8 +25,8 @@ func TestAccSonarqubeProjectDataSource(t *testing.T) {
 	name := "data.sonarqube_project." + rnd
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubeProjectDataSourceConfig(rnd, "testAccSonarqubeProjectDataSource", "testAccSonarqubeProjectDataSource", "public"),
@@ -1,13 +1,16 @@
 package sonarqube
 
 import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func dataSourceSonarqubeQualityGate() *schema.Resource {
 	return &schema.Resource{
 		Description: "Use this data source to get a Sonarqube qualitygate resource",
-		Read:        dataSourceSonarqubeQualityGateRead,
+		ReadContext: dataSourceSonarqubeQualityGateRead,
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:        schema.TypeString,
@@ -53,7 +56,13 @@ func dataSourceSonarqubeQualityGate() *schema.Resource {
 	}
 }
 
-func dataSourceSonarqubeQualityGateRead(d *schema.ResourceData, m interface{}) error {
+func dataSourceSonarqubeQualityGateRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	d.SetId(d.Get("name").(string))
-	return resourceSonarqubeQualityGateRead(d, m)
+	if diags := resourceSonarqubeQualityGateRead(ctx, d, m); diags.HasError() {
+		return diags
+	}
+	if d.Id() == "" {
+		return diag.Errorf("dataSourceSonarqubeQualityGateRead: Failed to find quality gate: %+v", d.Get("name").(string))
+	}
+	return nil
 }
@@ -42,8 +42,8 @@ func TestAccSonarqubeQualityGateDataSource(t *testing.T) {
 	name := "data.sonarqube_qualitygate." + rnd
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			// QualityGate with copy_from
 			{
@@ -1,13 +1,16 @@
 package sonarqube
 
 import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func dataSourceSonarqubeQualityProfile() *schema.Resource {
 	return &schema.Resource{
 		Description: "Use this data source to get a Sonarqube qualityprofile resource",
-		Read:        dataSourceSonarqubeQualityProfileRead,
+		ReadContext: dataSourceSonarqubeQualityProfileRead,
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:        schema.TypeString,
@@ -33,7 +36,13 @@ func dataSourceSonarqubeQualityProfile() *schema.Resource {
 	}
 }
 
-func dataSourceSonarqubeQualityProfileRead(d *schema.ResourceData, m interface{}) error {
+func dataSourceSonarqubeQualityProfileRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	d.SetId(d.Get("name").(string))
-	return resourceSonarqubeQualityProfileRead(d, m)
+	if diags := resourceSonarqubeQualityProfileRead(ctx, d, m); diags.HasError() {
+		return diags
+	}
+	if d.Id() == "" {
+		return diag.Errorf("dataSourceSonarqubeQualityProfileRead: Failed to find quality profile: %+v", d.Get("name").(string))
+	}
+	return nil
 }
@@ -24,8 +24,8 @@ func TestAccSonarqubeQualityProfileDataSource(t *testing.T) {
 	name := "data.sonarqube_qualityprofile." + rnd
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubeQualityProfileDataSourceConfig(rnd, "testAccSonarqubeQualityProfileDataSource", "js"),
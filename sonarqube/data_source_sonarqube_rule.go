@@ -1,13 +1,16 @@
 package sonarqube
 
 import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func dataSourceSonarqubeRule() *schema.Resource {
 	return &schema.Resource{
 		Description: "Use this data source to get a Sonarqube rule resource",
-		Read:        dataSourceSonarqubeRuleRead,
+		ReadContext: dataSourceSonarqubeRuleRead,
 		Schema: map[string]*schema.Schema{
 			"key": {
 				Type:        schema.TypeString,
@@ -48,7 +51,13 @@ func dataSourceSonarqubeRule() *schema.Resource {
 	}
 }
 
-func dataSourceSonarqubeRuleRead(d *schema.ResourceData, m interface{}) error {
+func dataSourceSonarqubeRuleRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	d.SetId(d.Get("key").(string))
-	return resourceSonarqubeRuleRead(d, m)
+	if diags := resourceSonarqubeRuleRead(ctx, d, m); diags.HasError() {
+		return diags
+	}
+	if d.Id() == "" {
+		return diag.Errorf("dataSourceSonarqubeRuleRead: Failed to find rule: %+v", d.Get("key").(string))
+	}
+	return nil
 }
@@ -29,8 +29,8 @@ func TestAccSonarqubeRuleDataSource(t *testing.T) {
 	name := "data.sonarqube_rule." + rnd
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubeRuleDataSourceConfig(rnd, "basicRule", "markdown_description", "name", "xml:XPathCheck", "INFO", "READY", "VULNERABILITY"),
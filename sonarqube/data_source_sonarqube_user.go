@@ -1,13 +1,16 @@
 package sonarqube
 
 import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func dataSourceSonarqubeUser() *schema.Resource {
 	return &schema.Resource{
 		Description: "Use this data source to get a Sonarqube User resource",
-		Read:        dataSourceSonarqubeUserRead,
+		ReadContext: dataSourceSonarqubeUserRead,
 		Schema: map[string]*schema.Schema{
 			"login_name": {
 				Type:        schema.TypeString,
@@ -33,7 +36,13 @@ func dataSourceSonarqubeUser() *schema.Resource {
 	}
 }
 
-func dataSourceSonarqubeUserRead(d *schema.ResourceData, m interface{}) error {
+func dataSourceSonarqubeUserRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	d.SetId(d.Get("login_name").(string))
-	return resourceSonarqubeUserRead(d, m)
+	if diags := resourceSonarqubeUserRead(ctx, d, m); diags.HasError() {
+		return diags
+	}
+	if d.Id() == "" {
+		return diag.Errorf("dataSourceSonarqubeUserRead: Failed to find user: %+v", d.Get("login_name").(string))
+	}
+	return nil
 }
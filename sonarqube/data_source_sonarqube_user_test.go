@@ -26,8 +26,8 @@ func TestAccSonarqubeUserDataSource(t *testing.T) {
 	name := "data.sonarqube_user." + rnd
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubeUserDataSourceConfig(rnd, "testAccSonarqubeUserDataSource", "terraform-test-user-data-source@sonarqube.com", "secret-sauce37!"),
@@ -0,0 +1,85 @@
+package sonarqube
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+	"github.com/jdamata/terraform-provider-sonarqube/internal/client"
+)
+
+// serverAPI is the web API exposed by the SonarQube server, as discovered at configuration time
+type serverAPI struct {
+	// actions maps every action, e.g. "api/users/deactivate", to the set of its parameters
+	actions map[string]map[string]bool
+	// v2Paths holds the paths of the v2 web API, e.g. "api/v2/users-management/users/{id}"
+	v2Paths map[string]bool
+}
+
+// Discovers the web services, actions and parameters exposed by the server, and the paths of the v2 web API on
+// SonarQube 10 and newer. sonarQubeVersion is nil for SonarCloud, which does not publish the v2 web API document.
+func discoverServerAPI(ctx context.Context, c *client.Client, sonarQubeVersion *version.Version) (*serverAPI, error) {
+	webServices, err := c.Webservices.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list web services: %w", err)
+	}
+
+	api := &serverAPI{
+		actions: map[string]map[string]bool{},
+		v2Paths: map[string]bool{},
+	}
+	for _, webService := range webServices {
+		for _, action := range webService.Actions {
+			params := map[string]bool{}
+			for _, param := range action.Params {
+				params[param.Key] = true
+			}
+			api.actions[strings.Trim(webService.Path, "/")+"/"+action.Key] = params
+		}
+	}
+
+	minimumVersionForV2, _ := version.NewVersion("10.0")
+	if sonarQubeVersion == nil || sonarQubeVersion.LessThan(minimumVersionForV2) {
+		return api, nil
+	}
+	document, err := c.Webservices.APIDocs(ctx)
+	if err != nil {
+		// Early 10.x releases do not publish the document, only the v1 web API is used with them
+		if client.IsNotFound(err) {
+			return api, nil
+		}
+		return nil, fmt.Errorf("failed to get the v2 web API document: %w", err)
+	}
+	for path := range document.Paths {
+		// Depending on the release, paths are relative to the server or to the v2 web API
+		path = strings.Trim(path, "/")
+		if !strings.HasPrefix(path, "api/v2/") {
+			path = "api/v2/" + path
+		}
+		api.v2Paths[path] = true
+	}
+	return api, nil
+}
+
+// Reports whether the server exposes an action, e.g. "api/users/deactivate"
+func (a *serverAPI) hasAction(endpoint string) bool {
+	_, ok := a.actions[endpoint]
+	return ok
+}
+
+// Reports whether an action of the server accepts a parameter
+func (a *serverAPI) hasParam(endpoint string, param string) bool {
+	return a.actions[endpoint][param]
+}
+
+// Reports whether the server exposes a path of the v2 web API, e.g. "api/v2/users-management/users/{id}"
+func (a *serverAPI) hasV2Path(path string) bool {
+	return a.v2Paths[path]
+}
+
+// Reports whether an action identifies quality gates with gateId only, on servers which predate gateName. It is false
+// when the web API was not discovered, gateName is then used.
+func (a *serverAPI) requiresGateID(endpoint string) bool {
+	return a != nil && a.hasParam(endpoint, "gateId") && !a.hasParam(endpoint, "gateName")
+}
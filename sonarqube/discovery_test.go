@@ -0,0 +1,93 @@
+package sonarqube
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/go-version"
+	"github.com/jdamata/terraform-provider-sonarqube/internal/fakesonarqube"
+)
+
+func TestDiscoverServerAPI(t *testing.T) {
+	tests := []struct {
+		version string
+		v2      bool
+	}{
+		{"9.9.4.87374", false},
+		{"10.4.1.88267", true},
+	}
+	for _, test := range tests {
+		server := fakesonarqube.NewServer(fakesonarqube.Options{Version: test.version})
+		defer server.Close()
+
+		api, err := discoverServerAPI(context.Background(), testServerClient(t, server.URL), version.Must(version.NewVersion(test.version)))
+		if err != nil {
+			t.Fatalf("unexpected error: %+v", err)
+		}
+		if !api.hasAction("api/users/deactivate") || !api.hasParam("api/users/deactivate", "anonymize") {
+			t.Errorf("SonarQube %s: api/users/deactivate with anonymize was not discovered", test.version)
+		}
+		if api.hasAction("api/users/unknown") || api.hasParam("api/users/deactivate", "unknown") {
+			t.Errorf("SonarQube %s: unknown action or parameter discovered", test.version)
+		}
+		if v2 := api.hasV2Path("api/v2/users-management/users/{id}"); v2 != test.v2 {
+			t.Errorf("SonarQube %s: hasV2Path() = %v, want %v", test.version, v2, test.v2)
+		}
+	}
+}
+
+func TestRequiresGateID(t *testing.T) {
+	tests := []struct {
+		version string
+		gateID  bool
+	}{
+		{"8.3.1.34397", true},
+		{"9.9.4.87374", false},
+		{"10.4.1.88267", false},
+	}
+	for _, test := range tests {
+		server := fakesonarqube.NewServer(fakesonarqube.Options{Version: test.version})
+		defer server.Close()
+
+		api, err := discoverServerAPI(context.Background(), testServerClient(t, server.URL), version.Must(version.NewVersion(test.version)))
+		if err != nil {
+			t.Fatalf("unexpected error: %+v", err)
+		}
+		for _, action := range []string{"api/qualitygates/create_condition", "api/qualitygates/select", "api/qualitygates/deselect"} {
+			if gateID := api.requiresGateID(action); gateID != test.gateID {
+				t.Errorf("SonarQube %s: requiresGateID(%s) = %v, want %v", test.version, action, gateID, test.gateID)
+			}
+		}
+	}
+
+	// Without discovery gateName is used
+	var api *serverAPI
+	if api.requiresGateID("api/qualitygates/select") {
+		t.Errorf("requiresGateID() = true without a discovered web API, want false")
+	}
+}
+
+func TestCheckCapabilityWithServerAPI(t *testing.T) {
+	conf := &ProviderConfiguration{
+		// The version alone would not support quality gate permissions, the discovered web API takes precedence
+		sonarQubeVersion: version.Must(version.NewVersion("9.1")),
+		sonarQubeEdition: "Community",
+		sonarQubeAPI: &serverAPI{
+			actions: map[string]map[string]bool{
+				"api/qualitygates/add_group": {},
+				"api/users/deactivate":       {"login": true},
+				"api/views/create":           {},
+			},
+		},
+	}
+	if err := conf.checkCapability(capabilityQualityGatePermissions); err != nil {
+		t.Errorf("unexpected error: %+v", err)
+	}
+	if err := conf.checkCapability(capabilityAnonymizeUsers); err == nil {
+		t.Errorf("checkCapability(%s) = nil, want an error as the anonymize parameter is missing", capabilityAnonymizeUsers)
+	}
+	// Editions are still checked
+	if err := conf.checkCapability(capabilityPortfolios); err == nil {
+		t.Errorf("checkCapability(%s) = nil, want an error for the Community edition", capabilityPortfolios)
+	}
+}
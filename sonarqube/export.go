@@ -0,0 +1,847 @@
+package sonarqube
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/jdamata/terraform-provider-sonarqube/internal/client"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// ExportOptions configures Export
+type ExportOptions struct {
+	// Directory receives one file per exported resource type, imports.tf with the import blocks and variables.tf with
+	// the variables holding the secrets which can not be read from the server
+	Directory string
+	// ResourceTypes restricts the export to these resource types. Every type of ExportResourceTypes is exported when empty.
+	ResourceTypes []string
+	// ProjectKeyPattern restricts the export of projects and of project scoped resources to the matching project keys
+	ProjectKeyPattern *regexp.Regexp
+	// Progress receives a line per exported resource type, it is optional
+	Progress io.Writer
+}
+
+// exportedResource is a resource found on the server
+type exportedResource struct {
+	// name is the name of the resource block before it is sanitized
+	name string
+	// importID is passed to the importer of the resource
+	importID string
+}
+
+// resourceExporter lists the resources of a type found on the server
+type resourceExporter struct {
+	resourceType string
+	// capability is required for resources of this type to be listed, it is optional
+	capability string
+	list       func(e *exporter, ctx context.Context) ([]exportedResource, error)
+}
+
+// resourceExporters are run in order, so the generated files read like a hand written configuration
+var resourceExporters = []resourceExporter{
+	{resourceType: "sonarqube_project", list: (*exporter).listProjects},
+	{resourceType: "sonarqube_qualitygate", list: (*exporter).listQualityGates},
+	{resourceType: "sonarqube_qualityprofile", list: (*exporter).listQualityProfiles},
+	{resourceType: "sonarqube_qualityprofile_activate_rule", list: (*exporter).listActivatedRules},
+	{resourceType: "sonarqube_group", list: (*exporter).listGroups},
+	{resourceType: "sonarqube_group_member", list: (*exporter).listGroupMembers},
+	{resourceType: "sonarqube_permission_template", list: (*exporter).listPermissionTemplates},
+	{resourceType: "sonarqube_permissions", list: (*exporter).listPermissions},
+	{resourceType: "sonarqube_setting", list: (*exporter).listSettings},
+	{resourceType: "sonarqube_webhook", list: (*exporter).listWebhooks},
+	{resourceType: "sonarqube_alm_azure", capability: capabilityAlmSettings, list: (*exporter).listAzureAlmSettings},
+	{resourceType: "sonarqube_alm_bitbucket", capability: capabilityAlmSettings, list: (*exporter).listBitbucketAlmSettings},
+	{resourceType: "sonarqube_alm_bitbucket_cloud", capability: capabilityAlmSettings, list: (*exporter).listBitbucketCloudAlmSettings},
+	{resourceType: "sonarqube_alm_github", capability: capabilityAlmSettings, list: (*exporter).listGithubAlmSettings},
+	{resourceType: "sonarqube_alm_gitlab", capability: capabilityAlmSettings, list: (*exporter).listGitlabAlmSettings},
+	{resourceType: "sonarqube_azure_binding", capability: capabilityAzureBindings, list: bindingLister("azure")},
+	{resourceType: "sonarqube_bitbucket_binding", capability: capabilityBitbucketBindings, list: bindingLister("bitbucket")},
+	{resourceType: "sonarqube_bitbucket_cloud_binding", capability: capabilityBitbucketCloudBindings, list: bindingLister("bitbucketcloud")},
+	{resourceType: "sonarqube_github_binding", capability: capabilityGithubBindings, list: bindingLister("github")},
+	{resourceType: "sonarqube_gitlab_binding", capability: capabilityGitlabBindings, list: bindingLister("gitlab")},
+}
+
+// exportReferences maps the attributes referencing another resource, as resource_type.attribute, to the referenced
+// attribute. The references are written instead of the values when the referenced resource is exported, so Terraform
+// orders the creation and the destruction of the resources.
+var exportReferences = map[string]string{
+	"sonarqube_qualityprofile_activate_rule.key":    "sonarqube_qualityprofile.key",
+	"sonarqube_group_member.name":                   "sonarqube_group.name",
+	"sonarqube_permissions.project_key":             "sonarqube_project.project",
+	"sonarqube_permissions.group_name":              "sonarqube_group.name",
+	"sonarqube_permissions.template_id":             "sonarqube_permission_template.id",
+	"sonarqube_permissions.template_name":           "sonarqube_permission_template.name",
+	"sonarqube_webhook.project":                     "sonarqube_project.project",
+	"sonarqube_azure_binding.project":               "sonarqube_project.project",
+	"sonarqube_azure_binding.alm_setting":           "sonarqube_alm_azure.key",
+	"sonarqube_bitbucket_binding.project":           "sonarqube_project.project",
+	"sonarqube_bitbucket_binding.alm_setting":       "sonarqube_alm_bitbucket.key",
+	"sonarqube_bitbucket_cloud_binding.project":     "sonarqube_project.project",
+	"sonarqube_bitbucket_cloud_binding.alm_setting": "sonarqube_alm_bitbucket_cloud.key",
+	"sonarqube_github_binding.project":              "sonarqube_project.project",
+	"sonarqube_github_binding.alm_setting":          "sonarqube_alm_github.key",
+	"sonarqube_gitlab_binding.project":              "sonarqube_project.project",
+	"sonarqube_gitlab_binding.alm_setting":          "sonarqube_alm_gitlab.key",
+}
+
+// ExportResourceTypes returns the resource types supported by Export
+func ExportResourceTypes() []string {
+	resourceTypes := []string{}
+	for _, re := range resourceExporters {
+		resourceTypes = append(resourceTypes, re.resourceType)
+	}
+	return resourceTypes
+}
+
+// Export writes the configuration of the resources found on the server to options.Directory, along with the import
+// blocks adopting them. The provider is configured from the environment, like a provider block without arguments, and
+// every resource goes through its importer and its read function as it would with terraform import.
+func Export(ctx context.Context, options ExportOptions) error {
+	for _, resourceType := range options.ResourceTypes {
+		if !slices.Contains(ExportResourceTypes(), resourceType) {
+			return fmt.Errorf("export: Resource type %s is not supported, use one of %s", resourceType, strings.Join(ExportResourceTypes(), ", "))
+		}
+	}
+
+	sdkProvider := Provider()
+	providerServerFactory, err := ProtoV6ProviderServerFactory(ctx, sdkProvider)
+	if err != nil {
+		return err
+	}
+	server := providerServerFactory()
+
+	schemas, err := server.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		return fmt.Errorf("export: Failed to read the provider schema: %+v", err)
+	}
+	if err := diagnosticsError(schemas.Diagnostics); err != nil {
+		return fmt.Errorf("export: Failed to read the provider schema: %+v", err)
+	}
+
+	config, err := emptyConfig(schemas.Provider)
+	if err != nil {
+		return err
+	}
+	configured, err := server.ConfigureProvider(ctx, &tfprotov6.ConfigureProviderRequest{Config: config})
+	if err != nil {
+		return fmt.Errorf("export: Failed to configure the provider: %+v", err)
+	}
+	if err := diagnosticsError(configured.Diagnostics); err != nil {
+		return fmt.Errorf("export: Failed to configure the provider: %+v", err)
+	}
+
+	e := &exporter{
+		server:    server,
+		schemas:   schemas.ResourceSchemas,
+		conf:      sdkProvider.Meta().(*ProviderConfiguration),
+		options:   options,
+		files:     map[string]*hclwrite.File{},
+		names:     map[string]bool{},
+		addresses: map[string]map[string]string{},
+	}
+	for _, re := range resourceExporters {
+		if len(options.ResourceTypes) > 0 && !slices.Contains(options.ResourceTypes, re.resourceType) {
+			continue
+		}
+		if err := e.export(ctx, re); err != nil {
+			return err
+		}
+	}
+	return e.write()
+}
+
+// exporter holds the state of an export
+type exporter struct {
+	server  tfprotov6.ProviderServer
+	schemas map[string]*tfprotov6.Schema
+	conf    *ProviderConfiguration
+	options ExportOptions
+
+	// files holds the generated files by name
+	files map[string]*hclwrite.File
+	// names holds the addresses of the exported resources and the names of the variables, so they are unique
+	names map[string]bool
+	// addresses holds the addresses of the exported resources by referenced attribute and value, see exportReferences
+	addresses map[string]map[string]string
+
+	// projects and bindings are listed once, as they are used by several resource types
+	projectList []client.Project
+	bindingList map[string]*client.AlmBinding
+}
+
+// export writes the resources of a type to its own file and their import blocks to imports.tf
+func (e *exporter) export(ctx context.Context, re resourceExporter) error {
+	if re.capability != "" {
+		if err := e.conf.checkCapability(re.capability); err != nil {
+			e.progress("Skipping %s: %s", re.resourceType, err)
+			return nil
+		}
+	}
+
+	resources, err := re.list(e, ctx)
+	if err != nil {
+		return fmt.Errorf("export: Failed to list %s resources: %+v", re.resourceType, err)
+	}
+
+	count := 0
+	for _, r := range resources {
+		state, err := e.importResource(ctx, re.resourceType, r.importID)
+		if err != nil {
+			return fmt.Errorf("export: Failed to import %s %s: %+v", re.resourceType, r.importID, err)
+		}
+		if state.IsNull() {
+			// The resource is gone, or its read function does not manage it
+			log.Printf("[DEBUG][export] Skipping %s %s which was not found by its read function", re.resourceType, r.importID)
+			continue
+		}
+
+		name := e.uniqueName(re.resourceType+".", r.name)
+		body := e.file(re.resourceType + ".tf").Body()
+		if count > 0 {
+			body.AppendNewline()
+		}
+		block := body.AppendNewBlock("resource", []string{re.resourceType, name})
+		if err := e.writeBlock(block.Body(), re.resourceType, strings.TrimPrefix(re.resourceType, "sonarqube_")+"_"+name, e.schemas[re.resourceType].Block, state); err != nil {
+			return fmt.Errorf("export: Failed to write %s %s: %+v", re.resourceType, r.importID, err)
+		}
+		if err := e.addAddress(re.resourceType, name, state); err != nil {
+			return fmt.Errorf("export: Failed to write %s %s: %+v", re.resourceType, r.importID, err)
+		}
+
+		imports := e.file("imports.tf").Body()
+		if len(imports.Blocks()) > 0 {
+			imports.AppendNewline()
+		}
+		importBlock := imports.AppendNewBlock("import", nil).Body()
+		importBlock.SetAttributeTraversal("to", hcl.Traversal{hcl.TraverseRoot{Name: re.resourceType}, hcl.TraverseAttr{Name: name}})
+		importBlock.SetAttributeValue("id", cty.StringVal(r.importID))
+		count++
+	}
+	e.progress("Exported %d %s resources", count, re.resourceType)
+	return nil
+}
+
+// importResource imports a resource like terraform import does, and returns its state. The state is null when the
+// resource was not found.
+func (e *exporter) importResource(ctx context.Context, resourceType string, id string) (tftypes.Value, error) {
+	schema := e.schemas[resourceType]
+	imported, err := e.server.ImportResourceState(ctx, &tfprotov6.ImportResourceStateRequest{TypeName: resourceType, ID: id})
+	if err != nil {
+		return tftypes.Value{}, err
+	}
+	if err := diagnosticsError(imported.Diagnostics); err != nil {
+		return tftypes.Value{}, err
+	}
+	if len(imported.ImportedResources) != 1 {
+		return tftypes.Value{}, fmt.Errorf("the importer returned %d resources instead of 1", len(imported.ImportedResources))
+	}
+
+	read, err := e.server.ReadResource(ctx, &tfprotov6.ReadResourceRequest{
+		TypeName:     resourceType,
+		CurrentState: imported.ImportedResources[0].State,
+		Private:      imported.ImportedResources[0].Private,
+	})
+	if err != nil {
+		return tftypes.Value{}, err
+	}
+	if err := diagnosticsError(read.Diagnostics); err != nil {
+		return tftypes.Value{}, err
+	}
+	if read.NewState == nil {
+		return tftypes.NewValue(schema.ValueType(), nil), nil
+	}
+	return read.NewState.Unmarshal(schema.ValueType())
+}
+
+// addAddress records the address of an exported resource for the attributes referenced by other resources
+func (e *exporter) addAddress(resourceType string, name string, state tftypes.Value) error {
+	values := map[string]tftypes.Value{}
+	if err := state.As(&values); err != nil {
+		return err
+	}
+	for _, referenced := range exportReferences {
+		attributeType, attribute, _ := strings.Cut(referenced, ".")
+		var value string
+		if attributeType != resourceType || values[attribute].IsNull() {
+			continue
+		}
+		if err := values[attribute].As(&value); err != nil {
+			return err
+		}
+		if e.addresses[referenced] == nil {
+			e.addresses[referenced] = map[string]string{}
+		}
+		e.addresses[referenced][value] = name
+	}
+	return nil
+}
+
+// writeBlock writes the arguments of a resource of type resourceType, or of one of its nested blocks, to body.
+// Computed attributes and the id are left out. Sensitive values, and required values which can not be read from the
+// server, are replaced with variables named after prefix.
+func (e *exporter) writeBlock(body *hclwrite.Body, resourceType string, prefix string, schema *tfprotov6.SchemaBlock, value tftypes.Value) error {
+	values := map[string]tftypes.Value{}
+	if err := value.As(&values); err != nil {
+		return err
+	}
+
+	for _, attribute := range schema.Attributes {
+		if (resourceType != "" && attribute.Name == "id") || (attribute.Computed && !attribute.Optional && !attribute.Required) {
+			continue
+		}
+		v := values[attribute.Name]
+		if (attribute.Sensitive && !v.IsNull()) || (attribute.Required && v.IsNull()) {
+			body.SetAttributeTraversal(attribute.Name, hcl.Traversal{hcl.TraverseRoot{Name: "var"}, hcl.TraverseAttr{Name: e.variable(prefix+"_"+attribute.Name, attribute)}})
+			continue
+		}
+		if v.IsNull() {
+			continue
+		}
+		if referenced, ok := exportReferences[resourceType+"."+attribute.Name]; ok {
+			var reference string
+			if err := v.As(&reference); err != nil {
+				return fmt.Errorf("attribute %s: %+v", attribute.Name, err)
+			}
+			if name, ok := e.addresses[referenced][reference]; ok {
+				referencedType, referencedAttribute, _ := strings.Cut(referenced, ".")
+				body.SetAttributeTraversal(attribute.Name, hcl.Traversal{hcl.TraverseRoot{Name: referencedType}, hcl.TraverseAttr{Name: name}, hcl.TraverseAttr{Name: referencedAttribute}})
+				continue
+			}
+		}
+		converted, err := ctyValue(v)
+		if err != nil {
+			return fmt.Errorf("attribute %s: %+v", attribute.Name, err)
+		}
+		body.SetAttributeValue(attribute.Name, converted)
+	}
+
+	for _, block := range schema.BlockTypes {
+		v := values[block.TypeName]
+		if block.TypeName == "timeouts" || v.IsNull() {
+			continue
+		}
+		elements := []tftypes.Value{v}
+		if block.Nesting == tfprotov6.SchemaNestedBlockNestingModeList || block.Nesting == tfprotov6.SchemaNestedBlockNestingModeSet {
+			if err := v.As(&elements); err != nil {
+				return err
+			}
+		}
+		for _, element := range elements {
+			nested := body.AppendNewBlock(block.TypeName, nil)
+			// Nested blocks are written without resource type, their attributes do not reference other resources
+			if err := e.writeBlock(nested.Body(), "", prefix+"_"+block.TypeName, block.Block, element); err != nil {
+				return fmt.Errorf("block %s: %+v", block.TypeName, err)
+			}
+		}
+	}
+	return nil
+}
+
+// variable declares a variable in variables.tf for a value which is not written to the configuration and returns its name
+func (e *exporter) variable(name string, attribute *tfprotov6.SchemaAttribute) string {
+	name = e.uniqueName("var.", name)
+	body := e.file("variables.tf").Body()
+	if len(body.Blocks()) > 0 {
+		body.AppendNewline()
+	}
+	variable := body.AppendNewBlock("variable", []string{name}).Body()
+	if attribute.Description != "" {
+		variable.SetAttributeValue("description", cty.StringVal(attribute.Description))
+	}
+	variable.SetAttributeTraversal("type", hcl.Traversal{hcl.TraverseRoot{Name: "string"}})
+	variable.SetAttributeValue("sensitive", cty.True)
+	return name
+}
+
+var invalidNameCharacters = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// uniqueName turns name into a valid identifier which is unique within its namespace, e.g. a resource type
+func (e *exporter) uniqueName(namespace string, name string) string {
+	name = strings.Trim(invalidNameCharacters.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if name == "" || !(name[0] == '_' || (name[0] >= 'a' && name[0] <= 'z')) {
+		name = "_" + name
+	}
+	unique := name
+	for i := 2; e.names[namespace+unique]; i++ {
+		unique = fmt.Sprintf("%s_%d", name, i)
+	}
+	e.names[namespace+unique] = true
+	return unique
+}
+
+func (e *exporter) file(name string) *hclwrite.File {
+	if _, ok := e.files[name]; !ok {
+		e.files[name] = hclwrite.NewEmptyFile()
+	}
+	return e.files[name]
+}
+
+// write writes the generated files to the output directory
+func (e *exporter) write() error {
+	if err := os.MkdirAll(e.options.Directory, 0o755); err != nil {
+		return fmt.Errorf("export: Failed to create directory %s: %+v", e.options.Directory, err)
+	}
+	for name, file := range e.files {
+		if err := os.WriteFile(filepath.Join(e.options.Directory, name), file.Bytes(), 0o644); err != nil {
+			return fmt.Errorf("export: Failed to write %s: %+v", name, err)
+		}
+	}
+	return nil
+}
+
+func (e *exporter) progress(format string, a ...interface{}) {
+	if e.options.Progress != nil {
+		fmt.Fprintf(e.options.Progress, format+"\n", a...)
+	}
+}
+
+// projects returns the projects matching the project key pattern
+func (e *exporter) projects(ctx context.Context) ([]client.Project, error) {
+	if e.projectList != nil {
+		return e.projectList, nil
+	}
+	projects, err := e.conf.client.Projects.SearchPages(client.ProjectsSearchRequest{}).All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	e.projectList = []client.Project{}
+	for _, project := range projects {
+		if e.options.ProjectKeyPattern == nil || e.options.ProjectKeyPattern.MatchString(project.Key) {
+			e.projectList = append(e.projectList, project)
+		}
+	}
+	return e.projectList, nil
+}
+
+// bindings returns the DevOps platform bindings of the projects matching the project key pattern, by project key
+func (e *exporter) bindings(ctx context.Context) (map[string]*client.AlmBinding, error) {
+	if e.bindingList != nil {
+		return e.bindingList, nil
+	}
+	projects, err := e.projects(ctx)
+	if err != nil {
+		return nil, err
+	}
+	e.bindingList = map[string]*client.AlmBinding{}
+	for _, project := range projects {
+		binding, err := e.conf.client.AlmSettings.GetBinding(ctx, project.Key)
+		if err != nil {
+			if client.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		e.bindingList[project.Key] = binding
+	}
+	return e.bindingList, nil
+}
+
+func (e *exporter) listProjects(ctx context.Context) ([]exportedResource, error) {
+	projects, err := e.projects(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resources := []exportedResource{}
+	for _, project := range projects {
+		resources = append(resources, exportedResource{name: project.Key, importID: project.Key})
+	}
+	return resources, nil
+}
+
+func (e *exporter) listQualityGates(ctx context.Context) ([]exportedResource, error) {
+	gates, err := e.conf.client.QualityGates.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resources := []exportedResource{}
+	for _, gate := range gates {
+		if !gate.IsBuiltIn {
+			resources = append(resources, exportedResource{name: gate.Name, importID: gate.Name})
+		}
+	}
+	return resources, nil
+}
+
+// qualityProfiles returns the quality profiles which are not built-in, the built-in ones can not be managed
+func (e *exporter) qualityProfiles(ctx context.Context) ([]client.QualityProfile, error) {
+	profiles, err := e.conf.client.QualityProfiles.Search(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(profiles, func(profile client.QualityProfile) bool { return profile.IsBuiltIn }), nil
+}
+
+func (e *exporter) listQualityProfiles(ctx context.Context) ([]exportedResource, error) {
+	profiles, err := e.qualityProfiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resources := []exportedResource{}
+	for _, profile := range profiles {
+		resources = append(resources, exportedResource{name: profile.Language + "_" + profile.Name, importID: profile.Key})
+	}
+	return resources, nil
+}
+
+func (e *exporter) listActivatedRules(ctx context.Context) ([]exportedResource, error) {
+	profiles, err := e.qualityProfiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resources := []exportedResource{}
+	for _, profile := range profiles {
+		rules, err := e.conf.client.Rules.SearchPages(client.RulesSearchRequest{QProfile: profile.Key, Activation: "true"}).All(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, rule := range rules {
+			resources = append(resources, exportedResource{
+				name:     profile.Language + "_" + profile.Name + "_" + rule.Key,
+				importID: profile.Key + "/" + rule.Key,
+			})
+		}
+	}
+	return resources, nil
+}
+
+// groups returns the groups which are not the default group, every user is a member of the default group
+func (e *exporter) groups(ctx context.Context) ([]client.Group, error) {
+	groups, err := e.conf.client.UserGroups.SearchPages(client.UserGroupsSearchRequest{}).All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(groups, func(group client.Group) bool { return group.IsDefault }), nil
+}
+
+func (e *exporter) listGroups(ctx context.Context) ([]exportedResource, error) {
+	groups, err := e.groups(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resources := []exportedResource{}
+	for _, group := range groups {
+		resources = append(resources, exportedResource{name: group.Name, importID: group.ID})
+	}
+	return resources, nil
+}
+
+func (e *exporter) listGroupMembers(ctx context.Context) ([]exportedResource, error) {
+	groups, err := e.groups(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resources := []exportedResource{}
+	for _, group := range groups {
+		members, err := e.conf.client.UserGroups.UsersPages(client.UserGroupsUsersRequest{Name: group.Name}).All(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, member := range members {
+			resources = append(resources, exportedResource{
+				name:     group.Name + "_" + member.Login,
+				importID: createGroupMembershipId(group.Name, member.Login),
+			})
+		}
+	}
+	return resources, nil
+}
+
+func (e *exporter) listPermissionTemplates(ctx context.Context) ([]exportedResource, error) {
+	templates, err := e.conf.client.Permissions.SearchTemplates(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	resources := []exportedResource{}
+	for _, template := range templates.PermissionTemplates {
+		resources = append(resources, exportedResource{name: template.Name, importID: template.ID})
+	}
+	return resources, nil
+}
+
+func (e *exporter) listPermissions(ctx context.Context) ([]exportedResource, error) {
+	resources := []exportedResource{}
+	// add lists the users and groups having permissions in scope, importPrefix and namePrefix identify the scope
+	add := func(request client.PermissionsSearchRequest, template bool, importPrefix string, namePrefix string) error {
+		groupsPages, usersPages := e.conf.client.Permissions.GroupsPages, e.conf.client.Permissions.UsersPages
+		if template {
+			groupsPages, usersPages = e.conf.client.Permissions.TemplateGroupsPages, e.conf.client.Permissions.TemplateUsersPages
+		}
+		groups, err := groupsPages(request).All(ctx)
+		if err != nil {
+			return err
+		}
+		for _, group := range groups {
+			if len(group.Permissions) > 0 {
+				resources = append(resources, exportedResource{name: namePrefix + "group_" + group.Name, importID: importPrefix + "group/" + group.Name})
+			}
+		}
+		users, err := usersPages(request).All(ctx)
+		if err != nil {
+			return err
+		}
+		for _, user := range users {
+			if len(user.Permissions) > 0 {
+				resources = append(resources, exportedResource{name: namePrefix + "user_" + user.Login, importID: importPrefix + "user/" + user.Login})
+			}
+		}
+		return nil
+	}
+
+	if err := add(client.PermissionsSearchRequest{}, false, "", "global_"); err != nil {
+		return nil, err
+	}
+	projects, err := e.projects(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, project := range projects {
+		if err := add(client.PermissionsSearchRequest{ProjectKey: project.Key}, false, project.Key+"/", project.Key+"_"); err != nil {
+			return nil, err
+		}
+	}
+	templates, err := e.conf.client.Permissions.SearchTemplates(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	for _, template := range templates.PermissionTemplates {
+		if err := add(client.PermissionsSearchRequest{TemplateID: template.ID}, true, "template_id/"+template.ID+"/", template.Name+"_"); err != nil {
+			return nil, err
+		}
+	}
+	return resources, nil
+}
+
+func (e *exporter) listSettings(ctx context.Context) ([]exportedResource, error) {
+	settings, err := e.conf.client.Settings.Values(ctx, nil, "")
+	if err != nil {
+		return nil, err
+	}
+	resources := []exportedResource{}
+	for _, setting := range settings {
+		// Secured settings are never returned with their value
+		if !setting.Inherited && !strings.HasSuffix(setting.Key, ".secured") {
+			resources = append(resources, exportedResource{name: setting.Key, importID: setting.Key})
+		}
+	}
+	return resources, nil
+}
+
+func (e *exporter) listWebhooks(ctx context.Context) ([]exportedResource, error) {
+	webhooks, err := e.conf.client.Webhooks.List(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	resources := []exportedResource{}
+	for _, webhook := range webhooks {
+		resources = append(resources, exportedResource{name: webhook.Name, importID: webhook.Key})
+	}
+
+	projects, err := e.projects(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, project := range projects {
+		webhooks, err := e.conf.client.Webhooks.List(ctx, project.Key)
+		if err != nil {
+			return nil, err
+		}
+		for _, webhook := range webhooks {
+			resources = append(resources, exportedResource{name: project.Key + "_" + webhook.Name, importID: webhook.Key + "/" + project.Key})
+		}
+	}
+	return resources, nil
+}
+
+func (e *exporter) listAzureAlmSettings(ctx context.Context) ([]exportedResource, error) {
+	definitions, err := e.conf.client.AlmSettings.ListDefinitions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resources := []exportedResource{}
+	for _, definition := range definitions.Azure {
+		// The importer expects the personal access token, which is left to a variable
+		resources = append(resources, exportedResource{name: definition.Key, importID: definition.Key + "/"})
+	}
+	return resources, nil
+}
+
+func (e *exporter) listBitbucketAlmSettings(ctx context.Context) ([]exportedResource, error) {
+	definitions, err := e.conf.client.AlmSettings.ListDefinitions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resources := []exportedResource{}
+	for _, definition := range definitions.Bitbucket {
+		// The importer expects the personal access token, which is left to a variable
+		resources = append(resources, exportedResource{name: definition.Key, importID: definition.Key + "/"})
+	}
+	return resources, nil
+}
+
+func (e *exporter) listBitbucketCloudAlmSettings(ctx context.Context) ([]exportedResource, error) {
+	definitions, err := e.conf.client.AlmSettings.ListDefinitions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resources := []exportedResource{}
+	for _, definition := range definitions.BitbucketCloud {
+		// The importer expects the client secret, which is left to a variable
+		resources = append(resources, exportedResource{name: definition.Key, importID: definition.Key + "/"})
+	}
+	return resources, nil
+}
+
+func (e *exporter) listGithubAlmSettings(ctx context.Context) ([]exportedResource, error) {
+	definitions, err := e.conf.client.AlmSettings.ListDefinitions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resources := []exportedResource{}
+	for _, definition := range definitions.Github {
+		resources = append(resources, exportedResource{name: definition.Key, importID: definition.Key})
+	}
+	return resources, nil
+}
+
+func (e *exporter) listGitlabAlmSettings(ctx context.Context) ([]exportedResource, error) {
+	definitions, err := e.conf.client.AlmSettings.ListDefinitions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resources := []exportedResource{}
+	for _, definition := range definitions.Gitlab {
+		// The importer expects the personal access token, which is left to a variable
+		resources = append(resources, exportedResource{name: definition.Key, importID: definition.Key + "/"})
+	}
+	return resources, nil
+}
+
+// bindingLister returns the list function of the binding resource of a DevOps platform
+func bindingLister(alm string) func(e *exporter, ctx context.Context) ([]exportedResource, error) {
+	return func(e *exporter, ctx context.Context) ([]exportedResource, error) {
+		bindings, err := e.bindings(ctx)
+		if err != nil {
+			return nil, err
+		}
+		projects := []string{}
+		for project, binding := range bindings {
+			if binding.Alm == alm {
+				projects = append(projects, project)
+			}
+		}
+		sort.Strings(projects)
+
+		resources := []exportedResource{}
+		for _, project := range projects {
+			binding := bindings[project]
+			importID := project + "/" + binding.Repository
+			switch alm {
+			case "azure":
+				// For Azure DevOps the slug holds the project name
+				importID = project + "/" + binding.Slug + "/" + binding.Repository
+			case "bitbucket":
+				importID = project + "/" + binding.Repository + "/" + binding.Slug
+			}
+			resources = append(resources, exportedResource{name: project, importID: importID})
+		}
+		return resources, nil
+	}
+}
+
+// emptyConfig returns a provider configuration without arguments, so every argument is read from the environment
+func emptyConfig(schema *tfprotov6.Schema) (*tfprotov6.DynamicValue, error) {
+	objectType := schema.ValueType().(tftypes.Object)
+	values := map[string]tftypes.Value{}
+	for name, attributeType := range objectType.AttributeTypes {
+		values[name] = tftypes.NewValue(attributeType, nil)
+	}
+	for _, block := range schema.Block.BlockTypes {
+		values[block.TypeName] = tftypes.NewValue(objectType.AttributeTypes[block.TypeName], []tftypes.Value{})
+	}
+	config, err := tfprotov6.NewDynamicValue(objectType, tftypes.NewValue(objectType, values))
+	if err != nil {
+		return nil, fmt.Errorf("export: Failed to create the provider configuration: %+v", err)
+	}
+	return &config, nil
+}
+
+// ctyValue converts a known value of the provider protocol to the equivalent value of the HCL writer
+func ctyValue(value tftypes.Value) (cty.Value, error) {
+	if value.IsNull() {
+		return cty.NullVal(cty.DynamicPseudoType), nil
+	}
+	switch {
+	case value.Type().Is(tftypes.String):
+		var s string
+		err := value.As(&s)
+		return cty.StringVal(s), err
+	case value.Type().Is(tftypes.Number):
+		n := new(big.Float)
+		err := value.As(n)
+		return cty.NumberVal(n), err
+	case value.Type().Is(tftypes.Bool):
+		var b bool
+		err := value.As(&b)
+		return cty.BoolVal(b), err
+	case value.Type().Is(tftypes.List{}), value.Type().Is(tftypes.Set{}), value.Type().Is(tftypes.Tuple{}):
+		elements := []tftypes.Value{}
+		if err := value.As(&elements); err != nil {
+			return cty.NilVal, err
+		}
+		if len(elements) == 0 {
+			return cty.EmptyTupleVal, nil
+		}
+		converted := []cty.Value{}
+		for _, element := range elements {
+			c, err := ctyValue(element)
+			if err != nil {
+				return cty.NilVal, err
+			}
+			converted = append(converted, c)
+		}
+		return cty.TupleVal(converted), nil
+	case value.Type().Is(tftypes.Map{}), value.Type().Is(tftypes.Object{}):
+		elements := map[string]tftypes.Value{}
+		if err := value.As(&elements); err != nil {
+			return cty.NilVal, err
+		}
+		if len(elements) == 0 {
+			return cty.EmptyObjectVal, nil
+		}
+		converted := map[string]cty.Value{}
+		for key, element := range elements {
+			c, err := ctyValue(element)
+			if err != nil {
+				return cty.NilVal, err
+			}
+			converted[key] = c
+		}
+		return cty.ObjectVal(converted), nil
+	}
+	return cty.NilVal, fmt.Errorf("unsupported type %s", value.Type())
+}
+
+// diagnosticsError returns the error diagnostics of a provider protocol response as an error
+func diagnosticsError(diagnostics []*tfprotov6.Diagnostic) error {
+	errs := []error{}
+	for _, d := range diagnostics {
+		if d.Severity == tfprotov6.DiagnosticSeverityError {
+			errs = append(errs, fmt.Errorf("%s: %s", d.Summary, d.Detail))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,238 @@
+package sonarqube
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/config"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	tfterraform "github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/jdamata/terraform-provider-sonarqube/internal/client"
+	"github.com/jdamata/terraform-provider-sonarqube/internal/fakesonarqube"
+)
+
+// testAccExportPopulate creates resources of every exported type on the server the provider is configured for
+func testAccExportPopulate(t *testing.T) {
+	ctx := context.Background()
+	p := Provider()
+	if diags := p.Configure(ctx, terraform.NewResourceConfigRaw(nil)); diags.HasError() {
+		t.Fatalf("failed to configure the provider: %+v", diagnosticsToError(diags))
+	}
+	c := p.Meta().(*ProviderConfiguration).client
+
+	steps := []func() error{
+		func() error {
+			_, err := c.Projects.Create(ctx, client.ProjectsCreateRequest{Name: "My project", Project: "my-project"})
+			return err
+		},
+		func() error {
+			_, err := c.Projects.Create(ctx, client.ProjectsCreateRequest{Name: "Filtered out", Project: "filtered-out"})
+			return err
+		},
+		func() error {
+			if _, err := c.QualityGates.Create(ctx, "My gate"); err != nil {
+				return err
+			}
+			_, err := c.QualityGates.CreateCondition(ctx, client.QualityGateConditionRequest{GateName: "My gate", Metric: "new_coverage", OP: "LT", Error: "50"})
+			return err
+		},
+		func() error {
+			profile, err := c.QualityProfiles.Create(ctx, "My profile", "xml")
+			if err != nil {
+				return err
+			}
+			return c.QualityProfiles.ActivateRule(ctx, client.ActivateRuleRequest{Key: profile.Key, Rule: "xml:S1135", Reset: "false", Severity: "MINOR"})
+		},
+		func() error {
+			if _, err := c.UserGroups.Create(ctx, "my-group", "My group"); err != nil {
+				return err
+			}
+			return c.UserGroups.AddUser(ctx, "my-group", fakesonarqube.DefaultLogin)
+		},
+		func() error {
+			if _, err := c.Permissions.CreateTemplate(ctx, client.PermissionTemplateRequest{Name: "My template"}); err != nil {
+				return err
+			}
+			return c.Permissions.AddGroupToTemplate(ctx, client.PermissionsRequest{Permission: "codeviewer", GroupName: "my-group", TemplateName: "My template"})
+		},
+		func() error {
+			return c.Permissions.AddGroup(ctx, client.PermissionsRequest{Permission: "admin", GroupName: "my-group", ProjectKey: "my-project"})
+		},
+		func() error {
+			return c.Settings.Set(ctx, client.SettingsSetRequest{Key: "sonar.dbcleaner.daysBeforeDeletingClosedIssues", Value: "60"})
+		},
+		func() error {
+			_, err := c.Webhooks.Create(ctx, client.WebhooksCreateRequest{Name: "My project webhook", Url: "https://example.com/hook", Project: "my-project"})
+			return err
+		},
+		func() error {
+			return c.AlmSettings.CreateGithub(ctx, client.GithubRequest{Key: "my-github", AppID: "1", ClientID: "2", ClientSecret: "secret", PrivateKey: "key", URL: "https://api.github.com"})
+		},
+		func() error {
+			return c.AlmSettings.SetGithubBinding(ctx, client.GithubBindingRequest{AlmSetting: "my-github", Project: "my-project", Repository: "org/repo"})
+		},
+	}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			t.Fatalf("failed to populate the server: %+v", err)
+		}
+	}
+}
+
+func TestAccExport(t *testing.T) {
+	// The export runs against a dedicated fake server, as it adopts every resource of the server and destroys them afterwards
+	server := fakesonarqube.NewServer(fakesonarqube.Options{})
+	defer server.Close()
+	t.Setenv("SONAR_HOST", server.URL)
+	t.Setenv("SONAR_USER", fakesonarqube.DefaultLogin)
+	t.Setenv("SONAR_PASS", fakesonarqube.DefaultPassword)
+	t.Setenv("SONAR_TOKEN", "")
+	t.Setenv("SONARQUBE_TOKEN", "")
+
+	directory := t.TempDir()
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// Applying the exported configuration imports every resource, the plan which follows must be empty
+				PreConfig: func() {
+					testAccExportPopulate(t)
+					// Groups, permissions and permission templates are left out, as the server defaults can not be destroyed
+					err := Export(context.Background(), ExportOptions{
+						Directory: directory,
+						ResourceTypes: []string{
+							"sonarqube_project", "sonarqube_qualitygate", "sonarqube_qualityprofile", "sonarqube_qualityprofile_activate_rule",
+							"sonarqube_setting", "sonarqube_webhook", "sonarqube_alm_github", "sonarqube_github_binding",
+						},
+						ProjectKeyPattern: regexp.MustCompile("^my-"),
+					})
+					if err != nil {
+						t.Fatalf("failed to export: %+v", err)
+					}
+				},
+				ConfigDirectory: config.StaticDirectory(directory),
+				ConfigVariables: config.Variables{
+					"alm_github_my-github_client_secret": config.StringVariable("secret"),
+					"alm_github_my-github_private_key":   config.StringVariable("key"),
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("sonarqube_project.my-project", "name", "My project"),
+					testAccCheckExportedResourceCount("sonarqube_project", 1),
+					resource.TestCheckResourceAttr("sonarqube_qualitygate.my_gate", "condition.0.threshold", "50"),
+					resource.TestCheckResourceAttr("sonarqube_qualityprofile_activate_rule.xml_my_profile_xml_s1135", "severity", "MINOR"),
+					resource.TestCheckResourceAttr("sonarqube_setting.sonar_dbcleaner_daysbeforedeletingclosedissues", "value", "60"),
+					resource.TestCheckResourceAttr("sonarqube_webhook.my-project_my_project_webhook", "project", "my-project"),
+					resource.TestCheckResourceAttr("sonarqube_github_binding.my-project", "repository", "org/repo"),
+				),
+			},
+		},
+	})
+}
+
+// testAccCheckExportedResourceCount checks the number of resources of a type in the state
+func testAccCheckExportedResourceCount(resourceType string, expected int) resource.TestCheckFunc {
+	return func(s *tfterraform.State) error {
+		count := 0
+		for _, rs := range s.RootModule().Resources {
+			if rs.Type == resourceType {
+				count++
+			}
+		}
+		if count != expected {
+			return fmt.Errorf("expected %d %s resources, got %d", expected, resourceType, count)
+		}
+		return nil
+	}
+}
+
+func TestAccExportPermissions(t *testing.T) {
+	server := fakesonarqube.NewServer(fakesonarqube.Options{})
+	defer server.Close()
+	t.Setenv("SONAR_HOST", server.URL)
+	t.Setenv("SONAR_USER", fakesonarqube.DefaultLogin)
+	t.Setenv("SONAR_PASS", fakesonarqube.DefaultPassword)
+	t.Setenv("SONAR_TOKEN", "")
+	t.Setenv("SONARQUBE_TOKEN", "")
+	if os.Getenv(resource.EnvTfAcc) == "" {
+		t.Skipf("Acceptance tests skipped unless env '%s' set", resource.EnvTfAcc)
+	}
+	testAccExportPopulate(t)
+
+	directory := t.TempDir()
+	err := Export(context.Background(), ExportOptions{
+		Directory:         directory,
+		ResourceTypes:     []string{"sonarqube_project", "sonarqube_group", "sonarqube_group_member", "sonarqube_permission_template", "sonarqube_permissions"},
+		ProjectKeyPattern: regexp.MustCompile("^my-"),
+	})
+	if err != nil {
+		t.Fatalf("failed to export: %+v", err)
+	}
+
+	read := func(name string) string {
+		content, err := os.ReadFile(filepath.Join(directory, name))
+		if err != nil {
+			t.Fatalf("failed to read %s: %+v", name, err)
+		}
+		return string(content)
+	}
+	for name, expected := range map[string][]string{
+		"sonarqube_group_member.tf": {
+			`resource "sonarqube_group_member" "my-group_admin" {`,
+			`name       = sonarqube_group.my-group.name`,
+		},
+		"sonarqube_permissions.tf": {
+			`resource "sonarqube_permissions" "my-project_group_my-group" {`,
+			`project_key = sonarqube_project.my-project.project`,
+			`template_id = sonarqube_permission_template.my_template.id`,
+		},
+		"imports.tf": {
+			`id = "my-project/group/my-group"`,
+			`id = "my-group[admin]"`,
+		},
+	} {
+		content := read(name)
+		for _, e := range expected {
+			if !strings.Contains(content, e) {
+				t.Errorf("%s does not contain %q:\n%s", name, e, content)
+			}
+		}
+	}
+}
+
+func TestAccExportResourceTypes(t *testing.T) {
+	server := fakesonarqube.NewServer(fakesonarqube.Options{Edition: "Community"})
+	defer server.Close()
+	t.Setenv("SONAR_HOST", server.URL)
+	t.Setenv("SONAR_USER", fakesonarqube.DefaultLogin)
+	t.Setenv("SONAR_PASS", fakesonarqube.DefaultPassword)
+	t.Setenv("SONAR_TOKEN", "")
+	t.Setenv("SONARQUBE_TOKEN", "")
+	if os.Getenv(resource.EnvTfAcc) == "" {
+		t.Skipf("Acceptance tests skipped unless env '%s' set", resource.EnvTfAcc)
+	}
+
+	err := Export(context.Background(), ExportOptions{Directory: t.TempDir(), ResourceTypes: []string{"sonarqube_unknown"}})
+	if err == nil || !strings.Contains(err.Error(), "sonarqube_unknown is not supported") {
+		t.Errorf("expected an unsupported resource type error, got %+v", err)
+	}
+
+	directory := t.TempDir()
+	err = Export(context.Background(), ExportOptions{Directory: directory, ResourceTypes: []string{"sonarqube_group", "sonarqube_github_binding"}})
+	if err != nil {
+		t.Fatalf("failed to export: %+v", err)
+	}
+	files, _ := filepath.Glob(filepath.Join(directory, "*.tf"))
+	for i := range files {
+		files[i] = filepath.Base(files[i])
+	}
+	// The bindings are skipped on the Community edition, sonar-administrators is the only group which is not the default group
+	if strings.Join(files, ",") != "imports.tf,sonarqube_group.tf" {
+		t.Errorf("unexpected files %v", files)
+	}
+}
@@ -1,18 +1,25 @@
 package sonarqube
 
 import (
+	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"fmt"
-	"io/ioutil"
+	"log"
 	"net/http"
 	"net/url"
+	"os"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/go-cleanhttp"
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/go-retryablehttp"
 	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/tidwall/gjson"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/jdamata/terraform-provider-sonarqube/internal/client"
 )
 
 var sonarqubeProvider *schema.Provider
@@ -61,22 +68,131 @@ func Provider() *schema.Provider {
 				DefaultFunc: schema.MultiEnvDefaultFunc([]string{"INSTALLED_EDITION"}, ""),
 				Optional:    true,
 			},
+			"sonarcloud": {
+				Optional:    true,
+				Type:        schema.TypeBool,
+				Description: "Manages SonarCloud instead of a SonarQube server. The version and edition are not requested from the server, `organization` is sent with every request and resources SonarCloud lacks fail to plan. Defaults to `false`.",
+				Default:     false,
+			},
+			"organization": {
+				Optional:    true,
+				Type:        schema.TypeString,
+				DefaultFunc: schema.MultiEnvDefaultFunc([]string{"SONAR_ORGANIZATION", "SONARQUBE_ORGANIZATION"}, ""),
+				Description: "The SonarCloud organization managed by the provider. Required when `sonarcloud` is enabled, not supported otherwise.",
+			},
 			"tls_insecure_skip_verify": {
 				Optional:    true,
 				Type:        schema.TypeBool,
 				Description: "Allows ignoring insecure certificates when set to true. Defaults to false. Disabling TLS verification is dangerous and should only be done for local testing.",
 				Default:     false,
 			},
+			"ca_cert_pem": {
+				Optional:      true,
+				Type:          schema.TypeString,
+				Description:   "PEM encoded certificates of the certificate authorities to trust in addition to the system ones, e.g. an internal CA.",
+				ConflictsWith: []string{"ca_cert_file"},
+			},
+			"ca_cert_file": {
+				Optional:      true,
+				Type:          schema.TypeString,
+				Description:   "Path to a file holding PEM encoded certificates of the certificate authorities to trust in addition to the system ones.",
+				ConflictsWith: []string{"ca_cert_pem"},
+			},
+			"client_cert_pem": {
+				Optional:      true,
+				Type:          schema.TypeString,
+				Description:   "PEM encoded client certificate for mutual TLS authentication. Requires client_key_pem or client_key_file.",
+				ConflictsWith: []string{"client_cert_file"},
+			},
+			"client_cert_file": {
+				Optional:      true,
+				Type:          schema.TypeString,
+				Description:   "Path to a file holding a PEM encoded client certificate for mutual TLS authentication. Requires client_key_pem or client_key_file.",
+				ConflictsWith: []string{"client_cert_pem"},
+			},
+			"client_key_pem": {
+				Optional:      true,
+				Type:          schema.TypeString,
+				Description:   "PEM encoded private key of the client certificate.",
+				Sensitive:     true,
+				ConflictsWith: []string{"client_key_file"},
+			},
+			"client_key_file": {
+				Optional:      true,
+				Type:          schema.TypeString,
+				Description:   "Path to a file holding the PEM encoded private key of the client certificate.",
+				ConflictsWith: []string{"client_key_pem"},
+			},
+			"discover_capabilities": {
+				Optional:    true,
+				Type:        schema.TypeBool,
+				Description: "Discover the web services, actions and parameters exposed by the server with `api/webservices/list`, and the v2 web API on SonarQube 10 and newer, when the provider is configured. The supported features are then derived from what the server exposes rather than from its version, as are the endpoints used to read and deactivate users and to identify quality gates. Defaults to `false`.",
+				Default:     false,
+			},
 			"anonymize_user_on_delete": {
 				Optional:    true,
 				Type:        schema.TypeBool,
 				Description: "Allows anonymizing users on destroy. Requires Sonarqube version >= 9.7.",
 				Default:     false,
 			},
+			"retry_max": {
+				Optional:     true,
+				Type:         schema.TypeInt,
+				Description:  "Maximum number of times a request is retried after a connection error or a 429, 502, 503 or 504 response. Defaults to 4.",
+				Default:      4,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"retry_wait_min": {
+				Optional:     true,
+				Type:         schema.TypeString,
+				Description:  "Minimum time to wait before retrying a request, unless the server asks for a different delay with a Retry-After header. Defaults to 1s.",
+				Default:      "1s",
+				ValidateFunc: validateDuration,
+			},
+			"retry_wait_max": {
+				Optional:     true,
+				Type:         schema.TypeString,
+				Description:  "Maximum time to wait before retrying a request, including a longer delay asked for by the server with a Retry-After header. Defaults to 30s.",
+				Default:      "30s",
+				ValidateFunc: validateDuration,
+			},
+			"request_timeout": {
+				Optional:     true,
+				Type:         schema.TypeString,
+				Description:  "Time limit for a single request to SonarQube, including reading the response. Defaults to 0s, which means no timeout.",
+				Default:      "0s",
+				ValidateFunc: validateDuration,
+			},
+			"wait_for_ready": {
+				Optional:    true,
+				Type:        schema.TypeList,
+				MaxItems:    1,
+				Description: "Waits for Sonarqube to be up before configuring the provider, e.g. when the server is started in the same apply.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"timeout": {
+							Optional:     true,
+							Type:         schema.TypeString,
+							Description:  "How long to wait for Sonarqube to be up. Defaults to 10m.",
+							Default:      "10m",
+							ValidateFunc: validateDuration,
+						},
+						"poll_interval": {
+							Optional:     true,
+							Type:         schema.TypeString,
+							Description:  "How long to wait between two status checks. Defaults to 5s.",
+							Default:      "5s",
+							ValidateFunc: validateDuration,
+						},
+					},
+				},
+			},
 		},
 		// Add the resources supported by this provider to this map.
+		// Resources migrated to the plugin framework are listed in frameworkProvider.Resources instead.
 		ResourcesMap: map[string]*schema.Resource{
 			"sonarqube_alm_azure":                          resourceSonarqubeAlmAzure(),
+			"sonarqube_application":                        resourceSonarqubeApplication(),
 			"sonarqube_azure_binding":                      resourceSonarqubeAzureBinding(),
 			"sonarqube_group":                              resourceSonarqubeGroup(),
 			"sonarqube_group_member":                       resourceSonarqubeGroupMember(),
@@ -85,6 +201,8 @@ func Provider() *schema.Provider {
 			"sonarqube_plugin":                             resourceSonarqubePlugin(),
 			"sonarqube_project":                            resourceSonarqubeProject(),
 			"sonarqube_project_main_branch":                resourceSonarqubeProjectMainBranch(),
+			"sonarqube_project_branch":                     resourceSonarqubeProjectBranch(),
+			"sonarqube_project_link":                       resourceSonarqubeProjectLink(),
 			"sonarqube_portfolio":                          resourceSonarqubePortfolio(),
 			"sonarqube_qualityprofile":                     resourceSonarqubeQualityProfile(),
 			"sonarqube_qualityprofile_project_association": resourceSonarqubeQualityProfileProjectAssociation(),
@@ -93,136 +211,322 @@ func Provider() *schema.Provider {
 			"sonarqube_qualitygate_usergroup_association":  resourceSonarqubeQualityGateUsergroupAssociation(),
 			"sonarqube_user":                               resourceSonarqubeUser(),
 			"sonarqube_user_external_identity":             resourceSonarqubeUserExternalIdentity(),
-			"sonarqube_user_token":                         resourceSonarqubeUserToken(),
 			"sonarqube_webhook":                            resourceSonarqubeWebhook(),
 			"sonarqube_rule":                               resourceSonarqubeRule(),
-			"sonarqube_setting":                            resourceSonarqubeSettings(),
 			"sonarqube_qualityprofile_activate_rule":       resourceSonarqubeQualityProfileRule(),
 			"sonarqube_alm_github":                         resourceSonarqubeAlmGithub(),
 			"sonarqube_github_binding":                     resourceSonarqubeGithubBinding(),
 			"sonarqube_alm_gitlab":                         resourceSonarqubeAlmGitlab(),
 			"sonarqube_gitlab_binding":                     resourceSonarqubeGitlabBinding(),
+			"sonarqube_alm_bitbucket":                      resourceSonarqubeAlmBitbucket(),
+			"sonarqube_bitbucket_binding":                  resourceSonarqubeBitbucketBinding(),
+			"sonarqube_alm_bitbucket_cloud":                resourceSonarqubeAlmBitbucketCloud(),
+			"sonarqube_bitbucket_cloud_binding":            resourceSonarqubeBitbucketCloudBinding(),
 			"sonarqube_new_code_periods":                   resourceSonarqubeNewCodePeriodsBinding(),
 		},
 		DataSourcesMap: map[string]*schema.Resource{
-			"sonarqube_user":           dataSourceSonarqubeUser(),
-			"sonarqube_group":          dataSourceSonarqubeGroup(),
-			"sonarqube_project":        dataSourceSonarqubeProject(),
-			"sonarqube_portfolio":      dataSourceSonarqubePortfolio(),
-			"sonarqube_qualityprofile": dataSourceSonarqubeQualityProfile(),
-			"sonarqube_qualitygate":    dataSourceSonarqubeQualityGate(),
-			"sonarqube_rule":           dataSourceSonarqubeRule(),
+			"sonarqube_alm_settings":           dataSourceSonarqubeAlmSettings(),
+			"sonarqube_alm_setting_validation": dataSourceSonarqubeAlmSettingValidation(),
+			"sonarqube_user":                   dataSourceSonarqubeUser(),
+			"sonarqube_group":                  dataSourceSonarqubeGroup(),
+			"sonarqube_project":                dataSourceSonarqubeProject(),
+			"sonarqube_project_branches":       dataSourceSonarqubeProjectBranches(),
+			"sonarqube_portfolio":              dataSourceSonarqubePortfolio(),
+			"sonarqube_qualityprofile":         dataSourceSonarqubeQualityProfile(),
+			"sonarqube_qualitygate":            dataSourceSonarqubeQualityGate(),
+			"sonarqube_rule":                   dataSourceSonarqubeRule(),
 		},
-		ConfigureFunc: configureProvider,
+		ConfigureContextFunc: configureProvider,
 	}
 	return sonarqubeProvider
 }
 
 // ProviderConfiguration contains the sonarqube providers configuration
 type ProviderConfiguration struct {
-	httpClient              *retryablehttp.Client
-	sonarQubeURL            url.URL
+	client                  *client.Client
 	sonarQubeVersion        *version.Version
 	sonarQubeEdition        string
 	sonarQubeAnonymizeUsers bool
+	// sonarCloud is set when the provider manages SonarCloud, whose version and edition are unknown
+	sonarCloud bool
+	// sonarQubeAPI is the web API discovered with discover_capabilities, nil when discovery is disabled
+	sonarQubeAPI *serverAPI
 }
 
-func configureProvider(d *schema.ResourceData) (interface{}, error) {
+func configureProvider(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
 	transport := cleanhttp.DefaultPooledTransport()
 	if proxy, ok := d.GetOk("http_proxy"); ok {
 		proxyUrl, err := url.Parse(proxy.(string))
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse http_proxy: %+v", err)
+			return nil, attributeDiagnostics("http_proxy", "failed to parse http_proxy", err)
 		}
 		transport.Proxy = http.ProxyURL(proxyUrl)
 	}
-	transport.TLSClientConfig = &tls.Config{
-		InsecureSkipVerify: d.Get("tls_insecure_skip_verify").(bool),
+	tlsConfig, err := configureTLS(d)
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+	transport.TLSClientConfig = tlsConfig
+	if tlsConfig.InsecureSkipVerify {
+		diags = append(diags, diag.Diagnostic{
+			Severity:      diag.Warning,
+			Summary:       "TLS certificate verification is disabled",
+			Detail:        "tls_insecure_skip_verify should only be used for local testing. Configure ca_cert_pem or ca_cert_file to trust a private certificate authority instead.",
+			AttributePath: cty.GetAttrPath("tls_insecure_skip_verify"),
+		})
+	}
+
+	retryWaitMin, _ := time.ParseDuration(d.Get("retry_wait_min").(string))
+	retryWaitMax, _ := time.ParseDuration(d.Get("retry_wait_max").(string))
+	if retryWaitMin > retryWaitMax {
+		return nil, attributeDiagnostics("retry_wait_min", "invalid retry configuration", fmt.Errorf("retry_wait_min (%s) must not be greater than retry_wait_max (%s)", retryWaitMin, retryWaitMax))
 	}
+	requestTimeout, _ := time.ParseDuration(d.Get("request_timeout").(string))
 
-	client := retryablehttp.NewClient()
-	client.HTTPClient.Transport = transport
+	httpClient := retryablehttp.NewClient()
+	httpClient.HTTPClient.Transport = transport
+	httpClient.HTTPClient.Timeout = requestTimeout
+	httpClient.RetryMax = d.Get("retry_max").(int)
+	httpClient.RetryWaitMin = retryWaitMin
+	httpClient.RetryWaitMax = retryWaitMax
+	httpClient.CheckRetry = client.RetryPolicy
+	httpClient.Backoff = client.Backoff
+	// Return the last response once retries are exhausted, so it is reported as an APIError with the SonarQube messages
+	httpClient.ErrorHandler = retryablehttp.PassthroughErrorHandler
+	// Retries are logged through the Terraform logging system instead of retryablehttp's default stdout logger
+	httpClient.Logger = nil
+	httpClient.RequestLogHook = client.LogRetry
 
 	host, err := url.Parse(d.Get("host").(string))
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse sonarqube host: %+v", err)
+		return nil, attributeDiagnostics("host", "failed to parse sonarqube host", err)
 	}
 
 	sonarQubeURL := url.URL{
-		Scheme:     host.Scheme,
-		Host:       host.Host,
-		Path:       host.Path,
-		ForceQuery: true,
+		Scheme: host.Scheme,
+		Host:   host.Host,
+		Path:   host.Path,
 	}
 
-	if token, ok := d.GetOk("token"); ok {
-		sonarQubeURL.User = url.UserPassword(token.(string), "")
-	} else {
-		sonarQubeURL.User = url.UserPassword(d.Get("user").(string), d.Get("pass").(string))
+	// Credentials are sent in the Authorization header so they never end up in URLs, errors or logs.
+	// A token is used as login for basic authentication until we know the server accepts Bearer tokens.
+	token, hasToken := d.GetOk("token")
+	authenticator := client.BasicAuth(d.Get("user").(string), d.Get("pass").(string))
+	if hasToken {
+		authenticator = client.BasicAuth(token.(string), "")
+	}
+
+	sonarQubeClient := client.New(httpClient, sonarQubeURL, authenticator)
+
+	organization := d.Get("organization").(string)
+	if d.Get("sonarcloud").(bool) {
+		if organization == "" {
+			return nil, attributeDiagnostics("organization", "missing sonarcloud organization", fmt.Errorf("organization must be set when sonarcloud is enabled"))
+		}
+		return configureSonarCloud(ctx, d, httpClient, sonarQubeURL, authenticator, organization)
+	}
+	if organization != "" {
+		return nil, attributeDiagnostics("organization", "organizations are not supported by sonarqube", fmt.Errorf("organization can only be set when sonarcloud is enabled"))
+	}
+
+	if waitForReady, ok := d.GetOk("wait_for_ready"); ok {
+		timeout, pollInterval := 10*time.Minute, 5*time.Second
+		if settings, ok := waitForReady.([]interface{})[0].(map[string]interface{}); ok {
+			timeout, _ = time.ParseDuration(settings["timeout"].(string))
+			pollInterval, _ = time.ParseDuration(settings["poll_interval"].(string))
+		}
+		if err := waitForServerReady(ctx, sonarQubeClient, timeout, pollInterval); err != nil {
+			return nil, attributeDiagnostics("wait_for_ready", "sonarqube is not ready", err)
+		}
 	}
 
 	// If either of installed_version or installed_edition is not set, we need to fetch them from the API
 	installedVersion := d.Get("installed_version").(string)
 	installedEdition := d.Get("installed_edition").(string)
 	if installedVersion == "" || installedEdition == "" {
-		installedVersionAPI, installedEditionAPI, err := sonarqubeSystemInfo(client, sonarQubeURL)
+		info, err := sonarQubeClient.System.Info(ctx)
 		if err != nil {
-			return nil, err
+			return nil, diag.Errorf("cannot get sonarqube version/edition. Please configure installed_version and installed_edition: %+v", err)
 		}
 
 		if installedVersion == "" {
-			installedVersion = installedVersionAPI
+			installedVersion = info.System.Version
 		}
 		if installedEdition == "" {
-			installedEdition = installedEditionAPI
+			installedEdition = info.System.Edition
 		}
 	}
 
 	parsedInstalledVersion, err := version.NewVersion(installedVersion)
 	if err != nil {
-		return nil, fmt.Errorf("failed to convert sonarqube version to a version: %+v", err)
+		return nil, attributeDiagnostics("installed_version", "failed to convert sonarqube version to a version", err)
 	}
 
 	minimumVersion, _ := version.NewVersion("9.9")
 	if parsedInstalledVersion.LessThan(minimumVersion) {
-		return nil, fmt.Errorf("unsupported version of sonarqube. Minimum supported version is %+v. Running version is %+v", minimumVersion, installedVersion)
+		return nil, diag.Errorf("unsupported version of sonarqube. Minimum supported version is %+v. Running version is %+v", minimumVersion, installedVersion)
+	}
+
+	// Bearer tokens are supported since version 10.0, basic authentication with a token is deprecated there
+	minimumVersionForBearer, _ := version.NewVersion("10.0")
+	if hasToken && parsedInstalledVersion.GreaterThanOrEqual(minimumVersionForBearer) {
+		sonarQubeClient = client.New(httpClient, sonarQubeURL, client.BearerAuth(token.(string)))
+	}
+
+	conf := &ProviderConfiguration{
+		client:           sonarQubeClient,
+		sonarQubeVersion: parsedInstalledVersion,
+		sonarQubeEdition: installedEdition,
+	}
+	if d.Get("discover_capabilities").(bool) {
+		conf.sonarQubeAPI, err = discoverServerAPI(ctx, sonarQubeClient, parsedInstalledVersion)
+		if err != nil {
+			return nil, attributeDiagnostics("discover_capabilities", "failed to discover the sonarqube web API", err)
+		}
 	}
 
-	// Anonymizing users is supported since version 9.7. For older releases we reset it to false:
-	minimumVersionForAnonymize, _ := version.NewVersion("9.7")
-	anonymizeUsers := d.Get("anonymize_user_on_delete").(bool) && parsedInstalledVersion.GreaterThanOrEqual(minimumVersionForAnonymize)
+	// Releases which cannot anonymize users ignore anonymize_user_on_delete
+	conf.sonarQubeAnonymizeUsers = d.Get("anonymize_user_on_delete").(bool) && conf.checkCapability(capabilityAnonymizeUsers) == nil
 
-	return &ProviderConfiguration{
-		httpClient:              client,
-		sonarQubeURL:            sonarQubeURL,
-		sonarQubeVersion:        parsedInstalledVersion,
-		sonarQubeEdition:        installedEdition,
-		sonarQubeAnonymizeUsers: anonymizeUsers,
-	}, nil
+	return conf, diags
 }
 
-func sonarqubeSystemInfo(client *retryablehttp.Client, sonarqube url.URL) (string, string, error) {
-	// Make request to sonarqube version endpoint
-	sonarqube.Path = strings.TrimSuffix(sonarqube.Path, "/") + "/api/system/info"
-	resp, err := httpRequestHelper(
-		client,
-		"GET",
-		sonarqube.String(),
-		http.StatusOK,
-		"sonarqubeHealth",
-	)
+// Configures the provider for SonarCloud. SonarCloud always runs the latest release and does not expose
+// api/system/info, so capabilities are only checked against what SonarCloud supports.
+func configureSonarCloud(ctx context.Context, d *schema.ResourceData, httpClient *retryablehttp.Client, sonarCloudURL url.URL, authenticator client.Authenticator, organization string) (interface{}, diag.Diagnostics) {
+	if token, ok := d.GetOk("token"); ok {
+		authenticator = client.BearerAuth(token.(string))
+	}
+	sonarCloudClient := client.New(httpClient, sonarCloudURL, authenticator)
+	sonarCloudClient.SetOrganization(organization)
+
+	conf := &ProviderConfiguration{
+		client:           sonarCloudClient,
+		sonarQubeEdition: "SonarCloud",
+		sonarCloud:       true,
+	}
+	if d.Get("discover_capabilities").(bool) {
+		var err error
+		conf.sonarQubeAPI, err = discoverServerAPI(ctx, sonarCloudClient, nil)
+		if err != nil {
+			return nil, attributeDiagnostics("discover_capabilities", "failed to discover the sonarcloud web API", err)
+		}
+	}
+	return conf, nil
+}
+
+// Builds the TLS configuration of the transport from the provider's CA and client certificate arguments
+func configureTLS(d *schema.ResourceData) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: d.Get("tls_insecure_skip_verify").(bool),
+	}
+
+	caCert, err := pemFromAttribute(d, "ca_cert_pem", "ca_cert_file")
+	if err != nil {
+		return nil, err
+	}
+	if caCert != nil {
+		// Trust the given certificate authorities in addition to the system ones
+		rootCAs, err := x509.SystemCertPool()
+		if err != nil {
+			log.Printf("[WARN][configureTLS] Failed to load the system certificate pool, only trusting ca_cert_pem/ca_cert_file: %+v", err)
+			rootCAs = x509.NewCertPool()
+		}
+		if !rootCAs.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("failed to parse ca_cert_pem/ca_cert_file: no PEM encoded certificate found")
+		}
+		tlsConfig.RootCAs = rootCAs
+	}
+
+	clientCert, err := pemFromAttribute(d, "client_cert_pem", "client_cert_file")
 	if err != nil {
-		return "", "", fmt.Errorf("cannot get sonarqube version/edition. Please configure installed_version and installed_edition: %+v", err)
+		return nil, err
+	}
+	clientKey, err := pemFromAttribute(d, "client_key_pem", "client_key_file")
+	if err != nil {
+		return nil, err
+	}
+	if (clientCert == nil) != (clientKey == nil) {
+		return nil, fmt.Errorf("a client certificate (client_cert_pem/client_cert_file) and its key (client_key_pem/client_key_file) must be configured together")
+	}
+	if clientCert != nil {
+		certificate, err := tls.X509KeyPair(clientCert, clientKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the client certificate: %+v", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
 	}
-	defer resp.Body.Close()
 
-	// Read in the response
-	responseData, err := ioutil.ReadAll(resp.Body)
+	return tlsConfig, nil
+}
+
+// Returns the PEM content set inline with pemAttribute or read from the path set with fileAttribute, or nil if neither is set
+func pemFromAttribute(d *schema.ResourceData, pemAttribute string, fileAttribute string) ([]byte, error) {
+	if value, ok := d.GetOk(pemAttribute); ok {
+		return []byte(value.(string)), nil
+	}
+	if path, ok := d.GetOk(fileAttribute); ok {
+		content, err := os.ReadFile(path.(string))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %+v", fileAttribute, err)
+		}
+		return content, nil
+	}
+	return nil, nil
+}
+
+// Polls api/system/status until Sonarqube is UP. When the credentials are allowed to, api/system/health is checked as well
+// so the provider does not start configuring a server that is up but not functional yet.
+func waitForServerReady(ctx context.Context, sonarQubeClient *client.Client, timeout time.Duration, pollInterval time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	lastStatus := "unknown"
+	for {
+		ready, status := sonarQubeServerReady(ctx, sonarQubeClient)
+		if ready {
+			return nil
+		}
+		// A request aborted by the timeout tells nothing about the server, keep the status observed before
+		if ctx.Err() == nil {
+			lastStatus = status
+		}
+		log.Printf("[DEBUG][waitForServerReady] Sonarqube is not ready yet: %s", lastStatus)
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("sonarqube was not ready after %s. Last observed status: %s", timeout, lastStatus)
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// Checks once whether Sonarqube is ready and returns the observed status
+func sonarQubeServerReady(ctx context.Context, sonarQubeClient *client.Client) (bool, string) {
+	status, err := sonarQubeClient.System.Status(ctx)
 	if err != nil {
-		return "", "", fmt.Errorf("failed to parse response body on GET sonarqube system/info api: %+v", err)
+		return false, fmt.Sprintf("unreachable (%+v)", err)
+	}
+	if status.Status != "UP" {
+		return false, status.Status
 	}
 
-	sonarqubeVersion := gjson.GetBytes(responseData, "System.Version").String()
-	sonarqubeEdition := gjson.GetBytes(responseData, "System.Edition").String()
-	return sonarqubeVersion, sonarqubeEdition, nil
+	health, err := sonarQubeClient.System.Health(ctx)
+	if err != nil {
+		if client.IsForbidden(err) {
+			// The health endpoint requires the 'Administer System' permission, the status is all we can check
+			return true, status.Status
+		}
+		return false, fmt.Sprintf("UP, health unknown (%+v)", err)
+	}
+	if health.Health == "RED" {
+		causes := make([]string, 0, len(health.Causes))
+		for _, cause := range health.Causes {
+			causes = append(causes, cause.Message)
+		}
+		return false, fmt.Sprintf("UP, health RED (%s)", strings.Join(causes, "; "))
+	}
+	return true, status.Status
 }
@@ -0,0 +1,138 @@
+package sonarqube
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	pschema "github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-mux/tf5to6server"
+	"github.com/hashicorp/terraform-plugin-mux/tf6muxserver"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// ProtoV6ProviderServerFactory serves the resources of the SDKv2 provider and of the plugin framework provider as a
+// single provider, so resources can be migrated to the plugin framework one at a time.
+func ProtoV6ProviderServerFactory(ctx context.Context, sdkProvider *schema.Provider) (func() tfprotov6.ProviderServer, error) {
+	upgradedSdkServer, err := tf5to6server.UpgradeServer(ctx, sdkProvider.GRPCProvider)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upgrade the SDKv2 provider to protocol version 6: %+v", err)
+	}
+
+	muxServer, err := tf6muxserver.NewMuxServer(ctx,
+		// The SDKv2 provider must be configured first, the framework provider reuses its configuration
+		func() tfprotov6.ProviderServer { return upgradedSdkServer },
+		providerserver.NewProtocol6(NewFrameworkProvider(sdkProvider)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the provider server: %+v", err)
+	}
+	return muxServer.ProviderServer, nil
+}
+
+// frameworkDefaultTimeout is the default timeout of the operations of plugin framework resources
+const frameworkDefaultTimeout = 5 * time.Minute
+
+// frameworkProvider serves the resources migrated to the plugin framework. It shares the configuration and the API
+// client of the SDKv2 provider instead of configuring its own.
+type frameworkProvider struct {
+	sdkProvider *schema.Provider
+}
+
+var _ provider.Provider = &frameworkProvider{}
+
+// NewFrameworkProvider returns the plugin framework provider sharing the configuration of sdkProvider
+func NewFrameworkProvider(sdkProvider *schema.Provider) provider.Provider {
+	return &frameworkProvider{sdkProvider: sdkProvider}
+}
+
+func (p *frameworkProvider) Metadata(_ context.Context, _ provider.MetadataRequest, resp *provider.MetadataResponse) {
+	resp.TypeName = "sonarqube"
+}
+
+// Schema must be identical to the schema of the SDKv2 provider for both to be served together, so it is derived from it
+func (p *frameworkProvider) Schema(_ context.Context, _ provider.SchemaRequest, resp *provider.SchemaResponse) {
+	attributes, blocks := frameworkProviderAttributes(p.sdkProvider.Schema)
+	resp.Schema = pschema.Schema{
+		Attributes: attributes,
+		Blocks:     blocks,
+	}
+}
+
+func (p *frameworkProvider) Configure(_ context.Context, _ provider.ConfigureRequest, resp *provider.ConfigureResponse) {
+	// The SDKv2 provider has been configured from the same configuration by now
+	providerConfiguration, ok := p.sdkProvider.Meta().(*ProviderConfiguration)
+	if !ok {
+		resp.Diagnostics.AddError("frameworkProviderConfigure: Provider is not configured", "The SDKv2 provider must be configured before the plugin framework provider.")
+		return
+	}
+	resp.ResourceData = providerConfiguration
+	resp.DataSourceData = providerConfiguration
+}
+
+func (p *frameworkProvider) Resources(_ context.Context) []func() resource.Resource {
+	return []func() resource.Resource{
+		newUserTokenResource,
+		newSettingResource,
+	}
+}
+
+func (p *frameworkProvider) DataSources(_ context.Context) []func() datasource.DataSource {
+	return []func() datasource.DataSource{}
+}
+
+// frameworkProviderAttributes converts the SDKv2 provider schema to the equivalent plugin framework attributes and blocks
+func frameworkProviderAttributes(sdkSchema map[string]*schema.Schema) (map[string]pschema.Attribute, map[string]pschema.Block) {
+	attributes := map[string]pschema.Attribute{}
+	blocks := map[string]pschema.Block{}
+	for name, s := range sdkSchema {
+		// Like the SDKv2, a required attribute with a default value in the environment is optional
+		required, optional := s.Required, s.Optional
+		if required && s.DefaultFunc != nil {
+			if value, err := s.DefaultFunc(); err != nil || value != nil {
+				required, optional = false, true
+			}
+		}
+
+		switch s.Type {
+		case schema.TypeString:
+			attributes[name] = pschema.StringAttribute{Required: required, Optional: optional, Sensitive: s.Sensitive, Description: s.Description}
+		case schema.TypeBool:
+			attributes[name] = pschema.BoolAttribute{Required: required, Optional: optional, Sensitive: s.Sensitive, Description: s.Description}
+		case schema.TypeInt:
+			attributes[name] = pschema.Int64Attribute{Required: required, Optional: optional, Sensitive: s.Sensitive, Description: s.Description}
+		case schema.TypeList:
+			nestedAttributes, nestedBlocks := frameworkProviderAttributes(s.Elem.(*schema.Resource).Schema)
+			blocks[name] = pschema.ListNestedBlock{
+				Description: s.Description,
+				NestedObject: pschema.NestedBlockObject{
+					Attributes: nestedAttributes,
+					Blocks:     nestedBlocks,
+				},
+			}
+		default:
+			panic(fmt.Sprintf("frameworkProviderAttributes: provider attribute %s has an unsupported type %s", name, s.Type))
+		}
+	}
+	return attributes, blocks
+}
+
+// frameworkProviderConfiguration returns the provider configuration passed to the Configure method of a resource or
+// data source, or nil if the provider is not configured yet
+func frameworkProviderConfiguration(providerData any, diagnostics *diag.Diagnostics) *ProviderConfiguration {
+	if providerData == nil {
+		return nil
+	}
+	providerConfiguration, ok := providerData.(*ProviderConfiguration)
+	if !ok {
+		diagnostics.AddError("Unexpected provider data", fmt.Sprintf("Expected *ProviderConfiguration, got %T.", providerData))
+		return nil
+	}
+	return providerConfiguration
+}
@@ -1,24 +1,73 @@
 package sonarqube
 
 import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/jdamata/terraform-provider-sonarqube/internal/client"
+	"github.com/jdamata/terraform-provider-sonarqube/internal/fakesonarqube"
 )
 
 var testAccProvider *schema.Provider
-var testAccProviders map[string]*schema.Provider
+var testAccProtoV6ProviderFactories map[string]func() (tfprotov6.ProviderServer, error)
+
+// testAccFakeServer is the fake the acceptance tests run against when no SonarQube instance is configured
+var testAccFakeServer *fakesonarqube.Server
+
+var (
+	testAccCapabilityProviderOnce sync.Once
+	testAccCapabilityProvider     *schema.Provider
+	testAccCapabilityProviderErr  error
+)
 
 func init() {
 	testAccProvider = Provider()
-	testAccProviders = map[string]*schema.Provider{
-		"sonarqube": testAccProvider,
+	testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
+		"sonarqube": func() (tfprotov6.ProviderServer, error) {
+			providerServerFactory, err := ProtoV6ProviderServerFactory(context.Background(), testAccProvider)
+			if err != nil {
+				return nil, err
+			}
+			return providerServerFactory(), nil
+		},
 	}
 }
 
 func TestMain(m *testing.M) {
+	// Without a SonarQube instance, acceptance tests run against an in-memory fake living as long as the test binary
+	if os.Getenv(resource.EnvTfAcc) != "" && os.Getenv("SONAR_HOST") == "" {
+		testAccFakeServer = fakesonarqube.NewServer(fakesonarqube.Options{
+			Version: os.Getenv("SONAR_FAKE_VERSION"),
+			Edition: os.Getenv("SONAR_FAKE_EDITION"),
+		})
+		os.Setenv("SONAR_HOST", testAccFakeServer.URL)
+		os.Setenv("SONAR_USER", fakesonarqube.DefaultLogin)
+		os.Setenv("SONAR_PASS", fakesonarqube.DefaultPassword)
+		os.Unsetenv("SONAR_TOKEN")
+	}
 	resource.TestMain(m)
 }
 
@@ -28,6 +77,33 @@ func TestProvider(t *testing.T) {
 	}
 }
 
+func TestProviderServerSchema(t *testing.T) {
+	// The SDKv2 and the plugin framework provider schemas must be identical for the provider server to serve both
+	for _, host := range []string{"", "http://127.0.0.1:9000"} {
+		t.Setenv("SONAR_HOST", host)
+		if host == "" {
+			os.Unsetenv("SONAR_HOST")
+		}
+
+		providerServerFactory, err := ProtoV6ProviderServerFactory(context.Background(), Provider())
+		if err != nil {
+			t.Fatalf("unexpected error: %+v", err)
+		}
+		resp, err := providerServerFactory().GetProviderSchema(context.Background(), &tfprotov6.GetProviderSchemaRequest{})
+		if err != nil {
+			t.Fatalf("unexpected error: %+v", err)
+		}
+		for _, diagnostic := range resp.Diagnostics {
+			t.Errorf("SONAR_HOST=%q: %s: %s", host, diagnostic.Summary, diagnostic.Detail)
+		}
+		for _, resourceType := range []string{"sonarqube_user_token", "sonarqube_setting", "sonarqube_project"} {
+			if _, ok := resp.ResourceSchemas[resourceType]; !ok {
+				t.Errorf("resource %s is not served", resourceType)
+			}
+		}
+	}
+}
+
 func TestProvider_impl(t *testing.T) {
 	var _ *schema.Provider = Provider()
 }
@@ -40,6 +116,80 @@ func testAccPreCheck(t *testing.T) {
 	}
 }
 
+// Skips an acceptance test when the SonarQube server under test does not support a feature. The server is queried
+// with a dedicated provider, as testAccProvider is only configured once a test step ran, possibly with overrides.
+func testAccPreCheckCapability(t *testing.T, name string) {
+	testAccCapabilityProviderOnce.Do(func() {
+		testAccCapabilityProvider = Provider()
+		if diags := testAccCapabilityProvider.Configure(context.Background(), terraform.NewResourceConfigRaw(nil)); diags.HasError() {
+			testAccCapabilityProviderErr = diagnosticsToError(diags)
+		}
+	})
+	if testAccCapabilityProviderErr != nil {
+		t.Fatalf("failed to configure the provider: %+v", testAccCapabilityProviderErr)
+	}
+	if err := testAccCapabilityProvider.Meta().(*ProviderConfiguration).checkCapability(name); err != nil {
+		t.Skipf("Skipping test of unsupported feature: %+v", err)
+	}
+}
+
+// Skips an acceptance test which depends on the fake server, e.g. to analyze a branch without a scanner
+func testAccPreCheckFakeServer(t *testing.T) {
+	if testAccFakeServer == nil {
+		t.Skip("Skipping test which requires the fake SonarQube server")
+	}
+}
+
+func TestAccProviderSonarCloud(t *testing.T) {
+	// SonarCloud is emulated by a dedicated fake server, which requires the organization and lacks api/system/info
+	server := fakesonarqube.NewServer(fakesonarqube.Options{Organization: "my-org"})
+	defer server.Close()
+	t.Setenv("SONAR_TOKEN", "")
+	t.Setenv("SONARQUBE_TOKEN", "")
+
+	providerConfig := fmt.Sprintf(`
+		provider "sonarqube" {
+			host         = "%s"
+			user         = "%s"
+			pass         = "%s"
+			sonarcloud   = true
+			organization = "my-org"
+		}
+		`, server.URL, fakesonarqube.DefaultLogin, fakesonarqube.DefaultPassword)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+					resource "sonarqube_plugin" "plugin" {
+						key = "cayc"
+					}`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("SonarCloud does not support the installation of plugins"),
+			},
+			{
+				Config: providerConfig + testAccSonarqubeProjectBasicConfig("project", "testAccProviderSonarCloud", "testAccProviderSonarCloud", "public"),
+				Check:  resource.TestCheckResourceAttr("sonarqube_project.project", "name", "testAccProviderSonarCloud"),
+			},
+		},
+	})
+}
+
+func TestConfigureSonarCloudRequiresOrganization(t *testing.T) {
+	t.Setenv("SONAR_ORGANIZATION", "")
+	t.Setenv("SONARQUBE_ORGANIZATION", "")
+	raw := map[string]interface{}{
+		"host":       "https://sonarcloud.io",
+		"token":      "token",
+		"sonarcloud": true,
+	}
+	diags := Provider().Configure(context.Background(), terraform.NewResourceConfigRaw(raw))
+	if !diags.HasError() || !strings.Contains(diagnosticsToError(diags).Error(), "organization must be set") {
+		t.Errorf("diagnostics = %+v, want a missing organization error", diags)
+	}
+}
+
 func testSonarUser(t *testing.T) {
 	if v := os.Getenv("SONAR_USER"); v == "" {
 		t.Fatal("SONAR_USER must be set for this acceptance test")
@@ -57,3 +207,163 @@ func testSonarHost(t *testing.T) {
 		t.Fatal("SONAR_HOST must be set for this acceptance test")
 	}
 }
+
+func TestWaitForServerReady(t *testing.T) {
+	statuses := []string{"STARTING", "DB_MIGRATION_RUNNING", "UP"}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/system/status":
+			fmt.Fprintf(w, `{"status":%q}`, statuses[0])
+			if len(statuses) > 1 {
+				statuses = statuses[1:]
+			}
+		case "/api/system/health":
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer server.Close()
+
+	if err := waitForServerReady(context.Background(), testServerClient(t, server.URL), time.Minute, time.Millisecond); err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	if statuses[0] != "UP" {
+		t.Errorf("waitForServerReady returned before the server was UP")
+	}
+}
+
+func TestWaitForServerReadyTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status":"DB_MIGRATION_NEEDED"}`))
+	}))
+	defer server.Close()
+
+	err := waitForServerReady(context.Background(), testServerClient(t, server.URL), 50*time.Millisecond, 10*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "Last observed status: DB_MIGRATION_NEEDED") {
+		t.Errorf("error = %v, want the last observed status", err)
+	}
+}
+
+func TestWaitForServerReadyUnhealthy(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/system/status":
+			w.Write([]byte(`{"status":"UP"}`))
+		case "/api/system/health":
+			w.Write([]byte(`{"health":"RED","causes":[{"message":"Elasticsearch is not available"}]}`))
+		}
+	}))
+	defer server.Close()
+
+	err := waitForServerReady(context.Background(), testServerClient(t, server.URL), 50*time.Millisecond, 10*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "Elasticsearch is not available") {
+		t.Errorf("error = %v, want the health causes", err)
+	}
+}
+
+// testServerClient returns a client for a test server that does not retry
+func testServerClient(t *testing.T, serverURL string) *client.Client {
+	httpClient := retryablehttp.NewClient()
+	httpClient.Logger = nil
+	httpClient.RetryMax = 0
+	httpClient.ErrorHandler = retryablehttp.PassthroughErrorHandler
+
+	baseURL, err := url.Parse(serverURL)
+	if err != nil {
+		t.Fatalf("failed to parse test server url: %+v", err)
+	}
+	return client.New(httpClient, *baseURL, client.BasicAuth("admin", "admin"))
+}
+
+func TestConfigureTLS(t *testing.T) {
+	clientCert, clientKey := testClientCertificate(t)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status":"UP"}`))
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	server.StartTLS()
+	defer server.Close()
+
+	caCert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFile, caCert, 0600); err != nil {
+		t.Fatalf("failed to write CA file: %+v", err)
+	}
+
+	d := schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{
+		"host":            server.URL,
+		"ca_cert_file":    caFile,
+		"client_cert_pem": string(clientCert),
+		"client_key_pem":  string(clientKey),
+	})
+	tlsConfig, err := configureTLS(d)
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+
+	httpClient := &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+	resp, err := httpClient.Get(server.URL)
+	if err != nil {
+		t.Fatalf("request with the configured CA and client certificate failed: %+v", err)
+	}
+	resp.Body.Close()
+}
+
+func TestConfigureTLSErrors(t *testing.T) {
+	clientCert, _ := testClientCertificate(t)
+	cases := []struct {
+		name   string
+		config map[string]interface{}
+		want   string
+	}{
+		{
+			name:   "invalid CA",
+			config: map[string]interface{}{"ca_cert_pem": "not a certificate"},
+			want:   "failed to parse ca_cert_pem/ca_cert_file",
+		},
+		{
+			name:   "missing CA file",
+			config: map[string]interface{}{"ca_cert_file": filepath.Join(t.TempDir(), "missing.pem")},
+			want:   "failed to read ca_cert_file",
+		},
+		{
+			name:   "certificate without key",
+			config: map[string]interface{}{"client_cert_pem": string(clientCert)},
+			want:   "must be configured together",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.config["host"] = "https://sonarqube.example.com"
+			_, err := configureTLS(schema.TestResourceDataRaw(t, Provider().Schema, c.config))
+			if err == nil || !strings.Contains(err.Error(), c.want) {
+				t.Errorf("error = %v, want %q", err, c.want)
+			}
+		})
+	}
+}
+
+// testClientCertificate returns a self-signed PEM encoded client certificate and its key
+func testClientCertificate(t *testing.T) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %+v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "terraform"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %+v", err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("failed to marshal key: %+v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+}
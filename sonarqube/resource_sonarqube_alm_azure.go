@@ -1,36 +1,35 @@
 package sonarqube
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"net/http"
-	"net/url"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/jdamata/terraform-provider-sonarqube/internal/client"
 )
 
-// GetAlmAzure for unmarshalling response body from alm list definitions. With only azure populated
-type GetAlmAzure struct {
-	Azure []struct {
-		Key string `json:"key"`
-		URL string `json:"url"`
-	} `json:"azure"`
-}
-
 // Returns the resource represented by this file.
 func resourceSonarqubeAlmAzure() *schema.Resource {
 	return &schema.Resource{
 		Description: `Provides a Sonarqube Azure Devops Alm/Devops Platform Integration resource. This can be used to create and manage a Alm/Devops
 Platform Integration for Azure Devops.`,
-		Create: resourceSonarqubeAlmAzureCreate,
-		Read:   resourceSonarqubeAlmAzureRead,
-		Update: resourceSonarqubeAlmAzureUpdate,
-		Delete: resourceSonarqubeAlmAzureDelete,
+		CreateContext: resourceSonarqubeAlmAzureCreate,
+		ReadContext:   resourceSonarqubeAlmAzureRead,
+		UpdateContext: resourceSonarqubeAlmAzureUpdate,
+		DeleteContext: resourceSonarqubeAlmAzureDelete,
 		Importer: &schema.ResourceImporter{
-			State: resourceSonarqubeAlmAzureImport,
+			StateContext: resourceSonarqubeAlmAzureImport,
+		},
+		CustomizeDiff: almSettingCustomizeDiff,
+
+		Timeouts: &schema.ResourceTimeout{
+			Default: schema.DefaultTimeout(5 * time.Minute),
 		},
+
 		// Define the fields of this schema.
 		Schema: map[string]*schema.Schema{
 			"key": {
@@ -54,118 +53,79 @@ Platform Integration for Azure Devops.`,
 				Description:      "Azure API URL",
 				ValidateDiagFunc: validation.ToDiagFunc(validation.StringLenBetween(1, 2000)),
 			},
+			"validate_on_apply": almSettingValidateOnApplySchema(),
 		},
 	}
 }
 
-func resourceSonarqubeAlmAzureCreate(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/alm_settings/create_azure"
-
-	sonarQubeURL.RawQuery = url.Values{
-		"key":                 []string{d.Get("key").(string)},
-		"personalAccessToken": []string{d.Get("personal_access_token").(string)},
-		"url":                 []string{d.Get("url").(string)},
-	}.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusNoContent,
-		"resourceSonarqubeAlmAzureCreate",
-	)
+func resourceSonarqubeAlmAzureCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if err := m.(*ProviderConfiguration).checkCapability(capabilityAlmSettings); err != nil {
+		return diag.FromErr(err)
+	}
+
+	request := client.AzureRequest{
+		Key:                 d.Get("key").(string),
+		PersonalAccessToken: d.Get("personal_access_token").(string),
+		URL:                 d.Get("url").(string),
+	}
+	err := m.(*ProviderConfiguration).client.AlmSettings.CreateAzure(ctx, request)
 	if err != nil {
-		return err
+		return diag.Errorf("resourceSonarqubeAlmAzureCreate: Failed to create azure alm setting: %+v", err)
 	}
-	defer resp.Body.Close()
 
 	d.SetId(d.Get("key").(string))
 
-	return resourceSonarqubeAlmAzureRead(d, m)
-}
-
-func resourceSonarqubeAlmAzureRead(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/alm_settings/list_definitions"
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"GET",
-		sonarQubeURL.String(),
-		http.StatusOK,
-		"resourceSonarqubeAlmAzureRead",
-	)
-	if err != nil {
-		return err
+	if diags := resourceSonarqubeAlmAzureRead(ctx, d, m); diags.HasError() {
+		return diags
 	}
-	defer resp.Body.Close()
+	return validateAlmSettingOnApply(ctx, d, m, "resourceSonarqubeAlmAzureCreate")
+}
 
-	// Decode response into struct
-	AlmAzureReadResponse := GetAlmAzure{}
-	err = json.NewDecoder(resp.Body).Decode(&AlmAzureReadResponse)
+func resourceSonarqubeAlmAzureRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	definitions, err := m.(*ProviderConfiguration).client.AlmSettings.ListDefinitions(ctx)
 	if err != nil {
-		return fmt.Errorf("resourceSonarqubeAlmAzureRead: Failed to decode json into struct: %+v", err)
+		return diag.Errorf("resourceSonarqubeAlmAzureRead: Failed to list alm definitions: %+v", err)
 	}
 	// Loop over all Azure instances to see if the Alm instance exists.
-	for _, value := range AlmAzureReadResponse.Azure {
+	for _, value := range definitions.Azure {
 		if d.Id() == value.Key {
 			d.Set("key", value.Key)
 			d.Set("url", value.URL)
 			return nil
 		}
 	}
-	return fmt.Errorf("resourceSonarqubeAzureBindingRead: Failed to find azure binding: %+v", d.Id())
+	removeResourceFromState(d, "resourceSonarqubeAlmAzureRead")
+	return nil
 }
 
-func resourceSonarqubeAlmAzureUpdate(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/alm_settings/update_azure"
-	sonarQubeURL.RawQuery = url.Values{
-		"key":                 []string{d.Id()},
-		"newKey":              []string{d.Get("key").(string)},
-		"personalAccessToken": []string{d.Get("personal_access_token").(string)},
-		"url":                 []string{d.Get("url").(string)},
-	}.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusOK,
-		"resourceSonarqubeAlmAzureUpdate",
-	)
+func resourceSonarqubeAlmAzureUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	request := client.AzureRequest{
+		Key:                 d.Id(),
+		NewKey:              d.Get("key").(string),
+		PersonalAccessToken: d.Get("personal_access_token").(string),
+		URL:                 d.Get("url").(string),
+	}
+	err := m.(*ProviderConfiguration).client.AlmSettings.UpdateAzure(ctx, request)
 	if err != nil {
-		return err
+		return diag.Errorf("resourceSonarqubeAlmAzureUpdate: Failed to update azure alm setting: %+v", err)
 	}
-	defer resp.Body.Close()
 
-	return resourceSonarqubeAlmAzureRead(d, m)
+	if diags := resourceSonarqubeAlmAzureRead(ctx, d, m); diags.HasError() {
+		return diags
+	}
+	return validateAlmSettingOnApply(ctx, d, m, "resourceSonarqubeAlmAzureUpdate")
 }
 
-func resourceSonarqubeAlmAzureDelete(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/alm_settings/delete"
-	sonarQubeURL.RawQuery = url.Values{
-		"key": []string{d.Get("key").(string)},
-	}.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusNoContent,
-		"resourceSonarqubeAlmAzureDelete",
-	)
+func resourceSonarqubeAlmAzureDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	err := m.(*ProviderConfiguration).client.AlmSettings.Delete(ctx, d.Get("key").(string))
 	if err != nil {
-		return err
+		return diag.Errorf("resourceSonarqubeAlmAzureDelete: Failed to delete azure alm setting: %+v", err)
 	}
-	defer resp.Body.Close()
 
 	return nil
 }
 
-func resourceSonarqubeAlmAzureImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+func resourceSonarqubeAlmAzureImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
 	// import id in format {key}/{personal_access_token}
 	importIdComponents := strings.SplitN(d.Id(), "/", 2)
 
@@ -175,12 +135,15 @@ func resourceSonarqubeAlmAzureImport(d *schema.ResourceData, m interface{}) ([]*
 
 	// set Id to key for Read
 	d.SetId(importIdComponents[0])
-	if err := resourceSonarqubeAlmAzureRead(d, m); err != nil {
-		return nil, err
+	if diags := resourceSonarqubeAlmAzureRead(ctx, d, m); diags.HasError() {
+		return nil, diagnosticsToError(diags)
 	}
 
 	// Add personal_access_token from import id
 	d.Set("personal_access_token", importIdComponents[1])
 
+	// validate_on_apply is not stored by Sonarqube, imports start from its default
+	d.Set("validate_on_apply", false)
+
 	return []*schema.ResourceData{d}, nil
 }
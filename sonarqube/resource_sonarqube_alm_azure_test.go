@@ -34,8 +34,8 @@ func TestAccSonarqubeAlmAzureName(t *testing.T) {
 	name := "sonarqube_alm_azure." + rnd
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubeAlmAzureName(rnd, "testAccSonarqubeAlmAzureName", "https://dev.azure.com/my-org"),
@@ -0,0 +1,149 @@
+package sonarqube
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/jdamata/terraform-provider-sonarqube/internal/client"
+)
+
+// Returns the resource represented by this file.
+func resourceSonarqubeAlmBitbucket() *schema.Resource {
+	return &schema.Resource{
+		Description: `Provides a Sonarqube Bitbucket Server Alm/Devops Platform Integration resource. This can be used to create and manage a
+Alm/Devops Platform Integration for Bitbucket Server or Bitbucket Data Center.`,
+		CreateContext: resourceSonarqubeAlmBitbucketCreate,
+		ReadContext:   resourceSonarqubeAlmBitbucketRead,
+		UpdateContext: resourceSonarqubeAlmBitbucketUpdate,
+		DeleteContext: resourceSonarqubeAlmBitbucketDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceSonarqubeAlmBitbucketImport,
+		},
+		CustomizeDiff: almSettingCustomizeDiff,
+
+		Timeouts: &schema.ResourceTimeout{
+			Default: schema.DefaultTimeout(5 * time.Minute),
+		},
+
+		// Define the fields of this schema.
+		Schema: map[string]*schema.Schema{
+			"key": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringLenBetween(1, 200)),
+				Description:      "Unique key of the Bitbucket Server instance setting. Maximum length: 200",
+			},
+			"personal_access_token": {
+				Type:             schema.TypeString,
+				Required:         true,
+				Sensitive:        true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringLenBetween(1, 2000)),
+				Description:      "Bitbucket Server personal access token with the `Read` permission on projects and repositories. See [this doc](https://docs.sonarqube.org/latest/devops-platform-integration/bitbucket-integration/bitbucket-server-integration/) for more information. Maximum length: 2000",
+			},
+			"url": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringLenBetween(1, 2000)),
+				Description:      "Bitbucket Server URL, e.g. `https://bitbucket.example.com`. Maximum length: 2000",
+			},
+			"validate_on_apply": almSettingValidateOnApplySchema(),
+		},
+	}
+}
+
+func resourceSonarqubeAlmBitbucketCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if err := m.(*ProviderConfiguration).checkCapability(capabilityAlmSettings); err != nil {
+		return diag.FromErr(err)
+	}
+
+	request := client.BitbucketRequest{
+		Key:                 d.Get("key").(string),
+		PersonalAccessToken: d.Get("personal_access_token").(string),
+		URL:                 d.Get("url").(string),
+	}
+	err := m.(*ProviderConfiguration).client.AlmSettings.CreateBitbucket(ctx, request)
+	if err != nil {
+		return diag.Errorf("resourceSonarqubeAlmBitbucketCreate: Failed to create bitbucket alm setting: %+v", err)
+	}
+
+	d.SetId(d.Get("key").(string))
+
+	if diags := resourceSonarqubeAlmBitbucketRead(ctx, d, m); diags.HasError() {
+		return diags
+	}
+	return validateAlmSettingOnApply(ctx, d, m, "resourceSonarqubeAlmBitbucketCreate")
+}
+
+func resourceSonarqubeAlmBitbucketRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	definitions, err := m.(*ProviderConfiguration).client.AlmSettings.ListDefinitions(ctx)
+	if err != nil {
+		return diag.Errorf("resourceSonarqubeAlmBitbucketRead: Failed to list alm definitions: %+v", err)
+	}
+	// Loop over all Bitbucket Server instances to see if the Alm instance exists.
+	for _, value := range definitions.Bitbucket {
+		if d.Id() == value.Key {
+			d.Set("key", value.Key)
+			d.Set("url", value.URL)
+			// The personal_access_token is a secured property that is not returned
+			return nil
+		}
+	}
+	removeResourceFromState(d, "resourceSonarqubeAlmBitbucketRead")
+	return nil
+}
+
+func resourceSonarqubeAlmBitbucketUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	request := client.BitbucketRequest{
+		Key:                 d.Id(),
+		NewKey:              d.Get("key").(string),
+		PersonalAccessToken: d.Get("personal_access_token").(string),
+		URL:                 d.Get("url").(string),
+	}
+	err := m.(*ProviderConfiguration).client.AlmSettings.UpdateBitbucket(ctx, request)
+	if err != nil {
+		return diag.Errorf("resourceSonarqubeAlmBitbucketUpdate: Failed to update bitbucket alm setting: %+v", err)
+	}
+
+	if diags := resourceSonarqubeAlmBitbucketRead(ctx, d, m); diags.HasError() {
+		return diags
+	}
+	return validateAlmSettingOnApply(ctx, d, m, "resourceSonarqubeAlmBitbucketUpdate")
+}
+
+func resourceSonarqubeAlmBitbucketDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	err := m.(*ProviderConfiguration).client.AlmSettings.Delete(ctx, d.Get("key").(string))
+	if err != nil {
+		return diag.Errorf("resourceSonarqubeAlmBitbucketDelete: Failed to delete bitbucket alm setting: %+v", err)
+	}
+
+	return nil
+}
+
+func resourceSonarqubeAlmBitbucketImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	// import id in format {key}/{personal_access_token}
+	importIdComponents := strings.SplitN(d.Id(), "/", 2)
+
+	if len(importIdComponents) != 2 {
+		return nil, fmt.Errorf("resourceSonarqubeAlmBitbucketImport: Import id: '%+v' is not in format {key}/{personal_access_token}", d.Id())
+	}
+
+	// set Id to key for Read
+	d.SetId(importIdComponents[0])
+	if diags := resourceSonarqubeAlmBitbucketRead(ctx, d, m); diags.HasError() {
+		return nil, diagnosticsToError(diags)
+	}
+
+	// Add personal_access_token from import id
+	d.Set("personal_access_token", importIdComponents[1])
+
+	// validate_on_apply is not stored by Sonarqube, imports start from its default
+	d.Set("validate_on_apply", false)
+
+	return []*schema.ResourceData{d}, nil
+}
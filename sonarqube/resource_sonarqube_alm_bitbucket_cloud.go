@@ -0,0 +1,158 @@
+package sonarqube
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/jdamata/terraform-provider-sonarqube/internal/client"
+)
+
+// Returns the resource represented by this file.
+func resourceSonarqubeAlmBitbucketCloud() *schema.Resource {
+	return &schema.Resource{
+		Description: `Provides a Sonarqube Bitbucket Cloud Alm/Devops Platform Integration resource. This can be used to create and manage a
+Alm/Devops Platform Integration for a Bitbucket Cloud workspace.`,
+		CreateContext: resourceSonarqubeAlmBitbucketCloudCreate,
+		ReadContext:   resourceSonarqubeAlmBitbucketCloudRead,
+		UpdateContext: resourceSonarqubeAlmBitbucketCloudUpdate,
+		DeleteContext: resourceSonarqubeAlmBitbucketCloudDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceSonarqubeAlmBitbucketCloudImport,
+		},
+		CustomizeDiff: almSettingCustomizeDiff,
+
+		Timeouts: &schema.ResourceTimeout{
+			Default: schema.DefaultTimeout(5 * time.Minute),
+		},
+
+		// Define the fields of this schema.
+		Schema: map[string]*schema.Schema{
+			"client_id": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringLenBetween(1, 80)),
+				Description:      "Bitbucket Cloud OAuth consumer key. Maximum length: 80",
+			},
+			"client_secret": {
+				Type:             schema.TypeString,
+				Required:         true,
+				Sensitive:        true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringLenBetween(1, 160)),
+				Description:      "Bitbucket Cloud OAuth consumer secret. See [this doc](https://docs.sonarqube.org/latest/devops-platform-integration/bitbucket-integration/bitbucket-cloud-integration/) for more information. Maximum length: 160",
+			},
+			"key": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringLenBetween(1, 200)),
+				Description:      "Unique key of the Bitbucket Cloud workspace setting. Maximum length: 200",
+			},
+			"workspace": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringLenBetween(1, 80)),
+				Description:      "Bitbucket Cloud workspace ID, as found in the URL of the workspace. Maximum length: 80",
+			},
+			"validate_on_apply": almSettingValidateOnApplySchema(),
+		},
+	}
+}
+
+func resourceSonarqubeAlmBitbucketCloudCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if err := m.(*ProviderConfiguration).checkCapability(capabilityAlmSettings); err != nil {
+		return diag.FromErr(err)
+	}
+
+	request := client.BitbucketCloudRequest{
+		Key:          d.Get("key").(string),
+		ClientID:     d.Get("client_id").(string),
+		ClientSecret: d.Get("client_secret").(string),
+		Workspace:    d.Get("workspace").(string),
+	}
+	err := m.(*ProviderConfiguration).client.AlmSettings.CreateBitbucketCloud(ctx, request)
+	if err != nil {
+		return diag.Errorf("resourceSonarqubeAlmBitbucketCloudCreate: Failed to create bitbucket cloud alm setting: %+v", err)
+	}
+
+	d.SetId(d.Get("key").(string))
+
+	if diags := resourceSonarqubeAlmBitbucketCloudRead(ctx, d, m); diags.HasError() {
+		return diags
+	}
+	return validateAlmSettingOnApply(ctx, d, m, "resourceSonarqubeAlmBitbucketCloudCreate")
+}
+
+func resourceSonarqubeAlmBitbucketCloudRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	definitions, err := m.(*ProviderConfiguration).client.AlmSettings.ListDefinitions(ctx)
+	if err != nil {
+		return diag.Errorf("resourceSonarqubeAlmBitbucketCloudRead: Failed to list alm definitions: %+v", err)
+	}
+	// Loop over all Bitbucket Cloud workspaces to see if the Alm instance exists.
+	for _, value := range definitions.BitbucketCloud {
+		if d.Id() == value.Key {
+			d.Set("key", value.Key)
+			d.Set("workspace", value.Workspace)
+			d.Set("client_id", value.ClientID)
+			// The client_secret is a secured property that is not returned
+			return nil
+		}
+	}
+	removeResourceFromState(d, "resourceSonarqubeAlmBitbucketCloudRead")
+	return nil
+}
+
+func resourceSonarqubeAlmBitbucketCloudUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	request := client.BitbucketCloudRequest{
+		Key:          d.Id(),
+		NewKey:       d.Get("key").(string),
+		ClientID:     d.Get("client_id").(string),
+		ClientSecret: d.Get("client_secret").(string),
+		Workspace:    d.Get("workspace").(string),
+	}
+	err := m.(*ProviderConfiguration).client.AlmSettings.UpdateBitbucketCloud(ctx, request)
+	if err != nil {
+		return diag.Errorf("resourceSonarqubeAlmBitbucketCloudUpdate: Failed to update bitbucket cloud alm setting: %+v", err)
+	}
+
+	if diags := resourceSonarqubeAlmBitbucketCloudRead(ctx, d, m); diags.HasError() {
+		return diags
+	}
+	return validateAlmSettingOnApply(ctx, d, m, "resourceSonarqubeAlmBitbucketCloudUpdate")
+}
+
+func resourceSonarqubeAlmBitbucketCloudDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	err := m.(*ProviderConfiguration).client.AlmSettings.Delete(ctx, d.Get("key").(string))
+	if err != nil {
+		return diag.Errorf("resourceSonarqubeAlmBitbucketCloudDelete: Failed to delete bitbucket cloud alm setting: %+v", err)
+	}
+
+	return nil
+}
+
+func resourceSonarqubeAlmBitbucketCloudImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	// import id in format {key}/{client_secret}
+	importIdComponents := strings.SplitN(d.Id(), "/", 2)
+
+	if len(importIdComponents) != 2 {
+		return nil, fmt.Errorf("resourceSonarqubeAlmBitbucketCloudImport: Import id: '%+v' is not in format {key}/{client_secret}", d.Id())
+	}
+
+	// set Id to key for Read
+	d.SetId(importIdComponents[0])
+	if diags := resourceSonarqubeAlmBitbucketCloudRead(ctx, d, m); diags.HasError() {
+		return nil, diagnosticsToError(diags)
+	}
+
+	// Add client_secret from import id
+	d.Set("client_secret", importIdComponents[1])
+
+	// validate_on_apply is not stored by Sonarqube, imports start from its default
+	d.Set("validate_on_apply", false)
+
+	return []*schema.ResourceData{d}, nil
+}
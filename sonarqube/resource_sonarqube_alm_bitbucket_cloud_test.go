@@ -0,0 +1,67 @@
+package sonarqube
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func init() {
+	resource.AddTestSweepers("sonarqube_alm_bitbucket_cloud", &resource.Sweeper{
+		Name: "sonarqube_alm_bitbucket_cloud",
+		F:    testSweepSonarqubeAlmBitbucketCloud,
+	})
+}
+
+// TODO: implement sweeper to clean up projects: https://www.terraform.io/docs/extend/testing/acceptance-tests/sweepers.html
+func testSweepSonarqubeAlmBitbucketCloud(r string) error {
+	return nil
+}
+
+func testAccSonarqubeAlmBitbucketCloudName(rnd string, name string, clientSecret string) string {
+	return fmt.Sprintf(`
+
+        resource "sonarqube_alm_bitbucket_cloud" "%[1]s" {
+            client_id     = "client-%[3]s"
+            client_secret = "%[3]s"
+            key           = "%[2]s"
+            workspace     = "workspace-%[3]s"
+        }`, rnd, name, clientSecret)
+}
+
+func TestAccSonarqubeAlmBitbucketCloudName(t *testing.T) {
+	rnd := generateRandomResourceName()
+	name := "sonarqube_alm_bitbucket_cloud." + rnd
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSonarqubeAlmBitbucketCloudName(rnd, "testAccSonarqubeAlmBitbucketCloudName", "123456"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(name, "key", "testAccSonarqubeAlmBitbucketCloudName"),
+					resource.TestCheckResourceAttr(name, "client_id", "client-123456"),
+					resource.TestCheckResourceAttr(name, "client_secret", "123456"),
+					resource.TestCheckResourceAttr(name, "workspace", "workspace-123456"),
+				),
+			},
+			{
+				Config: testAccSonarqubeAlmBitbucketCloudName(rnd, "testAccSonarqubeAlmBitbucketCloudNameUpdate", "654321"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(name, "key", "testAccSonarqubeAlmBitbucketCloudNameUpdate"),
+					resource.TestCheckResourceAttr(name, "client_id", "client-654321"),
+					resource.TestCheckResourceAttr(name, "client_secret", "654321"),
+					resource.TestCheckResourceAttr(name, "workspace", "workspace-654321"),
+				),
+			},
+			{
+				ResourceName:      name,
+				ImportState:       true,
+				ImportStateId:     "testAccSonarqubeAlmBitbucketCloudNameUpdate/654321",
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
@@ -1,34 +1,31 @@
 package sonarqube
 
 import (
-	"encoding/json"
-	"fmt"
-	"net/http"
-	"net/url"
-	"strings"
+	"context"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/jdamata/terraform-provider-sonarqube/internal/client"
 )
 
-// GetAlmGithub for unmarshalling response body from alm list definitions. With only github populated
-type GetAlmGithub struct {
-	Github []struct {
-		Key      string `json:"key"`
-		URL      string `json:"url"`
-		AppID    string `json:"appId"`
-		ClientID string `json:"clientId"`
-	} `json:"github"`
-}
-
 // Returns the resource represented by this file.
 func resourceSonarqubeAlmGithub() *schema.Resource {
 	return &schema.Resource{
 		Description: `Provides a Sonarqube GitHub Alm/Devops Platform Integration resource. This can be used to create and manage a Alm/Devops
 Platform Integration for GitHub.`,
-		Create: resourceSonarqubeAlmGithubCreate,
-		Read:   resourceSonarqubeAlmGithubRead,
-		Update: resourceSonarqubeAlmGithubUpdate,
-		Delete: resourceSonarqubeAlmGithubDelete,
+		CreateContext: resourceSonarqubeAlmGithubCreate,
+		ReadContext:   resourceSonarqubeAlmGithubRead,
+		UpdateContext: resourceSonarqubeAlmGithubUpdate,
+		DeleteContext: resourceSonarqubeAlmGithubDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceSonarqubeAlmGithubImport,
+		},
+		CustomizeDiff: almSettingCustomizeDiff,
+
+		Timeouts: &schema.ResourceTimeout{
+			Default: schema.DefaultTimeout(5 * time.Minute),
+		},
 
 		// Define the fields of this schema.
 		Schema: map[string]*schema.Schema{
@@ -47,7 +44,6 @@ Platform Integration for GitHub.`,
 			"client_secret": {
 				Type:        schema.TypeString,
 				Required:    true,
-				ForceNew:    true,
 				Description: "GitHub App Client Secret. Maximum length: 160",
 			},
 			"key": {
@@ -59,7 +55,6 @@ Platform Integration for GitHub.`,
 			"private_key": {
 				Type:        schema.TypeString,
 				Required:    true,
-				ForceNew:    true,
 				Description: "GitHub App private key. Maximum length: 2500",
 			},
 			"url": {
@@ -68,6 +63,7 @@ Platform Integration for GitHub.`,
 				ForceNew:    true,
 				Description: "GitHub API URL. Maximum length: 2000",
 			},
+			"validate_on_apply": almSettingValidateOnApplySchema(),
 			"webhook_secret": {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -78,62 +74,40 @@ Platform Integration for GitHub.`,
 	}
 }
 
-func resourceSonarqubeAlmGithubCreate(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/alm_settings/create_github"
-
-	sonarQubeURL.RawQuery = url.Values{
-		"appId":         []string{d.Get("app_id").(string)},
-		"clientId":      []string{d.Get("client_id").(string)},
-		"clientSecret":  []string{d.Get("client_secret").(string)},
-		"key":           []string{d.Get("key").(string)},
-		"privateKey":    []string{d.Get("private_key").(string)},
-		"url":           []string{d.Get("url").(string)},
-		"webhookSecret": []string{d.Get("webhook_secret").(string)},
-	}.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusNoContent,
-		"resourceSonarqubeAlmGithubCreate",
-	)
+func resourceSonarqubeAlmGithubCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if err := m.(*ProviderConfiguration).checkCapability(capabilityAlmSettings); err != nil {
+		return diag.FromErr(err)
+	}
+
+	request := client.GithubRequest{
+		Key:           d.Get("key").(string),
+		AppID:         d.Get("app_id").(string),
+		ClientID:      d.Get("client_id").(string),
+		ClientSecret:  d.Get("client_secret").(string),
+		PrivateKey:    d.Get("private_key").(string),
+		URL:           d.Get("url").(string),
+		WebhookSecret: d.Get("webhook_secret").(string),
+	}
+	err := m.(*ProviderConfiguration).client.AlmSettings.CreateGithub(ctx, request)
 	if err != nil {
-		return err
+		return diag.Errorf("resourceSonarqubeAlmGithubCreate: Failed to create github alm setting: %+v", err)
 	}
-	defer resp.Body.Close()
 
 	d.SetId(d.Get("key").(string))
 
-	return resourceSonarqubeAlmGithubRead(d, m)
-}
-
-func resourceSonarqubeAlmGithubRead(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/alm_settings/list_definitions"
-	sonarQubeURL.RawQuery = url.Values{}.Encode() // Dunno if you can keep it empty tbh?
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"GET",
-		sonarQubeURL.String(),
-		http.StatusOK,
-		"resourceSonarqubeAlmGithubRead",
-	)
-	if err != nil {
-		return err
+	if diags := resourceSonarqubeAlmGithubRead(ctx, d, m); diags.HasError() {
+		return diags
 	}
-	defer resp.Body.Close()
+	return validateAlmSettingOnApply(ctx, d, m, "resourceSonarqubeAlmGithubCreate")
+}
 
-	// Decode response into struct
-	AlmGithubReadResponse := GetAlmGithub{}
-	err = json.NewDecoder(resp.Body).Decode(&AlmGithubReadResponse)
+func resourceSonarqubeAlmGithubRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	definitions, err := m.(*ProviderConfiguration).client.AlmSettings.ListDefinitions(ctx)
 	if err != nil {
-		return fmt.Errorf("resourceSonarqubeAlmGithubRead: Failed to decode json into struct: %+v", err)
+		return diag.Errorf("resourceSonarqubeAlmGithubRead: Failed to list alm definitions: %+v", err)
 	}
 	// Loop over all GitHub instances to see if the Alm instance exists.
-	for _, value := range AlmGithubReadResponse.Github {
+	for _, value := range definitions.Github {
 		if d.Id() == value.Key {
 			d.Set("key", value.Key)
 			d.Set("url", value.URL)
@@ -142,56 +116,48 @@ func resourceSonarqubeAlmGithubRead(d *schema.ResourceData, m interface{}) error
 			return nil
 		}
 	}
-	return fmt.Errorf("resourceSonarqubeGithubBindingRead: Failed to find github binding: %+v", d.Id())
+	removeResourceFromState(d, "resourceSonarqubeAlmGithubRead")
+	return nil
 }
 
-func resourceSonarqubeAlmGithubUpdate(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/alm_settings/update_github"
-	sonarQubeURL.RawQuery = url.Values{
-		"appId":         []string{d.Get("app_id").(string)},
-		"clientId":      []string{d.Get("client_id").(string)},
-		"clientSecret":  []string{d.Get("client_secret").(string)},
-		"key":           []string{d.Id()},
-		"newKey":        []string{d.Get("key").(string)},
-		"privateKey":    []string{d.Get("private_key").(string)},
-		"url":           []string{d.Get("url").(string)},
-		"webhookSecret": []string{d.Get("webhook_secret").(string)},
-	}.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusOK,
-		"resourceSonarqubeAlmGithubUpdate",
-	)
+func resourceSonarqubeAlmGithubUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	request := client.GithubRequest{
+		Key:           d.Id(),
+		NewKey:        d.Get("key").(string),
+		AppID:         d.Get("app_id").(string),
+		ClientID:      d.Get("client_id").(string),
+		ClientSecret:  d.Get("client_secret").(string),
+		PrivateKey:    d.Get("private_key").(string),
+		URL:           d.Get("url").(string),
+		WebhookSecret: d.Get("webhook_secret").(string),
+	}
+	err := m.(*ProviderConfiguration).client.AlmSettings.UpdateGithub(ctx, request)
 	if err != nil {
-		return err
+		return diag.Errorf("resourceSonarqubeAlmGithubUpdate: Failed to update github alm setting: %+v", err)
 	}
-	defer resp.Body.Close()
 
-	return resourceSonarqubeAlmGithubRead(d, m)
+	if diags := resourceSonarqubeAlmGithubRead(ctx, d, m); diags.HasError() {
+		return diags
+	}
+	return validateAlmSettingOnApply(ctx, d, m, "resourceSonarqubeAlmGithubUpdate")
 }
 
-func resourceSonarqubeAlmGithubDelete(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/alm_settings/delete"
-	sonarQubeURL.RawQuery = url.Values{
-		"key": []string{d.Get("key").(string)},
-	}.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusNoContent,
-		"resourceSonarqubeAlmGithubDelete",
-	)
+func resourceSonarqubeAlmGithubDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	err := m.(*ProviderConfiguration).client.AlmSettings.Delete(ctx, d.Get("key").(string))
 	if err != nil {
-		return err
+		return diag.Errorf("resourceSonarqubeAlmGithubDelete: Failed to delete github alm setting: %+v", err)
 	}
-	defer resp.Body.Close()
 
 	return nil
 }
+
+func resourceSonarqubeAlmGithubImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	// import id is the key of the setting, the secrets are not returned by the api
+	if diags := resourceSonarqubeAlmGithubRead(ctx, d, m); diags.HasError() {
+		return nil, diagnosticsToError(diags)
+	}
+	// validate_on_apply is not stored by Sonarqube, imports start from its default
+	d.Set("validate_on_apply", false)
+
+	return []*schema.ResourceData{d}, nil
+}
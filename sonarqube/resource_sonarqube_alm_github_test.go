@@ -38,8 +38,8 @@ func TestAccSonarqubeAlmGithubName(t *testing.T) {
 	name := "sonarqube_alm_github." + rnd
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubeAlmGithubName(rnd, "testAccSonarqubeAlmGithubName", "123456", "234567"),
@@ -57,6 +57,12 @@ func TestAccSonarqubeAlmGithubName(t *testing.T) {
 					resource.TestCheckResourceAttr(name, "client_id", "765432"),
 				),
 			},
+			{
+				ResourceName:            name,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"client_secret", "private_key", "webhook_secret"},
+			},
 		},
 	})
 }
@@ -1,34 +1,34 @@
 package sonarqube
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"net/http"
-	"net/url"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/jdamata/terraform-provider-sonarqube/internal/client"
 )
 
-// GetAlmGitlab for unmarshalling response body from alm list definitions. With only gitlab populated
-type GetAlmGitlab struct {
-	Gitlab []struct {
-		Key                 string `json:"key"`
-		URL                 string `json:"url"`
-		PersonalAccessToken string `json:"personalAccessToken,omitempty"`
-	} `json:"gitlab"`
-}
-
 // Returns the resource represented by this file.
 func resourceSonarqubeAlmGitlab() *schema.Resource {
 	return &schema.Resource{
 		Description: `Provides a Sonarqube GitLab Alm/Devops Platform Integration resource. This can be used to create and manage a Alm/Devops
 Platform Integration for GitLab.`,
-		Create: resourceSonarqubeAlmGitlabCreate,
-		Read:   resourceSonarqubeAlmGitlabRead,
-		Update: resourceSonarqubeAlmGitlabUpdate,
-		Delete: resourceSonarqubeAlmGitlabDelete,
+		CreateContext: resourceSonarqubeAlmGitlabCreate,
+		ReadContext:   resourceSonarqubeAlmGitlabRead,
+		UpdateContext: resourceSonarqubeAlmGitlabUpdate,
+		DeleteContext: resourceSonarqubeAlmGitlabDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceSonarqubeAlmGitlabImport,
+		},
+		CustomizeDiff: almSettingCustomizeDiff,
+
+		Timeouts: &schema.ResourceTimeout{
+			Default: schema.DefaultTimeout(5 * time.Minute),
+		},
 
 		// Define the fields of this schema.
 		Schema: map[string]*schema.Schema{
@@ -52,115 +52,98 @@ Platform Integration for GitLab.`,
 				ValidateDiagFunc: validation.ToDiagFunc(validation.StringLenBetween(1, 2000)),
 				Description:      "GitLab API URL. Maximum length: 2000",
 			},
+			"validate_on_apply": almSettingValidateOnApplySchema(),
 		},
 	}
 }
 
-func resourceSonarqubeAlmGitlabCreate(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/alm_settings/create_gitlab"
-
-	sonarQubeURL.RawQuery = url.Values{
-		"key":                 []string{d.Get("key").(string)},
-		"personalAccessToken": []string{d.Get("personal_access_token").(string)},
-		"url":                 []string{d.Get("url").(string)},
-	}.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusNoContent,
-		"resourceSonarqubeAlmGitlabCreate",
-	)
+func resourceSonarqubeAlmGitlabCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if err := m.(*ProviderConfiguration).checkCapability(capabilityAlmSettings); err != nil {
+		return diag.FromErr(err)
+	}
+
+	request := client.GitlabRequest{
+		Key:                 d.Get("key").(string),
+		PersonalAccessToken: d.Get("personal_access_token").(string),
+		URL:                 d.Get("url").(string),
+	}
+	err := m.(*ProviderConfiguration).client.AlmSettings.CreateGitlab(ctx, request)
 	if err != nil {
-		return err
+		return diag.Errorf("resourceSonarqubeAlmGitlabCreate: Failed to create gitlab alm setting: %+v", err)
 	}
-	defer resp.Body.Close()
 
 	d.SetId(d.Get("key").(string))
 
-	return resourceSonarqubeAlmGitlabRead(d, m)
-}
-
-func resourceSonarqubeAlmGitlabRead(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/alm_settings/list_definitions"
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"GET",
-		sonarQubeURL.String(),
-		http.StatusOK,
-		"resourceSonarqubeAlmGitlabRead",
-	)
-	if err != nil {
-		return err
+	if diags := resourceSonarqubeAlmGitlabRead(ctx, d, m); diags.HasError() {
+		return diags
 	}
-	defer resp.Body.Close()
+	return validateAlmSettingOnApply(ctx, d, m, "resourceSonarqubeAlmGitlabCreate")
+}
 
-	// Decode response into struct
-	AlmGitlabReadResponse := GetAlmGitlab{}
-	err = json.NewDecoder(resp.Body).Decode(&AlmGitlabReadResponse)
+func resourceSonarqubeAlmGitlabRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	definitions, err := m.(*ProviderConfiguration).client.AlmSettings.ListDefinitions(ctx)
 	if err != nil {
-		return fmt.Errorf("resourceSonarqubeAlmGitlabRead: Failed to decode json into struct: %+v", err)
+		return diag.Errorf("resourceSonarqubeAlmGitlabRead: Failed to list alm definitions: %+v", err)
 	}
-	// Loop over all GitHub instances to see if the Alm instance exists.
-	for _, value := range AlmGitlabReadResponse.Gitlab {
+	// Loop over all GitLab instances to see if the Alm instance exists.
+	for _, value := range definitions.Gitlab {
 		if d.Id() == value.Key {
 			d.Set("key", value.Key)
 			d.Set("url", value.URL)
 			// The personal_access_token is a secured property that is not returned
-			// d.Set("personal_access_token", value.PersonalAccessToken)
 			return nil
 		}
 	}
-	return fmt.Errorf("resourceSonarqubeGitlabBindingRead: Failed to find gitlab binding: %+v", d.Id())
+	removeResourceFromState(d, "resourceSonarqubeAlmGitlabRead")
+	return nil
 }
 
-func resourceSonarqubeAlmGitlabUpdate(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/alm_settings/update_gitlab"
-	sonarQubeURL.RawQuery = url.Values{
-		"key":                 []string{d.Id()},
-		"newKey":              []string{d.Get("key").(string)},
-		"personalAccessToken": []string{d.Get("personal_access_token").(string)},
-		"url":                 []string{d.Get("url").(string)},
-	}.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusNoContent,
-		"resourceSonarqubeAlmGitlabUpdate",
-	)
+func resourceSonarqubeAlmGitlabUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	request := client.GitlabRequest{
+		Key:                 d.Id(),
+		NewKey:              d.Get("key").(string),
+		PersonalAccessToken: d.Get("personal_access_token").(string),
+		URL:                 d.Get("url").(string),
+	}
+	err := m.(*ProviderConfiguration).client.AlmSettings.UpdateGitlab(ctx, request)
 	if err != nil {
-		return err
+		return diag.Errorf("resourceSonarqubeAlmGitlabUpdate: Failed to update gitlab alm setting: %+v", err)
 	}
-	defer resp.Body.Close()
 
-	return resourceSonarqubeAlmGitlabRead(d, m)
+	if diags := resourceSonarqubeAlmGitlabRead(ctx, d, m); diags.HasError() {
+		return diags
+	}
+	return validateAlmSettingOnApply(ctx, d, m, "resourceSonarqubeAlmGitlabUpdate")
 }
 
-func resourceSonarqubeAlmGitlabDelete(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/alm_settings/delete"
-	sonarQubeURL.RawQuery = url.Values{
-		"key": []string{d.Get("key").(string)},
-	}.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusNoContent,
-		"resourceSonarqubeAlmGitlabDelete",
-	)
+func resourceSonarqubeAlmGitlabDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	err := m.(*ProviderConfiguration).client.AlmSettings.Delete(ctx, d.Get("key").(string))
 	if err != nil {
-		return err
+		return diag.Errorf("resourceSonarqubeAlmGitlabDelete: Failed to delete gitlab alm setting: %+v", err)
 	}
-	defer resp.Body.Close()
 
 	return nil
 }
+
+func resourceSonarqubeAlmGitlabImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	// import id in format {key}/{personal_access_token}
+	importIdComponents := strings.SplitN(d.Id(), "/", 2)
+
+	if len(importIdComponents) != 2 {
+		return nil, fmt.Errorf("resourceSonarqubeAlmGitlabImport: Import id: '%+v' is not in format {key}/{personal_access_token}", d.Id())
+	}
+
+	// set Id to key for Read
+	d.SetId(importIdComponents[0])
+	if diags := resourceSonarqubeAlmGitlabRead(ctx, d, m); diags.HasError() {
+		return nil, diagnosticsToError(diags)
+	}
+
+	// Add personal_access_token from import id
+	d.Set("personal_access_token", importIdComponents[1])
+
+	// validate_on_apply is not stored by Sonarqube, imports start from its default
+	d.Set("validate_on_apply", false)
+
+	return []*schema.ResourceData{d}, nil
+}
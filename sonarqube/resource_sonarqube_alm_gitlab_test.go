@@ -2,6 +2,7 @@ package sonarqube
 
 import (
 	"fmt"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
@@ -34,8 +35,8 @@ func TestAccSonarqubeAlmGitlabName(t *testing.T) {
 	name := "sonarqube_alm_gitlab." + rnd
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubeAlmGitlabName(rnd, "testAccSonarqubeAlmGitlabName", "123456"),
@@ -53,6 +54,44 @@ func TestAccSonarqubeAlmGitlabName(t *testing.T) {
 					resource.TestCheckResourceAttr(name, "url", "https://654321.gitlab.com/api/v4"),
 				),
 			},
+			{
+				ResourceName:      name,
+				ImportState:       true,
+				ImportStateId:     "testAccSonarqubeAlmGitlabNameUpdate/654321",
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccSonarqubeAlmGitlabValidateOnApply(rnd string, url string) string {
+	return fmt.Sprintf(`
+		resource "sonarqube_alm_gitlab" "%[1]s" {
+			key                   = "%[1]s"
+			personal_access_token = "123456"
+			url                   = "%[2]s"
+			validate_on_apply     = true
+		}`, rnd, url)
+}
+
+func TestAccSonarqubeAlmGitlabValidateOnApply(t *testing.T) {
+	rnd := generateRandomResourceName()
+	name := "sonarqube_alm_gitlab." + rnd
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t); testAccPreCheckCapability(t, capabilityAlmValidation) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSonarqubeAlmGitlabValidateOnApply(rnd, "https://gitlab.com/api/v4"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(name, "validate_on_apply", "true"),
+				),
+			},
+			{
+				Config:      testAccSonarqubeAlmGitlabValidateOnApply(rnd, "https://gitlab.example.invalid/api/v4"),
+				ExpectError: regexp.MustCompile("can not reach the DevOps platform"),
+			},
 		},
 	})
 }
@@ -0,0 +1,336 @@
+package sonarqube
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/jdamata/terraform-provider-sonarqube/internal/client"
+)
+
+// Returns the resource represented by this file.
+func resourceSonarqubeApplication() *schema.Resource {
+	return &schema.Resource{
+		Description: `Provides a Sonarqube Application resource. This can be used to create and manage Sonarqube Applications, which aggregate the
+analyses of several projects. The main branch of an application aggregates the main branches of its projects, its other branches
+select a branch of some of its projects. Applications require the Developer, Enterprise or Data Center edition.`,
+		CreateContext: resourceSonarqubeApplicationCreate,
+		ReadContext:   resourceSonarqubeApplicationRead,
+		UpdateContext: resourceSonarqubeApplicationUpdate,
+		DeleteContext: resourceSonarqubeApplicationDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceSonarqubeApplicationImport,
+		},
+		CustomizeDiff: requireCapability(capabilityApplications),
+		Timeouts: &schema.ResourceTimeout{
+			Default: schema.DefaultTimeout(5 * time.Minute),
+		},
+
+		// Define the fields of this schema.
+		Schema: map[string]*schema.Schema{
+			"key": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The key of the Application. Changing this forces a new resource to be created.",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The name of the Application.",
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "A description of the Application.",
+			},
+			"visibility": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "public",
+				ValidateFunc: validation.StringInSlice([]string{"public", "private"}, false),
+				Description:  "Whether the Application should be visible to everyone, or only specific user/groups. Valid values are `public` and `private`. Defaults to `public`.",
+			},
+			"tags": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "A set of tags to put on the Application.",
+			},
+			"projects": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "The keys of the projects of the Application.",
+			},
+			"branch": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "The branches of the Application other than its main branch.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "The name of the application branch.",
+						},
+						"project": {
+							Type:        schema.TypeSet,
+							Required:    true,
+							Description: "The projects of the application branch, each one with the branch of the project to include.",
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"key": {
+										Type:        schema.TypeString,
+										Required:    true,
+										Description: "The key of the project. The project must be one of the `projects` of the Application.",
+									},
+									"branch": {
+										Type:        schema.TypeString,
+										Required:    true,
+										Description: "The branch of the project to include in the application branch.",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// applicationBranch is an application branch as configured in the branch block
+type applicationBranch struct {
+	name            string
+	projects        []string
+	projectBranches []string
+}
+
+func (b applicationBranch) equal(other applicationBranch) bool {
+	return b.name == other.name && stringSlicesEqual(b.projects, other.projects, false) && stringSlicesEqual(b.projectBranches, other.projectBranches, false)
+}
+
+// Returns the application branches of a branch block set by name. The projects are sorted by key.
+func expandApplicationBranches(set *schema.Set) map[string]applicationBranch {
+	branches := map[string]applicationBranch{}
+	for _, b := range set.List() {
+		block := b.(map[string]interface{})
+		projects := block["project"].(*schema.Set).List()
+		sort.Slice(projects, func(i, j int) bool {
+			return projects[i].(map[string]interface{})["key"].(string) < projects[j].(map[string]interface{})["key"].(string)
+		})
+
+		branch := applicationBranch{name: block["name"].(string)}
+		for _, p := range projects {
+			branch.projects = append(branch.projects, p.(map[string]interface{})["key"].(string))
+			branch.projectBranches = append(branch.projectBranches, p.(map[string]interface{})["branch"].(string))
+		}
+		branches[branch.name] = branch
+	}
+	return branches
+}
+
+func expandStringSet(set *schema.Set) []string {
+	values := []string{}
+	for _, v := range set.List() {
+		values = append(values, v.(string))
+	}
+	sort.Strings(values)
+	return values
+}
+
+// Adds and removes the projects and the branches of an application, from the previous configuration to the current one.
+// The projects are added first and removed last, as the application branches can only include projects of the application.
+func synchronizeApplication(ctx context.Context, d *schema.ResourceData, m interface{}) error {
+	applicationsService := m.(*ProviderConfiguration).client.Applications
+	key := d.Get("key").(string)
+
+	oldProjects, newProjects := d.GetChange("projects")
+	for _, project := range newProjects.(*schema.Set).Difference(oldProjects.(*schema.Set)).List() {
+		if err := applicationsService.AddProject(ctx, key, project.(string)); err != nil {
+			return fmt.Errorf("synchronizeApplication: Failed to add project '%s': %+v", project, err)
+		}
+	}
+
+	oldBranchSet, newBranchSet := d.GetChange("branch")
+	oldBranches, newBranches := expandApplicationBranches(oldBranchSet.(*schema.Set)), expandApplicationBranches(newBranchSet.(*schema.Set))
+	for name := range oldBranches {
+		if _, ok := newBranches[name]; !ok {
+			if err := applicationsService.DeleteBranch(ctx, key, name); err != nil {
+				return fmt.Errorf("synchronizeApplication: Failed to delete branch '%s': %+v", name, err)
+			}
+		}
+	}
+	for name, branch := range newBranches {
+		request := client.ApplicationBranchRequest{
+			Application:     key,
+			Branch:          name,
+			Projects:        branch.projects,
+			ProjectBranches: branch.projectBranches,
+		}
+		if oldBranch, ok := oldBranches[name]; !ok {
+			if err := applicationsService.CreateBranch(ctx, request); err != nil {
+				return fmt.Errorf("synchronizeApplication: Failed to create branch '%s': %+v", name, err)
+			}
+		} else if !oldBranch.equal(branch) {
+			request.Name = name
+			if err := applicationsService.UpdateBranch(ctx, request); err != nil {
+				return fmt.Errorf("synchronizeApplication: Failed to update branch '%s': %+v", name, err)
+			}
+		}
+	}
+
+	for _, project := range oldProjects.(*schema.Set).Difference(newProjects.(*schema.Set)).List() {
+		if err := applicationsService.RemoveProject(ctx, key, project.(string)); err != nil {
+			return fmt.Errorf("synchronizeApplication: Failed to remove project '%s': %+v", project, err)
+		}
+	}
+	return nil
+}
+
+func resourceSonarqubeApplicationCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if err := m.(*ProviderConfiguration).checkCapability(capabilityApplications); err != nil {
+		return diag.FromErr(err)
+	}
+
+	applicationsService := m.(*ProviderConfiguration).client.Applications
+	application, err := applicationsService.Create(ctx, client.ApplicationsCreateRequest{
+		Key:         d.Get("key").(string),
+		Name:        d.Get("name").(string),
+		Description: d.Get("description").(string),
+		Visibility:  d.Get("visibility").(string),
+	})
+	if err != nil {
+		return diag.Errorf("resourceSonarqubeApplicationCreate: Failed to create application: %+v", err)
+	}
+
+	d.SetId(application.Key)
+
+	if tags := expandStringSet(d.Get("tags").(*schema.Set)); len(tags) > 0 {
+		if err := applicationsService.SetTags(ctx, application.Key, tags); err != nil {
+			return diag.Errorf("resourceSonarqubeApplicationCreate: Failed to set application tags: %+v", err)
+		}
+	}
+
+	if err := synchronizeApplication(ctx, d, m); err != nil {
+		return diag.Errorf("resourceSonarqubeApplicationCreate: Failed to set the projects and branches of the application: %+v", err)
+	}
+
+	return resourceSonarqubeApplicationRead(ctx, d, m)
+}
+
+func resourceSonarqubeApplicationRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if err := m.(*ProviderConfiguration).checkCapability(capabilityApplications); err != nil {
+		return diag.FromErr(err)
+	}
+
+	applicationsService := m.(*ProviderConfiguration).client.Applications
+	application, err := applicationsService.Show(ctx, d.Id(), "")
+	if err != nil {
+		if client.IsNotFound(err) {
+			removeResourceFromState(d, "resourceSonarqubeApplicationRead")
+			return nil
+		}
+		return diag.Errorf("resourceSonarqubeApplicationRead: Failed to read application: %+v", err)
+	}
+
+	projects := []string{}
+	for _, project := range application.Projects {
+		projects = append(projects, project.Key)
+	}
+
+	// Every branch other than the main branch is shown on its own to read the branches of its projects
+	branches := []interface{}{}
+	for _, branch := range application.Branches {
+		if branch.IsMain {
+			continue
+		}
+		applicationBranch, err := applicationsService.Show(ctx, d.Id(), branch.Name)
+		if err != nil {
+			return diag.Errorf("resourceSonarqubeApplicationRead: Failed to read application branch '%s': %+v", branch.Name, err)
+		}
+		branchProjects := []interface{}{}
+		for _, project := range applicationBranch.Projects {
+			if project.Selected {
+				branchProjects = append(branchProjects, map[string]interface{}{"key": project.Key, "branch": project.Branch})
+			}
+		}
+		branches = append(branches, map[string]interface{}{"name": branch.Name, "project": branchProjects})
+	}
+
+	d.SetId(application.Key)
+	d.Set("key", application.Key)
+	d.Set("name", application.Name)
+	d.Set("description", application.Description)
+	d.Set("visibility", application.Visibility)
+	d.Set("tags", application.Tags)
+	d.Set("projects", projects)
+	d.Set("branch", branches)
+	return nil
+}
+
+func resourceSonarqubeApplicationUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if err := m.(*ProviderConfiguration).checkCapability(capabilityApplications); err != nil {
+		return diag.FromErr(err)
+	}
+
+	sonarQubeClient := m.(*ProviderConfiguration).client
+	if d.HasChanges("name", "description") {
+		err := sonarQubeClient.Applications.Update(ctx, d.Id(), d.Get("name").(string), d.Get("description").(string))
+		if err != nil {
+			return diag.Errorf("resourceSonarqubeApplicationUpdate: Failed to update application: %+v", err)
+		}
+	}
+
+	// Applications are components, their visibility is updated like the one of projects
+	if d.HasChange("visibility") {
+		err := sonarQubeClient.Projects.UpdateVisibility(ctx, d.Id(), d.Get("visibility").(string))
+		if err != nil {
+			return diag.Errorf("resourceSonarqubeApplicationUpdate: Failed to update application visibility: %+v", err)
+		}
+	}
+
+	if d.HasChange("tags") {
+		err := sonarQubeClient.Applications.SetTags(ctx, d.Id(), expandStringSet(d.Get("tags").(*schema.Set)))
+		if err != nil {
+			return diag.Errorf("resourceSonarqubeApplicationUpdate: Failed to set application tags: %+v", err)
+		}
+	}
+
+	if d.HasChanges("projects", "branch") {
+		if err := synchronizeApplication(ctx, d, m); err != nil {
+			return diag.Errorf("resourceSonarqubeApplicationUpdate: Failed to update the projects and branches of the application: %+v", err)
+		}
+	}
+
+	return resourceSonarqubeApplicationRead(ctx, d, m)
+}
+
+func resourceSonarqubeApplicationDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if err := m.(*ProviderConfiguration).checkCapability(capabilityApplications); err != nil {
+		return diag.FromErr(err)
+	}
+
+	err := m.(*ProviderConfiguration).client.Applications.Delete(ctx, d.Id())
+	if err != nil {
+		return diag.Errorf("resourceSonarqubeApplicationDelete: Failed to delete application: %+v", err)
+	}
+
+	return nil
+}
+
+func resourceSonarqubeApplicationImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	if diags := resourceSonarqubeApplicationRead(ctx, d, m); diags.HasError() {
+		return nil, diagnosticsToError(diags)
+	}
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,176 @@
+package sonarqube
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+)
+
+func init() {
+	resource.AddTestSweepers("sonarqube_application", &resource.Sweeper{
+		Name: "sonarqube_application",
+		F:    testSweepSonarqubeApplicationSweeper,
+	})
+}
+
+// TODO: implement sweeper to clean up applications: https://www.terraform.io/docs/extend/testing/acceptance-tests/sweepers.html
+func testSweepSonarqubeApplicationSweeper(r string) error {
+	return nil
+}
+
+func testAccPreCheckApplicationSupport(t *testing.T) {
+	testAccPreCheckCapability(t, capabilityApplications)
+}
+
+func testAccSonarqubeApplicationConfig(rnd string, key string, application string) string {
+	return fmt.Sprintf(`
+		resource "sonarqube_project" "%[1]s_backend" {
+		  name    = "%[2]s-backend"
+		  project = "%[2]s-backend"
+		}
+
+		resource "sonarqube_project" "%[1]s_frontend" {
+		  name    = "%[2]s-frontend"
+		  project = "%[2]s-frontend"
+		}
+
+		resource "sonarqube_application" "%[1]s" {
+		  key = "%[2]s"
+		  %[3]s
+		}
+		`, rnd, key, application)
+}
+
+func TestAccSonarqubeApplicationBasic(t *testing.T) {
+	rnd := generateRandomResourceName()
+	name := "sonarqube_application." + rnd
+	key := "testAccSonarqubeApplication"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t); testAccPreCheckApplicationSupport(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSonarqubeApplicationConfig(rnd, key, fmt.Sprintf(`
+		  name        = "My application"
+		  description = "My description"
+		  tags        = ["backend", "frontend"]
+		  projects    = [sonarqube_project.%[1]s_backend.project, sonarqube_project.%[1]s_frontend.project]`, rnd)),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(name, "id", key),
+					resource.TestCheckResourceAttr(name, "name", "My application"),
+					resource.TestCheckResourceAttr(name, "description", "My description"),
+					resource.TestCheckResourceAttr(name, "visibility", "public"),
+					resource.TestCheckResourceAttr(name, "tags.#", "2"),
+					resource.TestCheckTypeSetElemAttr(name, "projects.*", key+"-backend"),
+					resource.TestCheckTypeSetElemAttr(name, "projects.*", key+"-frontend"),
+					resource.TestCheckResourceAttr(name, "branch.#", "0"),
+				),
+			},
+			{
+				ResourceName:      name,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config: testAccSonarqubeApplicationConfig(rnd, key, fmt.Sprintf(`
+		  name       = "My renamed application"
+		  visibility = "private"
+		  tags       = ["backend"]
+		  projects   = [sonarqube_project.%[1]s_backend.project]`, rnd)),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(name, plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(name, "name", "My renamed application"),
+					resource.TestCheckResourceAttr(name, "description", ""),
+					resource.TestCheckResourceAttr(name, "visibility", "private"),
+					resource.TestCheckResourceAttr(name, "tags.#", "1"),
+					resource.TestCheckResourceAttr(name, "projects.#", "1"),
+					resource.TestCheckTypeSetElemAttr(name, "projects.*", key+"-backend"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccSonarqubeApplicationBranch(t *testing.T) {
+	rnd := generateRandomResourceName()
+	name := "sonarqube_application." + rnd
+	key := "testAccSonarqubeApplicationBranch"
+
+	application := func(frontendBranch string) string {
+		return testAccSonarqubeApplicationConfig(rnd, key, fmt.Sprintf(`
+		  name     = "My application"
+		  projects = [sonarqube_project.%[1]s_backend.project, sonarqube_project.%[1]s_frontend.project]
+
+		  branch {
+		    name = "release"
+		    project {
+		      key    = sonarqube_project.%[1]s_backend.project
+		      branch = "release-1.0"
+		    }
+		    project {
+		      key    = sonarqube_project.%[1]s_frontend.project
+		      branch = "%[2]s"
+		    }
+		  }`, rnd, frontendBranch))
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckApplicationSupport(t)
+			testAccPreCheckFakeServer(t)
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSonarqubeApplicationConfig(rnd, key, `name = "My application"`),
+			},
+			{
+				PreConfig: func() {
+					testAccAnalyzeBranch(t, key+"-backend", "release-1.0")
+					testAccAnalyzeBranch(t, key+"-frontend", "release-1.0")
+					testAccAnalyzeBranch(t, key+"-frontend", "release-1.1")
+				},
+				Config: application("release-1.0"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(name, "branch.#", "1"),
+					resource.TestCheckResourceAttr(name, "branch.0.name", "release"),
+					resource.TestCheckTypeSetElemNestedAttrs(name, "branch.0.project.*", map[string]string{"key": key + "-backend", "branch": "release-1.0"}),
+					resource.TestCheckTypeSetElemNestedAttrs(name, "branch.0.project.*", map[string]string{"key": key + "-frontend", "branch": "release-1.0"}),
+				),
+			},
+			{
+				ResourceName:      name,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config: application("release-1.1"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(name, plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckTypeSetElemNestedAttrs(name, "branch.0.project.*", map[string]string{"key": key + "-frontend", "branch": "release-1.1"}),
+				),
+			},
+			{
+				Config: testAccSonarqubeApplicationConfig(rnd, key, fmt.Sprintf(`
+		  name     = "My application"
+		  projects = [sonarqube_project.%[1]s_backend.project]`, rnd)),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(name, "projects.#", "1"),
+					resource.TestCheckResourceAttr(name, "branch.#", "0"),
+				),
+			},
+		},
+	})
+}
@@ -1,108 +1,84 @@
 package sonarqube
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"net/http"
-	"net/url"
-	"strconv"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/jdamata/terraform-provider-sonarqube/internal/client"
 )
 
-// GetAzureBinding for unmarshalling response body from getting project binding details
-type GetAzureBinding struct {
-	Key        string `json:"key"`
-	Alm        string `json:"alm"`
-	Repository string `json:"repository"` // Azure DevOps Repository
-	Slug       string `json:"slug"`       // Azure DevOps Project
-	URL        string `json:"url"`
-	Monorepo   bool   `json:"monorepo"`
-}
-
 // Returns the resource represented by this file.
 func resourceSonarqubeAzureBinding() *schema.Resource {
 	return &schema.Resource{
 		Description: `Provides a Sonarqube Azure Devops binding resource. This can be used to create and manage the binding between an
 Azure Devops repository and a SonarQube project`,
-		Create: resourceSonarqubeAzureBindingCreate,
-		Read:   resourceSonarqubeAzureBindingRead,
-		Delete: resourceSonarqubeAzureBindingDelete,
+		CreateContext: resourceSonarqubeAzureBindingCreate,
+		// You can update any project binding with the same API call as the CREATE
+		UpdateContext: resourceSonarqubeAzureBindingCreate,
+		ReadContext:   resourceSonarqubeAzureBindingRead,
+		DeleteContext: resourceSonarqubeAzureBindingDelete,
 		Importer: &schema.ResourceImporter{
-			State: resourceSonarqubeAzureBindingImport,
+			StateContext: resourceSonarqubeAzureBindingImport,
 		},
+		CustomizeDiff: requireCapability(capabilityAzureBindings),
+
+		Timeouts: &schema.ResourceTimeout{
+			Default: schema.DefaultTimeout(5 * time.Minute),
+		},
+
 		// Define the fields of this schema.
 		Schema: map[string]*schema.Schema{
 			"alm_setting": {
 				Type:        schema.TypeString,
 				Required:    true,
-				ForceNew:    true,
 				Description: "Azure DevOps setting key",
 			},
 			"monorepo": {
 				Type:        schema.TypeBool,
 				Optional:    true,
 				Default:     false,
-				ForceNew:    true,
 				Description: "Is this project part of a monorepo",
 			},
 			"project": {
 				Type:        schema.TypeString,
 				Required:    true,
 				ForceNew:    true,
-				Description: "SonarQube project key",
+				Description: "SonarQube project key. Changing this will force a new resource to be created",
 			},
 			"project_name": {
 				Type:        schema.TypeString,
 				Required:    true,
-				ForceNew:    true,
 				Description: "Azure project name",
 			},
 			"repository_name": {
 				Type:        schema.TypeString,
 				Required:    true,
-				ForceNew:    true,
 				Description: "Azure repository name",
 			},
 		},
 	}
 }
 
-func checkAzureBindingSupport(conf *ProviderConfiguration) error {
-	if strings.ToLower(conf.sonarQubeEdition) == "community" {
-		return fmt.Errorf("Azure Devops Bindings are not supported in the Community edition of SonarQube. You are using: SonarQube %s version %s", conf.sonarQubeEdition, conf.sonarQubeVersion)
+func resourceSonarqubeAzureBindingCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if err := m.(*ProviderConfiguration).checkCapability(capabilityAzureBindings); err != nil {
+		return diag.FromErr(err)
 	}
-	return nil
-}
 
-func resourceSonarqubeAzureBindingCreate(d *schema.ResourceData, m interface{}) error {
-	if err := checkAzureBindingSupport(m.(*ProviderConfiguration)); err != nil {
-		return err
+	request := client.AzureBindingRequest{
+		AlmSetting:     d.Get("alm_setting").(string),
+		Monorepo:       d.Get("monorepo").(bool),
+		Project:        d.Get("project").(string),
+		ProjectName:    d.Get("project_name").(string),
+		RepositoryName: d.Get("repository_name").(string),
 	}
-
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/alm_settings/set_azure_binding"
-
-	sonarQubeURL.RawQuery = url.Values{
-		"almSetting":     []string{d.Get("alm_setting").(string)},
-		"monorepo":       []string{strconv.FormatBool(d.Get("monorepo").(bool))},
-		"project":        []string{d.Get("project").(string)},
-		"projectName":    []string{d.Get("project_name").(string)},
-		"repositoryName": []string{d.Get("repository_name").(string)},
-	}.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusNoContent,
-		"resourceSonarqubeAzureBindingCreate",
-	)
+	err := m.(*ProviderConfiguration).client.AlmSettings.SetAzureBinding(ctx, request)
 	if err != nil {
-		return err
+		return diag.Errorf("resourceSonarqubeAzureBindingCreate: Failed to create azure binding: %+v", err)
 	}
-	defer resp.Body.Close()
 
 	// id consists of "project/project_name/repository"
 	id := fmt.Sprintf("%v/%v/%v",
@@ -112,84 +88,57 @@ func resourceSonarqubeAzureBindingCreate(d *schema.ResourceData, m interface{})
 	)
 	d.SetId(id)
 
-	return resourceSonarqubeAzureBindingRead(d, m)
+	return resourceSonarqubeAzureBindingRead(ctx, d, m)
 }
 
-func resourceSonarqubeAzureBindingRead(d *schema.ResourceData, m interface{}) error {
-	if err := checkAzureBindingSupport(m.(*ProviderConfiguration)); err != nil {
-		return err
+func resourceSonarqubeAzureBindingRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if err := m.(*ProviderConfiguration).checkCapability(capabilityAzureBindings); err != nil {
+		return diag.FromErr(err)
 	}
 
 	// id consists of "project/project_name/repository"
 	idSlice := strings.SplitN(d.Id(), "/", 3)
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/alm_settings/get_binding"
-	sonarQubeURL.RawQuery = url.Values{
-		"project": []string{idSlice[0]},
-	}.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"GET",
-		sonarQubeURL.String(),
-		http.StatusOK,
-		"resourceSonarqubeAzureBindingRead",
-	)
+	binding, err := m.(*ProviderConfiguration).client.AlmSettings.GetBinding(ctx, idSlice[0])
 	if err != nil {
-		return err
+		if client.IsNotFound(err) {
+			removeResourceFromState(d, "resourceSonarqubeAzureBindingRead")
+			return nil
+		}
+		return diag.Errorf("resourceSonarqubeAzureBindingRead: Failed to read azure binding: %+v", err)
 	}
-	defer resp.Body.Close()
 
-	// Decode response into struct
-	BindingReadResponse := GetAzureBinding{}
-	err = json.NewDecoder(resp.Body).Decode(&BindingReadResponse)
-	if err != nil {
-		return fmt.Errorf("resourceSonarqubeAzureBindingRead: Failed to decode json into struct: %+v", err)
-	}
-
-	if idSlice[1] == BindingReadResponse.Slug &&
-		idSlice[2] == BindingReadResponse.Repository &&
-		BindingReadResponse.Alm == "azure" {
+	// For Azure DevOps the slug holds the project name
+	if idSlice[1] == binding.Slug &&
+		idSlice[2] == binding.Repository &&
+		binding.Alm == "azure" {
 		d.Set("project", idSlice[0])
 		d.Set("project_name", idSlice[1])
 		d.Set("repository_name", idSlice[2])
-		d.Set("alm_setting", BindingReadResponse.Key)
-		d.Set("monorepo", BindingReadResponse.Monorepo)
+		d.Set("alm_setting", binding.Key)
+		d.Set("monorepo", binding.Monorepo)
 
 		return nil
 	}
-	return fmt.Errorf("resourceSonarqubeAzureBindingRead: Failed to find azure binding: %+v", d.Id())
+	removeResourceFromState(d, "resourceSonarqubeAzureBindingRead")
+	return nil
 }
 
-func resourceSonarqubeAzureBindingDelete(d *schema.ResourceData, m interface{}) error {
-	if err := checkAzureBindingSupport(m.(*ProviderConfiguration)); err != nil {
-		return err
+func resourceSonarqubeAzureBindingDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if err := m.(*ProviderConfiguration).checkCapability(capabilityAzureBindings); err != nil {
+		return diag.FromErr(err)
 	}
 
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/alm_settings/delete_binding"
-	sonarQubeURL.RawQuery = url.Values{
-		"project": []string{d.Get("project").(string)},
-	}.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusNoContent,
-		"resourceSonarqubeAzureBindingDelete",
-	)
+	err := m.(*ProviderConfiguration).client.AlmSettings.DeleteBinding(ctx, d.Get("project").(string))
 	if err != nil {
-		return err
+		return diag.Errorf("resourceSonarqubeAzureBindingDelete: Failed to delete azure binding: %+v", err)
 	}
-	defer resp.Body.Close()
 
 	return nil
 }
 
-func resourceSonarqubeAzureBindingImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
-	if err := resourceSonarqubeAzureBindingRead(d, m); err != nil {
-		return nil, err
+func resourceSonarqubeAzureBindingImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	if diags := resourceSonarqubeAzureBindingRead(ctx, d, m); diags.HasError() {
+		return nil, diagnosticsToError(diags)
 	}
 	return []*schema.ResourceData{d}, nil
 }
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
 )
 
 func init() {
@@ -19,9 +20,7 @@ func testSweepSonarqubeAzureBinding(r string) error {
 	return nil
 }
 func testAccPreCheckAzureBindingSupport(t *testing.T) {
-	if err := checkAzureBindingSupport(testAccProvider.Meta().(*ProviderConfiguration)); err != nil {
-		t.Skipf("Skipping test of unsupported feature (Azure Binding)")
-	}
+	testAccPreCheckCapability(t, capabilityAzureBindings)
 }
 
 func testAccSonarqubeAzureBindingName(rnd string, projKey string, almSetting string, projName string, repoName string) string {
@@ -52,8 +51,8 @@ func TestAccSonarqubeAzureBindingName(t *testing.T) {
 	name := "sonarqube_azure_binding." + rnd
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t); testAccPreCheckAzureBindingSupport(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t); testAccPreCheckAzureBindingSupport(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubeAzureBindingName(rnd, "testSqProjectKey", "azure", "testAzProjName", "testAzRepoName"),
@@ -115,6 +114,20 @@ func TestAccSonarqubeAzureBindingName(t *testing.T) {
 					resource.TestCheckResourceAttr(name, "repository_name", "testAzRepoName"),
 				),
 			},
+			{
+				Config: testAccSonarqubeAzureBindingName(rnd, "testSqProjectKey", "azurea", "testAzOtherProjName", "testAzOtherRepoName"),
+				// Moving the repository updates the binding in place
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(name, plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(name, "id", "testSqProjectKey/testAzOtherProjName/testAzOtherRepoName"),
+					resource.TestCheckResourceAttr(name, "project_name", "testAzOtherProjName"),
+					resource.TestCheckResourceAttr(name, "repository_name", "testAzOtherRepoName"),
+				),
+			},
 		},
 	})
 }
@@ -0,0 +1,152 @@
+package sonarqube
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/jdamata/terraform-provider-sonarqube/internal/client"
+)
+
+// Returns the resource represented by this file.
+func resourceSonarqubeBitbucketBinding() *schema.Resource {
+	return &schema.Resource{
+		Description: `Provides a Sonarqube Bitbucket Server binding resource. This can be used to create and manage the binding between a
+Bitbucket Server or Bitbucket Data Center repository and a SonarQube project`,
+		CreateContext: resourceSonarqubeBitbucketBindingCreate,
+		// You can update any project binding with the same API call as the CREATE
+		UpdateContext: resourceSonarqubeBitbucketBindingCreate,
+		ReadContext:   resourceSonarqubeBitbucketBindingRead,
+		DeleteContext: resourceSonarqubeBitbucketBindingDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceSonarqubeBitbucketBindingImport,
+		},
+		CustomizeDiff: requireCapability(capabilityBitbucketBindings),
+
+		Timeouts: &schema.ResourceTimeout{
+			Default: schema.DefaultTimeout(5 * time.Minute),
+		},
+
+		// Define the fields of this schema.
+		Schema: map[string]*schema.Schema{
+			"alm_setting": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Bitbucket Server ALM setting key",
+			},
+			"monorepo": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "false",
+				Description: "Is this project part of a monorepo. Default value: false",
+			},
+			"project": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "SonarQube project key. Changing this will force a new resource to be created",
+			},
+			"repository": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The key of the Bitbucket project holding the repository",
+			},
+			"slug": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The slug of the Bitbucket repository",
+			},
+		},
+	}
+}
+
+func resourceSonarqubeBitbucketBindingCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if err := m.(*ProviderConfiguration).checkCapability(capabilityBitbucketBindings); err != nil {
+		return diag.FromErr(err)
+	}
+
+	monorepo, err := strconv.ParseBool(d.Get("monorepo").(string))
+	if err != nil {
+		return diag.Errorf("resourceSonarqubeBitbucketBindingCreate: Failed to parse monorepo: %+v", err)
+	}
+
+	request := client.BitbucketBindingRequest{
+		AlmSetting: d.Get("alm_setting").(string),
+		Monorepo:   monorepo,
+		Project:    d.Get("project").(string),
+		Repository: d.Get("repository").(string),
+		Slug:       d.Get("slug").(string),
+	}
+	err = m.(*ProviderConfiguration).client.AlmSettings.SetBitbucketBinding(ctx, request)
+	if err != nil {
+		return diag.Errorf("resourceSonarqubeBitbucketBindingCreate: Failed to create bitbucket binding: %+v", err)
+	}
+
+	// id consists of "project/repository/slug"
+	id := fmt.Sprintf("%v/%v/%v",
+		d.Get("project").(string),
+		d.Get("repository").(string),
+		d.Get("slug").(string),
+	)
+	d.SetId(id)
+
+	return resourceSonarqubeBitbucketBindingRead(ctx, d, m)
+}
+
+func resourceSonarqubeBitbucketBindingRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if err := m.(*ProviderConfiguration).checkCapability(capabilityBitbucketBindings); err != nil {
+		return diag.FromErr(err)
+	}
+
+	// id consists of "project/repository/slug"
+	idSlice := strings.SplitN(d.Id(), "/", 3)
+	if len(idSlice) != 3 {
+		return diag.Errorf("resourceSonarqubeBitbucketBindingRead: Id '%s' is not in format {project}/{repository}/{slug}", d.Id())
+	}
+	binding, err := m.(*ProviderConfiguration).client.AlmSettings.GetBinding(ctx, idSlice[0])
+	if err != nil {
+		if client.IsNotFound(err) {
+			removeResourceFromState(d, "resourceSonarqubeBitbucketBindingRead")
+			return nil
+		}
+		return diag.Errorf("resourceSonarqubeBitbucketBindingRead: Failed to read bitbucket binding: %+v", err)
+	}
+
+	if idSlice[1] == binding.Repository &&
+		idSlice[2] == binding.Slug &&
+		binding.Alm == "bitbucket" {
+		d.Set("project", idSlice[0])
+		d.Set("repository", idSlice[1])
+		d.Set("slug", idSlice[2])
+		d.Set("alm_setting", binding.Key)
+		d.Set("monorepo", strconv.FormatBool(binding.Monorepo))
+
+		return nil
+	}
+	removeResourceFromState(d, "resourceSonarqubeBitbucketBindingRead")
+	return nil
+}
+
+func resourceSonarqubeBitbucketBindingDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if err := m.(*ProviderConfiguration).checkCapability(capabilityBitbucketBindings); err != nil {
+		return diag.FromErr(err)
+	}
+
+	err := m.(*ProviderConfiguration).client.AlmSettings.DeleteBinding(ctx, d.Get("project").(string))
+	if err != nil {
+		return diag.Errorf("resourceSonarqubeBitbucketBindingDelete: Failed to delete bitbucket binding: %+v", err)
+	}
+
+	return nil
+}
+
+func resourceSonarqubeBitbucketBindingImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	if diags := resourceSonarqubeBitbucketBindingRead(ctx, d, m); diags.HasError() {
+		return nil, diagnosticsToError(diags)
+	}
+	return []*schema.ResourceData{d}, nil
+}
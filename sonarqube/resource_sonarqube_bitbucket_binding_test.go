@@ -0,0 +1,86 @@
+package sonarqube
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func init() {
+	resource.AddTestSweepers("sonarqube_bitbucket_binding", &resource.Sweeper{
+		Name: "sonarqube_bitbucket_binding",
+		F:    testSweepSonarqubeBitbucketBinding,
+	})
+}
+
+// TODO: implement sweeper to clean up projects: https://www.terraform.io/docs/extend/testing/acceptance-tests/sweepers.html
+func testSweepSonarqubeBitbucketBinding(r string) error {
+	return nil
+}
+func testAccPreCheckBitbucketBindingSupport(t *testing.T) {
+	testAccPreCheckCapability(t, capabilityBitbucketBindings)
+}
+
+func testAccSonarqubeBitbucketBindingName(rnd string, projName string, repository string, slug string) string {
+	return fmt.Sprintf(`
+
+        resource "sonarqube_alm_bitbucket" "%[1]s" {
+            personal_access_token       = "123456"
+            key    = "bitbucket"
+            url    = "https://bitbucket.example.com"
+        }
+
+        resource "sonarqube_project" "%[1]s" {
+            name       = "%[2]s"
+            project    = "%[2]s"
+            visibility = "public"
+        }
+
+        resource "sonarqube_bitbucket_binding" "%[1]s" {
+            alm_setting = sonarqube_alm_bitbucket.%[1]s.key
+            project     = sonarqube_project.%[1]s.project
+            repository  = "%[3]s"
+            slug        = "%[4]s"
+        }`, rnd, projName, repository, slug)
+}
+
+func TestAccSonarqubeBitbucketBindingName(t *testing.T) {
+	rnd := generateRandomResourceName()
+	name := "sonarqube_bitbucket_binding." + rnd
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t); testAccPreCheckBitbucketBindingSupport(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSonarqubeBitbucketBindingName(rnd, "testAccSonarqubeBitbucketBindingName", "PROJ", "my-repo"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(name, "project", "testAccSonarqubeBitbucketBindingName"),
+					resource.TestCheckResourceAttr(name, "repository", "PROJ"),
+					resource.TestCheckResourceAttr(name, "slug", "my-repo"),
+					resource.TestCheckResourceAttr(name, "alm_setting", "bitbucket"),
+					resource.TestCheckResourceAttr(name, "monorepo", "false"),
+				),
+			},
+			{
+				ResourceName:      name,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config: testAccSonarqubeBitbucketBindingName(rnd, "testAccSonarqubeBitbucketBindingName", "OTHER", "other-repo"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(name, "id", "testAccSonarqubeBitbucketBindingName/OTHER/other-repo"),
+					resource.TestCheckResourceAttr(name, "repository", "OTHER"),
+					resource.TestCheckResourceAttr(name, "slug", "other-repo"),
+				),
+			},
+			{
+				ResourceName:      name,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
@@ -0,0 +1,134 @@
+package sonarqube
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/jdamata/terraform-provider-sonarqube/internal/client"
+)
+
+// Returns the resource represented by this file.
+func resourceSonarqubeBitbucketCloudBinding() *schema.Resource {
+	return &schema.Resource{
+		Description: `Provides a Sonarqube Bitbucket Cloud binding resource. This can be used to create and manage the binding between a
+Bitbucket Cloud repository and a SonarQube project`,
+		CreateContext: resourceSonarqubeBitbucketCloudBindingCreate,
+		// You can update any project binding with the same API call as the CREATE
+		UpdateContext: resourceSonarqubeBitbucketCloudBindingCreate,
+		ReadContext:   resourceSonarqubeBitbucketCloudBindingRead,
+		DeleteContext: resourceSonarqubeBitbucketCloudBindingDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceSonarqubeBitbucketCloudBindingImport,
+		},
+		CustomizeDiff: requireCapability(capabilityBitbucketCloudBindings),
+
+		Timeouts: &schema.ResourceTimeout{
+			Default: schema.DefaultTimeout(5 * time.Minute),
+		},
+
+		// Define the fields of this schema.
+		Schema: map[string]*schema.Schema{
+			"alm_setting": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Bitbucket Cloud ALM setting key",
+			},
+			"monorepo": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "false",
+				Description: "Is this project part of a monorepo. Default value: false",
+			},
+			"project": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "SonarQube project key. Changing this will force a new resource to be created",
+			},
+			"repository": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The slug of the Bitbucket Cloud repository",
+			},
+		},
+	}
+}
+
+func resourceSonarqubeBitbucketCloudBindingCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if err := m.(*ProviderConfiguration).checkCapability(capabilityBitbucketCloudBindings); err != nil {
+		return diag.FromErr(err)
+	}
+
+	monorepo, err := strconv.ParseBool(d.Get("monorepo").(string))
+	if err != nil {
+		return diag.Errorf("resourceSonarqubeBitbucketCloudBindingCreate: Failed to parse monorepo: %+v", err)
+	}
+
+	request := client.BitbucketCloudBindingRequest{
+		AlmSetting: d.Get("alm_setting").(string),
+		Monorepo:   monorepo,
+		Project:    d.Get("project").(string),
+		Repository: d.Get("repository").(string),
+	}
+	err = m.(*ProviderConfiguration).client.AlmSettings.SetBitbucketCloudBinding(ctx, request)
+	if err != nil {
+		return diag.Errorf("resourceSonarqubeBitbucketCloudBindingCreate: Failed to create bitbucket cloud binding: %+v", err)
+	}
+
+	id := fmt.Sprintf("%v/%v", d.Get("project").(string), d.Get("repository").(string))
+	d.SetId(id)
+
+	return resourceSonarqubeBitbucketCloudBindingRead(ctx, d, m)
+}
+
+func resourceSonarqubeBitbucketCloudBindingRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if err := m.(*ProviderConfiguration).checkCapability(capabilityBitbucketCloudBindings); err != nil {
+		return diag.FromErr(err)
+	}
+
+	idSlice := strings.SplitN(d.Id(), "/", 2)
+	binding, err := m.(*ProviderConfiguration).client.AlmSettings.GetBinding(ctx, idSlice[0])
+	if err != nil {
+		if client.IsNotFound(err) {
+			removeResourceFromState(d, "resourceSonarqubeBitbucketCloudBindingRead")
+			return nil
+		}
+		return diag.Errorf("resourceSonarqubeBitbucketCloudBindingRead: Failed to read bitbucket cloud binding: %+v", err)
+	}
+
+	if idSlice[1] == binding.Repository && binding.Alm == "bitbucketcloud" {
+		d.Set("project", idSlice[0])
+		d.Set("repository", idSlice[1])
+		d.Set("alm_setting", binding.Key)
+		d.Set("monorepo", strconv.FormatBool(binding.Monorepo))
+
+		return nil
+	}
+	removeResourceFromState(d, "resourceSonarqubeBitbucketCloudBindingRead")
+	return nil
+}
+
+func resourceSonarqubeBitbucketCloudBindingDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if err := m.(*ProviderConfiguration).checkCapability(capabilityBitbucketCloudBindings); err != nil {
+		return diag.FromErr(err)
+	}
+
+	err := m.(*ProviderConfiguration).client.AlmSettings.DeleteBinding(ctx, d.Get("project").(string))
+	if err != nil {
+		return diag.Errorf("resourceSonarqubeBitbucketCloudBindingDelete: Failed to delete bitbucket cloud binding: %+v", err)
+	}
+
+	return nil
+}
+
+func resourceSonarqubeBitbucketCloudBindingImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	if diags := resourceSonarqubeBitbucketCloudBindingRead(ctx, d, m); diags.HasError() {
+		return nil, diagnosticsToError(diags)
+	}
+	return []*schema.ResourceData{d}, nil
+}
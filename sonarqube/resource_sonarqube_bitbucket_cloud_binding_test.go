@@ -0,0 +1,85 @@
+package sonarqube
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func init() {
+	resource.AddTestSweepers("sonarqube_bitbucket_cloud_binding", &resource.Sweeper{
+		Name: "sonarqube_bitbucket_cloud_binding",
+		F:    testSweepSonarqubeBitbucketCloudBinding,
+	})
+}
+
+// TODO: implement sweeper to clean up projects: https://www.terraform.io/docs/extend/testing/acceptance-tests/sweepers.html
+func testSweepSonarqubeBitbucketCloudBinding(r string) error {
+	return nil
+}
+func testAccPreCheckBitbucketCloudBindingSupport(t *testing.T) {
+	testAccPreCheckCapability(t, capabilityBitbucketCloudBindings)
+}
+
+func testAccSonarqubeBitbucketCloudBindingName(rnd string, projName string, repository string, monorepo string) string {
+	return fmt.Sprintf(`
+
+        resource "sonarqube_alm_bitbucket_cloud" "%[1]s" {
+            client_id     = "client"
+            client_secret = "123456"
+            key           = "bitbucketcloud"
+            workspace     = "workspace"
+        }
+
+        resource "sonarqube_project" "%[1]s" {
+            name       = "%[2]s"
+            project    = "%[2]s"
+            visibility = "public"
+        }
+
+        resource "sonarqube_bitbucket_cloud_binding" "%[1]s" {
+            alm_setting = sonarqube_alm_bitbucket_cloud.%[1]s.key
+            monorepo    = "%[4]s"
+            project     = sonarqube_project.%[1]s.project
+            repository  = "%[3]s"
+        }`, rnd, projName, repository, monorepo)
+}
+
+func TestAccSonarqubeBitbucketCloudBindingName(t *testing.T) {
+	rnd := generateRandomResourceName()
+	name := "sonarqube_bitbucket_cloud_binding." + rnd
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t); testAccPreCheckBitbucketCloudBindingSupport(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSonarqubeBitbucketCloudBindingName(rnd, "testAccSonarqubeBitbucketCloudBindingName", "my-repo", "false"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(name, "project", "testAccSonarqubeBitbucketCloudBindingName"),
+					resource.TestCheckResourceAttr(name, "repository", "my-repo"),
+					resource.TestCheckResourceAttr(name, "alm_setting", "bitbucketcloud"),
+					resource.TestCheckResourceAttr(name, "monorepo", "false"),
+				),
+			},
+			{
+				ResourceName:      name,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config: testAccSonarqubeBitbucketCloudBindingName(rnd, "testAccSonarqubeBitbucketCloudBindingName", "my-repo", "true"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(name, "repository", "my-repo"),
+					resource.TestCheckResourceAttr(name, "monorepo", "true"),
+				),
+			},
+			{
+				ResourceName:      name,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
@@ -1,188 +1,146 @@
 package sonarqube
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"net/http"
-	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/jdamata/terraform-provider-sonarqube/internal/client"
 )
 
-// GetBinding for unmarshalling response body from getting project binding details
-type GetBinding struct {
-	Key                   string `json:"key"`
-	Alm                   string `json:"alm"`
-	Repository            string `json:"repository"`
-	URL                   string `json:"url"`
-	SummaryCommentEnabled bool   `json:"summaryCommentEnabled,omitempty"`
-	Monorepo              bool   `json:"monorepo"`
-}
-
 // Returns the resource represented by this file.
 func resourceSonarqubeGithubBinding() *schema.Resource {
 	return &schema.Resource{
 		Description: `Provides a Sonarqube GitHub binding resource. This can be used to create and manage the binding between a
 GitHub repository and a SonarQube project`,
-		Create: resourceSonarqubeGithubBindingCreate,
-		Read:   resourceSonarqubeGithubBindingRead,
-		Delete: resourceSonarqubeGithubBindingDelete,
+		CreateContext: resourceSonarqubeGithubBindingCreate,
+		// You can update any project binding with the same API call as the CREATE
+		UpdateContext: resourceSonarqubeGithubBindingCreate,
+		ReadContext:   resourceSonarqubeGithubBindingRead,
+		DeleteContext: resourceSonarqubeGithubBindingDelete,
 		Importer: &schema.ResourceImporter{
-			State: resourceSonarqubeGithubBindingImport,
+			StateContext: resourceSonarqubeGithubBindingImport,
 		},
+		CustomizeDiff: requireCapability(capabilityGithubBindings),
+
+		Timeouts: &schema.ResourceTimeout{
+			Default: schema.DefaultTimeout(5 * time.Minute),
+		},
+
 		// Define the fields of this schema.
 		Schema: map[string]*schema.Schema{
 			"alm_setting": {
 				Type:        schema.TypeString,
 				Required:    true,
-				ForceNew:    true,
 				Description: "GitHub ALM setting key",
 			},
 			"monorepo": {
 				Type:        schema.TypeString,
 				Optional:    true,
 				Default:     "false",
-				ForceNew:    true,
 				Description: "Is this project part of a monorepo. Default value: false",
 			},
 			"project": {
 				Type:        schema.TypeString,
 				Required:    true,
 				ForceNew:    true,
-				Description: "Project key",
+				Description: "SonarQube project key. Changing this will force a new resource to be created",
 			},
 			"repository": {
 				Type:        schema.TypeString,
 				Required:    true,
-				ForceNew:    true,
 				Description: "The full name of your GitHub repository, including the organization, case-sensitive. Maximum length: 256",
 			},
 			"summary_comment_enabled": {
 				Type:        schema.TypeString,
 				Optional:    true,
 				Default:     "true",
-				ForceNew:    true,
 				Description: "Enable/disable summary in PR discussion tab. Default value: true",
 			},
 		},
 	}
 }
 
-func checkGithubBindingSupport(conf *ProviderConfiguration) error {
-	if strings.ToLower(conf.sonarQubeEdition) == "community" {
-		return fmt.Errorf("GitHub Bindings are not supported in the Community edition of SonarQube. You are using: SonaQube %s version %s", conf.sonarQubeEdition, conf.sonarQubeVersion)
+func resourceSonarqubeGithubBindingCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if err := m.(*ProviderConfiguration).checkCapability(capabilityGithubBindings); err != nil {
+		return diag.FromErr(err)
 	}
-	return nil
-}
 
-func resourceSonarqubeGithubBindingCreate(d *schema.ResourceData, m interface{}) error {
-	if err := checkGithubBindingSupport(m.(*ProviderConfiguration)); err != nil {
-		return err
+	monorepo, err := strconv.ParseBool(d.Get("monorepo").(string))
+	if err != nil {
+		return diag.Errorf("resourceSonarqubeGithubBindingCreate: Failed to parse monorepo: %+v", err)
+	}
+	summaryCommentEnabled, err := strconv.ParseBool(d.Get("summary_comment_enabled").(string))
+	if err != nil {
+		return diag.Errorf("resourceSonarqubeGithubBindingCreate: Failed to parse summary_comment_enabled: %+v", err)
 	}
 
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/alm_settings/set_github_binding"
-
-	sonarQubeURL.RawQuery = url.Values{
-		"almSetting":            []string{d.Get("alm_setting").(string)},
-		"monorepo":              []string{d.Get("monorepo").(string)},
-		"project":               []string{d.Get("project").(string)},
-		"repository":            []string{d.Get("repository").(string)},
-		"summaryCommentEnabled": []string{d.Get("summary_comment_enabled").(string)},
-	}.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusNoContent,
-		"resourceSonarqubeGithubBindingCreate",
-	)
+	request := client.GithubBindingRequest{
+		AlmSetting:            d.Get("alm_setting").(string),
+		Monorepo:              monorepo,
+		Project:               d.Get("project").(string),
+		Repository:            d.Get("repository").(string),
+		SummaryCommentEnabled: summaryCommentEnabled,
+	}
+	err = m.(*ProviderConfiguration).client.AlmSettings.SetGithubBinding(ctx, request)
 	if err != nil {
-		return err
+		return diag.Errorf("resourceSonarqubeGithubBindingCreate: Failed to create github binding: %+v", err)
 	}
-	defer resp.Body.Close()
 
 	id := fmt.Sprintf("%v/%v", d.Get("project").(string), d.Get("repository").(string))
 	d.SetId(id)
 
-	return resourceSonarqubeGithubBindingRead(d, m)
+	return resourceSonarqubeGithubBindingRead(ctx, d, m)
 }
 
-func resourceSonarqubeGithubBindingRead(d *schema.ResourceData, m interface{}) error {
-	if err := checkGithubBindingSupport(m.(*ProviderConfiguration)); err != nil {
-		return err
+func resourceSonarqubeGithubBindingRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if err := m.(*ProviderConfiguration).checkCapability(capabilityGithubBindings); err != nil {
+		return diag.FromErr(err)
 	}
 
 	idSlice := strings.SplitN(d.Id(), "/", 2)
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/alm_settings/get_binding"
-	sonarQubeURL.RawQuery = url.Values{
-		"project": []string{idSlice[0]},
-	}.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"GET",
-		sonarQubeURL.String(),
-		http.StatusOK,
-		"resourceSonarqubeGithubBindingRead",
-	)
+	binding, err := m.(*ProviderConfiguration).client.AlmSettings.GetBinding(ctx, idSlice[0])
 	if err != nil {
-		return err
+		if client.IsNotFound(err) {
+			removeResourceFromState(d, "resourceSonarqubeGithubBindingRead")
+			return nil
+		}
+		return diag.Errorf("resourceSonarqubeGithubBindingRead: Failed to read github binding: %+v", err)
 	}
-	defer resp.Body.Close()
 
-	// Decode response into struct
-	BindingReadResponse := GetBinding{}
-	err = json.NewDecoder(resp.Body).Decode(&BindingReadResponse)
-	if err != nil {
-		return fmt.Errorf("resourceSonarqubeGithubBindingRead: Failed to decode json into struct: %+v", err)
-	}
-	// Loop over all branches to see if the main branch we need exists.
-	if idSlice[1] == BindingReadResponse.Repository && BindingReadResponse.Alm == "github" {
+	if idSlice[1] == binding.Repository && binding.Alm == "github" {
 		d.Set("project", idSlice[0])
 		d.Set("repository", idSlice[1])
-		d.Set("alm_setting", BindingReadResponse.Key)
-		d.Set("monorepo", strconv.FormatBool(BindingReadResponse.Monorepo))
-		d.Set("summary_comment_enabled", strconv.FormatBool(BindingReadResponse.SummaryCommentEnabled))
+		d.Set("alm_setting", binding.Key)
+		d.Set("monorepo", strconv.FormatBool(binding.Monorepo))
+		d.Set("summary_comment_enabled", strconv.FormatBool(binding.SummaryCommentEnabled))
 
 		return nil
 	}
-	return fmt.Errorf("resourceSonarqubeGithubBindingRead: Failed to find github binding: %+v", d.Id())
+	removeResourceFromState(d, "resourceSonarqubeGithubBindingRead")
+	return nil
 }
 
-func resourceSonarqubeGithubBindingDelete(d *schema.ResourceData, m interface{}) error {
-	if err := checkGithubBindingSupport(m.(*ProviderConfiguration)); err != nil {
-		return err
+func resourceSonarqubeGithubBindingDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if err := m.(*ProviderConfiguration).checkCapability(capabilityGithubBindings); err != nil {
+		return diag.FromErr(err)
 	}
 
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/alm_settings/delete_binding"
-	sonarQubeURL.RawQuery = url.Values{
-		"project": []string{d.Get("project").(string)},
-	}.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusNoContent,
-		"resourceSonarqubeGithubBindingDelete",
-	)
+	err := m.(*ProviderConfiguration).client.AlmSettings.DeleteBinding(ctx, d.Get("project").(string))
 	if err != nil {
-		return err
+		return diag.Errorf("resourceSonarqubeGithubBindingDelete: Failed to delete github binding: %+v", err)
 	}
-	defer resp.Body.Close()
 
 	return nil
 }
 
-func resourceSonarqubeGithubBindingImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
-	if err := resourceSonarqubeGithubBindingRead(d, m); err != nil {
-		return nil, err
+func resourceSonarqubeGithubBindingImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	if diags := resourceSonarqubeGithubBindingRead(ctx, d, m); diags.HasError() {
+		return nil, diagnosticsToError(diags)
 	}
 	return []*schema.ResourceData{d}, nil
 }
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
 )
 
 func init() {
@@ -19,9 +20,7 @@ func testSweepSonarqubeGithubBinding(r string) error {
 	return nil
 }
 func testAccPreCheckGithubBindingSupport(t *testing.T) {
-	if err := checkGithubBindingSupport(testAccProvider.Meta().(*ProviderConfiguration)); err != nil {
-		t.Skipf("Skipping test of unsupported feature (GitHub Binding)")
-	}
+	testAccPreCheckCapability(t, capabilityGithubBindings)
 }
 
 func testAccSonarqubeGithubBindingName(rnd string, projName string, almSetting string, repoName string) string {
@@ -57,8 +56,8 @@ func TestAccSonarqubeGithubBindingName(t *testing.T) {
 	name := "sonarqube_github_binding." + rnd
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t); testAccPreCheckGithubBindingSupport(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t); testAccPreCheckGithubBindingSupport(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubeGithubBindingName(rnd, "testAccSonarqubeGithubBindingName", "github", "testAccSonarqubeGithubBindingName"),
@@ -118,3 +117,75 @@ func TestAccSonarqubeGithubBindingName(t *testing.T) {
 		},
 	})
 }
+
+func testAccSonarqubeGithubBindingUpdate(rnd string, repoName string, monorepo string, summaryCommentEnabled string) string {
+	return fmt.Sprintf(`
+		resource "sonarqube_alm_github" "%[1]s" {
+			app_id        = "12345"
+			client_id     = "56789"
+			client_secret = "secret"
+			key           = "%[1]s"
+			private_key   = "myprivate_key"
+			url           = "https://api.github.com"
+		}
+
+		resource "sonarqube_project" "%[1]s" {
+			name       = "%[1]s"
+			project    = "%[1]s"
+			visibility = "public"
+		}
+
+		resource "sonarqube_github_binding" "%[1]s" {
+			alm_setting             = sonarqube_alm_github.%[1]s.key
+			monorepo                = "%[3]s"
+			project                 = sonarqube_project.%[1]s.project
+			repository              = "%[2]s"
+			summary_comment_enabled = "%[4]s"
+		}`, rnd, repoName, monorepo, summaryCommentEnabled)
+}
+
+func TestAccSonarqubeGithubBindingUpdate(t *testing.T) {
+	rnd := generateRandomResourceName()
+	name := "sonarqube_github_binding." + rnd
+
+	// Every change but the project updates the binding in place
+	inPlace := resource.ConfigPlanChecks{
+		PreApply: []plancheck.PlanCheck{
+			plancheck.ExpectResourceAction(name, plancheck.ResourceActionUpdate),
+		},
+	}
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t); testAccPreCheckGithubBindingSupport(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSonarqubeGithubBindingUpdate(rnd, "org/repo", "false", "true"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(name, "monorepo", "false"),
+					resource.TestCheckResourceAttr(name, "summary_comment_enabled", "true"),
+				),
+			},
+			{
+				Config:           testAccSonarqubeGithubBindingUpdate(rnd, "org/repo", "true", "false"),
+				ConfigPlanChecks: inPlace,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(name, "monorepo", "true"),
+					resource.TestCheckResourceAttr(name, "summary_comment_enabled", "false"),
+				),
+			},
+			{
+				Config:           testAccSonarqubeGithubBindingUpdate(rnd, "org/other-repo", "true", "false"),
+				ConfigPlanChecks: inPlace,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(name, "id", rnd+"/org/other-repo"),
+					resource.TestCheckResourceAttr(name, "repository", "org/other-repo"),
+				),
+			},
+			{
+				ResourceName:      name,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
@@ -1,14 +1,15 @@
 package sonarqube
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"net/http"
-	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/jdamata/terraform-provider-sonarqube/internal/client"
 )
 
 // Returns the resource represented by this file.
@@ -16,14 +17,20 @@ func resourceSonarqubeGitlabBinding() *schema.Resource {
 	return &schema.Resource{
 		Description: `Provides a Sonarqube GitLab binding resource. This can be used to create and manage the binding between a
 GitLab repository and a SonarQube project`,
-		Create: resourceSonarqubeGitlabBindingCreate,
+		CreateContext: resourceSonarqubeGitlabBindingCreate,
 		// You can update any project binding with the same API call as the CREATE
-		Update: resourceSonarqubeGitlabBindingCreate,
-		Read:   resourceSonarqubeGitlabBindingRead,
-		Delete: resourceSonarqubeGitlabBindingDelete,
+		UpdateContext: resourceSonarqubeGitlabBindingCreate,
+		ReadContext:   resourceSonarqubeGitlabBindingRead,
+		DeleteContext: resourceSonarqubeGitlabBindingDelete,
 		Importer: &schema.ResourceImporter{
-			State: resourceSonarqubeGitlabBindingImport,
+			StateContext: resourceSonarqubeGitlabBindingImport,
 		},
+		CustomizeDiff: requireCapability(capabilityGitlabBindings),
+
+		Timeouts: &schema.ResourceTimeout{
+			Default: schema.DefaultTimeout(5 * time.Minute),
+		},
+
 		// Define the fields of this schema.
 		Schema: map[string]*schema.Schema{
 			"alm_setting": {
@@ -52,117 +59,76 @@ GitLab repository and a SonarQube project`,
 	}
 }
 
-func checkGitlabBindingSupport(conf *ProviderConfiguration) error {
-	if strings.ToLower(conf.sonarQubeEdition) == "community" {
-		return fmt.Errorf("GitLab Bindings are not supported in the Community edition of SonarQube. You are using: SonarQube %s version %s", conf.sonarQubeEdition, conf.sonarQubeVersion)
+func resourceSonarqubeGitlabBindingCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if err := m.(*ProviderConfiguration).checkCapability(capabilityGitlabBindings); err != nil {
+		return diag.FromErr(err)
 	}
-	return nil
-}
 
-func resourceSonarqubeGitlabBindingCreate(d *schema.ResourceData, m interface{}) error {
-	if err := checkGitlabBindingSupport(m.(*ProviderConfiguration)); err != nil {
-		return err
+	monorepo, err := strconv.ParseBool(d.Get("monorepo").(string))
+	if err != nil {
+		return diag.Errorf("resourceSonarqubeGitlabBindingCreate: Failed to parse monorepo: %+v", err)
 	}
 
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/alm_settings/set_gitlab_binding"
-
-	sonarQubeURL.RawQuery = url.Values{
-		"almSetting": []string{d.Get("alm_setting").(string)},
-		"monorepo":   []string{d.Get("monorepo").(string)},
-		"project":    []string{d.Get("project").(string)},
-		"repository": []string{d.Get("repository").(string)},
-	}.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusNoContent,
-		"resourceSonarqubeGitlabBindingCreate",
-	)
+	request := client.GitlabBindingRequest{
+		AlmSetting: d.Get("alm_setting").(string),
+		Monorepo:   monorepo,
+		Project:    d.Get("project").(string),
+		Repository: d.Get("repository").(string),
+	}
+	err = m.(*ProviderConfiguration).client.AlmSettings.SetGitlabBinding(ctx, request)
 	if err != nil {
-		return err
+		return diag.Errorf("resourceSonarqubeGitlabBindingCreate: Failed to create gitlab binding: %+v", err)
 	}
-	defer resp.Body.Close()
 
 	id := fmt.Sprintf("%v/%v", d.Get("project").(string), d.Get("repository").(string))
 	d.SetId(id)
 
-	return resourceSonarqubeGitlabBindingRead(d, m)
+	return resourceSonarqubeGitlabBindingRead(ctx, d, m)
 }
 
-func resourceSonarqubeGitlabBindingRead(d *schema.ResourceData, m interface{}) error {
-	if err := checkGitlabBindingSupport(m.(*ProviderConfiguration)); err != nil {
-		return err
+func resourceSonarqubeGitlabBindingRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if err := m.(*ProviderConfiguration).checkCapability(capabilityGitlabBindings); err != nil {
+		return diag.FromErr(err)
 	}
 
 	idSlice := strings.SplitN(d.Id(), "/", 2)
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/alm_settings/get_binding"
-	sonarQubeURL.RawQuery = url.Values{
-		"project": []string{idSlice[0]},
-	}.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"GET",
-		sonarQubeURL.String(),
-		http.StatusOK,
-		"resourceSonarqubeGitlabBindingRead",
-	)
+	binding, err := m.(*ProviderConfiguration).client.AlmSettings.GetBinding(ctx, idSlice[0])
 	if err != nil {
-		return err
+		if client.IsNotFound(err) {
+			removeResourceFromState(d, "resourceSonarqubeGitlabBindingRead")
+			return nil
+		}
+		return diag.Errorf("resourceSonarqubeGitlabBindingRead: Failed to read gitlab binding: %+v", err)
 	}
-	defer resp.Body.Close()
 
-	// Decode response into struct
-	BindingReadResponse := GetBinding{}
-	err = json.NewDecoder(resp.Body).Decode(&BindingReadResponse)
-	if err != nil {
-		return fmt.Errorf("resourceSonarqubeGitlabBindingRead: Failed to decode json into struct: %+v", err)
-	}
-	// Loop over all branches to see if the main branch we need exists.
-	if idSlice[1] == BindingReadResponse.Repository && BindingReadResponse.Alm == "gitlab" {
+	if idSlice[1] == binding.Repository && binding.Alm == "gitlab" {
 		d.Set("project", idSlice[0])
 		d.Set("repository", idSlice[1])
-		d.Set("alm_setting", BindingReadResponse.Key)
-		d.Set("monorepo", strconv.FormatBool(BindingReadResponse.Monorepo))
+		d.Set("alm_setting", binding.Key)
+		d.Set("monorepo", strconv.FormatBool(binding.Monorepo))
 
 		return nil
 	}
-	return fmt.Errorf("resourceSonarqubeGitlabBindingRead: Failed to find gitlab binding: %+v", d.Id())
+	removeResourceFromState(d, "resourceSonarqubeGitlabBindingRead")
+	return nil
 }
 
-func resourceSonarqubeGitlabBindingDelete(d *schema.ResourceData, m interface{}) error {
-	if err := checkGitlabBindingSupport(m.(*ProviderConfiguration)); err != nil {
-		return err
+func resourceSonarqubeGitlabBindingDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if err := m.(*ProviderConfiguration).checkCapability(capabilityGitlabBindings); err != nil {
+		return diag.FromErr(err)
 	}
 
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/alm_settings/delete_binding"
-	sonarQubeURL.RawQuery = url.Values{
-		"project": []string{d.Get("project").(string)},
-	}.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusNoContent,
-		"resourceSonarqubeGitlabBindingDelete",
-	)
+	err := m.(*ProviderConfiguration).client.AlmSettings.DeleteBinding(ctx, d.Get("project").(string))
 	if err != nil {
-		return err
+		return diag.Errorf("resourceSonarqubeGitlabBindingDelete: Failed to delete gitlab binding: %+v", err)
 	}
-	defer resp.Body.Close()
 
 	return nil
 }
 
-func resourceSonarqubeGitlabBindingImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
-	if err := resourceSonarqubeGitlabBindingRead(d, m); err != nil {
-		return nil, err
+func resourceSonarqubeGitlabBindingImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	if diags := resourceSonarqubeGitlabBindingRead(ctx, d, m); diags.HasError() {
+		return nil, diagnosticsToError(diags)
 	}
 	return []*schema.ResourceData{d}, nil
 }
@@ -19,9 +19,7 @@ func testSweepSonarqubeGitlabBinding(r string) error {
 	return nil
 }
 func testAccPreCheckGitlabBindingSupport(t *testing.T) {
-	if err := checkGitlabBindingSupport(testAccProvider.Meta().(*ProviderConfiguration)); err != nil {
-		t.Skipf("Skipping test of unsupported feature (Gitlab Binding)")
-	}
+	testAccPreCheckCapability(t, capabilityGitlabBindings)
 }
 
 func testAccSonarqubeGitlabBindingName(rnd string, projName string, almSetting string, repoName string) string {
@@ -53,8 +51,8 @@ func TestAccSonarqubeGitlabBindingName(t *testing.T) {
 	name := "sonarqube_gitlab_binding." + rnd
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t); testAccPreCheckGitlabBindingSupport(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t); testAccPreCheckGitlabBindingSupport(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubeGitlabBindingName(rnd, "testAccSonarqubeGitlabBindingName", "gitlab", "testAccSonarqubeGitlabBindingName"),
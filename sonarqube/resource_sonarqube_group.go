@@ -1,47 +1,27 @@
 package sonarqube
 
 import (
-	"encoding/json"
-	"fmt"
-	"net/http"
-	"net/url"
-	"strings"
+	"context"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/jdamata/terraform-provider-sonarqube/internal/client"
 )
 
-// GetGroup for unmarshalling response body from getting group details
-type GetGroup struct {
-	Paging Paging  `json:"paging"`
-	Groups []Group `json:"groups"`
-}
-
-// CreateGroupResponse for unmarshalling response body of group creation
-type CreateGroupResponse struct {
-	Group Group `json:"group"`
-}
-
-// Group struct
-type Group struct {
-	ID           string   `json:"id,omitempty"`
-	Organization string   `json:"organization,omitempty"`
-	Name         string   `json:"name,omitempty"`
-	Description  string   `json:"description,omitempty"`
-	MembersCount int      `json:"membersCount,omitempty"`
-	IsDefault    bool     `json:"default,omitempty"`
-	Permissions  []string `json:"permissions,omitempty"`
-}
-
 // Returns the resource represented by this file.
 func resourceSonarqubeGroup() *schema.Resource {
 	return &schema.Resource{
-		Description: "Provides a Sonarqube Group resource. This can be used to create and manage Sonarqube Groups.",
-		Create:      resourceSonarqubeGroupCreate,
-		Read:        resourceSonarqubeGroupRead,
-		Update:      resourceSonarqubeGroupUpdate,
-		Delete:      resourceSonarqubeGroupDelete,
+		Description:   "Provides a Sonarqube Group resource. This can be used to create and manage Sonarqube Groups.",
+		CreateContext: resourceSonarqubeGroupCreate,
+		ReadContext:   resourceSonarqubeGroupRead,
+		UpdateContext: resourceSonarqubeGroupUpdate,
+		DeleteContext: resourceSonarqubeGroupDelete,
 		Importer: &schema.ResourceImporter{
-			State: resourceSonarqubeGroupImport,
+			StateContext: resourceSonarqubeGroupImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Default: schema.DefaultTimeout(5 * time.Minute),
 		},
 
 		// Define the fields of this schema.
@@ -60,155 +40,83 @@ func resourceSonarqubeGroup() *schema.Resource {
 	}
 }
 
-func resourceSonarqubeGroupCreate(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/user_groups/create"
-	sonarQubeURL.RawQuery = url.Values{
-		"name":        []string{d.Get("name").(string)},
-		"description": []string{d.Get("description").(string)},
-	}.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusOK,
-		"resourceSonarqubeGroupCreate",
+func resourceSonarqubeGroupCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	group, err := m.(*ProviderConfiguration).client.UserGroups.Create(
+		ctx,
+		d.Get("name").(string),
+		d.Get("description").(string),
 	)
 	if err != nil {
-		return fmt.Errorf("error creating Sonarqube group: %+v", err)
+		return diag.Errorf("error creating Sonarqube group: %+v", err)
 	}
-	defer resp.Body.Close()
+	d.SetId(group.ID)
 
-	// Decode response into struct
-	groupResponse := CreateGroupResponse{}
-	err = json.NewDecoder(resp.Body).Decode(&groupResponse)
-	if err != nil {
-		return fmt.Errorf("resourceSonarqubeGroupRead: Failed to decode json into struct: %+v", err)
-	}
-	d.SetId(groupResponse.Group.ID)
-
-	return resourceSonarqubeGroupRead(d, m)
+	return resourceSonarqubeGroupRead(ctx, d, m)
 }
 
-func resourceSonarqubeGroupRead(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/user_groups/search"
-	sonarQubeURL.RawQuery = url.Values{
-		"ps": []string{"500"},
-		"q":  []string{d.Get("name").(string)},
-	}.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"GET",
-		sonarQubeURL.String(),
-		http.StatusOK,
-		"resourceSonarqubeGroupRead",
-	)
-	if err != nil {
-		return fmt.Errorf("error reading Sonarqube group: %+v", err)
-	}
-	defer resp.Body.Close()
-
-	readSuccess := false
-	// Decode response into struct
-	groupReadResponse := GetGroup{}
-	err = json.NewDecoder(resp.Body).Decode(&groupReadResponse)
-	if err != nil {
-		return fmt.Errorf("resourceSonarqubeGroupRead: Failed to decode json into struct: %+v", err)
-	}
-
+func resourceSonarqubeGroupRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	groupName := d.Get("name").(string)
+	request := client.UserGroupsSearchRequest{
+		Query: groupName,
+	}
 
-	// Loop over all groups to see if the group we need exists.
-	for _, value := range groupReadResponse.Groups {
+	// Walk the search results to see if the group we need exists.
+	group, err := m.(*ProviderConfiguration).client.UserGroups.SearchPages(request).Find(ctx, func(value client.Group) bool {
 		// no ID in the group search response from sonarqube 10.0+,
 		// here is to make comparison compatible with sonarqube 9.9 and 10+
-		if (d.Id() != "" && d.Id() == value.ID) || groupName == value.Name {
-			if value.ID != "" {
-				d.SetId(value.ID)
-			}
-			// If it does, set the values of that group
-			d.Set("name", value.Name)
-			d.Set("description", value.Description)
-			readSuccess = true
-			break
-		}
+		return (d.Id() != "" && d.Id() == value.ID) || groupName == value.Name
+	})
+	if err != nil {
+		return diag.Errorf("error reading Sonarqube group: %+v", err)
 	}
 
-	if !readSuccess {
+	if group == nil {
 		// Group not found
-		if _, ok := d.GetOk("id"); ok {
-			d.SetId("")
-		}
+		removeResourceFromState(d, "resourceSonarqubeGroupRead")
+		return nil
 	}
 
+	if group.ID != "" {
+		d.SetId(group.ID)
+	}
+	// If it does, set the values of that group
+	d.Set("name", group.Name)
+	d.Set("description", group.Description)
+
 	return nil
 }
 
-func resourceSonarqubeGroupUpdate(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/user_groups/update"
-
+func resourceSonarqubeGroupUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	oldName, newName := d.GetChange("name")
-	rawQuery := url.Values{
-		"currentName": []string{oldName.(string)},
+	request := client.UserGroupsUpdateRequest{
+		CurrentName: oldName.(string),
+		Description: d.Get("description").(string),
 	}
 
 	if newName != oldName {
-		rawQuery.Add("name", newName.(string))
+		request.Name = newName.(string)
 	}
 
-	if _, ok := d.GetOk("description"); ok {
-		rawQuery.Add("description", d.Get("description").(string))
-	} else {
-		rawQuery.Add("description", "")
-	}
-
-	sonarQubeURL.RawQuery = rawQuery.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusOK,
-		"resourceSonarqubeGroupUpdate",
-	)
+	err := m.(*ProviderConfiguration).client.UserGroups.Update(ctx, request)
 	if err != nil {
-		return fmt.Errorf("error updating Sonarqube group: %+v", err)
+		return diag.Errorf("error updating Sonarqube group: %+v", err)
 	}
-	defer resp.Body.Close()
 
-	return resourceSonarqubeGroupRead(d, m)
+	return resourceSonarqubeGroupRead(ctx, d, m)
 }
 
-func resourceSonarqubeGroupDelete(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/user_groups/delete"
-
-	sonarQubeURL.RawQuery = url.Values{
-		"name": []string{d.Get("name").(string)},
-	}.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusNoContent,
-		"resourceSonarqubeGroupDelete",
-	)
+func resourceSonarqubeGroupDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	err := m.(*ProviderConfiguration).client.UserGroups.Delete(ctx, d.Get("name").(string))
 	if err != nil {
-		return fmt.Errorf("error deleting Sonarqube group: %+v", err)
+		return diag.Errorf("error deleting Sonarqube group: %+v", err)
 	}
-	defer resp.Body.Close()
 
 	return nil
 }
 
-func resourceSonarqubeGroupImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
-	if err := resourceSonarqubeGroupRead(d, m); err != nil {
-		return nil, err
+func resourceSonarqubeGroupImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	if diags := resourceSonarqubeGroupRead(ctx, d, m); diags.HasError() {
+		return nil, diagnosticsToError(diags)
 	}
 	return []*schema.ResourceData{d}, nil
 }
@@ -1,37 +1,28 @@
 package sonarqube
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"net/http"
-	"net/url"
 	"regexp"
-	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/jdamata/terraform-provider-sonarqube/internal/client"
 )
 
-// GroupMember struct
-type GroupMember struct {
-	LoginName string `json:"login,omitempty"`
-	Name      string `json:"name,omitempty"`
-}
-
-// GetGroupMembersResponse for unmarshalling response body of group creation
-type GetGroupMembersResponse struct {
-	Paging  Paging        `json:"paging"`
-	Members []GroupMember `json:"users"`
-}
-
 // Returns the resource represented by this file.
 func resourceSonarqubeGroupMember() *schema.Resource {
 	return &schema.Resource{
-		Description: "Provides a Sonarqube Group Member resource. This can be used to add or remove user to or from Sonarqube Groups.",
-		Create:      resourceSonarqubeGroupMemberCreate,
-		Read:        resourceSonarqubeGroupMemberRead,
-		Delete:      resourceSonarqubeGroupMemberDelete,
+		Description:   "Provides a Sonarqube Group Member resource. This can be used to add or remove user to or from Sonarqube Groups.",
+		CreateContext: resourceSonarqubeGroupMemberCreate,
+		ReadContext:   resourceSonarqubeGroupMemberRead,
+		DeleteContext: resourceSonarqubeGroupMemberDelete,
 		Importer: &schema.ResourceImporter{
-			State: resourceSonarqubeGroupMemberImport,
+			StateContext: resourceSonarqubeGroupMemberImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Default: schema.DefaultTimeout(5 * time.Minute),
 		},
 
 		// Define the fields of this schema.
@@ -52,117 +43,62 @@ func resourceSonarqubeGroupMember() *schema.Resource {
 	}
 }
 
-func resourceSonarqubeGroupMemberCreate(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/user_groups/add_user"
-	sonarQubeURL.RawQuery = url.Values{
-		"name":  []string{d.Get("name").(string)},
-		"login": []string{d.Get("login_name").(string)},
-	}.Encode()
-
+func resourceSonarqubeGroupMemberCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	groupMembershipId := createGroupMembershipId(d.Get("name").(string), d.Get("login_name").(string))
 
 	// We need to check if a user is already a member in advance because SQ does not report this conflict in the add_user API call:
-	exists, _ := checkGroupMemberExists(d.Get("name").(string), d.Get("login_name").(string), m)
+	exists, _ := checkGroupMemberExists(ctx, d.Get("name").(string), d.Get("login_name").(string), m)
 	if exists {
-		return fmt.Errorf("resourceSonarqubeGroupMemberCreate: Group membership already exists: %+v", groupMembershipId)
+		return diag.Errorf("resourceSonarqubeGroupMemberCreate: Group membership already exists: %+v", groupMembershipId)
 	}
 
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusNoContent,
-		"resourceSonarqubeGroupMemberCreate",
-	)
+	err := m.(*ProviderConfiguration).client.UserGroups.AddUser(ctx, d.Get("name").(string), d.Get("login_name").(string))
 	if err != nil {
-		return fmt.Errorf("error adding user '%s' to Sonarqube group '%s': %w", d.Get("login_name").(string), d.Get("name").(string), err)
+		return diag.Errorf("error adding user '%s' to Sonarqube group '%s': %+v", d.Get("login_name").(string), d.Get("name").(string), err)
 	}
-	defer resp.Body.Close()
 
 	d.SetId(groupMembershipId)
 
-	return resourceSonarqubeGroupMemberRead(d, m)
+	return resourceSonarqubeGroupMemberRead(ctx, d, m)
 }
 
-func resourceSonarqubeGroupMemberRead(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/user_groups/users"
-	sonarQubeURL.RawQuery = url.Values{
-		"name": []string{d.Get("name").(string)},
-		"q":    []string{d.Get("login_name").(string)},
-	}.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"GET",
-		sonarQubeURL.String(),
-		http.StatusOK,
-		"resourceSonarqubeGroupMemberRead",
-	)
+func resourceSonarqubeGroupMemberRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	exists, err := checkGroupMemberExists(ctx, d.Get("name").(string), d.Get("login_name").(string), m)
 	if err != nil {
-		return fmt.Errorf("error reading Sonarqube members of group '%s': %w", d.Get("name").(string), err)
-	}
-	defer resp.Body.Close()
-
-	readSuccess := false
-	// Decode response into struct
-	groupMemberReadResponse := GetGroupMembersResponse{}
-	err = json.NewDecoder(resp.Body).Decode(&groupMemberReadResponse)
-	if err != nil {
-		return fmt.Errorf("resourceSonarqubeGroupRead: Failed to decode json into struct: %+v", err)
-	}
-	// Loop over all returned members to see if the member we need exists.
-	for _, value := range groupMemberReadResponse.Members {
-		if d.Get("login_name").(string) == value.LoginName {
-			// If it does, set the values of that group membership
-			d.SetId(createGroupMembershipId(d.Get("name").(string), d.Get("login_name").(string)))
-			d.Set("name", d.Get("name").(string))
-			d.Set("login_name", value.LoginName)
-			readSuccess = true
-			break
+		if client.IsNotFound(err) {
+			removeResourceFromState(d, "resourceSonarqubeGroupMemberRead")
+			return nil
 		}
+		return diag.FromErr(err)
 	}
 
-	if !readSuccess {
+	if !exists {
 		// Group member not found
-		d.SetId("")
+		removeResourceFromState(d, "resourceSonarqubeGroupMemberRead")
+		return nil
 	}
 
+	// If it does, set the values of that group membership
+	d.SetId(createGroupMembershipId(d.Get("name").(string), d.Get("login_name").(string)))
 	return nil
 }
 
-func resourceSonarqubeGroupMemberDelete(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/user_groups/remove_user"
-
-	sonarQubeURL.RawQuery = url.Values{
-		"name":  []string{d.Get("name").(string)},
-		"login": []string{d.Get("login_name").(string)},
-	}.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusNoContent,
-		"resourceSonarqubeGroupMemberDelete",
-	)
+func resourceSonarqubeGroupMemberDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	err := m.(*ProviderConfiguration).client.UserGroups.RemoveUser(ctx, d.Get("name").(string), d.Get("login_name").(string))
 	if err != nil {
-		return fmt.Errorf("error deleting Sonarqube member '%s' from group '%s': %w", d.Get("login_name").(string), d.Get("name").(string), err)
+		return diag.Errorf("error deleting Sonarqube member '%s' from group '%s': %+v", d.Get("login_name").(string), d.Get("name").(string), err)
 	}
-	defer resp.Body.Close()
 
 	return nil
 }
 
-func resourceSonarqubeGroupMemberImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+func resourceSonarqubeGroupMemberImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
 	rgx := regexp.MustCompile(`(.*?)\[(.*?)\]`)
 	rs := rgx.FindStringSubmatch(d.Id())
 	groupName := rs[1]
 	loginName := rs[2]
 
-	exists, _ := checkGroupMemberExists(groupName, loginName, m)
+	exists, _ := checkGroupMemberExists(ctx, groupName, loginName, m)
 	if exists {
 		d.Set("name", groupName)
 		d.Set("login_name", loginName)
@@ -173,40 +109,21 @@ func resourceSonarqubeGroupMemberImport(d *schema.ResourceData, m interface{}) (
 	}
 }
 
-func checkGroupMemberExists(groupName string, loginName string, m interface{}) (bool, error) {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/user_groups/users"
-	sonarQubeURL.RawQuery = url.Values{
-		"name": []string{groupName},
-		"q":    []string{loginName},
-	}.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"GET",
-		sonarQubeURL.String(),
-		http.StatusOK,
-		"checkGroupMemberExists",
-	)
-	if err != nil {
-		return false, fmt.Errorf("error reading Sonarqube members of group '%s': %w", groupName, err)
+func checkGroupMemberExists(ctx context.Context, groupName string, loginName string, m interface{}) (bool, error) {
+	request := client.UserGroupsUsersRequest{
+		Name:  groupName,
+		Query: loginName,
 	}
-	defer resp.Body.Close()
 
-	// Decode response into struct
-	groupMemberReadResponse := GetGroupMembersResponse{}
-	err = json.NewDecoder(resp.Body).Decode(&groupMemberReadResponse)
+	// Walk all returned members to see if the member we need exists.
+	member, err := m.(*ProviderConfiguration).client.UserGroups.UsersPages(request).Find(ctx, func(value client.GroupMember) bool {
+		return loginName == value.Login
+	})
 	if err != nil {
-		return false, fmt.Errorf("checkGroupMemberExists: Failed to decode json into struct: %w", err)
-	}
-	// Loop over all returned members to see if the member we need exists.
-	for _, value := range groupMemberReadResponse.Members {
-		if loginName == value.LoginName {
-			return true, nil
-		}
+		return false, fmt.Errorf("error reading Sonarqube members of group '%s': %w", groupName, err)
 	}
 
-	return false, nil
+	return member != nil, nil
 }
 
 func createGroupMembershipId(groupName string, loginName string) string {
@@ -44,8 +44,8 @@ func TestAccSonarqubeGroupMemberBasic(t *testing.T) {
 	name := "sonarqube_group_member." + rnd
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubeGroupMemberBasicConfig(rnd, "testAccSonarqubeGroup", "testAccSonarqubeUser"),
@@ -35,8 +35,8 @@ func TestAccSonarqubeGroupBasic(t *testing.T) {
 	groupDescription := "testAccSonarqubeDescription" + rnd
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubeGroupBasicConfig(rnd, groupName, groupDescription),
@@ -56,8 +56,8 @@ func TestAccSonarqubeGroupUpdate(t *testing.T) {
 	updatedGroupName := "testAccSonarqubeGroupUpdated" + rnd
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubeGroupBasicConfig(rnd, groupName, "group description"),
@@ -91,8 +91,8 @@ func TestAccSonarqubeGroupImport(t *testing.T) {
 	groupDescription := "testAccSonarqubeGroupDescription" + rnd
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubeGroupBasicConfig(rnd, groupName, groupDescription),
@@ -1,27 +1,18 @@
 package sonarqube
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"net/http"
-	"net/url"
 	"regexp"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/jdamata/terraform-provider-sonarqube/internal/client"
 )
 
-// NewCodePeriods for unmarshalling response body from new_code_periods list definitions.
-type NewCodePeriod struct {
-	Project        string `json:"projectKey"`
-	Branch         string `json:"branchKey"`
-	Type           string `json:"type"`
-	Value          string `json:"value,omitempty"`
-	EffectiveValue string `json:"effectiveValue"`
-	Inherited      bool   `json:"inherited"`
-}
-
 // New Code Period types
 type NewCodePeriodType string
 
@@ -35,11 +26,18 @@ const (
 // Returns the resource represented by this file.
 func resourceSonarqubeNewCodePeriodsBinding() *schema.Resource {
 	return &schema.Resource{
-		Description: "Provides a Sonarqube New Code Periods resource. This can be used to manage Sonarqube New Code Periods.",
-		Create:      resourceSonarqubeNewCodePeriodsCreate,
-		Read:        resourceSonarqubeNewCodePeriodsRead,
-		Update:      resourceSonarqubeNewCodePeriodsCreate,
-		Delete:      resourceSonarqubeNewCodePeriodsDelete,
+		Description:   "Provides a Sonarqube New Code Periods resource. This can be used to manage Sonarqube New Code Periods.",
+		CreateContext: resourceSonarqubeNewCodePeriodsCreate,
+		ReadContext:   resourceSonarqubeNewCodePeriodsRead,
+		UpdateContext: resourceSonarqubeNewCodePeriodsCreate,
+		DeleteContext: resourceSonarqubeNewCodePeriodsDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceSonarqubeNewCodePeriodsImport,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Default: schema.DefaultTimeout(5 * time.Minute),
+		},
 
 		// Define the fields of this schema.
 		Schema: map[string]*schema.Schema{
@@ -71,145 +69,112 @@ func resourceSonarqubeNewCodePeriodsBinding() *schema.Resource {
 	}
 }
 
-func resourceSonarqubeNewCodePeriodsCreate(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/new_code_periods/set"
-
+func resourceSonarqubeNewCodePeriodsCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	periodType := NewCodePeriodType(d.Get("type").(string))
-	rawQuery := url.Values{
-		"type": []string{string(periodType)},
+	request := client.NewCodePeriodsSetRequest{
+		Type: string(periodType),
 	}
 
-	id := "newCodePeriod"
-
 	branch := d.Get("branch").(string)
 	project := d.Get("project").(string)
 	value := d.Get("value").(string)
 
-	if branch != "" {
-		rawQuery.Add("branch", branch)
-		id += "/" + branch
-
-		rawQuery.Add("project", project)
-		id += "/" + project
-	} else if project != "" {
-		rawQuery.Add("project", project)
-		id += "/" + project
-	}
-	if value != "" {
-		rawQuery.Add("value", value)
-	}
+	request.Branch = branch
+	request.Project = project
+	request.Value = value
 
 	if periodType == PreviousVersion {
 		if value != "" {
-			return fmt.Errorf("resourceSonarqubeNewCodePeriodsCreate: 'value' must be unset when the 'type' is %s", periodType)
+			return attributeDiagnostics("value", "resourceSonarqubeNewCodePeriodsCreate: Invalid configuration", fmt.Errorf("'value' must be unset when the 'type' is %s", periodType))
 		}
 	} else if value == "" {
-		return fmt.Errorf("resourceSonarqubeNewCodePeriodsCreate: 'value' must be configured when the 'type' is %s", periodType)
+		return attributeDiagnostics("value", "resourceSonarqubeNewCodePeriodsCreate: Invalid configuration", fmt.Errorf("'value' must be configured when the 'type' is %s", periodType))
 	}
 
 	if periodType == SpecificAnalysis && branch == "" {
-		return fmt.Errorf("resourceSonarqubeNewCodePeriodsCreate: 'branch' must be configured when the 'type' is %s", periodType)
+		return attributeDiagnostics("branch", "resourceSonarqubeNewCodePeriodsCreate: Invalid configuration", fmt.Errorf("'branch' must be configured when the 'type' is %s", periodType))
 	} else if periodType == ReferenceBranch && branch == "" && project == "" {
-		return fmt.Errorf("resourceSonarqubeNewCodePeriodsCreate: both 'branch' and 'project' must be configured when the 'type' is %s", periodType)
+		return attributeDiagnostics("branch", "resourceSonarqubeNewCodePeriodsCreate: Invalid configuration", fmt.Errorf("both 'branch' and 'project' must be configured when the 'type' is %s", periodType))
 	} else if periodType == NumberOfDays && !regexp.MustCompile(`^\d+$`).MatchString(value) {
-		return fmt.Errorf("resourceSonarqubeNewCodePeriodsCreate: 'value' must be a numeric string when the 'type' is %s", periodType)
+		return attributeDiagnostics("value", "resourceSonarqubeNewCodePeriodsCreate: Invalid configuration", fmt.Errorf("'value' must be a numeric string when the 'type' is %s", periodType))
 	}
 
-	sonarQubeURL.RawQuery = rawQuery.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusOK,
-		"resourceSonarqubeNewCodePeriodsCreate",
-	)
+	err := m.(*ProviderConfiguration).client.NewCodePeriods.Set(ctx, request)
 	if err != nil {
-		return err
+		return diag.Errorf("resourceSonarqubeNewCodePeriodsCreate: Failed to set new code period: %+v", err)
 	}
-	defer resp.Body.Close()
 
-	d.SetId(id)
+	d.SetId(newCodePeriodID(project, branch))
 
-	return resourceSonarqubeNewCodePeriodsRead(d, m)
+	return resourceSonarqubeNewCodePeriodsRead(ctx, d, m)
 }
 
-func resourceSonarqubeNewCodePeriodsRead(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/new_code_periods/show"
-
-	rawQuery := url.Values{}
+func resourceSonarqubeNewCodePeriodsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	branch := d.Get("branch").(string)
-	if branch != "" {
-		rawQuery.Add("branch", branch)
-	}
 	project := d.Get("project").(string)
-	if project != "" {
-		rawQuery.Add("project", project)
-	}
-	sonarQubeURL.RawQuery = rawQuery.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"GET",
-		sonarQubeURL.String(),
-		http.StatusOK,
-		"resourceSonarqubeNewCodePeriodsRead",
-	)
+
+	newCodePeriod, err := m.(*ProviderConfiguration).client.NewCodePeriods.Show(ctx, project, branch)
 	if err != nil {
-		return err
+		if client.IsNotFound(err) {
+			removeResourceFromState(d, "resourceSonarqubeNewCodePeriodsRead")
+			return nil
+		}
+		return diag.Errorf("resourceSonarqubeNewCodePeriodsRead: Failed to read new code period: %+v", err)
 	}
-	defer resp.Body.Close()
 
-	// Decode response into struct
-	NewCodePeriodsReadResponse := NewCodePeriod{}
-	err = json.NewDecoder(resp.Body).Decode(&NewCodePeriodsReadResponse)
-	if err != nil {
-		return fmt.Errorf("resourceSonarqubeNewCodePeriodsRead: Failed to decode json into struct: %+v", err)
+	// A project or branch without its own new code period inherits the one of its parent
+	if newCodePeriod.Inherited {
+		removeResourceFromState(d, "resourceSonarqubeNewCodePeriodsRead")
+		return nil
 	}
+
 	// Check that the project and branch match
-	if branch == NewCodePeriodsReadResponse.Branch && project == NewCodePeriodsReadResponse.Project {
-		id := "newCodePeriod"
-		if NewCodePeriodsReadResponse.Branch != "" {
-			id += "/" + NewCodePeriodsReadResponse.Branch
-		}
-		if NewCodePeriodsReadResponse.Project != "" {
-			id += "/" + NewCodePeriodsReadResponse.Project
-		}
-		d.SetId(id)
+	if branch == newCodePeriod.Branch && project == newCodePeriod.Project {
+		d.SetId(newCodePeriodID(newCodePeriod.Project, newCodePeriod.Branch))
+		d.Set("type", newCodePeriod.Type)
+		d.Set("value", newCodePeriod.Value)
 		return nil
 	}
 
-	return fmt.Errorf("resourceSonarqubeNewCodePeriodsRead: Failed to find new code period: %+v", d.Id())
+	return diag.Errorf("resourceSonarqubeNewCodePeriodsRead: Failed to find new code period: %+v", d.Id())
 }
 
-func resourceSonarqubeNewCodePeriodsDelete(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/new_code_periods/unset"
+func resourceSonarqubeNewCodePeriodsImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	// import id in format {project}/{branch} or {project}, the global new code period is imported with the id newCodePeriod
+	if d.Id() != newCodePeriodID("", "") {
+		project, branch, hasBranch := strings.Cut(d.Id(), "/")
+		d.Set("project", project)
+		if hasBranch {
+			d.Set("branch", branch)
+		}
+	}
 
-	rawQuery := url.Values{}
-	branch := d.Get("branch").(string)
-	if branch != "" {
-		rawQuery.Add("branch", branch)
+	if diags := resourceSonarqubeNewCodePeriodsRead(ctx, d, m); diags.HasError() {
+		return nil, diagnosticsToError(diags)
 	}
-	project := d.Get("project").(string)
-	if project != "" {
-		rawQuery.Add("project", project)
+	if d.Id() == "" {
+		return nil, fmt.Errorf("resourceSonarqubeNewCodePeriodsImport: No new code period is set for '%s'", d.Get("project").(string))
 	}
-	sonarQubeURL.RawQuery = rawQuery.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusOK,
-		"resourceSonarqubeNewCodePeriodsDelete",
-	)
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceSonarqubeNewCodePeriodsDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	err := m.(*ProviderConfiguration).client.NewCodePeriods.Unset(ctx, d.Get("project").(string), d.Get("branch").(string))
 	if err != nil {
-		return err
+		return diag.Errorf("resourceSonarqubeNewCodePeriodsDelete: Failed to unset new code period: %+v", err)
 	}
-	defer resp.Body.Close()
 
 	return nil
 }
+
+// newCodePeriodID returns the ID of the new code period of a branch, a project or the global one
+func newCodePeriodID(project string, branch string) string {
+	id := "newCodePeriod"
+	if branch != "" {
+		id += "/" + branch
+	}
+	if project != "" {
+		id += "/" + project
+	}
+	return id
+}
@@ -31,8 +31,8 @@ func TestAccSonarqubeNewCodePeriodsGlobalPreviousVersion(t *testing.T) {
 	name := "sonarqube_new_code_periods." + rnd
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubeNewCodePeriodsGlobalPreviousVersion(rnd),
@@ -57,8 +57,8 @@ func TestAccSonarqubeNewCodePeriodsGlobalNumberOfDays(t *testing.T) {
 	name := "sonarqube_new_code_periods." + rnd
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubeNewCodePeriodsGlobalNumberOfDays(rnd),
@@ -91,8 +91,8 @@ func TestAccSonarqubeNewCodePeriodsBranchPreviousVersion(t *testing.T) {
 	name := "sonarqube_new_code_periods." + rnd
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubeNewCodePeriodsBranchPreviousVersion(rnd),
@@ -127,8 +127,8 @@ func TestAccSonarqubeNewCodePeriodsBranchNumberOfDays(t *testing.T) {
 	name := "sonarqube_new_code_periods." + rnd
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubeNewCodePeriodsBranchNumberOfDays(rnd),
@@ -139,6 +139,12 @@ func TestAccSonarqubeNewCodePeriodsBranchNumberOfDays(t *testing.T) {
 					resource.TestCheckResourceAttr(name, "value", "5"),
 				),
 			},
+			{
+				ResourceName:      name,
+				ImportState:       true,
+				ImportStateId:     rnd + "/main",
+				ImportStateVerify: true,
+			},
 		},
 	})
 }
@@ -166,7 +172,7 @@ func TestAccSonarqubeNewCodePeriodsBranchNumberOfDays(t *testing.T) {
 //
 // 	resource.Test(t, resource.TestCase{
 // 		PreCheck:  func() { testAccPreCheck(t) },
-// 		Providers: testAccProviders,
+// 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 // 		Steps: []resource.TestStep{
 // 			{
 // 				Config: testAccSonarqubeNewCodePeriodsBranchSpecificAnalysis(rnd),
@@ -201,8 +207,8 @@ func TestAccSonarqubeNewCodePeriodsBranchReferenceBranch(t *testing.T) {
 	name := "sonarqube_new_code_periods." + rnd
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubeNewCodePeriodsBranchReferenceBranch(rnd),
@@ -236,8 +242,8 @@ func TestAccSonarqubeNewCodePeriodsProjectPreviousVersion(t *testing.T) {
 	name := "sonarqube_new_code_periods." + rnd
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubeNewCodePeriodsProjectPreviousVersion(rnd),
@@ -270,8 +276,8 @@ func TestAccSonarqubeNewCodePeriodsProjectNumberOfDays(t *testing.T) {
 	name := "sonarqube_new_code_periods." + rnd
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubeNewCodePeriodsProjectNumberOfDays(rnd),
@@ -281,6 +287,12 @@ func TestAccSonarqubeNewCodePeriodsProjectNumberOfDays(t *testing.T) {
 					resource.TestCheckResourceAttr(name, "value", "5"),
 				),
 			},
+			{
+				ResourceName:      name,
+				ImportState:       true,
+				ImportStateId:     rnd,
+				ImportStateVerify: true,
+			},
 		},
 	})
 }
@@ -305,8 +317,8 @@ func TestAccSonarqubeNewCodePeriodsProjectReferenceProject(t *testing.T) {
 	name := "sonarqube_new_code_periods." + rnd
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubeNewCodePeriodsProjectReferenceProject(rnd),
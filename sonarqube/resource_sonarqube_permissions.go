@@ -1,37 +1,30 @@
 package sonarqube
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"net/http"
-	"net/url"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/satori/uuid"
+	"github.com/jdamata/terraform-provider-sonarqube/internal/client"
 )
 
-// GetGroupPermissions struct
-type GetGroupPermissions struct {
-	Paging Paging            `json:"paging"`
-	Groups []GroupPermission `json:"groups"`
-}
-
-// GroupPermission struct
-type GroupPermission struct {
-	Id          string   `json:"id"`
-	Name        string   `json:"name,omitempty"`
-	Description string   `json:"description"`
-	Permissions []string `json:"permissions,omitempty"`
-}
-
 // Returns the resource represented by this file.
 func resourceSonarqubePermissions() *schema.Resource {
 	return &schema.Resource{
-		Description: "Provides a Sonarqube Permissions resource. This can be used to manage global and project permissions.",
-		Create:      resourceSonarqubePermissionsCreate,
-		Read:        resourceSonarqubePermissionsRead,
-		Delete:      resourceSonarqubePermissionsDelete,
+		Description:   "Provides a Sonarqube Permissions resource. This can be used to manage global and project permissions.",
+		CreateContext: resourceSonarqubePermissionsCreate,
+		ReadContext:   resourceSonarqubePermissionsRead,
+		DeleteContext: resourceSonarqubePermissionsDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceSonarqubePermissionsImport,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Default: schema.DefaultTimeout(5 * time.Minute),
+		},
 
 		// Define the fields of this schema.
 		Schema: map[string]*schema.Schema{
@@ -84,261 +77,253 @@ func resourceSonarqubePermissions() *schema.Resource {
 	}
 }
 
-func resourceSonarqubePermissionsCreate(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
+func resourceSonarqubePermissionsCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	permissionsService := m.(*ProviderConfiguration).client.Permissions
 	permissions := expandPermissions(d)
+	request := getPermissionsRequest(d)
 
-	// build the base query
-	RawQuery := url.Values{}
-
-	// if the permissions should be applied to a project
-	// we append the project_key to the request
-	if projectKey, ok := d.GetOk("project_key"); ok {
-		RawQuery.Add("projectKey", projectKey.(string))
-	}
-
-	// we use different API endpoints and request params
-	// based on the target principal type (group or user)
+	// we use different API endpoints based on the target principal type (group or user)
 	// and if its a direct or template permission
+	var addPermission func(context.Context, client.PermissionsRequest) error
+	isTemplate := request.TemplateID != "" || request.TemplateName != ""
 	if _, ok := d.GetOk("login_name"); ok {
-		// user permission
-		RawQuery.Add("login", d.Get("login_name").(string))
-		if templateID, ok := d.GetOk("template_id"); ok {
-			// template user permission
-			sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/permissions/add_user_to_template"
-			RawQuery.Add("templateId", templateID.(string))
-			// name provide instead of id
-		} else if templateName, ok := d.GetOk("template_name"); ok {
-			sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/permissions/add_user_to_template"
-			RawQuery.Add("templateName", templateName.(string))
+		if isTemplate {
+			addPermission = permissionsService.AddUserToTemplate
 		} else {
-			// direct user permission
-			sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/permissions/add_user"
+			addPermission = permissionsService.AddUser
 		}
 	} else {
-		// group permission
-		RawQuery.Add("groupName", d.Get("group_name").(string))
-		if templateID, ok := d.GetOk("template_id"); ok {
-			// template user permission
-			sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/permissions/add_group_to_template"
-			RawQuery.Add("templateId", templateID.(string))
-			// name provide instead of id
-		} else if templateName, ok := d.GetOk("template_name"); ok {
-			sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/permissions/add_group_to_template"
-			RawQuery.Add("templateName", templateName.(string))
+		if isTemplate {
+			addPermission = permissionsService.AddGroupToTemplate
 		} else {
-			// direct user permission
-			sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/permissions/add_group"
+			addPermission = permissionsService.AddGroup
 		}
 	}
 
 	// loop through all permissions that should be applied
 	for _, permission := range permissions {
-		CurrentRawQuery := RawQuery
-		CurrentRawQuery.Del("permission")
-		CurrentRawQuery.Add("permission", permission)
-		sonarQubeURL.RawQuery = CurrentRawQuery.Encode()
-
-		resp, err := httpRequestHelper(
-			m.(*ProviderConfiguration).httpClient,
-			"POST",
-			sonarQubeURL.String(),
-			http.StatusNoContent,
-			"resourceSonarqubePermissionsCreate",
-		)
-		if err != nil {
-			return fmt.Errorf("error creating Sonarqube permission: %+v", err)
+		request.Permission = permission
+		if err := addPermission(ctx, request); err != nil {
+			return diag.Errorf("error creating Sonarqube permission: %+v", err)
 		}
-		defer resp.Body.Close()
 	}
 
-	// generate a unique ID
-	d.SetId(uuid.NewV4().String())
-	return resourceSonarqubePermissionsRead(d, m)
+	d.SetId(permissionsID(d))
+	return resourceSonarqubePermissionsRead(ctx, d, m)
 }
 
-func resourceSonarqubePermissionsRead(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-
-	// build the base query
-	RawQuery := url.Values{
-		// set the page size to 100
-		"ps": []string{"100"},
-	}
-
-	// if the permissions should be applied to a project
-	// we append the project_key to the request
-	if projectKey, ok := d.GetOk("project_key"); ok {
-		RawQuery.Add("projectKey", projectKey.(string))
+func resourceSonarqubePermissionsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	permissionsService := m.(*ProviderConfiguration).client.Permissions
+	request := client.PermissionsSearchRequest{
+		ProjectKey:   d.Get("project_key").(string),
+		TemplateID:   d.Get("template_id").(string),
+		TemplateName: d.Get("template_name").(string),
 	}
+	isTemplate := request.TemplateID != "" || request.TemplateName != ""
 
-	// we use different API endpoints and request params
-	// based on the target principal type (group or user)
+	// we use different API endpoints based on the target principal type (group or user)
 	// and if its a direct or template permission
 	if _, ok := d.GetOk("login_name"); ok {
 		// permission target is USER
-		if templateID, ok := d.GetOk("template_id"); ok {
-			// template user permission
-			sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/permissions/template_users"
-			RawQuery.Add("templateId", templateID.(string))
-			// name provide instead of id
-		} else if templateName, ok := d.GetOk("template_name"); ok {
-			sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/permissions/template_users"
-			RawQuery.Add("templateName", templateName.(string))
-		} else {
-			// direct user permission
-			sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/permissions/users"
-		}
-		sonarQubeURL.RawQuery = RawQuery.Encode()
-
-		resp, err := httpRequestHelper(
-			m.(*ProviderConfiguration).httpClient,
-			"GET",
-			sonarQubeURL.String(),
-			http.StatusOK,
-			"resourceSonarqubePermissionsRead",
-		)
-		if err != nil {
-			return fmt.Errorf("error reading Sonarqube permissions: %+v", err)
-		}
-		defer resp.Body.Close()
-
-		// Decode response into struct
-		users := GetUser{}
-		err = json.NewDecoder(resp.Body).Decode(&users)
-		if err != nil {
-			return fmt.Errorf("resourceSonarqubePermissionsRead: Failed to decode json into struct: %+v", err)
+		pager := permissionsService.UsersPages(request)
+		if isTemplate {
+			pager = permissionsService.TemplateUsersPages(request)
 		}
 
-		// Loop over all groups to see if the group we need exists.
+		// Walk all users to see if the user we need exists.
 		loginName := d.Get("login_name").(string)
-		for _, value := range users.Users {
-			if strings.EqualFold(value.Login, loginName) {
-				d.Set("login_name", value.Login)
-				d.Set("permissions", flattenPermissions(&value.Permissions))
+		user, err := pager.Find(ctx, func(value client.UserPermissions) bool {
+			return strings.EqualFold(value.Login, loginName)
+		})
+		if err != nil {
+			if client.IsNotFound(err) {
+				removeResourceFromState(d, "resourceSonarqubePermissionsRead")
 				return nil
 			}
+			return diag.Errorf("error reading Sonarqube permissions: %+v", err)
+		}
+		if user != nil {
+			d.Set("login_name", user.Login)
+			d.Set("permissions", flattenPermissions(&user.Permissions))
+			return nil
 		}
 
 	} else {
 		// permission target is GROUP
-		if templateID, ok := d.GetOk("template_id"); ok {
-			// template group permission
-			sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/permissions/template_groups"
-			RawQuery.Add("templateId", templateID.(string))
-		} else if templateName, ok := d.GetOk("template_name"); ok {
-			sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/permissions/template_groups"
-			RawQuery.Add("templateName", templateName.(string))
-		} else {
-			// direct group permission
-			sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/permissions/groups"
-		}
-		sonarQubeURL.RawQuery = RawQuery.Encode()
-
-		resp, err := httpRequestHelper(
-			m.(*ProviderConfiguration).httpClient,
-			"GET",
-			sonarQubeURL.String(),
-			http.StatusOK,
-			"resourceSonarqubePermissionsRead",
-		)
-		if err != nil {
-			return fmt.Errorf("error reading Sonarqube permissions: %+v", err)
-		}
-		defer resp.Body.Close()
-
-		// Decode response into struct
-		groups := GetGroupPermissions{}
-		err = json.NewDecoder(resp.Body).Decode(&groups)
-		if err != nil {
-			return fmt.Errorf("resourceSonarqubePermissionsRead: Failed to decode json into struct: %+v", err)
+		pager := permissionsService.GroupsPages(request)
+		if isTemplate {
+			pager = permissionsService.TemplateGroupsPages(request)
 		}
 
-		// Loop over all groups to see if the group we need exists.
+		// Walk all groups to see if the group we need exists.
 		groupName := d.Get("group_name").(string)
-		for _, value := range groups.Groups {
-			if strings.EqualFold(value.Name, groupName) {
-				d.Set("group_name", value.Name)
-				d.Set("permissions", flattenPermissions(&value.Permissions))
+		group, err := pager.Find(ctx, func(value client.GroupPermissions) bool {
+			return strings.EqualFold(value.Name, groupName)
+		})
+		if err != nil {
+			if client.IsNotFound(err) {
+				removeResourceFromState(d, "resourceSonarqubePermissionsRead")
 				return nil
 			}
+			return diag.Errorf("error reading Sonarqube permissions: %+v", err)
+		}
+		if group != nil {
+			d.Set("group_name", group.Name)
+			d.Set("permissions", flattenPermissions(&group.Permissions))
+			return nil
 		}
 	}
 
-	return fmt.Errorf("resourceSonarqubePermissionsRead: Unable to find group permissions for group: %+v", d.Id())
+	removeResourceFromState(d, "resourceSonarqubePermissionsRead")
+	return nil
 }
 
-func resourceSonarqubePermissionsDelete(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
+func resourceSonarqubePermissionsDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	permissionsService := m.(*ProviderConfiguration).client.Permissions
 	permissions := expandPermissions(d)
+	request := getPermissionsRequest(d)
 
-	// build the base query
-	RawQuery := url.Values{}
-
-	// if the permissions should be applied to a project
-	// we append the project_key to the request
-	if projectKey, ok := d.GetOk("project_key"); ok {
-		RawQuery.Add("projectKey", projectKey.(string))
-	}
-
-	// we use different API endpoints and request params
-	// based on the target principal type (group or user)
+	// we use different API endpoints based on the target principal type (group or user)
+	var removePermission func(context.Context, client.PermissionsRequest) error
+	isTemplate := request.TemplateID != "" || request.TemplateName != ""
 	if _, ok := d.GetOk("login_name"); ok {
-		// permission target is USER
-		if templateID, ok := d.GetOk("template_id"); ok {
-			// template user permission
-			sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/permissions/remove_user_from_template"
-			RawQuery.Add("templateId", templateID.(string))
-		} else if templateName, ok := d.GetOk("template_name"); ok {
-			sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/permissions/remove_user_from_template"
-			RawQuery.Add("templateName", templateName.(string))
+		if isTemplate {
+			removePermission = permissionsService.RemoveUserFromTemplate
 		} else {
-			// direct user permission
-			sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/permissions/remove_user"
+			removePermission = permissionsService.RemoveUser
 		}
-		RawQuery.Add("login", d.Get("login_name").(string))
-		sonarQubeURL.RawQuery = RawQuery.Encode()
-
 	} else {
-		// permission target is GROUP
-		if templateID, ok := d.GetOk("template_id"); ok {
-			// template group permission
-			sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/permissions/remove_group_from_template"
-			RawQuery.Add("templateId", templateID.(string))
-		} else if templateName, ok := d.GetOk("template_name"); ok {
-			sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/permissions/remove_group_from_template"
-			RawQuery.Add("templateName", templateName.(string))
+		if isTemplate {
+			removePermission = permissionsService.RemoveGroupFromTemplate
 		} else {
-			// direct group permission
-			sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/permissions/remove_group"
+			removePermission = permissionsService.RemoveGroup
 		}
-		RawQuery.Add("groupName", d.Get("group_name").(string))
-		sonarQubeURL.RawQuery = RawQuery.Encode()
 	}
 
-	// loop through all permissions that should be applied
+	// loop through all permissions that should be removed
 	for _, permission := range permissions {
-		CurrentRawQuery := RawQuery
-		CurrentRawQuery.Del("permission")
-		CurrentRawQuery.Add("permission", permission)
-		sonarQubeURL.RawQuery = CurrentRawQuery.Encode()
-
-		resp, err := httpRequestHelper(
-			m.(*ProviderConfiguration).httpClient,
-			"POST",
-			sonarQubeURL.String(),
-			http.StatusNoContent,
-			"resourceSonarqubePermissionsDelete",
-		)
-		if err != nil {
-			return fmt.Errorf("error creating Sonarqube permission: %+v", err)
+		request.Permission = permission
+		if err := removePermission(ctx, request); err != nil {
+			return diag.Errorf("error creating Sonarqube permission: %+v", err)
 		}
-		defer resp.Body.Close()
 	}
 
 	return nil
 }
 
+func resourceSonarqubePermissionsImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	// import id in format {type}/{name} for global permissions, {project_key}/{type}/{name} for project permissions and
+	// template_id/{template_id}/{type}/{name} or template_name/{template_name}/{type}/{name} for permission templates,
+	// where type is either user or group
+	scope, scopeValue, principalType, principalName, ok := parsePermissionsID(d.Id())
+	if !ok {
+		return nil, fmt.Errorf("resourceSonarqubePermissionsImport: Import id '%s' is not in format {type}/{name}, {project_key}/{type}/{name}, template_id/{template_id}/{type}/{name} or template_name/{template_name}/{type}/{name}, where type is user or group", d.Id())
+	}
+	if scope != "" {
+		d.Set(scope, scopeValue)
+	}
+	if principalType == "user" {
+		d.Set("login_name", principalName)
+	} else {
+		d.Set("group_name", principalName)
+	}
+
+	d.SetId(permissionsID(d))
+	if diags := resourceSonarqubePermissionsRead(ctx, d, m); diags.HasError() {
+		return nil, diagnosticsToError(diags)
+	}
+	if d.Id() == "" {
+		return nil, fmt.Errorf("resourceSonarqubePermissionsImport: No permissions found for '%s'", principalName)
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+// parsePermissionsID splits an ID in the format of permissionsID. scope is empty for global permissions, otherwise the
+// attribute holding scopeValue: project_key, template_id or template_name. Group and template names may contain "/",
+// so the name of the user or group is always the remainder of the ID. A template name is split at its first "/user/"
+// or "/group/". Logins and project keys can not contain "/".
+func parsePermissionsID(id string) (scope string, scopeValue string, principalType string, principalName string, ok bool) {
+	isType := func(value string) bool {
+		return value == "user" || value == "group"
+	}
+
+	switch {
+	case strings.HasPrefix(id, "template_id/"):
+		parts := strings.SplitN(id, "/", 4)
+		if len(parts) == 4 && parts[1] != "" && isType(parts[2]) && parts[3] != "" {
+			return "template_id", parts[1], parts[2], parts[3], true
+		}
+		return "", "", "", "", false
+
+	case strings.HasPrefix(id, "template_name/"):
+		rest := strings.TrimPrefix(id, "template_name/")
+		i := strings.Index(rest, "/user/")
+		if j := strings.Index(rest, "/group/"); j >= 0 && (i < 0 || j < i) {
+			i = j
+		}
+		if i <= 0 {
+			return "", "", "", "", false
+		}
+		parts := strings.SplitN(rest[i+1:], "/", 2)
+		if parts[1] == "" {
+			return "", "", "", "", false
+		}
+		return "template_name", rest[:i], parts[0], parts[1], true
+	}
+
+	// A global group permission, or a global user permission as logins can not contain "/". IDs starting with group/
+	// are always global permissions.
+	parts := strings.SplitN(id, "/", 2)
+	if len(parts) == 2 && (parts[0] == "group" || (parts[0] == "user" && !strings.Contains(parts[1], "/"))) && parts[1] != "" {
+		return "", "", parts[0], parts[1], true
+	}
+
+	parts = strings.SplitN(id, "/", 3)
+	if len(parts) == 3 && parts[0] != "" && isType(parts[1]) && parts[2] != "" {
+		return "project_key", parts[0], parts[1], parts[2], true
+	}
+	return "", "", "", "", false
+}
+
+// permissionsID returns the ID of the permissions of a user or group, in the format of the import id
+func permissionsID(d *schema.ResourceData) string {
+	id := "group/" + d.Get("group_name").(string)
+	if loginName, ok := d.GetOk("login_name"); ok {
+		id = "user/" + loginName.(string)
+	}
+	if templateID, ok := d.GetOk("template_id"); ok {
+		return "template_id/" + templateID.(string) + "/" + id
+	}
+	if templateName, ok := d.GetOk("template_name"); ok {
+		return "template_name/" + templateName.(string) + "/" + id
+	}
+	if projectKey, ok := d.GetOk("project_key"); ok {
+		return projectKey.(string) + "/" + id
+	}
+	return id
+}
+
+// getPermissionsRequest builds the request shared by the add and remove calls, without the permission itself
+func getPermissionsRequest(d *schema.ResourceData) client.PermissionsRequest {
+	request := client.PermissionsRequest{
+		// if the permissions should be applied to a project
+		// we append the project_key to the request
+		ProjectKey: d.Get("project_key").(string),
+	}
+	if _, ok := d.GetOk("login_name"); ok {
+		request.Login = d.Get("login_name").(string)
+	} else {
+		request.GroupName = d.Get("group_name").(string)
+	}
+	if templateID, ok := d.GetOk("template_id"); ok {
+		request.TemplateID = templateID.(string)
+		// name provide instead of id
+	} else if templateName, ok := d.GetOk("template_name"); ok {
+		request.TemplateName = templateName.(string)
+	}
+	return request
+}
+
 func expandPermissions(d *schema.ResourceData) []string {
 	expandedPermissions := make([]string, 0)
 	flatPermissions := d.Get("permissions").([]interface{})
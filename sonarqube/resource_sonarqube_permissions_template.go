@@ -1,46 +1,30 @@
 package sonarqube
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"log"
-	"net/http"
-	"net/url"
-	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/jdamata/terraform-provider-sonarqube/internal/client"
 )
 
-// CreatePermissionTemplateResponse struct
-type CreatePermissionTemplateResponse struct {
-	PermissionTemplate PermissionTemplate `json:"permissionTemplate"`
-}
-
-// GetPermissionTemplates struct
-type GetPermissionTemplates struct {
-	Paging              Paging               `json:"paging"`
-	PermissionTemplates []PermissionTemplate `json:"permissionTemplates"`
-}
-
-// PermissionTemplate struct
-type PermissionTemplate struct {
-	ID                string `json:"id,omitempty"`
-	Name              string `json:"name,omitempty"`
-	Description       string `json:"description,omitempty"`
-	ProjectKeyPattern string `json:"projectKeyPattern,omitempty"`
-}
-
 // Returns the resource represented by this file.
 func resourceSonarqubePermissionTemplate() *schema.Resource {
 	return &schema.Resource{
 		Description: `Provides a Sonarqube Permission template resource. This can be used to create and manage Sonarqube Permission
 templates.`,
-		Create: resourceSonarqubePermissionTemplateCreate,
-		Read:   resourceSonarqubePermissionTemplateRead,
-		Update: resourceSonarqubePermissionTemplateUpdate,
-		Delete: resourceSonarqubePermissionTemplateDelete,
+		CreateContext: resourceSonarqubePermissionTemplateCreate,
+		ReadContext:   resourceSonarqubePermissionTemplateRead,
+		UpdateContext: resourceSonarqubePermissionTemplateUpdate,
+		DeleteContext: resourceSonarqubePermissionTemplateDelete,
 		Importer: &schema.ResourceImporter{
-			State: resourceSonarqubePermissionTemplateImport,
+			StateContext: resourceSonarqubePermissionTemplateImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Default: schema.DefaultTimeout(5 * time.Minute),
 		},
 
 		// Define the fields of this schema.
@@ -70,76 +54,38 @@ templates.`,
 	}
 }
 
-func resourceSonarqubePermissionTemplateCreate(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/permissions/create_template"
-	sonarQubeURL.RawQuery = url.Values{
-		"name":              []string{d.Get("name").(string)},
-		"description":       []string{d.Get("description").(string)},
-		"projectKeyPattern": []string{d.Get("project_key_pattern").(string)},
-	}.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusOK,
-		"resourceSonarqubePermissionTemplateCreate",
-	)
-	if err != nil {
-		return fmt.Errorf("error creating Sonarqube permission template: %+v", err)
+func resourceSonarqubePermissionTemplateCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	request := client.PermissionTemplateRequest{
+		Name:              d.Get("name").(string),
+		Description:       d.Get("description").(string),
+		ProjectKeyPattern: d.Get("project_key_pattern").(string),
 	}
-	defer resp.Body.Close()
-
-	// Decode response into struct
-	permissionTemplateResponse := CreatePermissionTemplateResponse{}
-	err = json.NewDecoder(resp.Body).Decode(&permissionTemplateResponse)
+	permissionTemplate, err := m.(*ProviderConfiguration).client.Permissions.CreateTemplate(ctx, request)
 	if err != nil {
-		return fmt.Errorf("resourceSonarqubePermissionTemplateCreate: Failed to decode json into struct: %+v", err)
+		return diag.Errorf("error creating Sonarqube permission template: %+v", err)
 	}
 
-	if permissionTemplateResponse.PermissionTemplate.ID != "" {
-		d.SetId(permissionTemplateResponse.PermissionTemplate.ID)
+	if permissionTemplate.ID != "" {
+		d.SetId(permissionTemplate.ID)
 	} else {
-		return fmt.Errorf("resourceSonarqubePermissionTemplateCreate: Create response didn't contain an ID")
+		return diag.Errorf("resourceSonarqubePermissionTemplateCreate: Create response didn't contain an ID")
 	}
 
 	// If default is set to true, set this permission template as the default.
 	if d.Get("default").(bool) {
-		sonarQubeURL = m.(*ProviderConfiguration).sonarQubeURL
-		err = resourceSonarqubePermissionTemplateSetDefault(sonarQubeURL, d.Id(), m)
+		err = resourceSonarqubePermissionTemplateSetDefault(ctx, d.Id(), m)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 
-	return resourceSonarqubePermissionTemplateRead(d, m)
+	return resourceSonarqubePermissionTemplateRead(ctx, d, m)
 }
 
-func resourceSonarqubePermissionTemplateRead(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/permissions/search_templates"
-	sonarQubeURL.RawQuery = url.Values{
-		"q": []string{d.Get("name").(string)},
-	}.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"GET",
-		sonarQubeURL.String(),
-		http.StatusOK,
-		"resourceSonarqubePermissionTemplateRead",
-	)
-	if err != nil {
-		return fmt.Errorf("error reading Sonarqube permission templates: %+v", err)
-	}
-	defer resp.Body.Close()
-
-	// Decode response into struct
-	permissionTemplateReadResponse := GetPermissionTemplates{}
-	err = json.NewDecoder(resp.Body).Decode(&permissionTemplateReadResponse)
+func resourceSonarqubePermissionTemplateRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	permissionTemplateReadResponse, err := m.(*ProviderConfiguration).client.Permissions.SearchTemplates(ctx, d.Get("name").(string))
 	if err != nil {
-		return fmt.Errorf("resourceSonarqubePermissionTemplateRead: Failed to decode json into struct: %+v", err)
+		return diag.Errorf("error reading Sonarqube permission templates: %+v", err)
 	}
 
 	// Loop over all permission templates to see if the template we look for exists.
@@ -156,100 +102,52 @@ func resourceSonarqubePermissionTemplateRead(d *schema.ResourceData, m interface
 		}
 	}
 
-	return fmt.Errorf("resourceSonarqubePermissionTemplateRead: Failed to find template with ID: %+v", d.Id())
+	removeResourceFromState(d, "resourceSonarqubePermissionTemplateRead")
+	return nil
 }
 
-func resourceSonarqubePermissionTemplateUpdate(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/permissions/update_template"
-
-	rawQuery := url.Values{
-		"id": []string{d.Id()},
-	}
-
-	if _, ok := d.GetOk("description"); ok {
-		rawQuery.Add("description", d.Get("description").(string))
-	} else {
-		rawQuery.Add("description", "")
+func resourceSonarqubePermissionTemplateUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	request := client.PermissionTemplateRequest{
+		ID:                d.Id(),
+		Description:       d.Get("description").(string),
+		ProjectKeyPattern: d.Get("project_key_pattern").(string),
 	}
-
-	if _, ok := d.GetOk("project_key_pattern"); ok {
-		rawQuery.Add("projectKeyPattern", d.Get("project_key_pattern").(string))
-	} else {
-		rawQuery.Add("projectKeyPattern", "")
-	}
-
-	sonarQubeURL.RawQuery = rawQuery.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusOK,
-		"resourceSonarqubePermissionTemplateUpdate",
-	)
+	err := m.(*ProviderConfiguration).client.Permissions.UpdateTemplate(ctx, request)
 	if err != nil {
-		return fmt.Errorf("error updating Sonarqube permission template: %+v", err)
+		return diag.Errorf("error updating Sonarqube permission template: %+v", err)
 	}
-	defer resp.Body.Close()
 
 	// If default is set to true, set this permission template as the default.
 	if d.Get("default").(bool) {
-		sonarQubeURL = m.(*ProviderConfiguration).sonarQubeURL
-		err = resourceSonarqubePermissionTemplateSetDefault(sonarQubeURL, d.Id(), m)
+		err = resourceSonarqubePermissionTemplateSetDefault(ctx, d.Id(), m)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 
-	return resourceSonarqubePermissionTemplateRead(d, m)
+	return resourceSonarqubePermissionTemplateRead(ctx, d, m)
 }
 
-func resourceSonarqubePermissionTemplateDelete(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/permissions/delete_template"
-	sonarQubeURL.RawQuery = url.Values{
-		"templateId": []string{d.Id()},
-	}.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusNoContent,
-		"resourceSonarqubePermissionTemplateDelete",
-	)
+func resourceSonarqubePermissionTemplateDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	err := m.(*ProviderConfiguration).client.Permissions.DeleteTemplate(ctx, d.Id())
 	if err != nil {
-		return fmt.Errorf("error deleting Sonarqube permission template: %+v", err)
+		return diag.Errorf("error deleting Sonarqube permission template: %+v", err)
 	}
-	defer resp.Body.Close()
 
 	return nil
 }
 
-func resourceSonarqubePermissionTemplateImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
-	if err := resourceSonarqubePermissionTemplateRead(d, m); err != nil {
-		return nil, err
+func resourceSonarqubePermissionTemplateImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	if diags := resourceSonarqubePermissionTemplateRead(ctx, d, m); diags.HasError() {
+		return nil, diagnosticsToError(diags)
 	}
 	return []*schema.ResourceData{d}, nil
 }
 
-func resourceSonarqubePermissionTemplateSetDefault(sonarQubeURL url.URL, templateID string, m interface{}) error {
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/permissions/set_default_template"
-	sonarQubeURL.RawQuery = url.Values{
-		"templateId": []string{templateID},
-	}.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusNoContent,
-		"resourceSonarqubePermissionTemplateCreate",
-	)
+func resourceSonarqubePermissionTemplateSetDefault(ctx context.Context, templateID string, m interface{}) error {
+	err := m.(*ProviderConfiguration).client.Permissions.SetDefaultTemplate(ctx, templateID)
 	if err != nil {
 		return fmt.Errorf("error setting Sonarqube permission template to default: %+v", err)
 	}
-	defer resp.Body.Close()
 	return nil
 }
@@ -43,8 +43,8 @@ func TestAccSonarqubePermissionTemplateBasic(t *testing.T) {
 	name := "sonarqube_permission_template." + rnd
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubePermissionTemplateBasicConfig(rnd, "testAccSonarqubePermissionTemplate", "These are internal projects", "internal.*"),
@@ -81,8 +81,8 @@ func TestAccSonarqubePermissionTemplateDefaultTemplate(t *testing.T) {
 	name := "sonarqube_permission_template." + rnd
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubePermissionTemplateDefaultTemplate(rnd, "testAccSonarqubePermissionTemplateDefault", "These are internal projects", "internal.*"),
@@ -40,8 +40,8 @@ func TestAccSonarqubePermissionGroupName(t *testing.T) {
 	updatedPermissions := []string{"admin", "profileadmin"}
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubePermissionGroupNameConfig(rnd, "testAccSonarqubePermissions", permissions),
@@ -55,6 +55,12 @@ func TestAccSonarqubePermissionGroupName(t *testing.T) {
 					resource.TestCheckResourceAttr(name, "group_name", "testAccSonarqubePermissions"),
 				),
 			},
+			{
+				ResourceName:      name,
+				ImportState:       true,
+				ImportStateId:     "group/testAccSonarqubePermissions",
+				ImportStateVerify: true,
+			},
 		},
 	})
 }
@@ -81,8 +87,8 @@ func TestAccSonarqubePermissionLoginName(t *testing.T) {
 	permissions := []string{"gateadmin", "profileadmin"}
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubePermissionLoginNameConfig(rnd, "testAccSonarqubePermissions", permissions),
@@ -121,8 +127,8 @@ func TestAccSonarqubePermissionLoginNameTemplateName(t *testing.T) {
 	permissions := []string{"codeviewer", "scan"}
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubePermissionLoginNameTemplateNameConfig(rnd, "testAccSonarqubePermissions", permissions),
@@ -130,6 +136,79 @@ func TestAccSonarqubePermissionLoginNameTemplateName(t *testing.T) {
 					resource.TestCheckResourceAttr(name, "login_name", "testAccSonarqubePermissions"),
 				),
 			},
+			{
+				ResourceName:      name,
+				ImportState:       true,
+				ImportStateId:     "template_name/foo/user/testAccSonarqubePermissions",
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestParsePermissionsID(t *testing.T) {
+	tests := []struct {
+		id                                              string
+		scope, scopeValue, principalType, principalName string
+		ok                                              bool
+	}{
+		{"group/sonar-users", "", "", "group", "sonar-users", true},
+		{"group/team/backend", "", "", "group", "team/backend", true},
+		{"user/admin", "", "", "user", "admin", true},
+		{"my-project/user/admin", "project_key", "my-project", "user", "admin", true},
+		{"my-project/group/team/backend", "project_key", "my-project", "group", "team/backend", true},
+		{"template_id/AY1/group/team/backend", "template_id", "AY1", "group", "team/backend", true},
+		{"template_name/apps/java/group/team/backend", "template_name", "apps/java", "group", "team/backend", true},
+		{"template_name/apps/user/admin", "template_name", "apps", "user", "admin", true},
+		{"my-project/owner/admin", "", "", "", "", false},
+		{"template_name/apps", "", "", "", "", false},
+		{"template_id/AY1/group/", "", "", "", "", false},
+		{"admin", "", "", "", "", false},
+	}
+	for _, test := range tests {
+		scope, scopeValue, principalType, principalName, ok := parsePermissionsID(test.id)
+		if ok != test.ok || scope != test.scope || scopeValue != test.scopeValue || principalType != test.principalType || principalName != test.principalName {
+			t.Errorf("parsePermissionsID(%q) = %q, %q, %q, %q, %v, want %q, %q, %q, %q, %v", test.id, scope, scopeValue, principalType, principalName, ok,
+				test.scope, test.scopeValue, test.principalType, test.principalName, test.ok)
+		}
+	}
+}
+
+func TestAccSonarqubePermissionGroupNameWithSlash(t *testing.T) {
+	rnd := generateRandomResourceName()
+	name := "sonarqube_permissions." + rnd
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+		resource "sonarqube_group" "%[1]s" {
+			name = "testAccSonarqubePermissions/backend"
+		}
+
+		resource "sonarqube_project" "%[1]s" {
+			name    = "testAccSonarqubePermissionsSlash"
+			project = "testAccSonarqubePermissionsSlash"
+		}
+
+		resource "sonarqube_permissions" "%[1]s" {
+			group_name  = sonarqube_group.%[1]s.name
+			project_key = sonarqube_project.%[1]s.project
+			permissions = ["codeviewer", "user"]
+		}`, rnd),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(name, "id", "testAccSonarqubePermissionsSlash/group/testAccSonarqubePermissions/backend"),
+					resource.TestCheckResourceAttr(name, "group_name", "testAccSonarqubePermissions/backend"),
+				),
+			},
+			{
+				ResourceName:      name,
+				ImportState:       true,
+				ImportStateId:     "testAccSonarqubePermissionsSlash/group/testAccSonarqubePermissions/backend",
+				ImportStateVerify: true,
+			},
 		},
 	})
 }
@@ -1,49 +1,29 @@
 package sonarqube
 
 import (
-	"encoding/json"
-	"fmt"
-	"net/http"
-	"net/url"
-	"strings"
+	"context"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
-// GetInstalledPlugins for unmarshalling response body from geting installed plugins
-type GetInstalledPlugins struct {
-	Plugins []Plugin `json:"plugins"`
-}
-
-// Plugin used in GetInstalledPlugins
-type Plugin struct {
-	Key                 string `json:"key"`
-	Name                string `json:"name"`
-	Description         string `json:"description"`
-	Version             string `json:"version"`
-	License             string `json:"license"`
-	OrganizationName    string `json:"organizationName"`
-	OrganizationURL     string `json:"organizationUrl"`
-	EditionBundled      bool   `json:"editionBundled"`
-	HomepageURL         string `json:"homepageUrl"`
-	IssueTrackerURL     string `json:"issueTrackerUrl"`
-	ImplementationBuild string `json:"implementationBuild"`
-	Filename            string `json:"filename"`
-	Hash                string `json:"hash"`
-	SonarLintSupported  bool   `json:"sonarLintSupported"`
-	DocumentationPath   string `json:"documentationPath"`
-	UpdatedAt           int    `json:"updatedAt"`
-}
-
 // Returns the resource represented by this file.
 func resourceSonarqubePlugin() *schema.Resource {
 	return &schema.Resource{
-		Description: "Provides a Sonarqube Plugin resource. This can be used to create and manage Sonarqube Plugins.",
-		Create:      resourceSonarqubePluginCreate,
-		Read:        resourceSonarqubePluginRead,
-		Delete:      resourceSonarqubePluginDelete,
+		Description:   "Provides a Sonarqube Plugin resource. This can be used to create and manage Sonarqube Plugins.",
+		CreateContext: resourceSonarqubePluginCreate,
+		ReadContext:   resourceSonarqubePluginRead,
+		DeleteContext: resourceSonarqubePluginDelete,
 		Importer: &schema.ResourceImporter{
-			State: resourceSonarqubePluginImport,
+			StateContext: resourceSonarqubePluginImport,
+		},
+		CustomizeDiff: requireCapability(capabilityPlugins),
+
+		Timeouts: &schema.ResourceTimeout{
+			Create:  schema.DefaultTimeout(10 * time.Minute),
+			Delete:  schema.DefaultTimeout(10 * time.Minute),
+			Default: schema.DefaultTimeout(5 * time.Minute),
 		},
 
 		// Define the fields of this schema.
@@ -58,91 +38,52 @@ func resourceSonarqubePlugin() *schema.Resource {
 	}
 }
 
-func resourceSonarqubePluginCreate(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/plugins/install"
-	sonarQubeURL.RawQuery = url.Values{
-		"key": []string{d.Get("key").(string)},
-	}.Encode()
+func resourceSonarqubePluginCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if err := m.(*ProviderConfiguration).checkCapability(capabilityPlugins); err != nil {
+		return diag.FromErr(err)
+	}
 
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusNoContent,
-		"resourceSonarqubePluginCreate",
-	)
+	err := m.(*ProviderConfiguration).client.Plugins.Install(ctx, d.Get("key").(string))
 	if err != nil {
-		return err
+		return diag.Errorf("resourceSonarqubePluginCreate: Failed to install plugin: %+v", err)
 	}
-	defer resp.Body.Close()
 
 	d.SetId(d.Get("key").(string))
-	return resourceSonarqubePluginRead(d, m)
+	return resourceSonarqubePluginRead(ctx, d, m)
 }
 
-func resourceSonarqubePluginRead(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/plugins/installed"
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"GET",
-		sonarQubeURL.String(),
-		http.StatusOK,
-		"resourceSonarqubePluginRead",
-	)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	// Decode response into struct
-	getInstalledPlugins := GetInstalledPlugins{}
-	err = json.NewDecoder(resp.Body).Decode(&getInstalledPlugins)
+func resourceSonarqubePluginRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	plugins, err := m.(*ProviderConfiguration).client.Plugins.Installed(ctx)
 	if err != nil {
-		return fmt.Errorf("resourceSonarqubePluginRead: Failed to decode json into struct: %+v", err)
+		return diag.Errorf("resourceSonarqubePluginRead: Failed to list installed plugins: %+v", err)
 	}
 
-	// Loop over all projects to see if the project we need exists.
-	for _, value := range getInstalledPlugins.Plugins {
+	// Loop over all plugins to see if the plugin we need exists.
+	for _, value := range plugins {
 		if d.Id() == value.Key {
-			// If it does, set the values of that project
+			// If it does, set the values of that plugin
 			d.SetId(value.Key)
 			d.Set("key", value.Key)
 			return nil
 		}
 	}
 
-	return fmt.Errorf("resourceSonarqubePluginRead: Failed to find plugin: %+v", d.Id())
+	removeResourceFromState(d, "resourceSonarqubePluginRead")
+	return nil
 }
 
-func resourceSonarqubePluginDelete(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/plugins/uninstall"
-
-	sonarQubeURL.RawQuery = url.Values{
-		"key": []string{d.Id()},
-	}.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusNoContent,
-		"resourceSonarqubePluginDelete",
-	)
+func resourceSonarqubePluginDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	err := m.(*ProviderConfiguration).client.Plugins.Uninstall(ctx, d.Id())
 	if err != nil {
-		return fmt.Errorf("resourceSonarqubePluginDelete: Failed to delete plugin: %+v", err)
+		return diag.Errorf("resourceSonarqubePluginDelete: Failed to delete plugin: %+v", err)
 	}
-	defer resp.Body.Close()
 
 	return nil
 }
 
-func resourceSonarqubePluginImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
-	if err := resourceSonarqubePluginRead(d, m); err != nil {
-		return nil, err
+func resourceSonarqubePluginImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	if diags := resourceSonarqubePluginRead(ctx, d, m); diags.HasError() {
+		return nil, diagnosticsToError(diags)
 	}
 
 	return []*schema.ResourceData{d}, nil
@@ -2,39 +2,19 @@ package sonarqube
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"net/http"
-	"net/url"
+	"log"
 	"sort"
-	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/jdamata/terraform-provider-sonarqube/internal/client"
 	"golang.org/x/exp/slices"
 )
 
-// Portfolio used in Portfolio
-type Portfolio struct {
-	Key              string             `json:"key"`
-	Name             string             `json:"name"`
-	Desc             string             `json:"desc,omitempty"`
-	Qualifier        string             `json:"qualifier"`
-	Visibility       string             `json:"visibility"`
-	SelectionMode    string             `json:"selectionMode"`
-	Branch           string             `json:"branch,omitempty"`
-	Tags             []string           `json:"tags,omitempty"`
-	Regexp           string             `json:"regexp,omitempty"`
-	SelectedProjects []PortfolioProject `json:"selectedProjects,omitempty"`
-}
-
-// Portfolio project
-type PortfolioProject struct {
-	ProjectKey       string   `json:"projectKey"`
-	SelectedBranches []string `json:"selectedBranches,omitempty"`
-}
-
 const (
 	NONE   = "NONE"
 	MANUAL = "MANUAL"
@@ -43,24 +23,38 @@ const (
 	REST   = "REST"
 )
 
+// portfolioChildTypes maps the qualifiers of the subViews of api/views/show to the types of the children block
+var portfolioChildTypes = map[string]string{
+	"VW":  "portfolio",
+	"SVW": "local_view",
+	"APP": "application",
+}
+
 // Returns the resource represented by this file.
 func resourceSonarqubePortfolio() *schema.Resource {
 	return &schema.Resource{
-		Description: "Provides a Sonarqube Portfolio resource. This can be used to create and manage Sonarqube Portfolio. Note that the SonarQube API for Portfolios is called ``views``",
-		Create:      resourceSonarqubePortfolioCreate,
-		Read:        resourceSonarqubePortfolioRead,
-		Update:      resourceSonarqubePortfolioUpdate,
-		Delete:      resourceSonarqubePortfolioDelete,
+		Description:   "Provides a Sonarqube Portfolio resource. This can be used to create and manage Sonarqube Portfolio. Note that the SonarQube API for Portfolios is called ``views``",
+		CreateContext: resourceSonarqubePortfolioCreate,
+		ReadContext:   resourceSonarqubePortfolioRead,
+		UpdateContext: resourceSonarqubePortfolioUpdate,
+		DeleteContext: resourceSonarqubePortfolioDelete,
 		Importer: &schema.ResourceImporter{
-			State: resourceSonarqubePortfolioImport,
+			StateContext: resourceSonarqubePortfolioImport,
 		},
 		// Validation that runs after the read in plan has completed (https://developer.hashicorp.com/terraform/plugin/sdkv2/resources/customizing-differences)
 		CustomizeDiff: customdiff.All(
+			requireCapability(capabilityPortfolios),
 			func(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
 				return validatePortfolioResource(d)
 			},
 		),
 
+		Timeouts: &schema.ResourceTimeout{
+			Create:  schema.DefaultTimeout(20 * time.Minute),
+			Update:  schema.DefaultTimeout(20 * time.Minute),
+			Default: schema.DefaultTimeout(5 * time.Minute),
+		},
+
 		// Define the fields of this schema.
 		Schema: map[string]*schema.Schema{
 			"key": {
@@ -150,18 +144,34 @@ func resourceSonarqubePortfolio() *schema.Resource {
 					Description: "Block set of projects to add to the portfolio. Only active when `selection_mode` is `MANUAL`. See [below for nested schema](#selected_projects)",
 				},
 			},
+			"children": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Computed: true,
+				// Lets `children = []` remove every child, omitting the block leaves the children unmanaged
+				ConfigMode:  schema.SchemaConfigModeAttr,
+				ForceNew:    false,
+				Description: "A set of portfolios and applications to add to the portfolio. When the block is configured, children added outside of Terraform are removed, `children = []` removes every child. When it is omitted, the children are not managed.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"key": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "The key of the portfolio or the application to add to the portfolio",
+						},
+						"type": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringInSlice([]string{"portfolio", "local_view", "application"}, false),
+							Description:  "How the child is added to the portfolio. Possible values are `portfolio` for a reference to another portfolio, `local_view` for a local sub-portfolio and `application`",
+						},
+					},
+				},
+			},
 		},
 	}
 }
 
-func checkPortfolioSupport(conf *ProviderConfiguration) error {
-	edition := strings.ToLower(conf.sonarQubeEdition)
-	if edition != "enterprise" && edition != "data center" {
-		return fmt.Errorf("portfolios are only supported in the Enterprise and Datacenter editions of SonarQube. You are using: SonarQube %s version %s", conf.sonarQubeEdition, conf.sonarQubeVersion)
-	}
-	return nil
-}
-
 // Validate the selection_mode and its corresponding fields
 func validatePortfolioResource(d *schema.ResourceDiff) error {
 	switch selectionMode := d.Get("selection_mode"); selectionMode {
@@ -201,100 +211,49 @@ func validatePortfolioResource(d *schema.ResourceDiff) error {
 	}
 }
 
-func portfolioSetSelectionMode(d *schema.ResourceData, m interface{}, sonarQubeURL url.URL) error {
-	var endpoint string
+func portfolioSetSelectionMode(ctx context.Context, d *schema.ResourceData, m interface{}) error {
+	viewsService := m.(*ProviderConfiguration).client.Views
+	portfolioKey := d.Get("key").(string)
+	// SonarQube handles "" like it actually is a name of a branch, see PR for reference: https://github.com/jdamata/terraform-provider-sonarqube/pull/150
+	// so the client only sends the branch when it is set
+	branch := d.Get("branch").(string)
+
+	var err error
 	switch selectionMode := d.Get("selection_mode"); selectionMode {
 	case NONE:
-		endpoint = "/api/views/set_none_mode"
-		sonarQubeURL.RawQuery = url.Values{
-			"portfolio": []string{d.Get("key").(string)},
-		}.Encode()
+		err = viewsService.SetNoneMode(ctx, portfolioKey)
 
 	case MANUAL:
-		endpoint = "/api/views/set_manual_mode"
-		sonarQubeURL.RawQuery = url.Values{
-			"portfolio": []string{d.Get("key").(string)},
-		}.Encode()
+		err = viewsService.SetManualMode(ctx, portfolioKey)
 
 	case TAGS:
-		endpoint = "/api/views/set_tags_mode"
-
 		var tags []string
 		for _, v := range d.Get("tags").([]interface{}) {
 			tags = append(tags, fmt.Sprint(v))
 		}
-		tagsCSV := strings.Trim(strings.Join(strings.Fields(fmt.Sprint(tags)), ","), "[]")
-
-		urlParameters := url.Values{
-			"portfolio": []string{d.Get("key").(string)},
-			"tags":      []string{tagsCSV},
-		}
-
-		// SonarQube handles "" like it actually is a name of a branch, see PR for reference: https://github.com/jdamata/terraform-provider-sonarqube/pull/150
-		branch := d.Get("branch").(string)
-		if len(branch) > 0 {
-			urlParameters.Add("branch", branch)
-		}
-
-		sonarQubeURL.RawQuery = urlParameters.Encode()
+		err = viewsService.SetTagsMode(ctx, portfolioKey, tags, branch)
 
 	case REGEXP:
-		endpoint = "/api/views/set_regexp_mode"
-
-		urlParameters := url.Values{
-			"portfolio": []string{d.Get("key").(string)},
-			"regexp":    []string{d.Get("regexp").(string)},
-		}
-
-		// SonarQube handles "" like it actually is a name of a branch, see PR for reference: https://github.com/jdamata/terraform-provider-sonarqube/pull/150
-		branch := d.Get("branch").(string)
-		if len(branch) > 0 {
-			urlParameters.Add("branch", branch)
-		}
-
-		sonarQubeURL.RawQuery = urlParameters.Encode()
+		err = viewsService.SetRegexpMode(ctx, portfolioKey, d.Get("regexp").(string), branch)
 
 	case REST:
-		endpoint = "/api/views/set_remaining_projects_mode"
-
-		urlParameters := url.Values{
-			"portfolio": []string{d.Get("key").(string)},
-		}
-
-		// SonarQube handles "" like it actually is a name of a branch, see PR for reference: https://github.com/jdamata/terraform-provider-sonarqube/pull/150
-		branch := d.Get("branch").(string)
-		if len(branch) > 0 {
-			urlParameters.Add("branch", branch)
-		}
-
-		sonarQubeURL.RawQuery = urlParameters.Encode()
+		err = viewsService.SetRemainingProjectsMode(ctx, portfolioKey, branch)
 
 	default:
 		return fmt.Errorf("resourceSonarqubePortfolioCreate: selection_mode needs to be set to one of NONE, MANUAL, TAGS, REGEXP, REST")
 	}
-
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + endpoint
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusNoContent,
-		"resourceSonarqubePortfolioCreate",
-	)
 	if err != nil {
-		return err
+		return fmt.Errorf("resourceSonarqubePortfolioCreate: Failed to set selection mode: %+v", err)
 	}
-	defer resp.Body.Close()
 
 	// The rest of the options populate the portfolio in the "setMode" call. MANUAL portfolios needs to be manually populated afterwards
 	if selectionMode := d.Get("selection_mode").(string); selectionMode == MANUAL {
-		portfolioReadResponse, err := readPortfolioFromApi(d, m)
+		portfolioReadResponse, err := readPortfolioFromApi(ctx, d, m)
 		if err != nil {
 			return fmt.Errorf("resourceSonarqubePortfolioCreate: Failed to read the portfolio from the API: %+v", err)
 		}
 
-		err = synchronizeSelectedProjects(d, m, &portfolioReadResponse.SelectedProjects)
+		err = synchronizeSelectedProjects(ctx, d, m, &portfolioReadResponse.SelectedProjects)
 		if err != nil {
 			return fmt.Errorf("resourceSonarqubePortfolioCreate: Failed to synchronise portfolio projects: %+v", err)
 		}
@@ -303,134 +262,109 @@ func portfolioSetSelectionMode(d *schema.ResourceData, m interface{}, sonarQubeU
 	return nil
 }
 
-func resourceSonarqubePortfolioCreate(d *schema.ResourceData, m interface{}) error {
-	if err := checkPortfolioSupport(m.(*ProviderConfiguration)); err != nil {
-		return err
+func resourceSonarqubePortfolioCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if err := m.(*ProviderConfiguration).checkCapability(capabilityPortfolios); err != nil {
+		return diag.FromErr(err)
 	}
 
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/views/create"
-
-	sonarQubeURL.RawQuery = url.Values{
-		"description": []string{d.Get("description").(string)},
-		"key":         []string{d.Get("key").(string)},
-		"name":        []string{d.Get("name").(string)},
-		"visibility":  []string{d.Get("visibility").(string)},
-	}.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusOK,
-		"resourceSonarqubePortfolioCreate",
-	)
-	if err != nil {
-		return err
+	request := client.ViewsCreateRequest{
+		Key:         d.Get("key").(string),
+		Name:        d.Get("name").(string),
+		Description: d.Get("description").(string),
+		Visibility:  d.Get("visibility").(string),
 	}
-	defer resp.Body.Close()
-
-	// Decode response into struct
-	portfolioResponse := Portfolio{}
-	err = json.NewDecoder(resp.Body).Decode(&portfolioResponse)
+	portfolio, err := m.(*ProviderConfiguration).client.Views.Create(ctx, request)
 	if err != nil {
-		return fmt.Errorf("resourceSonarqubePortfolioCreate: Failed to decode json into struct: %+v", err)
+		return diag.Errorf("resourceSonarqubePortfolioCreate: Failed to create portfolio: %+v", err)
 	}
 
-	d.SetId(portfolioResponse.Key)
+	d.SetId(portfolio.Key)
 
-	err = portfolioSetSelectionMode(d, m, m.(*ProviderConfiguration).sonarQubeURL)
+	err = portfolioSetSelectionMode(ctx, d, m)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	return resourceSonarqubePortfolioRead(d, m)
+	if _, ok := d.GetOk("children"); ok {
+		if err := synchronizePortfolioChildren(ctx, d, m); err != nil {
+			return diag.Errorf("resourceSonarqubePortfolioCreate: Failed to synchronise portfolio children: %+v", err)
+		}
+	}
+
+	return resourceSonarqubePortfolioRead(ctx, d, m)
 }
 
-func resourceSonarqubePortfolioRead(d *schema.ResourceData, m interface{}) error {
-	if err := checkPortfolioSupport(m.(*ProviderConfiguration)); err != nil {
-		return err
+func resourceSonarqubePortfolioRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if err := m.(*ProviderConfiguration).checkCapability(capabilityPortfolios); err != nil {
+		return diag.FromErr(err)
 	}
 
-	portfolioReadResponse, err := readPortfolioFromApi(d, m)
+	portfolioReadResponse, err := readPortfolioFromApi(ctx, d, m)
 	if err != nil {
-		return err
+		if client.IsNotFound(err) {
+			removeResourceFromState(d, "resourceSonarqubePortfolioRead")
+			return nil
+		}
+		return diag.FromErr(err)
 	}
 	updateResourceDataFromPortfolioReadResponse(d, portfolioReadResponse)
 	return nil
 }
 
-func resourceSonarqubePortfolioUpdate(d *schema.ResourceData, m interface{}) error {
-	if err := checkPortfolioSupport(m.(*ProviderConfiguration)); err != nil {
-		return err
+func resourceSonarqubePortfolioUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if err := m.(*ProviderConfiguration).checkCapability(capabilityPortfolios); err != nil {
+		return diag.FromErr(err)
 	}
 
 	if d.HasChanges("name", "description") {
-		sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-		sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/views/update"
-		sonarQubeURL.RawQuery = url.Values{
-			"key":         []string{d.Id()},
-			"description": []string{d.Get("description").(string)},
-			"name":        []string{d.Get("name").(string)},
-		}.Encode()
-
-		resp, err := httpRequestHelper(
-			m.(*ProviderConfiguration).httpClient,
-			"POST",
-			sonarQubeURL.String(),
-			http.StatusOK,
-			"resourceSonarqubePortfolioUpdate",
+		err := m.(*ProviderConfiguration).client.Views.Update(
+			ctx,
+			d.Id(),
+			d.Get("name").(string),
+			d.Get("description").(string),
 		)
 		if err != nil {
-			return fmt.Errorf("error updating Sonarqube Portfolio Name and Description: %+v", err)
+			return diag.Errorf("error updating Sonarqube Portfolio Name and Description: %+v", err)
 		}
-		defer resp.Body.Close()
 	}
 
 	if d.HasChanges("selection_mode", "branch", "tags", "regexp", "selected_projects") {
-		err := portfolioSetSelectionMode(d, m, m.(*ProviderConfiguration).sonarQubeURL)
+		err := portfolioSetSelectionMode(ctx, d, m)
 		if err != nil {
-			return fmt.Errorf("error updating Sonarqube selection mode: %+v", err)
+			return diag.Errorf("error updating Sonarqube selection mode: %+v", err)
+		}
+	}
+
+	if d.HasChange("children") {
+		if err := synchronizePortfolioChildren(ctx, d, m); err != nil {
+			return diag.Errorf("error updating Sonarqube Portfolio children: %+v", err)
 		}
 	}
 
-	return resourceSonarqubePortfolioRead(d, m)
+	return resourceSonarqubePortfolioRead(ctx, d, m)
 }
 
-func resourceSonarqubePortfolioDelete(d *schema.ResourceData, m interface{}) error {
-	if err := checkPortfolioSupport(m.(*ProviderConfiguration)); err != nil {
-		return err
+func resourceSonarqubePortfolioDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if err := m.(*ProviderConfiguration).checkCapability(capabilityPortfolios); err != nil {
+		return diag.FromErr(err)
 	}
 
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/views/delete"
-	sonarQubeURL.RawQuery = url.Values{
-		"key": []string{d.Id()},
-	}.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusNoContent,
-		"resourceSonarqubePortfolioDelete",
-	)
+	err := m.(*ProviderConfiguration).client.Views.Delete(ctx, d.Id())
 	if err != nil {
-		return err
+		return diag.Errorf("resourceSonarqubePortfolioDelete: Failed to delete portfolio: %+v", err)
 	}
-	defer resp.Body.Close()
 
 	return nil
 }
 
-func resourceSonarqubePortfolioImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
-	if err := resourceSonarqubePortfolioRead(d, m); err != nil {
-		return nil, err
+func resourceSonarqubePortfolioImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	if diags := resourceSonarqubePortfolioRead(ctx, d, m); diags.HasError() {
+		return nil, diagnosticsToError(diags)
 	}
 	return []*schema.ResourceData{d}, nil
 }
 
-func updateResourceDataFromPortfolioReadResponse(d *schema.ResourceData, portfolioReadResponse *Portfolio) {
+func updateResourceDataFromPortfolioReadResponse(d *schema.ResourceData, portfolioReadResponse *client.Portfolio) {
 	d.SetId(portfolioReadResponse.Key)
 	d.Set("key", portfolioReadResponse.Key)
 	d.Set("name", portfolioReadResponse.Name)
@@ -453,33 +387,14 @@ func updateResourceDataFromPortfolioReadResponse(d *schema.ResourceData, portfol
 	if len(portfolioReadResponse.SelectedProjects) > 0 {
 		d.Set("selected_projects", flattenReadPortfolioSelectedProjectsResponse(&portfolioReadResponse.SelectedProjects))
 	}
-}
 
-func readPortfolioFromApi(d *schema.ResourceData, m interface{}) (*Portfolio, error) {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/views/show"
-
-	sonarQubeURL.RawQuery = url.Values{
-		"key": []string{d.Id()},
-	}.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"GET",
-		sonarQubeURL.String(),
-		http.StatusOK,
-		"readPortfolioFromApi",
-	)
-	if err != nil {
-		return nil, fmt.Errorf("readPortfolioFromApi: Failed to call api/views/show: %+v", err)
-	}
-	defer resp.Body.Close()
+	d.Set("children", flattenReadPortfolioSubViewsResponse(portfolioReadResponse.SubViews))
+}
 
-	// Decode response into struct
-	portfolioReadResponse := Portfolio{}
-	err = json.NewDecoder(resp.Body).Decode(&portfolioReadResponse)
+func readPortfolioFromApi(ctx context.Context, d *schema.ResourceData, m interface{}) (*client.Portfolio, error) {
+	portfolioReadResponse, err := m.(*ProviderConfiguration).client.Views.Show(ctx, d.Id())
 	if err != nil {
-		return nil, fmt.Errorf("readPortfolioFromApi: Failed to decode json into struct: %+v", err)
+		return nil, fmt.Errorf("readPortfolioFromApi: Failed to call api/views/show: %w", err)
 	}
 
 	// Make sure the order is always the same for when we are comparing lists of conditions
@@ -487,10 +402,10 @@ func readPortfolioFromApi(d *schema.ResourceData, m interface{}) (*Portfolio, er
 		return portfolioReadResponse.SelectedProjects[i].ProjectKey < portfolioReadResponse.SelectedProjects[j].ProjectKey
 	})
 
-	return &portfolioReadResponse, nil
+	return portfolioReadResponse, nil
 }
 
-func synchronizeSelectedProjects(d *schema.ResourceData, m interface{}, apiPortfolioSelectedProjects *[]PortfolioProject) error {
+func synchronizeSelectedProjects(ctx context.Context, d *schema.ResourceData, m interface{}, apiPortfolioSelectedProjects *[]client.PortfolioProject) error {
 	portfolioSelectedProjects := d.Get("selected_projects").(*schema.Set).List()
 
 	// Make sure the order is always the same for when we are comparing lists of projects
@@ -500,7 +415,7 @@ func synchronizeSelectedProjects(d *schema.ResourceData, m interface{}, apiPortf
 
 	// Determine which conditions have been added or changed and update those
 	for _, project := range portfolioSelectedProjects {
-		err := addOrUpdateSelectedProject(d, m, apiPortfolioSelectedProjects, project)
+		err := addOrUpdateSelectedProject(ctx, d, m, apiPortfolioSelectedProjects, project)
 		if err != nil {
 			return err
 		}
@@ -508,7 +423,7 @@ func synchronizeSelectedProjects(d *schema.ResourceData, m interface{}, apiPortf
 
 	// Determine if any conditions have been removed and delete them
 	portfolioKey := d.Get("key").(string)
-	err := removeDeletedSelectedProject(portfolioKey, apiPortfolioSelectedProjects, portfolioSelectedProjects, m)
+	err := removeDeletedSelectedProject(ctx, portfolioKey, apiPortfolioSelectedProjects, portfolioSelectedProjects, m)
 	if err != nil {
 		return err
 	}
@@ -516,7 +431,7 @@ func synchronizeSelectedProjects(d *schema.ResourceData, m interface{}, apiPortf
 	return nil
 }
 
-func addOrUpdateSelectedProject(d *schema.ResourceData, m interface{}, apiPortfolioSelectedProjects *[]PortfolioProject, project interface{}) error {
+func addOrUpdateSelectedProject(ctx context.Context, d *schema.ResourceData, m interface{}, apiPortfolioSelectedProjects *[]client.PortfolioProject, project interface{}) error {
 	portfolioKey := d.Get("key").(string)
 	projectKey := project.(map[string]interface{})["project_key"].(string)
 
@@ -531,7 +446,7 @@ func addOrUpdateSelectedProject(d *schema.ResourceData, m interface{}, apiPortfo
 	for _, apiProject := range *apiPortfolioSelectedProjects {
 		if projectKey == apiProject.ProjectKey {
 			if !stringSlicesEqual(selectedBranches, apiProject.SelectedBranches, true) {
-				err := updateSelectedProject(portfolioKey, projectKey, selectedBranches, apiProject.SelectedBranches, m)
+				err := updateSelectedProject(ctx, portfolioKey, projectKey, selectedBranches, apiProject.SelectedBranches, m)
 				if err != nil {
 					return fmt.Errorf("addOrUpdateSelectedProject: Failed to update project '%s': %+v", projectKey, err)
 				}
@@ -542,110 +457,53 @@ func addOrUpdateSelectedProject(d *schema.ResourceData, m interface{}, apiPortfo
 	}
 
 	// Add the project because it does not already exist
-	err := addSelectedProject(portfolioKey, projectKey, selectedBranches, m)
+	err := addSelectedProject(ctx, portfolioKey, projectKey, selectedBranches, m)
 	if err != nil {
 		return fmt.Errorf("addOrUpdateCondition: Failed to add project '%s': %+v", projectKey, err)
 	}
 	return nil
 }
 
-func addSelectedProject(portfolioKey, projectKey string, selectedBranches []string, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/views/add_project"
-
-	sonarQubeURL.RawQuery = url.Values{
-		"key":     []string{portfolioKey},
-		"project": []string{projectKey},
-	}.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusNoContent, // For some reason this endpoint returns 204 on success...
-		"addSelectedProject",
-	)
+func addSelectedProject(ctx context.Context, portfolioKey, projectKey string, selectedBranches []string, m interface{}) error {
+	err := m.(*ProviderConfiguration).client.Views.AddProject(ctx, portfolioKey, projectKey)
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
 
 	for _, branch := range selectedBranches {
-		addSelectedProjectBranch(portfolioKey, projectKey, branch, m)
+		addSelectedProjectBranch(ctx, portfolioKey, projectKey, branch, m)
 	}
 
 	return nil
 }
 
-func updateSelectedProject(portfolioKey, projectKey string, selectedBranches, apiSelectedBranches []string, m interface{}) error {
+func updateSelectedProject(ctx context.Context, portfolioKey, projectKey string, selectedBranches, apiSelectedBranches []string, m interface{}) error {
 	// For each branch in the terraform schema, make sure they are also in SonarQube
 	for _, branch := range selectedBranches {
 		if !slices.Contains(apiSelectedBranches, branch) {
-			addSelectedProjectBranch(portfolioKey, projectKey, branch, m)
+			addSelectedProjectBranch(ctx, portfolioKey, projectKey, branch, m)
 		}
 	}
 
 	// For each branch in SonarQube, ensure it exists in the terraform schema, otherwise remove it
 	for _, branch := range apiSelectedBranches {
 		if !slices.Contains(selectedBranches, branch) {
-			deleteSelectedProjectBranch(portfolioKey, projectKey, branch, m)
+			deleteSelectedProjectBranch(ctx, portfolioKey, projectKey, branch, m)
 		}
 	}
 
 	return nil
 }
 
-func addSelectedProjectBranch(portfolioKey, projectKey, branch string, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/views/add_project_branch"
-
-	sonarQubeURL.RawQuery = url.Values{
-		"key":     []string{portfolioKey},
-		"project": []string{projectKey},
-		"branch":  []string{branch},
-	}.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusNoContent, // For some reason this endpoint returns 204 on success...
-		"addSelectedProject",
-	)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	return nil
+func addSelectedProjectBranch(ctx context.Context, portfolioKey, projectKey, branch string, m interface{}) error {
+	return m.(*ProviderConfiguration).client.Views.AddProjectBranch(ctx, portfolioKey, projectKey, branch)
 }
 
-func deleteSelectedProjectBranch(portfolioKey, projectKey, branch string, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/views/remove_project_branch"
-
-	sonarQubeURL.RawQuery = url.Values{
-		"key":     []string{portfolioKey},
-		"project": []string{projectKey},
-		"branch":  []string{branch},
-	}.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusNoContent, // For some reason this endpoint returns 204 on success...
-		"addSelectedProject",
-	)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	return nil
+func deleteSelectedProjectBranch(ctx context.Context, portfolioKey, projectKey, branch string, m interface{}) error {
+	return m.(*ProviderConfiguration).client.Views.RemoveProjectBranch(ctx, portfolioKey, projectKey, branch)
 }
 
-func removeDeletedSelectedProject(portfolioKey string, apiPortfolioSelectedProjects *[]PortfolioProject, portfolioSelectedProjects []interface{}, m interface{}) error {
+func removeDeletedSelectedProject(ctx context.Context, portfolioKey string, apiPortfolioSelectedProjects *[]client.PortfolioProject, portfolioSelectedProjects []interface{}, m interface{}) error {
 	for _, apiProject := range *apiPortfolioSelectedProjects {
 		found := false
 		for _, project := range portfolioSelectedProjects {
@@ -655,7 +513,7 @@ func removeDeletedSelectedProject(portfolioKey string, apiPortfolioSelectedProje
 			}
 		}
 		if !found {
-			err := deleteSelectedProject(portfolioKey, apiProject.ProjectKey, m)
+			err := deleteSelectedProject(ctx, portfolioKey, apiProject.ProjectKey, m)
 			if err != nil {
 				return fmt.Errorf("removeDeletedSelectedProject: Failed to delete project from portfolio '%s': %+v", apiProject.ProjectKey, err)
 			}
@@ -664,30 +522,11 @@ func removeDeletedSelectedProject(portfolioKey string, apiPortfolioSelectedProje
 	return nil
 }
 
-func deleteSelectedProject(portfolioKey, projectKey string, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/views/remove_project"
-	sonarQubeURL.RawQuery = url.Values{
-		"key":     []string{portfolioKey},
-		"project": []string{projectKey},
-	}.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusNoContent,
-		"deleteCondition",
-	)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	return nil
+func deleteSelectedProject(ctx context.Context, portfolioKey, projectKey string, m interface{}) error {
+	return m.(*ProviderConfiguration).client.Views.RemoveProject(ctx, portfolioKey, projectKey)
 }
 
-func flattenReadPortfolioSelectedProjectsResponse(input *[]PortfolioProject) []interface{} {
+func flattenReadPortfolioSelectedProjectsResponse(input *[]client.PortfolioProject) []interface{} {
 	if input == nil || len(*input) == 0 {
 		return make([]interface{}, 0)
 	}
@@ -705,3 +544,93 @@ func flattenReadPortfolioSelectedProjectsResponse(input *[]PortfolioProject) []i
 
 	return flatSelectedProjects
 }
+
+// synchronizePortfolioChildren adds and removes the children of a portfolio to match the children block. A child whose
+// type changed is removed first, then added again. Children whose qualifier the block cannot express are left alone.
+func synchronizePortfolioChildren(ctx context.Context, d *schema.ResourceData, m interface{}) error {
+	portfolioReadResponse, err := readPortfolioFromApi(ctx, d, m)
+	if err != nil {
+		return err
+	}
+
+	portfolioKey := d.Get("key").(string)
+	children := map[string]string{}
+	for _, child := range d.Get("children").(*schema.Set).List() {
+		children[child.(map[string]interface{})["key"].(string)] = child.(map[string]interface{})["type"].(string)
+	}
+
+	existing := map[string]string{}
+	for _, subView := range portfolioReadResponse.SubViews {
+		childType, ok := portfolioChildTypes[subView.Qualifier]
+		if !ok {
+			log.Printf("[WARN][synchronizePortfolioChildren] Ignoring child '%s' of portfolio '%s' with unknown qualifier '%s'", subView.Key, portfolioKey, subView.Qualifier)
+			continue
+		}
+		if children[subView.Key] == childType {
+			existing[subView.Key] = childType
+			continue
+		}
+		if err := removePortfolioChild(ctx, portfolioKey, subView.Key, childType, m); err != nil {
+			return fmt.Errorf("synchronizePortfolioChildren: Failed to remove child '%s': %+v", subView.Key, err)
+		}
+	}
+
+	keys := make([]string, 0, len(children))
+	for key := range children {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if _, ok := existing[key]; ok {
+			continue
+		}
+		if err := addPortfolioChild(ctx, portfolioKey, key, children[key], m); err != nil {
+			return fmt.Errorf("synchronizePortfolioChildren: Failed to add child '%s': %+v", key, err)
+		}
+	}
+	return nil
+}
+
+func addPortfolioChild(ctx context.Context, portfolioKey, childKey, childType string, m interface{}) error {
+	viewsService := m.(*ProviderConfiguration).client.Views
+	switch childType {
+	case "portfolio":
+		return viewsService.AddPortfolio(ctx, portfolioKey, childKey)
+	case "local_view":
+		return viewsService.AddLocalView(ctx, portfolioKey, childKey)
+	case "application":
+		return viewsService.AddApplication(ctx, portfolioKey, childKey)
+	default:
+		return fmt.Errorf("addPortfolioChild: unknown child type '%s'", childType)
+	}
+}
+
+func removePortfolioChild(ctx context.Context, portfolioKey, childKey, childType string, m interface{}) error {
+	viewsService := m.(*ProviderConfiguration).client.Views
+	switch childType {
+	case "portfolio":
+		return viewsService.RemovePortfolio(ctx, portfolioKey, childKey)
+	case "local_view":
+		return viewsService.RemoveLocalView(ctx, portfolioKey, childKey)
+	case "application":
+		return viewsService.RemoveApplication(ctx, portfolioKey, childKey)
+	default:
+		return fmt.Errorf("removePortfolioChild: unknown child type '%s'", childType)
+	}
+}
+
+func flattenReadPortfolioSubViewsResponse(subViews []client.PortfolioSubView) []interface{} {
+	flatChildren := make([]interface{}, 0, len(subViews))
+	for _, subView := range subViews {
+		// Only the qualifiers the children block can manage are read
+		childType, ok := portfolioChildTypes[subView.Qualifier]
+		if !ok {
+			continue
+		}
+		flatChildren = append(flatChildren, map[string]interface{}{
+			"key":  subView.Key,
+			"type": childType,
+		})
+	}
+	return flatChildren
+}
@@ -1,12 +1,15 @@
 package sonarqube
 
 import (
+	"context"
 	"fmt"
 	"regexp"
 	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/jdamata/terraform-provider-sonarqube/internal/client"
 )
 
 func init() {
@@ -21,9 +24,7 @@ func testSweepSonarqubePortfolioSweeper(r string) error {
 	return nil
 }
 func testAccPreCheckPortfolioSupport(t *testing.T) {
-	if err := checkPortfolioSupport(testAccProvider.Meta().(*ProviderConfiguration)); err != nil {
-		t.Skipf("Skipping test of unsupported feature (Portfolio)")
-	}
+	testAccPreCheckCapability(t, capabilityPortfolios)
 }
 
 func testAccSonarqubePortfolioBasicConfig(rnd string, key string, name string, description string, visibility string) string {
@@ -82,8 +83,8 @@ func TestAccSonarqubePortfolioBasic(t *testing.T) {
 	name := "sonarqube_portfolio." + rnd
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t); testAccPreCheckPortfolioSupport(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t); testAccPreCheckPortfolioSupport(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubePortfolioBasicConfig(rnd, "testAccSonarqubePortfolioKey", "testAccSonarqubePortfolioName", "testAccSonarqubePortfolioDescription", "public"),
@@ -116,8 +117,8 @@ func TestAccSonarqubePortfolioNameUpdate(t *testing.T) {
 	name := "sonarqube_portfolio." + rnd
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t); testAccPreCheckPortfolioSupport(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t); testAccPreCheckPortfolioSupport(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubePortfolioBasicConfig(rnd, "testAccSonarqubePortfolioKey", "oldName", "testAccSonarqubePortfolioDescription", "public"),
@@ -144,7 +145,7 @@ func TestAccSonarqubePortfolioDescriptionUpdate(t *testing.T) {
 	resource.Test(t, resource.TestCase{
 		PreCheck: func() { testAccPreCheck(t); testAccPreCheckPortfolioSupport(t) },
 
-		Providers: testAccProviders,
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubePortfolioBasicConfig(rnd, "testAccSonarqubePortfolioKey", "testAccSonarqubePortfolioName", "oldDescription", "public"),
@@ -169,8 +170,8 @@ func TestAccSonarqubePortfolioVisibilityUpdate(t *testing.T) {
 	name := "sonarqube_portfolio." + rnd
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t); testAccPreCheckPortfolioSupport(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t); testAccPreCheckPortfolioSupport(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubePortfolioBasicConfig(rnd, "testAccSonarqubePortfolioKey", "testAccSonarqubePortfolioName", "testAccSonarqubePortfolioDescription", "public"),
@@ -194,8 +195,8 @@ func TestAccSonarqubePortfolioVisibilityError(t *testing.T) {
 	rnd := generateRandomResourceName()
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t); testAccPreCheckPortfolioSupport(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t); testAccPreCheckPortfolioSupport(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config:      testAccSonarqubePortfolioBasicConfig(rnd, "testAccSonarqubePortfolioKey", "testAccSonarqubePortfolioName", "testAccSonarqubePortfolioDescription", "badValue"),
@@ -205,12 +206,32 @@ func TestAccSonarqubePortfolioVisibilityError(t *testing.T) {
 	})
 }
 
+func TestAccSonarqubePortfolioUnsupportedEdition(t *testing.T) {
+	rnd := generateRandomResourceName()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// The plan fails before anything is created
+				Config: `provider "sonarqube" {
+					installed_edition = "Community"
+				}
+				` + testAccSonarqubePortfolioBasicConfig(rnd, "testAccSonarqubePortfolioKey", "testAccSonarqubePortfolioName", "testAccSonarqubePortfolioDescription", "public"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("does not support portfolios"),
+			},
+		},
+	})
+}
+
 func TestAccSonarqubePortfolioSelectionModeError(t *testing.T) {
 	rnd := generateRandomResourceName()
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t); testAccPreCheckPortfolioSupport(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t); testAccPreCheckPortfolioSupport(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config:      testAccSonarqubePortfolioConfigSelectionMode(rnd, "testAccSonarqubePortfolioKey", "testAccSonarqubePortfolioName", "testAccSonarqubePortfolioDescription", "public", "badValue"),
@@ -225,8 +246,8 @@ func TestAccSonarqubePortfolioSelectionModeNone(t *testing.T) {
 	name := "sonarqube_portfolio." + rnd
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t); testAccPreCheckPortfolioSupport(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t); testAccPreCheckPortfolioSupport(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubePortfolioConfigSelectionMode(rnd, "testAccSonarqubePortfolioKey", "testAccSonarqubePortfolioName", "testAccSonarqubePortfolioDescription", "public", "NONE"),
@@ -254,8 +275,8 @@ func TestAccSonarqubePortfolioSelectionModeTags(t *testing.T) {
 	tags := []string{"tag1", "tag2"}
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t); testAccPreCheckPortfolioSupport(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t); testAccPreCheckPortfolioSupport(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubePortfolioConfigSelectionModeTags(rnd, "testAccSonarqubePortfolioKey", "testAccSonarqubePortfolioName", "testAccSonarqubePortfolioDescription", "public", "TAGS", tags),
@@ -288,8 +309,8 @@ func TestAccSonarqubePortfolioSelectionModeRegexp(t *testing.T) {
 	name := "sonarqube_portfolio." + rnd
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t); testAccPreCheckPortfolioSupport(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t); testAccPreCheckPortfolioSupport(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubePortfolioConfigSelectionModeRegex(rnd, "testAccSonarqubePortfolioKey", "testAccSonarqubePortfolioName", "testAccSonarqubePortfolioDescription", "public", "REGEXP", "regexp1"),
@@ -321,8 +342,8 @@ func TestAccSonarqubePortfolioSelectionModeUpdate(t *testing.T) {
 	tags := []string{"tag1", "tag2"}
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t); testAccPreCheckPortfolioSupport(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t); testAccPreCheckPortfolioSupport(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubePortfolioBasicConfig(rnd, "testAccSonarqubePortfolioKey", "testAccSonarqubePortfolioName", "testAccSonarqubePortfolioDescription", "public"),
@@ -402,8 +423,8 @@ func TestAccSonarqubePortfolioManualProjectsReplaceProject(t *testing.T) {
 	}
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t); testAccPreCheckPortfolioSupport(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t); testAccPreCheckPortfolioSupport(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: configBefore,
@@ -462,8 +483,8 @@ func TestAccSonarqubePortfolioManualProjectsRemoveSelectedBranches(t *testing.T)
 	}
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t); testAccPreCheckPortfolioSupport(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t); testAccPreCheckPortfolioSupport(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: configBefore,
@@ -541,8 +562,8 @@ func TestAccSonarqubePortfolioManualAddAndRemoveMultipleProjects(t *testing.T) {
 		),
 	}
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t); testAccPreCheckPortfolioSupport(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t); testAccPreCheckPortfolioSupport(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: configBefore,
@@ -593,8 +614,8 @@ func TestAccSonarqubePortfolioManualImport(t *testing.T) {
 	)
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t); testAccPreCheckPortfolioSupport(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t); testAccPreCheckPortfolioSupport(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: config,
@@ -609,3 +630,149 @@ func TestAccSonarqubePortfolioManualImport(t *testing.T) {
 		},
 	})
 }
+
+func testAccSonarqubePortfolioChildrenConfig(rnd string, children string) string {
+	return fmt.Sprintf(`
+		resource "sonarqube_portfolio" "%[1]s_team_a" {
+		  key         = "testAccSonarqubePortfolioTeamA"
+		  name        = "Team A"
+		  description = "Team A"
+		}
+
+		resource "sonarqube_portfolio" "%[1]s_team_b" {
+		  key         = "testAccSonarqubePortfolioTeamB"
+		  name        = "Team B"
+		  description = "Team B"
+		}
+
+		resource "sonarqube_application" "%[1]s" {
+		  key  = "testAccSonarqubePortfolioApplication"
+		  name = "Application"
+		}
+
+		resource "sonarqube_portfolio" "%[1]s" {
+		  key         = "testAccSonarqubePortfolioBusinessUnit"
+		  name        = "Business unit"
+		  description = "Business unit"
+		  %[2]s
+		}
+		`, rnd, children)
+}
+
+func TestAccSonarqubePortfolioChildren(t *testing.T) {
+	rnd := generateRandomResourceName()
+	name := "sonarqube_portfolio." + rnd
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckPortfolioSupport(t)
+			testAccPreCheckApplicationSupport(t)
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSonarqubePortfolioChildrenConfig(rnd, fmt.Sprintf(`
+		  children {
+		    key  = sonarqube_portfolio.%[1]s_team_a.key
+		    type = "portfolio"
+		  }
+		  children {
+		    key  = sonarqube_portfolio.%[1]s_team_b.key
+		    type = "local_view"
+		  }
+		  children {
+		    key  = sonarqube_application.%[1]s.key
+		    type = "application"
+		  }`, rnd)),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(name, "children.#", "3"),
+					resource.TestCheckTypeSetElemNestedAttrs(name, "children.*", map[string]string{"key": "testAccSonarqubePortfolioTeamA", "type": "portfolio"}),
+					resource.TestCheckTypeSetElemNestedAttrs(name, "children.*", map[string]string{"key": "testAccSonarqubePortfolioTeamB", "type": "local_view"}),
+					resource.TestCheckTypeSetElemNestedAttrs(name, "children.*", map[string]string{"key": "testAccSonarqubePortfolioApplication", "type": "application"}),
+				),
+			},
+			{
+				ResourceName:      name,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				// Team A changes from a reference to a local view, team B and the application are removed
+				Config: testAccSonarqubePortfolioChildrenConfig(rnd, fmt.Sprintf(`
+		  children {
+		    key  = sonarqube_portfolio.%[1]s_team_a.key
+		    type = "local_view"
+		  }`, rnd)),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(name, "children.#", "1"),
+					resource.TestCheckTypeSetElemNestedAttrs(name, "children.*", map[string]string{"key": "testAccSonarqubePortfolioTeamA", "type": "local_view"}),
+				),
+			},
+			{
+				Config: testAccSonarqubePortfolioChildrenConfig(rnd, "children = []"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(name, "children.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccSonarqubePortfolioChildrenUnmanaged(t *testing.T) {
+	rnd := generateRandomResourceName()
+	name := "sonarqube_portfolio." + rnd
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckPortfolioSupport(t)
+			testAccPreCheckApplicationSupport(t)
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSonarqubePortfolioChildrenConfig(rnd, ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(name, "children.#", "0"),
+				),
+			},
+			{
+				// Without a children block, a child added outside of Terraform is kept
+				PreConfig: func() {
+					err := testAccCapabilityProvider.Meta().(*ProviderConfiguration).client.Views.AddApplication(context.Background(),
+						"testAccSonarqubePortfolioBusinessUnit", "testAccSonarqubePortfolioApplication")
+					if err != nil {
+						t.Fatalf("failed to add the application to the portfolio: %+v", err)
+					}
+				},
+				Config: testAccSonarqubePortfolioChildrenConfig(rnd, ""),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(name, "children.#", "1"),
+					resource.TestCheckTypeSetElemNestedAttrs(name, "children.*", map[string]string{"key": "testAccSonarqubePortfolioApplication", "type": "application"}),
+				),
+			},
+		},
+	})
+}
+
+func TestFlattenReadPortfolioSubViewsResponse(t *testing.T) {
+	children := flattenReadPortfolioSubViewsResponse([]client.PortfolioSubView{
+		{Key: "team-a", Qualifier: "VW"},
+		{Key: "unknown", Qualifier: "TRK"},
+		{Key: "application", Qualifier: "APP"},
+	})
+	if len(children) != 2 {
+		t.Fatalf("flattenReadPortfolioSubViewsResponse() returned %d children, want 2: %v", len(children), children)
+	}
+	for _, child := range children {
+		if child.(map[string]interface{})["key"] == "unknown" {
+			t.Errorf("flattenReadPortfolioSubViewsResponse() returned the child with an unknown qualifier: %v", children)
+		}
+	}
+}
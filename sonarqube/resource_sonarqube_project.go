@@ -1,63 +1,47 @@
 package sonarqube
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
-	"net/http"
-	"net/url"
-	"strings"
+	"log"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/jdamata/terraform-provider-sonarqube/internal/client"
 )
 
-// Project used in CreateProjectResponse
-type Project struct {
-	Key       string `json:"key"`
-	Name      string `json:"name"`
-	Qualifier string `json:"qualifier"`
-}
-
-// GetProject for unmarshalling response body from getting project details
-type GetProject struct {
-	Component ProjectComponent `json:"component"`
-}
-
-// ProjectComponents used in GetProject
-type ProjectComponent struct {
-	Key          string   `json:"key"`
-	Name         string   `json:"name"`
-	Description  string   `json:"description"`
-	Qualifier    string   `json:"qualifier"`
-	AnalysisDate string   `json:"analysisDate"`
-	Version      string   `json:"version"`
-	Tags         []string `json:"tags,omitempty"`
-	Visibility   string   `json:"visibility"`
-}
-
-// CreateProjectResponse for unmarshalling response body of project creation
-type CreateProjectResponse struct {
-	Project Project `json:"project"`
-}
-
 // Returns the resource represented by this file.
 func resourceSonarqubeProject() *schema.Resource {
 	return &schema.Resource{
-		Description: "Provides a Sonarqube Project resource. This can be used to create and manage Sonarqube Project.",
-		Create:      resourceSonarqubeProjectCreate,
-		Read:        resourceSonarqubeProjectRead,
-		Update:      resourceSonarqubeProjectUpdate,
-		Delete:      resourceSonarqubeProjectDelete,
+		Description:   "Provides a Sonarqube Project resource. This can be used to create and manage Sonarqube Project.",
+		CreateContext: resourceSonarqubeProjectCreate,
+		ReadContext:   resourceSonarqubeProjectRead,
+		UpdateContext: resourceSonarqubeProjectUpdate,
+		DeleteContext: resourceSonarqubeProjectDelete,
 		Importer: &schema.ResourceImporter{
-			State: resourceSonarqubeProjectImport,
+			StateContext: resourceSonarqubeProjectImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Delete:  schema.DefaultTimeout(20 * time.Minute),
+			Default: schema.DefaultTimeout(5 * time.Minute),
 		},
+		CustomizeDiff: customdiff.If(func(_ context.Context, d *schema.ResourceDiff, _ interface{}) bool {
+			return d.Get("export_on_delete").(bool)
+		}, requireCapability(capabilityProjectDump)),
 
 		// Define the fields of this schema.
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:        schema.TypeString,
 				Required:    true,
-				ForceNew:    true,
-				Description: "The name of the Project to create",
+				Description: "The name of the Project to create. Sonarqube has no web service to rename a project, so a change of the name of an existing project is suppressed in the plan: the project is neither renamed nor replaced. Sonarqube renames it when an analysis sets `sonar.projectName`, Terraform then reads the new name.",
+				// Sonarqube has no web service to rename a project, see the description
+				DiffSuppressFunc: func(_, _, _ string, d *schema.ResourceData) bool {
+					return d.Id() != ""
+				},
 			},
 			"project": {
 				Type:        schema.TypeString,
@@ -79,6 +63,18 @@ func resourceSonarqubeProject() *schema.Resource {
 				},
 				Description: "A list of tags to put on the project.",
 			},
+			"deletion_protection": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether Terraform is prevented from deleting the project. The project can only be deleted, or replaced, once this is set to `false` and applied. Defaults to `false`.",
+			},
+			"export_on_delete": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether the project is exported to a dump file on the Sonarqube server before it is deleted. The deletion waits for the export, and fails when the export fails. Requires the Enterprise or Data Center edition. Defaults to `false`.",
+			},
 			"setting": {
 				Type:        schema.TypeList,
 				Optional:    true,
@@ -121,119 +117,80 @@ func resourceSonarqubeProject() *schema.Resource {
 	}
 }
 
-func projectSetTags(d *schema.ResourceData, m interface{}, sonarQubeURL url.URL) error {
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/project_tags/set"
-
-	// TODO: Create a helper file for convertListToCSV or something. This is used in Portfolio too
+func projectSetTags(ctx context.Context, d *schema.ResourceData, m interface{}) error {
 	var tags []string
 	for _, v := range d.Get("tags").([]interface{}) {
 		tags = append(tags, fmt.Sprint(v))
 	}
-	tagsCSV := strings.Trim(strings.Join(strings.Fields(fmt.Sprint(tags)), ","), "[]")
-	sonarQubeURL.RawQuery = url.Values{
-		"project": []string{d.Get("project").(string)},
-		"tags":    []string{tagsCSV},
-	}.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusNoContent,
-		"resourceSonarqubePortfolioCreate",
-	)
+
+	err := m.(*ProviderConfiguration).client.ProjectTags.Set(ctx, d.Get("project").(string), tags)
 	if err != nil {
-		return err
+		return fmt.Errorf("projectSetTags: Failed to set project tags: %+v", err)
 	}
-	defer resp.Body.Close()
 
 	return nil
 }
 
-func resourceSonarqubeProjectCreate(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/projects/create"
-
-	sonarQubeURL.RawQuery = url.Values{
-		"name":       []string{d.Get("name").(string)},
-		"project":    []string{d.Get("project").(string)},
-		"visibility": []string{d.Get("visibility").(string)},
-	}.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusOK,
-		"resourceSonarqubeProjectCreate",
-	)
+func resourceSonarqubeProjectCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	project, err := m.(*ProviderConfiguration).client.Projects.Create(ctx, client.ProjectsCreateRequest{
+		Name:       d.Get("name").(string),
+		Project:    d.Get("project").(string),
+		Visibility: d.Get("visibility").(string),
+	})
 	if err != nil {
-		return err
+		return diag.Errorf("resourceSonarqubeProjectCreate: Failed to create project: %+v", err)
 	}
-	defer resp.Body.Close()
 
-	err = projectSetTags(d, m, m.(*ProviderConfiguration).sonarQubeURL)
+	err = projectSetTags(ctx, d, m)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	// Decode response into struct
-	projectResponse := CreateProjectResponse{}
-	err = json.NewDecoder(resp.Body).Decode(&projectResponse)
-	if err != nil {
-		return fmt.Errorf("resourceSonarqubeProjectCreate: Failed to decode json into struct: %+v", err)
-	}
-
-	d.SetId(projectResponse.Project.Key)
+	d.SetId(project.Key)
 
 	// Set settings
-	_, err = synchronizeSettings(d, m)
+	_, err = synchronizeSettings(ctx, d, m)
 	if err != nil {
-		return fmt.Errorf("resourceSonarqubeProjectCreate: Failed to sync project settings: %+v", err)
+		return diag.Errorf("resourceSonarqubeProjectCreate: Failed to sync project settings: %+v", err)
 	}
 
-	return resourceSonarqubeProjectRead(d, m)
+	return resourceSonarqubeProjectRead(ctx, d, m)
 }
 
-func resourceSonarqubeProjectRead(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/components/show"
-	sonarQubeURL.RawQuery = url.Values{
-		"component": []string{d.Get("project").(string)},
-	}.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"GET",
-		sonarQubeURL.String(),
-		http.StatusOK,
-		"resourceSonarqubeProjectRead",
-	)
-	if err != nil {
-		return err
+func resourceSonarqubeProjectRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if diags := readProject(ctx, d, m); diags.HasError() || d.Id() == "" {
+		return diags
 	}
-	defer resp.Body.Close()
 
-	// Decode response into struct
-	projectReadResponse := GetProject{}
+	// Not stored by Sonarqube, keep the configured values or default them on import
+	d.Set("deletion_protection", d.Get("deletion_protection").(bool))
+	d.Set("export_on_delete", d.Get("export_on_delete").(bool))
+
+	return nil
+}
 
-	err = json.NewDecoder(resp.Body).Decode(&projectReadResponse)
+// Reads the attributes stored by Sonarqube, shared with the project data source
+func readProject(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	project, err := m.(*ProviderConfiguration).client.Components.Show(ctx, d.Get("project").(string))
 	if err != nil {
-		return fmt.Errorf("resourceSonarqubeProjectRead: Failed to decode json into struct: %+v", err)
+		if client.IsNotFound(err) {
+			removeResourceFromState(d, "resourceSonarqubeProjectRead")
+			return nil
+		}
+		return diag.Errorf("readProject: Failed to read project: %+v", err)
 	}
 
-	d.SetId(projectReadResponse.Component.Key)
-	d.Set("name", projectReadResponse.Component.Name)
-	d.Set("project", projectReadResponse.Component.Key)
-	d.Set("visibility", projectReadResponse.Component.Visibility)
+	d.SetId(project.Key)
+	d.Set("name", project.Name)
+	d.Set("project", project.Key)
+	d.Set("visibility", project.Visibility)
 
 	// Get settings
-	var projectSettings []Setting
 	if _, ok := d.GetOk("setting"); ok {
 		componentSettings := d.Get("setting").([]interface{})
-		projectSettings, err = getComponentSettings(d.Id(), m)
+		projectSettings, err := getComponentSettings(ctx, d.Id(), m)
 		if err != nil {
-			return fmt.Errorf("resourceSonarqubeProjectRead: Failed to read project settings: %+v", err)
+			return diag.Errorf("readProject: Failed to read project settings: %+v", err)
 		}
 
 		settings := make([]interface{}, len(componentSettings))
@@ -242,7 +199,7 @@ func resourceSonarqubeProjectRead(d *schema.ResourceData, m interface{}) error {
 				key := s.(map[string]interface{})["key"].(string)
 				for _, apiSetting := range projectSettings {
 					if key == apiSetting.Key {
-						settings[i] = apiSetting.ToMap()
+						settings[i] = flattenSetting(apiSetting)
 					}
 				}
 			}
@@ -250,40 +207,28 @@ func resourceSonarqubeProjectRead(d *schema.ResourceData, m interface{}) error {
 		d.Set("setting", settings)
 	}
 
-	if len(projectReadResponse.Component.Tags) > 0 {
-		d.Set("tags", projectReadResponse.Component.Tags)
+	if len(project.Tags) > 0 {
+		d.Set("tags", project.Tags)
 	}
 
 	return nil
 }
 
-func resourceSonarqubeProjectUpdate(d *schema.ResourceData, m interface{}) error {
+func resourceSonarqubeProjectUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	sonarQubeClient := m.(*ProviderConfiguration).client
+
 	// handle default updates (api/users/update)
 	if d.HasChange("visibility") {
-		sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-		sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/projects/update_visibility"
-		sonarQubeURL.RawQuery = url.Values{
-			"project":    []string{d.Get("project").(string)},
-			"visibility": []string{d.Get("visibility").(string)},
-		}.Encode()
-
-		resp, err := httpRequestHelper(
-			m.(*ProviderConfiguration).httpClient,
-			"POST",
-			sonarQubeURL.String(),
-			http.StatusNoContent,
-			"resourceSonarqubeProjectUpdate",
-		)
+		err := sonarQubeClient.Projects.UpdateVisibility(ctx, d.Get("project").(string), d.Get("visibility").(string))
 		if err != nil {
-			return fmt.Errorf("error updating Sonarqube project: %+v", err)
+			return diag.Errorf("error updating Sonarqube project: %+v", err)
 		}
-		defer resp.Body.Close()
 	}
 
 	if d.HasChanges("tags") {
-		err := projectSetTags(d, m, m.(*ProviderConfiguration).sonarQubeURL)
+		err := projectSetTags(ctx, d, m)
 		if err != nil {
-			return fmt.Errorf("error updating Sonarqube selection mode: %+v", err)
+			return diag.Errorf("error updating Sonarqube selection mode: %+v", err)
 		}
 	}
 
@@ -291,62 +236,88 @@ func resourceSonarqubeProjectUpdate(d *schema.ResourceData, m interface{}) error
 	if d.HasChange("project") {
 		oldKey, newKey := d.GetChange("project")
 
-		sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-		sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/projects/update_key"
-		sonarQubeURL.RawQuery = url.Values{
-			"from": []string{oldKey.(string)},
-			"to":   []string{newKey.(string)},
-		}.Encode()
-
-		resp, err := httpRequestHelper(
-			m.(*ProviderConfiguration).httpClient,
-			"POST",
-			sonarQubeURL.String(),
-			http.StatusNoContent,
-			"resourceSonarqubeProjectUpdate",
-		)
+		err := sonarQubeClient.Projects.UpdateKey(ctx, oldKey.(string), newKey.(string))
 		if err != nil {
-			return fmt.Errorf("error updating Sonarqube project key: %+v", err)
+			return diag.Errorf("error updating Sonarqube project key: %+v", err)
 		}
-		defer resp.Body.Close()
 
 		// Update the id like in github provider (https://github.com/integrations/terraform-provider-github/blob/b7e63d63c59b9b1df9c6d05204bdaa1b349e8c8a/github/resource_github_repository.go#L746-L750)
 		d.SetId(newKey.(string))
 	}
 
 	if d.HasChange("setting") {
-		_, err := synchronizeSettings(d, m)
+		_, err := synchronizeSettings(ctx, d, m)
 		if err != nil {
-			return fmt.Errorf("failed to sync project settings: %+v", err)
+			return diag.Errorf("failed to sync project settings: %+v", err)
 		}
 	}
 
-	return resourceSonarqubeProjectRead(d, m)
+	return resourceSonarqubeProjectRead(ctx, d, m)
 }
 
-func resourceSonarqubeProjectDelete(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/projects/delete"
-	sonarQubeURL.RawQuery = url.Values{
-		"project": []string{d.Get("project").(string)},
-	}.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusNoContent,
-		"resourceSonarqubeProjectDelete",
-	)
+func resourceSonarqubeProjectDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	projectKey := d.Get("project").(string)
+	if d.Get("deletion_protection").(bool) {
+		return attributeDiagnostics("deletion_protection", fmt.Sprintf("resourceSonarqubeProjectDelete: Cannot delete project '%s'", projectKey),
+			errors.New("deletion_protection is enabled, set it to false and apply the change before deleting the project"))
+	}
+
+	if d.Get("export_on_delete").(bool) {
+		if err := exportProject(ctx, m, projectKey); err != nil {
+			return diag.Errorf("resourceSonarqubeProjectDelete: Failed to export project '%s', it was not deleted: %+v", projectKey, err)
+		}
+	}
+
+	err := m.(*ProviderConfiguration).client.Projects.Delete(ctx, projectKey)
 	if err != nil {
-		return err
+		return diag.Errorf("resourceSonarqubeProjectDelete: Failed to delete project: %+v", err)
 	}
-	defer resp.Body.Close()
 
 	return nil
 }
 
-func resourceSonarqubeProjectImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+// Exports a project to a dump file on the Sonarqube server and waits for the export to complete
+func exportProject(ctx context.Context, m interface{}, projectKey string) error {
+	conf := m.(*ProviderConfiguration)
+	if err := conf.checkCapability(capabilityProjectDump); err != nil {
+		return err
+	}
+
+	export, err := conf.client.ProjectDump.Export(ctx, projectKey)
+	if err != nil {
+		return err
+	}
+	log.Printf("[DEBUG][exportProject] Waiting for the export of project '%s', task %s", projectKey, export.TaskID)
+	return waitForCETask(ctx, conf.client, export.TaskID, ceTaskPollInterval)
+}
+
+// ceTaskPollInterval is the time between two checks of the status of a background task
+const ceTaskPollInterval = 2 * time.Second
+
+// Polls api/ce/task until a background task is done, it returns an error when the task failed or was canceled
+func waitForCETask(ctx context.Context, sonarQubeClient *client.Client, taskID string, pollInterval time.Duration) error {
+	for {
+		task, err := sonarQubeClient.CE.Task(ctx, taskID)
+		if err != nil {
+			return fmt.Errorf("failed to read the status of task %s: %+v", taskID, err)
+		}
+		switch task.Status {
+		case "SUCCESS":
+			return nil
+		case "FAILED", "CANCELED":
+			return fmt.Errorf("task %s ended with status %s: %s", taskID, task.Status, task.ErrorMessage)
+		}
+		log.Printf("[DEBUG][waitForCETask] Task %s is %s", taskID, task.Status)
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("task %s was still %s when the timeout expired", taskID, task.Status)
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+func resourceSonarqubeProjectImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
 	// As per the docs, use the id to make the read work as intended (https://developer.hashicorp.com/terraform/plugin/sdkv2/resources/import)
 	d.Set("project", d.Id())
 	return []*schema.ResourceData{d}, nil
@@ -0,0 +1,166 @@
+package sonarqube
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/jdamata/terraform-provider-sonarqube/internal/client"
+)
+
+// Returns the resource represented by this file.
+func resourceSonarqubeProjectBranch() *schema.Resource {
+	return &schema.Resource{
+		Description: `Provides a Sonarqube Project branch resource. This can be used to manage the long-lived branches of a project, e.g. to keep a
+release branch when it is inactive. Sonarqube creates a branch on its first analysis: the branch must exist before it is managed
+with this resource, and destroying the resource deletes the branch along with its analyses. The main branch is managed with the
+` + "`sonarqube_project_main_branch`" + ` resource.`,
+		CreateContext: resourceSonarqubeProjectBranchCreate,
+		ReadContext:   resourceSonarqubeProjectBranchRead,
+		UpdateContext: resourceSonarqubeProjectBranchUpdate,
+		DeleteContext: resourceSonarqubeProjectBranchDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceSonarqubeProjectBranchImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Default: schema.DefaultTimeout(5 * time.Minute),
+		},
+
+		// Define the fields of this schema.
+		Schema: map[string]*schema.Schema{
+			"project": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Key of the project. Changing this forces a new resource to be created.",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The name of the branch. Changing this forces a new resource to be created.",
+			},
+			"excluded_from_purge": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Computed:    true,
+				Description: "Whether the branch is kept when it is inactive, instead of being deleted by the housekeeping of Sonarqube. Defaults to whether the name of the branch matches the `sonar.dbcleaner.branchesToKeepWhenInactive` setting.",
+			},
+			"type": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The type of the branch, e.g. `BRANCH`.",
+			},
+			"analysis_date": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The date of the last analysis of the branch.",
+			},
+			"quality_gate_status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The quality gate status of the last analysis of the branch, e.g. `OK` or `ERROR`.",
+			},
+		},
+	}
+}
+
+// Returns the branch of a project, or nil when the project has no such branch
+func findProjectBranch(ctx context.Context, m interface{}, project string, name string) (*client.Branch, error) {
+	branches, err := m.(*ProviderConfiguration).client.ProjectBranches.List(ctx, project)
+	if err != nil {
+		return nil, err
+	}
+	for i := range branches {
+		if branches[i].Name == name {
+			return &branches[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func resourceSonarqubeProjectBranchCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	project := d.Get("project").(string)
+	name := d.Get("name").(string)
+	branch, err := findProjectBranch(ctx, m, project, name)
+	if err != nil {
+		return diag.Errorf("resourceSonarqubeProjectBranchCreate: Failed to list project branches: %+v", err)
+	}
+	if branch == nil {
+		return diag.Errorf("resourceSonarqubeProjectBranchCreate: Branch '%s' not found in project '%s', Sonarqube creates a branch on its first analysis", name, project)
+	}
+	if branch.IsMain {
+		return diag.Errorf("resourceSonarqubeProjectBranchCreate: Branch '%s' is the main branch of project '%s', use the sonarqube_project_main_branch resource instead", name, project)
+	}
+
+	// The branch keeps the protection Sonarqube gave it on its first analysis unless excluded_from_purge is set
+	if excluded := d.GetRawConfig().GetAttr("excluded_from_purge"); !excluded.IsNull() && excluded.True() != branch.ExcludedFromPurge {
+		err := m.(*ProviderConfiguration).client.ProjectBranches.SetAutomaticDeletionProtection(ctx, project, name, excluded.True())
+		if err != nil {
+			return diag.Errorf("resourceSonarqubeProjectBranchCreate: Failed to set the automatic deletion protection of branch '%s': %+v", name, err)
+		}
+	}
+
+	d.SetId(fmt.Sprintf("%v/%v", project, name))
+
+	return resourceSonarqubeProjectBranchRead(ctx, d, m)
+}
+
+func resourceSonarqubeProjectBranchRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	// Branch names may contain slashes, project keys can not
+	idSlice := strings.SplitN(d.Id(), "/", 2)
+	if len(idSlice) != 2 {
+		return diag.Errorf("resourceSonarqubeProjectBranchRead: Invalid id '%s', expected {project}/{branch}", d.Id())
+	}
+
+	branch, err := findProjectBranch(ctx, m, idSlice[0], idSlice[1])
+	if err != nil {
+		if client.IsNotFound(err) {
+			removeResourceFromState(d, "resourceSonarqubeProjectBranchRead")
+			return nil
+		}
+		return diag.Errorf("resourceSonarqubeProjectBranchRead: Failed to list project branches: %+v", err)
+	}
+	if branch == nil {
+		removeResourceFromState(d, "resourceSonarqubeProjectBranchRead")
+		return nil
+	}
+
+	d.Set("project", idSlice[0])
+	d.Set("name", branch.Name)
+	d.Set("excluded_from_purge", branch.ExcludedFromPurge)
+	d.Set("type", branch.Type)
+	d.Set("analysis_date", branch.AnalysisDate)
+	d.Set("quality_gate_status", branch.Status.QualityGateStatus)
+	return nil
+}
+
+func resourceSonarqubeProjectBranchUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if d.HasChange("excluded_from_purge") {
+		err := m.(*ProviderConfiguration).client.ProjectBranches.SetAutomaticDeletionProtection(ctx, d.Get("project").(string), d.Get("name").(string), d.Get("excluded_from_purge").(bool))
+		if err != nil {
+			return diag.Errorf("resourceSonarqubeProjectBranchUpdate: Failed to set the automatic deletion protection of branch '%s': %+v", d.Get("name").(string), err)
+		}
+	}
+
+	return resourceSonarqubeProjectBranchRead(ctx, d, m)
+}
+
+func resourceSonarqubeProjectBranchDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	err := m.(*ProviderConfiguration).client.ProjectBranches.Delete(ctx, d.Get("project").(string), d.Get("name").(string))
+	if err != nil {
+		return diag.Errorf("resourceSonarqubeProjectBranchDelete: Failed to delete branch '%s': %+v", d.Get("name").(string), err)
+	}
+
+	return nil
+}
+
+func resourceSonarqubeProjectBranchImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	if diags := resourceSonarqubeProjectBranchRead(ctx, d, m); diags.HasError() {
+		return nil, diagnosticsToError(diags)
+	}
+	return []*schema.ResourceData{d}, nil
+}
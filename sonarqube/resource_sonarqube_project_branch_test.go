@@ -0,0 +1,129 @@
+package sonarqube
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/jdamata/terraform-provider-sonarqube/internal/fakesonarqube"
+)
+
+func init() {
+	resource.AddTestSweepers("sonarqube_project_branch", &resource.Sweeper{
+		Name: "sonarqube_project_branch",
+		F:    testSweepSonarqubeProjectBranchSweeper,
+	})
+}
+
+// TODO: implement sweeper to clean up projects: https://www.terraform.io/docs/extend/testing/acceptance-tests/sweepers.html
+func testSweepSonarqubeProjectBranchSweeper(r string) error {
+	return nil
+}
+
+// testAccAnalyzeBranch creates a branch of a project the way a scanner does, by submitting an analysis to the fake server
+func testAccAnalyzeBranch(t *testing.T, project string, branch string) {
+	form := url.Values{
+		"projectKey":     []string{project},
+		"characteristic": []string{"branch=" + branch, "branchType=BRANCH"},
+	}
+	request, err := http.NewRequest(http.MethodPost, testAccFakeServer.URL+"/api/ce/submit", strings.NewReader(form.Encode()))
+	if err != nil {
+		t.Fatalf("failed to analyze branch %s: %+v", branch, err)
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.SetBasicAuth(fakesonarqube.DefaultLogin, fakesonarqube.DefaultPassword)
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatalf("failed to analyze branch %s: %+v", branch, err)
+	}
+	response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		t.Fatalf("failed to analyze branch %s: %s", branch, response.Status)
+	}
+}
+
+func testAccSonarqubeProjectBranchConfig(rnd string, project string, branches string) string {
+	return fmt.Sprintf(`
+		resource "sonarqube_project" "%[1]s" {
+		  name    = "%[2]s"
+		  project = "%[2]s"
+		}
+		%[3]s
+		`, rnd, project, branches)
+}
+
+func TestAccSonarqubeProjectBranch(t *testing.T) {
+	rnd := generateRandomResourceName()
+	project := "testAccSonarqubeProjectBranch"
+	release := "sonarqube_project_branch." + rnd + "_release"
+	feature := "sonarqube_project_branch." + rnd + "_feature"
+
+	branches := func(excludedFromPurge bool) string {
+		return fmt.Sprintf(`
+		resource "sonarqube_project_branch" "%[1]s_release" {
+		  project             = sonarqube_project.%[1]s.project
+		  name                = "release-1.0"
+		  excluded_from_purge = %[2]t
+		}
+
+		resource "sonarqube_project_branch" "%[1]s_feature" {
+		  project = sonarqube_project.%[1]s.project
+		  name    = "feature/my-feature"
+		}`, rnd, excludedFromPurge)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckFakeServer(t)
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSonarqubeProjectBranchConfig(rnd, project, ""),
+			},
+			{
+				Config:      testAccSonarqubeProjectBranchConfig(rnd, project, branches(false)),
+				ExpectError: regexp.MustCompile("Sonarqube creates a branch on its first analysis"),
+			},
+			{
+				PreConfig: func() {
+					testAccAnalyzeBranch(t, project, "release-1.0")
+					testAccAnalyzeBranch(t, project, "feature/my-feature")
+				},
+				Config: testAccSonarqubeProjectBranchConfig(rnd, project, branches(false)),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(release, "id", project+"/release-1.0"),
+					resource.TestCheckResourceAttr(release, "excluded_from_purge", "false"),
+					resource.TestCheckResourceAttr(release, "type", "BRANCH"),
+					resource.TestCheckResourceAttrSet(release, "analysis_date"),
+					resource.TestCheckResourceAttr(release, "quality_gate_status", "OK"),
+					// The name of the feature branch does not match sonar.dbcleaner.branchesToKeepWhenInactive
+					resource.TestCheckResourceAttr(feature, "excluded_from_purge", "false"),
+				),
+			},
+			{
+				ResourceName:      feature,
+				ImportState:       true,
+				ImportStateId:     project + "/feature/my-feature",
+				ImportStateVerify: true,
+			},
+			{
+				Config: testAccSonarqubeProjectBranchConfig(rnd, project, branches(true)),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(release, plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(release, "excluded_from_purge", "true"),
+				),
+			},
+		},
+	})
+}
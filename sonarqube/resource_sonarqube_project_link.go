@@ -0,0 +1,119 @@
+package sonarqube
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/jdamata/terraform-provider-sonarqube/internal/client"
+)
+
+// Returns the resource represented by this file.
+func resourceSonarqubeProjectLink() *schema.Resource {
+	return &schema.Resource{
+		Description: `Provides a Sonarqube Project link resource. This can be used to manage the links shown on the page of a project, e.g. its
+homepage or its continuous integration. Links named ` + "`homepage`, `ci`, `issue` or `scm`" + ` are shown with a dedicated icon.`,
+		CreateContext: resourceSonarqubeProjectLinkCreate,
+		ReadContext:   resourceSonarqubeProjectLinkRead,
+		DeleteContext: resourceSonarqubeProjectLinkDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceSonarqubeProjectLinkImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Default: schema.DefaultTimeout(5 * time.Minute),
+		},
+
+		// Define the fields of this schema.
+		Schema: map[string]*schema.Schema{
+			"project": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Key of the project. Changing this forces a new resource to be created.",
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringLenBetween(1, 128),
+				Description:  "The name of the link. Maximum length 128. Changing this forces a new resource to be created.",
+			},
+			"url": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringLenBetween(1, 2048),
+				Description:  "The URL of the link. Maximum length 2048. Changing this forces a new resource to be created.",
+			},
+			"type": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The type of the link: `homepage`, `ci`, `issue` or `scm` for the links named after them, `custom` otherwise.",
+			},
+		},
+	}
+}
+
+func resourceSonarqubeProjectLinkCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	link, err := m.(*ProviderConfiguration).client.ProjectLinks.Create(ctx, client.ProjectLinksCreateRequest{
+		ProjectKey: d.Get("project").(string),
+		Name:       d.Get("name").(string),
+		URL:        d.Get("url").(string),
+	})
+	if err != nil {
+		return diag.Errorf("resourceSonarqubeProjectLinkCreate: Failed to create project link: %+v", err)
+	}
+
+	d.SetId(fmt.Sprintf("%v/%v", d.Get("project").(string), link.ID))
+
+	return resourceSonarqubeProjectLinkRead(ctx, d, m)
+}
+
+func resourceSonarqubeProjectLinkRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	idSlice := strings.Split(d.Id(), "/")
+	if len(idSlice) != 2 {
+		return diag.Errorf("resourceSonarqubeProjectLinkRead: Invalid id '%s', expected {project}/{link_id}", d.Id())
+	}
+
+	links, err := m.(*ProviderConfiguration).client.ProjectLinks.Search(ctx, idSlice[0])
+	if err != nil {
+		if client.IsNotFound(err) {
+			removeResourceFromState(d, "resourceSonarqubeProjectLinkRead")
+			return nil
+		}
+		return diag.Errorf("resourceSonarqubeProjectLinkRead: Failed to search project links: %+v", err)
+	}
+
+	for _, link := range links {
+		if link.ID == idSlice[1] {
+			d.Set("project", idSlice[0])
+			d.Set("name", link.Name)
+			d.Set("url", link.URL)
+			d.Set("type", link.Type)
+			return nil
+		}
+	}
+	removeResourceFromState(d, "resourceSonarqubeProjectLinkRead")
+	return nil
+}
+
+func resourceSonarqubeProjectLinkDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	idSlice := strings.Split(d.Id(), "/")
+	err := m.(*ProviderConfiguration).client.ProjectLinks.Delete(ctx, idSlice[len(idSlice)-1])
+	if err != nil {
+		return diag.Errorf("resourceSonarqubeProjectLinkDelete: Failed to delete project link: %+v", err)
+	}
+
+	return nil
+}
+
+func resourceSonarqubeProjectLinkImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	if diags := resourceSonarqubeProjectLinkRead(ctx, d, m); diags.HasError() {
+		return nil, diagnosticsToError(diags)
+	}
+	return []*schema.ResourceData{d}, nil
+}
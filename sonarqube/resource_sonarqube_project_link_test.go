@@ -0,0 +1,71 @@
+package sonarqube
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func init() {
+	resource.AddTestSweepers("sonarqube_project_link", &resource.Sweeper{
+		Name: "sonarqube_project_link",
+		F:    testSweepSonarqubeProjectLinkSweeper,
+	})
+}
+
+// TODO: implement sweeper to clean up projects: https://www.terraform.io/docs/extend/testing/acceptance-tests/sweepers.html
+func testSweepSonarqubeProjectLinkSweeper(r string) error {
+	return nil
+}
+
+func testAccSonarqubeProjectLinkConfig(rnd string, project string, name string, url string) string {
+	return fmt.Sprintf(`
+		resource "sonarqube_project" "%[1]s" {
+		  name    = "%[2]s"
+		  project = "%[2]s"
+		}
+
+		resource "sonarqube_project_link" "%[1]s" {
+		  project = sonarqube_project.%[1]s.project
+		  name    = "%[3]s"
+		  url     = "%[4]s"
+		}
+		`, rnd, project, name, url)
+}
+
+func TestAccSonarqubeProjectLink(t *testing.T) {
+	rnd := generateRandomResourceName()
+	name := "sonarqube_project_link." + rnd
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSonarqubeProjectLinkConfig(rnd, "testAccSonarqubeProjectLink", "homepage", "https://example.com"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(name, "project", "testAccSonarqubeProjectLink"),
+					resource.TestCheckResourceAttr(name, "name", "homepage"),
+					resource.TestCheckResourceAttr(name, "url", "https://example.com"),
+					resource.TestCheckResourceAttr(name, "type", "homepage"),
+					resource.TestMatchResourceAttr(name, "id", regexp.MustCompile("^testAccSonarqubeProjectLink/.+$")),
+				),
+			},
+			{
+				ResourceName:      name,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config: testAccSonarqubeProjectLinkConfig(rnd, "testAccSonarqubeProjectLink", "Runbook", "https://example.com/runbook"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(name, "name", "Runbook"),
+					resource.TestCheckResourceAttr(name, "url", "https://example.com/runbook"),
+					resource.TestCheckResourceAttr(name, "type", "custom"),
+				),
+			},
+		},
+	})
+}
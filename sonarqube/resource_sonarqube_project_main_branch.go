@@ -1,41 +1,30 @@
 package sonarqube
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"net/http"
-	"net/url"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/jdamata/terraform-provider-sonarqube/internal/client"
 )
 
-// GetBranches for unmarshalling response body from getting project branch details
-type GetBranches struct {
-	Branches []Branches `json:"branches"`
-}
-type Status struct {
-	QualityGateStatus string `json:"qualityGateStatus"`
-}
-type Branches struct {
-	Name              string `json:"name"`
-	IsMain            bool   `json:"isMain"`
-	Type              string `json:"type"`
-	Status            Status `json:"status"`
-	AnalysisDate      string `json:"analysisDate"`
-	ExcludedFromPurge bool   `json:"excludedFromPurge"`
-}
-
 // Returns the resource represented by this file.
 func resourceSonarqubeProjectMainBranch() *schema.Resource {
 	return &schema.Resource{
-		Description: "Provides a Sonarqube Project main branch resource. This can be used to create and manage a Sonarqube Projects main branch.",
-		Create:      resourceSonarqubeProjectMainBranchCreate,
-		Read:        resourceSonarqubeProjectMainBranchRead,
-		Delete:      resourceSonarqubeProjectMainBranchDelete,
+		Description:   "Provides a Sonarqube Project main branch resource. This can be used to create and manage a Sonarqube Projects main branch.",
+		CreateContext: resourceSonarqubeProjectMainBranchCreate,
+		ReadContext:   resourceSonarqubeProjectMainBranchRead,
+		DeleteContext: resourceSonarqubeProjectMainBranchDelete,
 		Importer: &schema.ResourceImporter{
-			State: resourceSonarqubeProjectMainBranchImport,
+			StateContext: resourceSonarqubeProjectMainBranchImport,
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Default: schema.DefaultTimeout(5 * time.Minute),
+		},
+
 		// Define the fields of this schema.
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -54,97 +43,54 @@ func resourceSonarqubeProjectMainBranch() *schema.Resource {
 	}
 }
 
-func resourceSonarqubeProjectMainBranchCreate(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/project_branches/rename"
-
-	sonarQubeURL.RawQuery = url.Values{
-		"name":    []string{d.Get("name").(string)},
-		"project": []string{d.Get("project").(string)},
-	}.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusNoContent,
-		"resourceSonarqubeProjectMainBranchCreate",
-	)
+func resourceSonarqubeProjectMainBranchCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	err := m.(*ProviderConfiguration).client.ProjectBranches.Rename(ctx, d.Get("project").(string), d.Get("name").(string))
 	if err != nil {
-		return err
+		return diag.Errorf("resourceSonarqubeProjectMainBranchCreate: Failed to rename main branch: %+v", err)
 	}
-	defer resp.Body.Close()
 
 	id := fmt.Sprintf("%v/%v", d.Get("project").(string), d.Get("name").(string))
 	d.SetId(id)
 
-	return resourceSonarqubeProjectMainBranchRead(d, m)
+	return resourceSonarqubeProjectMainBranchRead(ctx, d, m)
 }
 
-func resourceSonarqubeProjectMainBranchRead(d *schema.ResourceData, m interface{}) error {
+func resourceSonarqubeProjectMainBranchRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	idSlice := strings.SplitN(d.Id(), "/", 2)
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/project_branches/list"
-	sonarQubeURL.RawQuery = url.Values{
-		"project": []string{idSlice[0]},
-	}.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"GET",
-		sonarQubeURL.String(),
-		http.StatusOK,
-		"resourceSonarqubeProjectMainBranchRead",
-	)
+	branches, err := m.(*ProviderConfiguration).client.ProjectBranches.List(ctx, idSlice[0])
 	if err != nil {
-		return err
+		if client.IsNotFound(err) {
+			removeResourceFromState(d, "resourceSonarqubeProjectMainBranchRead")
+			return nil
+		}
+		return diag.Errorf("resourceSonarqubeProjectMainBranchRead: Failed to list project branches: %+v", err)
 	}
-	defer resp.Body.Close()
 
-	// Decode response into struct
-	branchReadResponse := GetBranches{}
-	err = json.NewDecoder(resp.Body).Decode(&branchReadResponse)
-	if err != nil {
-		return fmt.Errorf("resourceSonarqubeProjectMainBranchRead: Failed to decode json into struct: %+v", err)
-	}
 	// Loop over all branches to see if the main branch we need exists.
-	for _, value := range branchReadResponse.Branches {
+	for _, value := range branches {
 		if idSlice[1] == value.Name && value.IsMain {
 			d.Set("project", idSlice[0])
 			d.Set("name", value.Name)
 			return nil
 		}
 	}
-	return fmt.Errorf("resourceSonarqubeProjectMainBranchRead: Failed to find project main branch: %+v", d.Id())
+	removeResourceFromState(d, "resourceSonarqubeProjectMainBranchRead")
+	return nil
 }
 
 // TODO make the delete function read the default branch name of the sonarQube instance instead of assuming
-func resourceSonarqubeProjectMainBranchDelete(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/project_branches/rename"
-	sonarQubeURL.RawQuery = url.Values{
-		"name":    []string{"main"},
-		"project": []string{d.Get("project").(string)},
-	}.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusNoContent,
-		"resourceSonarqubeProjectMainBranchDelete",
-	)
+func resourceSonarqubeProjectMainBranchDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	err := m.(*ProviderConfiguration).client.ProjectBranches.Rename(ctx, d.Get("project").(string), "main")
 	if err != nil {
-		return err
+		return diag.Errorf("resourceSonarqubeProjectMainBranchDelete: Failed to rename main branch: %+v", err)
 	}
-	defer resp.Body.Close()
 
 	return nil
 }
 
-func resourceSonarqubeProjectMainBranchImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
-	if err := resourceSonarqubeProjectMainBranchRead(d, m); err != nil {
-		return nil, err
+func resourceSonarqubeProjectMainBranchImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	if diags := resourceSonarqubeProjectMainBranchRead(ctx, d, m); diags.HasError() {
+		return nil, diagnosticsToError(diags)
 	}
 	return []*schema.ResourceData{d}, nil
 }
@@ -1,12 +1,16 @@
 package sonarqube
 
 import (
+	"context"
 	"fmt"
+	"regexp"
 	"strconv"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/jdamata/terraform-provider-sonarqube/internal/client"
 )
 
 func init() {
@@ -91,8 +95,8 @@ func TestAccSonarqubeProjectBasic(t *testing.T) {
 	name := "sonarqube_project." + rnd
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubeProjectBasicConfig(rnd, "testAccSonarqubeProject", "testAccSonarqubeProject", "public"),
@@ -135,8 +139,8 @@ func TestAccSonarqubeProjectVisibilityUpdate(t *testing.T) {
 	name := "sonarqube_project." + rnd
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubeProjectBasicConfig(rnd, "testAccSonarqubeProject", "testAccSonarqubeProject", "public"),
@@ -166,8 +170,8 @@ func TestAccSonarqubeProjectTagsCreate(t *testing.T) {
 	tags := []string{"tag1", "tag2"}
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubeProjectTagsConfig(rnd, "testAccSonarqubeProject", "testAccSonarqubeProject", "public", tags),
@@ -188,8 +192,8 @@ func TestAccSonarqubeProjectTagsUpdate(t *testing.T) {
 	tagsUpdated := []string{"tag1", "tag2", "tag3"}
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubeProjectBasicConfig(rnd, "testAccSonarqubeProject", "testAccSonarqubeProject", "public"),
@@ -236,8 +240,8 @@ func TestAccSonarqubeProjectKeyUpdate(t *testing.T) {
 	newKey := "testAccSonarqubeProjectNew"
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubeProjectBasicConfig(rnd, "testAccSonarqubeProject", oldKey, "public"),
@@ -262,14 +266,131 @@ func TestAccSonarqubeProjectKeyUpdate(t *testing.T) {
 	})
 }
 
+func TestAccSonarqubeProjectNameUpdate(t *testing.T) {
+	rnd := generateRandomResourceName()
+	name := "sonarqube_project." + rnd
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSonarqubeProjectBasicConfig(rnd, "testAccSonarqubeProject", "testAccSonarqubeProject", "public"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(name, "name", "testAccSonarqubeProject"),
+				),
+			},
+			{
+				// Sonarqube has no web service to rename a project, the change is suppressed rather than replacing the project
+				Config: testAccSonarqubeProjectBasicConfig(rnd, "testAccSonarqubeProjectRenamed", "testAccSonarqubeProject", "public"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(name, "name", "testAccSonarqubeProject"),
+				),
+			},
+		},
+	})
+}
+
+func testAccSonarqubeProjectDeletionConfig(rnd string, project string, deletionProtection bool, exportOnDelete bool) string {
+	return fmt.Sprintf(`
+		resource "sonarqube_project" "%[1]s" {
+		  name                = "%[2]s"
+		  project             = "%[2]s"
+		  deletion_protection = %[3]t
+		  export_on_delete    = %[4]t
+		}
+		`, rnd, project, deletionProtection, exportOnDelete)
+}
+
+func TestAccSonarqubeProjectDeletionProtection(t *testing.T) {
+	rnd := generateRandomResourceName()
+	name := "sonarqube_project." + rnd
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSonarqubeProjectDeletionConfig(rnd, "testAccSonarqubeProject", true, false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(name, "deletion_protection", "true"),
+				),
+			},
+			{
+				Config:      testAccSonarqubeProjectDeletionConfig(rnd, "testAccSonarqubeProject", true, false),
+				Destroy:     true,
+				ExpectError: regexp.MustCompile("deletion_protection is enabled"),
+			},
+			{
+				// Switching the protection off lets the test delete the project
+				Config: testAccSonarqubeProjectDeletionConfig(rnd, "testAccSonarqubeProject", false, false),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(name, plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(name, "deletion_protection", "false"),
+				),
+			},
+			{
+				ResourceName:      name,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccSonarqubeProjectExportOnDelete(t *testing.T) {
+	rnd := generateRandomResourceName()
+	name := "sonarqube_project." + rnd
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckCapability(t, capabilityProjectDump)
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckSonarqubeProjectDestroyed("testAccSonarqubeProjectExport"),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSonarqubeProjectDeletionConfig(rnd, "testAccSonarqubeProjectExport", false, true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(name, "export_on_delete", "true"),
+				),
+			},
+		},
+	})
+}
+
+// testAccCheckSonarqubeProjectDestroyed checks the project was deleted from the server
+func testAccCheckSonarqubeProjectDestroyed(project string) resource.TestCheckFunc {
+	return func(_ *terraform.State) error {
+		_, err := testAccCapabilityProvider.Meta().(*ProviderConfiguration).client.Components.Show(context.Background(), project)
+		if err == nil {
+			return fmt.Errorf("project %s still exists", project)
+		}
+		if !client.IsNotFound(err) {
+			return err
+		}
+		return nil
+	}
+}
+
 func TestAccSonarqubeProjectSettingsCreate(t *testing.T) {
 	rnd := generateRandomResourceName()
 	name := "sonarqube_project." + rnd
 	expectedSettings := 1
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubeProjectSettingsConfig(rnd, "testAccSonarqubeProject", "testAccSonarqubeProject", "public", "false"),
@@ -289,8 +410,8 @@ func TestAccSonarqubeProjectSettingsUpdate(t *testing.T) {
 	name := "sonarqube_project." + rnd
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubeProjectBasicConfig(rnd, "testAccSonarqubeProject", "testAccSonarqubeProject", "public"),
@@ -329,8 +450,8 @@ func TestAccSonarqubeProjectSettingsTypes(t *testing.T) {
 	fieldValues := map[string]string{"ruleKey": "foo", "resourceKey": "bar"}
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubeProjectSettingsMultiple(rnd, "testAccSonarqubeProject", "testAccSonarqubeProject", values, fieldValues),
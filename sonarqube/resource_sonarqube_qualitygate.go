@@ -1,59 +1,30 @@
 package sonarqube
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"net/http"
-	"net/url"
 	"sort"
-	"strings"
 	"sync"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/jdamata/terraform-provider-sonarqube/internal/client"
 )
 
-// ReadQualityGateConditionsResponse for unmarshalling response body of Quality Gate read
-type ReadQualityGateConditionsResponse struct {
-	ID     string `json:"id"`
-	Metric string `json:"metric"`
-	OP     string `json:"op"`
-	Error  string `json:"error"`
-}
-
-// GetQualityGate for unmarshalling response body of quality gate get
-type GetQualityGate struct {
-	ID         string                              `json:"id"`
-	Name       string                              `json:"name"`
-	Conditions []ReadQualityGateConditionsResponse `json:"conditions"`
-	IsBuiltIn  bool                                `json:"isBuiltIn"`
-	Actions    QualityGateActions                  `json:"actions"`
-}
-
-// QualityGateActions used in GetQualityGate
-type QualityGateActions struct {
-	Rename            bool `json:"rename"`
-	SetAsDefault      bool `json:"setAsDefault"`
-	Copy              bool `json:"copy"`
-	AssociateProjects bool `json:"associateProjects"`
-	Delete            bool `json:"delete"`
-	ManageConditions  bool `json:"manageConditions"`
-}
-
-// CreateQualityGateResponse for unmarshalling response body of quality gate creation
-type CreateQualityGateResponse struct {
-	Name string `json:"name"`
-}
-
 // Returns the resource represented by this file.
 func resourceSonarqubeQualityGate() *schema.Resource {
 	return &schema.Resource{
-		Description: "Provides a Sonarqube Quality Gate resource. This can be used to create and manage Sonarqube Quality Gates and their Conditions.",
-		Create:      resourceSonarqubeQualityGateCreate,
-		Read:        resourceSonarqubeQualityGateRead,
-		Update:      resourceSonarqubeQualityGateUpdate,
-		Delete:      resourceSonarqubeQualityGateDelete,
+		Description:   "Provides a Sonarqube Quality Gate resource. This can be used to create and manage Sonarqube Quality Gates and their Conditions.",
+		CreateContext: resourceSonarqubeQualityGateCreate,
+		ReadContext:   resourceSonarqubeQualityGateRead,
+		UpdateContext: resourceSonarqubeQualityGateUpdate,
+		DeleteContext: resourceSonarqubeQualityGateDelete,
 		Importer: &schema.ResourceImporter{
-			State: resourceSonarqubeQualityGateImport,
+			StateContext: resourceSonarqubeQualityGateImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Default: schema.DefaultTimeout(5 * time.Minute),
 		},
 
 		// Define the fields of this schema.
@@ -124,75 +95,54 @@ func resourceSonarqubeQualityGate() *schema.Resource {
 	}
 }
 
-func resourceSonarqubeQualityGateCreate(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
+func resourceSonarqubeQualityGateCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	qualityGatesService := m.(*ProviderConfiguration).client.QualityGates
 
+	var name string
+	var err error
 	copying_gate := false
 	if gate_to_copy, ok := d.GetOk("copy_from"); ok {
 		copying_gate = true
-		sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/qualitygates/copy"
-		sonarQubeURL.RawQuery = url.Values{
-			"name":       []string{d.Get("name").(string)},
-			"sourceName": []string{gate_to_copy.(string)},
-		}.Encode()
+		name, err = qualityGatesService.Copy(ctx, gate_to_copy.(string), d.Get("name").(string))
 	} else {
 		if _, ok := d.GetOk("condition"); !ok {
-			return fmt.Errorf("resourceQualityGateCreate: either copy_from or at least one condition block must be specified for a quality gate")
+			return diag.Errorf("resourceQualityGateCreate: either copy_from or at least one condition block must be specified for a quality gate")
 		}
-		sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/qualitygates/create"
-		sonarQubeURL.RawQuery = url.Values{
-			"name": []string{d.Get("name").(string)},
-		}.Encode()
-	}
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusOK,
-		"resourceQualityGateCreate",
-	)
-	if err != nil {
-		return err
+		name, err = qualityGatesService.Create(ctx, d.Get("name").(string))
 	}
-	defer resp.Body.Close()
-
-	// Decode response into struct
-	qualityGateResponse := CreateQualityGateResponse{}
-	err = json.NewDecoder(resp.Body).Decode(&qualityGateResponse)
 	if err != nil {
-		return fmt.Errorf("resourceQualityGateCreate: Failed to decode json into struct: %+v", err)
+		return diag.Errorf("resourceQualityGateCreate: Failed to create quality gate: %+v", err)
 	}
 
-	d.SetId(qualityGateResponse.Name)
+	d.SetId(name)
 
-	qualityGateReadResponse, err := readQualityGateFromApi(d, m)
+	qualityGateReadResponse, err := readQualityGateFromApi(ctx, d, m)
 	if err != nil {
-		return fmt.Errorf("resourceSonarqubeQualityGateCreate: Failed to read the quality gate from the API: %+v", err)
+		return diag.Errorf("resourceSonarqubeQualityGateCreate: Failed to read the quality gate from the API: %+v", err)
 	}
 
 	// SonarQube 9.9 and above will automatically create "Clean as you code" conditions for new quality gates
 	// If we are not copying a gate then we need to synchronise the conditions from the newly created gate with
 	// the ones declared on the terraform resource
 	if !copying_gate {
-		changes, err := synchronizeConditions(d, m, &qualityGateReadResponse.Conditions)
+		changes, err := synchronizeConditions(ctx, d, m, &qualityGateReadResponse.Conditions)
 		if err != nil {
-			return fmt.Errorf("resourceSonarqubeQualityGateCreate: Failed to synchronise quality gate conditions: %+v", err)
+			return diag.Errorf("resourceSonarqubeQualityGateCreate: Failed to synchronise quality gate conditions: %+v", err)
 		}
 
 		// If we did make any changes then re-read the quality gate from the API.
 		if changes {
-			qualityGateReadResponse, err = readQualityGateFromApi(d, m)
+			qualityGateReadResponse, err = readQualityGateFromApi(ctx, d, m)
 			if err != nil {
-				return fmt.Errorf("resourceSonarqubeQualityGateCreate: Failed to read the quality gate after conditions were updated: %+v", err)
+				return diag.Errorf("resourceSonarqubeQualityGateCreate: Failed to read the quality gate after conditions were updated: %+v", err)
 			}
 		}
 	}
 
 	if d.Get("is_default").(bool) {
-		err := setDefaultQualityGate(d, m, true)
+		err := setDefaultQualityGate(ctx, d, m, true)
 		if err != nil {
-			return fmt.Errorf("resourceSonarqubeQualityGateCreate: Failed to set this quality gate as default: %+v", err)
+			return diag.Errorf("resourceSonarqubeQualityGateCreate: Failed to set this quality gate as default: %+v", err)
 		}
 	}
 
@@ -200,10 +150,14 @@ func resourceSonarqubeQualityGateCreate(d *schema.ResourceData, m interface{}) e
 	return nil
 }
 
-func resourceSonarqubeQualityGateRead(d *schema.ResourceData, m interface{}) error {
-	qualityGateReadResponse, err := readQualityGateFromApi(d, m)
+func resourceSonarqubeQualityGateRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	qualityGateReadResponse, err := readQualityGateFromApi(ctx, d, m)
 	if err != nil {
-		return err
+		if client.IsNotFound(err) {
+			removeResourceFromState(d, "resourceSonarqubeQualityGateRead")
+			return nil
+		}
+		return diag.FromErr(err)
 	}
 	updateResourceDataFromQualityGateReadResponse(d, qualityGateReadResponse)
 	// Api returns if true if set as default is available. when is_default=true setAsDefault=false so is_default=true
@@ -213,33 +167,33 @@ func resourceSonarqubeQualityGateRead(d *schema.ResourceData, m interface{}) err
 
 var lock_update_default sync.Mutex
 
-func resourceSonarqubeQualityGateUpdate(d *schema.ResourceData, m interface{}) error {
+func resourceSonarqubeQualityGateUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	_, copied_gate := d.GetOk("copy_from")
 
 	if _, has_conditions := d.GetOk("condition"); !(copied_gate || has_conditions) {
-		return fmt.Errorf("resourceQualityGateCreate: either copy_from or at least one condition block must be specified for a quality gate")
+		return diag.Errorf("resourceQualityGateCreate: either copy_from or at least one condition block must be specified for a quality gate")
 	}
 
 	if d.HasChange("name") {
-		err := updateQualityGateName(d, m)
+		err := updateQualityGateName(ctx, d, m)
 		if err != nil {
-			return fmt.Errorf("resourceSonarqubeQualityGateUpdate: Failed to change the name of the quality gate: %+v", err)
+			return diag.Errorf("resourceSonarqubeQualityGateUpdate: Failed to change the name of the quality gate: %+v", err)
 		}
 		d.SetId(d.Get("name").(string))
 	}
 
-	qualityGateReadResponse, err := readQualityGateFromApi(d, m)
+	qualityGateReadResponse, err := readQualityGateFromApi(ctx, d, m)
 	if err != nil {
-		return fmt.Errorf("resourceSonarqubeQualityGateUpdate: Failed to read the quality gate from the API: %+v", err)
+		return diag.Errorf("resourceSonarqubeQualityGateUpdate: Failed to read the quality gate from the API: %+v", err)
 	}
 
 	conditionsChanged := false
 
 	// We only need to update the conditions if this is not a copied gate - they will still exist from when it was created originally
 	if !copied_gate {
-		conditionsChanged, err = synchronizeConditions(d, m, &qualityGateReadResponse.Conditions)
+		conditionsChanged, err = synchronizeConditions(ctx, d, m, &qualityGateReadResponse.Conditions)
 		if err != nil {
-			return fmt.Errorf("resourceSonarqubeQualityGateUpdate: Failed to synchronise quality gate conditions: %+v", err)
+			return diag.Errorf("resourceSonarqubeQualityGateUpdate: Failed to synchronise quality gate conditions: %+v", err)
 		}
 	}
 
@@ -252,9 +206,9 @@ func resourceSonarqubeQualityGateUpdate(d *schema.ResourceData, m interface{}) e
 
 	// If we made any condition changes or want to change the default quality gate then re-read the quality gate from the API.
 	if conditionsChanged || defaultChanged {
-		qualityGateReadResponse, err = readQualityGateFromApi(d, m)
+		qualityGateReadResponse, err = readQualityGateFromApi(ctx, d, m)
 		if err != nil {
-			return fmt.Errorf("resourceSonarqubeQualityGateUpdate: Failed to read the quality gate after conditions were updated: %+v", err)
+			return diag.Errorf("resourceSonarqubeQualityGateUpdate: Failed to read the quality gate after conditions were updated: %+v", err)
 		}
 	}
 
@@ -264,9 +218,9 @@ func resourceSonarqubeQualityGateUpdate(d *schema.ResourceData, m interface{}) e
 		// explicitly set as default) then we don't need to do anything (and accidentally set Sonar way as default!)
 		// In all other cases where the default has changed, we do need to update it.
 		if newDefault != !qualityGateReadResponse.Actions.SetAsDefault {
-			err := setDefaultQualityGate(d, m, newDefault)
+			err := setDefaultQualityGate(ctx, d, m, newDefault)
 			if err != nil {
-				return fmt.Errorf("resourceSonarqubeQualityGateUpdate: Failed to set this quality gate as default: %+v", err)
+				return diag.Errorf("resourceSonarqubeQualityGateUpdate: Failed to set this quality gate as default: %+v", err)
 			}
 		}
 	}
@@ -275,97 +229,43 @@ func resourceSonarqubeQualityGateUpdate(d *schema.ResourceData, m interface{}) e
 	return nil
 }
 
-func resourceSonarqubeQualityGateDelete(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/qualitygates/destroy"
-
-	sonarQubeURL.RawQuery = url.Values{
-		"name": []string{d.Id()},
-	}.Encode()
-
+func resourceSonarqubeQualityGateDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	// If this is the default quality gate then we need to default it back to "Sonar way" so there is still a default
 	if d.Get("is_default").(bool) {
-		err := setDefaultQualityGate(d, m, false)
+		err := setDefaultQualityGate(ctx, d, m, false)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusNoContent,
-		"resourceQualityGateDelete",
-	)
+	err := m.(*ProviderConfiguration).client.QualityGates.Destroy(ctx, d.Id())
 	if err != nil {
-		return fmt.Errorf("resourceQualityGateDelete: Failed to delete quality gate: %+v", err)
+		return diag.Errorf("resourceQualityGateDelete: Failed to delete quality gate: %+v", err)
 	}
-	defer resp.Body.Close()
 
 	return nil
 }
 
-func resourceSonarqubeQualityGateImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
-	if err := resourceSonarqubeQualityGateRead(d, m); err != nil {
-		return nil, err
+func resourceSonarqubeQualityGateImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	if diags := resourceSonarqubeQualityGateRead(ctx, d, m); diags.HasError() {
+		return nil, diagnosticsToError(diags)
 	}
 	return []*schema.ResourceData{d}, nil
 }
 
-func setDefaultQualityGate(d *schema.ResourceData, m interface{}, setDefault bool) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/qualitygates/set_as_default"
-
+func setDefaultQualityGate(ctx context.Context, d *schema.ResourceData, m interface{}, setDefault bool) error {
+	name := "Sonar way"
 	if setDefault {
-		sonarQubeURL.RawQuery = url.Values{
-			"name": []string{d.Get("name").(string)},
-		}.Encode()
-	} else {
-		sonarQubeURL.RawQuery = url.Values{
-			"name": []string{"Sonar way"},
-		}.Encode()
-	}
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusNoContent,
-		"setDefaultQualityGate",
-	)
-	if err != nil {
-		return err
+		name = d.Get("name").(string)
 	}
-	defer resp.Body.Close()
-	return nil
-}
 
-func readQualityGateFromApi(d *schema.ResourceData, m interface{}) (*GetQualityGate, error) {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/qualitygates/show"
-
-	sonarQubeURL.RawQuery = url.Values{
-		"name": []string{d.Id()},
-	}.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"GET",
-		sonarQubeURL.String(),
-		http.StatusOK,
-		"readQualityGateFromApi",
-	)
-	if err != nil {
-		return nil, fmt.Errorf("readQualityGateFromApi: Failed to call api/qualitygates/show: %+v", err)
-	}
-	defer resp.Body.Close()
+	return m.(*ProviderConfiguration).client.QualityGates.SetAsDefault(ctx, name)
+}
 
-	// Decode response into struct
-	qualityGateReadResponse := GetQualityGate{}
-	err = json.NewDecoder(resp.Body).Decode(&qualityGateReadResponse)
+func readQualityGateFromApi(ctx context.Context, d *schema.ResourceData, m interface{}) (*client.QualityGate, error) {
+	qualityGateReadResponse, err := m.(*ProviderConfiguration).client.QualityGates.Show(ctx, d.Id())
 	if err != nil {
-		return nil, fmt.Errorf("resourceQualityGateRead: Failed to decode json into struct: %+v", err)
+		return nil, fmt.Errorf("readQualityGateFromApi: Failed to call api/qualitygates/show: %w", err)
 	}
 
 	// Make sure the order is always the same for when we are comparing lists of conditions
@@ -373,10 +273,10 @@ func readQualityGateFromApi(d *schema.ResourceData, m interface{}) (*GetQualityG
 		return qualityGateReadResponse.Conditions[i].Metric < qualityGateReadResponse.Conditions[j].Metric
 	})
 
-	return &qualityGateReadResponse, nil
+	return qualityGateReadResponse, nil
 }
 
-func synchronizeConditions(d *schema.ResourceData, m interface{}, apiQualityGateConditions *[]ReadQualityGateConditionsResponse) (bool, error) {
+func synchronizeConditions(ctx context.Context, d *schema.ResourceData, m interface{}, apiQualityGateConditions *[]client.QualityGateCondition) (bool, error) {
 	changed := false
 	qualityGateConditions := d.Get("condition").([]interface{})
 
@@ -387,7 +287,7 @@ func synchronizeConditions(d *schema.ResourceData, m interface{}, apiQualityGate
 
 	// Determine which conditions have been added or changed and update those
 	for i, condition := range qualityGateConditions {
-		conditionId, err := addOrUpdateCondition(d, m, apiQualityGateConditions, condition, &changed)
+		conditionId, err := addOrUpdateCondition(ctx, d, m, apiQualityGateConditions, condition, &changed)
 		if err != nil {
 			return changed, err
 		}
@@ -397,7 +297,7 @@ func synchronizeConditions(d *schema.ResourceData, m interface{}, apiQualityGate
 	}
 
 	// Determine if any conditions have been removed and delete them
-	err := removeDeletedConditions(apiQualityGateConditions, qualityGateConditions, m, &changed)
+	err := removeDeletedConditions(ctx, apiQualityGateConditions, qualityGateConditions, m, &changed)
 	if err != nil {
 		return changed, err
 	}
@@ -409,7 +309,7 @@ func synchronizeConditions(d *schema.ResourceData, m interface{}, apiQualityGate
 	return changed, nil
 }
 
-func addOrUpdateCondition(d *schema.ResourceData, m interface{}, apiQualityGateConditions *[]ReadQualityGateConditionsResponse, condition interface{}, changed *bool) (string, error) {
+func addOrUpdateCondition(ctx context.Context, d *schema.ResourceData, m interface{}, apiQualityGateConditions *[]client.QualityGateCondition, condition interface{}, changed *bool) (string, error) {
 	metric := condition.(map[string]interface{})["metric"].(string)
 	op := condition.(map[string]interface{})["op"].(string)
 	threshold := condition.(map[string]interface{})["threshold"].(string)
@@ -418,7 +318,7 @@ func addOrUpdateCondition(d *schema.ResourceData, m interface{}, apiQualityGateC
 	for _, apiCondition := range *apiQualityGateConditions {
 		if metric == apiCondition.Metric {
 			if op != apiCondition.OP || threshold != apiCondition.Error {
-				err := updateCondition(apiCondition.ID, metric, op, threshold, m)
+				err := updateCondition(ctx, apiCondition.ID, metric, op, threshold, m)
 				if err != nil {
 					return "", fmt.Errorf("addOrUpdateCondition: Failed to update condition '%s': %+v", metric, err)
 				}
@@ -429,7 +329,7 @@ func addOrUpdateCondition(d *schema.ResourceData, m interface{}, apiQualityGateC
 	}
 
 	// Add the condition because it does not already exist
-	conditionId, err := createCondition(d.Id(), metric, op, threshold, m)
+	conditionId, err := createCondition(ctx, d.Id(), metric, op, threshold, m)
 	if err != nil {
 		return conditionId, fmt.Errorf("addOrUpdateCondition: Failed to create condition '%s': %+v", metric, err)
 	}
@@ -437,7 +337,7 @@ func addOrUpdateCondition(d *schema.ResourceData, m interface{}, apiQualityGateC
 	return conditionId, nil
 }
 
-func removeDeletedConditions(apiQualityGateConditions *[]ReadQualityGateConditionsResponse, qualityGateConditions []interface{}, m interface{}, changed *bool) error {
+func removeDeletedConditions(ctx context.Context, apiQualityGateConditions *[]client.QualityGateCondition, qualityGateConditions []interface{}, m interface{}, changed *bool) error {
 	for _, apiCondition := range *apiQualityGateConditions {
 		found := false
 
@@ -449,7 +349,7 @@ func removeDeletedConditions(apiQualityGateConditions *[]ReadQualityGateConditio
 		}
 
 		if !found {
-			err := deleteCondition(apiCondition.ID, m)
+			err := deleteCondition(ctx, apiCondition.ID, m)
 			if err != nil {
 				return fmt.Errorf("removeDeletedConditions: Failed to delete condition '%s': %+v", apiCondition.Metric, err)
 			}
@@ -459,7 +359,7 @@ func removeDeletedConditions(apiQualityGateConditions *[]ReadQualityGateConditio
 	return nil
 }
 
-func updateResourceDataFromQualityGateReadResponse(d *schema.ResourceData, qualityGateReadResponse *GetQualityGate) {
+func updateResourceDataFromQualityGateReadResponse(d *schema.ResourceData, qualityGateReadResponse *client.QualityGate) {
 	d.SetId(qualityGateReadResponse.Name)
 	d.Set("name", qualityGateReadResponse.Name)
 	// Copied gates do not have condition blocks so we don't want to populate from the API.
@@ -468,112 +368,59 @@ func updateResourceDataFromQualityGateReadResponse(d *schema.ResourceData, quali
 	}
 }
 
-func createCondition(qualityGateName string, metric string, op string, threshold string, m interface{}) (string, error) {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/qualitygates/create_condition"
-
-	sonarQubeURL.RawQuery = url.Values{
-		"gateName": []string{qualityGateName},
-		"metric":   []string{metric},
-		"op":       []string{op},
-		"error":    []string{threshold},
-	}.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusOK,
-		"createCondition",
-	)
-	if err != nil {
-		return "", err
+func createCondition(ctx context.Context, qualityGateName string, metric string, op string, threshold string, m interface{}) (string, error) {
+	conf := m.(*ProviderConfiguration)
+	request := client.QualityGateConditionRequest{
+		Metric: metric,
+		OP:     op,
+		Error:  threshold,
 	}
-	defer resp.Body.Close()
-
-	// Decode response into struct
-	qualityGateConditionResponse := ReadQualityGateConditionsResponse{}
-	err = json.NewDecoder(resp.Body).Decode(&qualityGateConditionResponse)
+	if conf.sonarQubeAPI.requiresGateID("api/qualitygates/create_condition") {
+		gateID, err := qualityGateID(ctx, qualityGateName, m)
+		if err != nil {
+			return "", err
+		}
+		request.GateID = gateID
+	} else {
+		request.GateName = qualityGateName
+	}
+	condition, err := conf.client.QualityGates.CreateCondition(ctx, request)
 	if err != nil {
-		return "", fmt.Errorf("createCondition: Failed to decode json into struct: %+v", err)
+		return "", err
 	}
 
-	return qualityGateConditionResponse.ID, nil
+	return condition.ID, nil
 }
 
-func updateCondition(id, metric, op, threshold string, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/qualitygates/update_condition"
-
-	sonarQubeURL.RawQuery = url.Values{
-		"id":     []string{id},
-		"metric": []string{metric},
-		"op":     []string{op},
-		"error":  []string{threshold},
-	}.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusOK,
-		"updateCondition",
-	)
+// qualityGateID returns the id of a quality gate, for the actions which only accept gateId on older servers
+func qualityGateID(ctx context.Context, qualityGateName string, m interface{}) (string, error) {
+	gate, err := m.(*ProviderConfiguration).client.QualityGates.Show(ctx, qualityGateName)
 	if err != nil {
-		return err
+		return "", err
 	}
-	defer resp.Body.Close()
-
-	return nil
+	return gate.ID, nil
 }
 
-func deleteCondition(id string, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/qualitygates/delete_condition"
-	sonarQubeURL.RawQuery = url.Values{
-		"id": []string{id},
-	}.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusNoContent,
-		"deleteCondition",
-	)
-	if err != nil {
-		return err
+func updateCondition(ctx context.Context, id, metric, op, threshold string, m interface{}) error {
+	request := client.QualityGateConditionRequest{
+		ID:     id,
+		Metric: metric,
+		OP:     op,
+		Error:  threshold,
 	}
-	defer resp.Body.Close()
-
-	return nil
+	return m.(*ProviderConfiguration).client.QualityGates.UpdateCondition(ctx, request)
 }
 
-func updateQualityGateName(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/qualitygates/rename"
+func deleteCondition(ctx context.Context, id string, m interface{}) error {
+	return m.(*ProviderConfiguration).client.QualityGates.DeleteCondition(ctx, id)
+}
 
+func updateQualityGateName(ctx context.Context, d *schema.ResourceData, m interface{}) error {
 	old, new := d.GetChange("name")
-	sonarQubeURL.RawQuery = url.Values{
-		"currentName": []string{old.(string)},
-		"name":        []string{new.(string)},
-	}.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusOK,
-		"updateQualityGateName",
-	)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-	return nil
+	return m.(*ProviderConfiguration).client.QualityGates.Rename(ctx, old.(string), new.(string))
 }
 
-func flattenReadQualityGateConditionsResponse(input *[]ReadQualityGateConditionsResponse) []interface{} {
+func flattenReadQualityGateConditionsResponse(input *[]client.QualityGateCondition) []interface{} {
 	if input == nil || len(*input) == 0 {
 		return make([]interface{}, 0)
 	}
@@ -1,33 +1,28 @@
 package sonarqube
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"net/http"
-	"net/url"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/jdamata/terraform-provider-sonarqube/internal/client"
 )
 
-// GetQualityGateAssociation for unmarshalling response body from getting quality gate association
-type GetQualityGateAssociation struct {
-	QualityGate struct {
-		Id      string `json:"id"`
-		Name    string `json:"name"`
-		Default bool   `json:"default"`
-	} `json:"qualityGate"`
-}
-
 // Returns the resource represented by this file.
 func resourceSonarqubeQualityGateProjectAssociation() *schema.Resource {
 	return &schema.Resource{
-		Description: "Provides a Sonarqube Quality Gate Project association resource. This can be used to associate a Quality Gate to a Project",
-		Create:      resourceSonarqubeQualityGateProjectAssociationCreate,
-		Read:        resourceSonarqubeQualityGateProjectAssociationRead,
-		Delete:      resourceSonarqubeQualityGateProjectAssociationDelete,
+		Description:   "Provides a Sonarqube Quality Gate Project association resource. This can be used to associate a Quality Gate to a Project",
+		CreateContext: resourceSonarqubeQualityGateProjectAssociationCreate,
+		ReadContext:   resourceSonarqubeQualityGateProjectAssociationRead,
+		DeleteContext: resourceSonarqubeQualityGateProjectAssociationDelete,
 		Importer: &schema.ResourceImporter{
-			State: resourceSonarqubeQualityGateProjectAssociationImport,
+			StateContext: resourceSonarqubeQualityGateProjectAssociationImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Default: schema.DefaultTimeout(5 * time.Minute),
 		},
 
 		// Define the fields of this schema.
@@ -53,93 +48,71 @@ func resourceSonarqubeQualityGateProjectAssociation() *schema.Resource {
 	}
 }
 
-func resourceSonarqubeQualityGateProjectAssociationCreate(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/qualitygates/select"
-
-	sonarQubeURL.RawQuery = url.Values{
-		"gateName":   []string{d.Get("gatename").(string)},
-		"projectKey": []string{d.Get("projectkey").(string)},
-	}.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusNoContent,
-		"resourceSonarqubeQualityGateProjectAssociationCreate",
-	)
+func resourceSonarqubeQualityGateProjectAssociationCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	err := selectQualityGate(ctx, d.Get("gatename").(string), d.Get("projectkey").(string), m, false)
 	if err != nil {
-		return err
+		return diag.Errorf("resourceSonarqubeQualityGateProjectAssociationCreate: Failed to associate quality gate: %+v", err)
 	}
-	defer resp.Body.Close()
 
 	id := fmt.Sprintf("%v/%v", d.Get("gatename").(string), d.Get("projectkey").(string))
 	d.SetId(id)
 
-	return resourceSonarqubeQualityGateProjectAssociationRead(d, m)
+	return resourceSonarqubeQualityGateProjectAssociationRead(ctx, d, m)
 }
 
-func resourceSonarqubeQualityGateProjectAssociationRead(d *schema.ResourceData, m interface{}) error {
+func resourceSonarqubeQualityGateProjectAssociationRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	idSlice := strings.Split(d.Id(), "/")
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/qualitygates/get_by_project"
-
-	sonarQubeURL.RawQuery = url.Values{
-		"project": []string{idSlice[1]},
-	}.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"GET",
-		sonarQubeURL.String(),
-		http.StatusOK,
-		"resourceSonarqubeQualityGateProjectAssociationRead",
-	)
+	qualityGate, err := m.(*ProviderConfiguration).client.QualityGates.GetByProject(ctx, idSlice[1])
 	if err != nil {
-		return err
+		if client.IsNotFound(err) {
+			removeResourceFromState(d, "resourceSonarqubeQualityGateProjectAssociationRead")
+			return nil
+		}
+		return diag.Errorf("resourceSonarqubeQualityGateProjectAssociationRead: Failed to read quality gate of project: %+v", err)
 	}
-	defer resp.Body.Close()
 
-	// Decode response into struct
-	qualityGateAssociationReadResponse := GetQualityGateAssociation{}
-	err = json.NewDecoder(resp.Body).Decode(&qualityGateAssociationReadResponse)
+	d.Set("projectkey", idSlice[1])
+	d.Set("gatename", qualityGate.Name)
+	return nil
+}
+
+func resourceSonarqubeQualityGateProjectAssociationDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	err := selectQualityGate(ctx, d.Get("gatename").(string), d.Get("projectkey").(string), m, true)
 	if err != nil {
-		return fmt.Errorf("resourceSonarqubeQualityGateProjectAssociationRead: Failed to decode json into struct: %+v", err)
+		return diag.Errorf("resourceSonarqubeQualityGateProjectAssociationDelete: Failed to remove quality gate association: %+v", err)
 	}
 
-	d.Set("projectkey", idSlice[1])
-	d.Set("gatename", qualityGateAssociationReadResponse.QualityGate.Name)
 	return nil
 }
 
-func resourceSonarqubeQualityGateProjectAssociationDelete(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/qualitygates/deselect"
-
-	sonarQubeURL.RawQuery = url.Values{
-		"gateName":   []string{d.Get("gatename").(string)},
-		"projectKey": []string{d.Get("projectkey").(string)},
-	}.Encode()
+// selectQualityGate calls api/qualitygates/select, or api/qualitygates/deselect when deselect is true. The gate is
+// identified by id on servers whose discovered web API predates the gateName parameter.
+func selectQualityGate(ctx context.Context, gateName string, projectKey string, m interface{}, deselect bool) error {
+	conf := m.(*ProviderConfiguration)
+	action := "api/qualitygates/select"
+	if deselect {
+		action = "api/qualitygates/deselect"
+	}
+	if !conf.sonarQubeAPI.requiresGateID(action) {
+		if deselect {
+			return conf.client.QualityGates.Deselect(ctx, gateName, projectKey)
+		}
+		return conf.client.QualityGates.Select(ctx, gateName, projectKey)
+	}
 
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusNoContent,
-		"resourceSonarqubeQualityGateProjectAssociationDelete",
-	)
+	gateID, err := qualityGateID(ctx, gateName, m)
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
-
-	return nil
+	if deselect {
+		return conf.client.QualityGates.DeselectByID(ctx, gateID, projectKey)
+	}
+	return conf.client.QualityGates.SelectByID(ctx, gateID, projectKey)
 }
 
-func resourceSonarqubeQualityGateProjectAssociationImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
-	if err := resourceSonarqubeQualityGateProjectAssociationRead(d, m); err != nil {
-		return nil, err
+func resourceSonarqubeQualityGateProjectAssociationImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	if diags := resourceSonarqubeQualityGateProjectAssociationRead(ctx, d, m); diags.HasError() {
+		return nil, diagnosticsToError(diags)
 	}
 	return []*schema.ResourceData{d}, nil
 }
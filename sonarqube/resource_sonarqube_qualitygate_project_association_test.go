@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/jdamata/terraform-provider-sonarqube/internal/fakesonarqube"
 )
 
 func init() {
@@ -48,8 +49,8 @@ func TestAccSonarqubeQualitygateProjectAssociationGateName(t *testing.T) {
 	name := "sonarqube_qualitygate_project_association." + rnd
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubeQualitygateProjectAssociationGateName(rnd, "testAccSonarqubeProjectAssociationGateName"),
@@ -70,3 +71,35 @@ func TestAccSonarqubeQualitygateProjectAssociationGateName(t *testing.T) {
 		},
 	})
 }
+
+func TestAccSonarqubeQualitygateProjectAssociationGateID(t *testing.T) {
+	// SonarQube 8.3 predates gateName. With installed_version pinned, the provider only learns from the discovered
+	// web API that the gate must be identified by id.
+	server := fakesonarqube.NewServer(fakesonarqube.Options{Version: "8.3.1.34397"})
+	defer server.Close()
+	t.Setenv("SONAR_HOST", server.URL)
+	t.Setenv("SONAR_USER", fakesonarqube.DefaultLogin)
+	t.Setenv("SONAR_PASS", fakesonarqube.DefaultPassword)
+	t.Setenv("SONAR_TOKEN", "")
+	t.Setenv("SONARQUBE_TOKEN", "")
+
+	rnd := generateRandomResourceName()
+	name := "sonarqube_qualitygate_project_association." + rnd
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `provider "sonarqube" {
+					installed_version     = "9.9.0"
+					discover_capabilities = true
+				}
+				` + testAccSonarqubeQualitygateProjectAssociationGateName(rnd, "testAccSonarqubeProjectAssociationGateID"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(name, "gatename", "testAccSonarqubeProjectAssociationGateID"),
+					resource.TestCheckResourceAttr("sonarqube_qualitygate."+rnd, "condition.#", "1"),
+				),
+			},
+		},
+	})
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/hashicorp/go-retryablehttp"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/jdamata/terraform-provider-sonarqube/internal/client"
 )
 
 func init() {
@@ -48,8 +49,8 @@ func TestAccSonarqubeQualitygateBasic(t *testing.T) {
 	expectedConditions := 1
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubeQualitygateBasicConfig(rnd, "testAccSonarqubeQualitygate", "true"),
@@ -102,8 +103,8 @@ func TestAccSonarqubeQualitygateConditions(t *testing.T) {
 	expectedConditions := 2
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubeQualitygateWithConditionsConfig(rnd, "TestAccSonarqubeQualitygateConditions", "true"),
@@ -156,8 +157,8 @@ func TestAccSonarqubeQualitygateChangeDefault(t *testing.T) {
 	gate2 := baseName + "-2"
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubeQualitygateChangeDefaultConfig(rnd, "TestAccSonarqubeQualitygateChangeDefaultName", true, "10"),
@@ -210,8 +211,8 @@ func TestAccSonarqubeQualitygateDeleteDefault(t *testing.T) {
 	name := "sonarqube_qualitygate." + rnd
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubeQualitygateDeleteDefaultConfig(rnd, "TestAccSonarqubeQualitygateDeleteDefault"),
@@ -262,8 +263,8 @@ func TestAccSonarqubeQualitygateCopy(t *testing.T) {
 	baseGateResourceName := "sonarqube_qualitygate." + baseGateName
 	copyName := baseGateName + "-copy"
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubeQualitygateCopyConfig(rnd, baseGateName, "comment_lines_density", "68", "LT", copyName),
@@ -284,14 +285,14 @@ func checkSonarWayIsDefault(s *terraform.State) error {
 	b64 := base64.StdEncoding.EncodeToString([]byte(auth))
 	authHeader := "Basic " + b64
 
-	client := retryablehttp.NewClient()
+	httpClient := retryablehttp.NewClient()
 	req, _ := retryablehttp.NewRequest("GET", sonarQubeURL, http.NoBody)
 	req.Header.Set("Authorization", authHeader)
 
 	// Execute request
-	resp, err := client.Do(req)
+	resp, err := httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("checkSonarWayIsDefault: failed to execute http request: %v. Request: %v", err, req)
+		return fmt.Errorf("checkSonarWayIsDefault: failed to execute http request: %v", err)
 	}
 
 	// Check response code
@@ -300,7 +301,7 @@ func checkSonarWayIsDefault(s *terraform.State) error {
 	}
 	defer resp.Body.Close()
 
-	qualityGateReadResponse := GetQualityGate{}
+	qualityGateReadResponse := client.QualityGate{}
 	err = json.NewDecoder(resp.Body).Decode(&qualityGateReadResponse)
 	if err != nil {
 		return fmt.Errorf("checkSonarWayIsDefault: Failed to decode json into struct: %+v", err)
@@ -1,39 +1,33 @@
 package sonarqube
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"net/http"
-	"net/url"
 	"strings"
+	"time"
 
-	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/jdamata/terraform-provider-sonarqube/internal/client"
 )
 
-// GetQualityGateUsergroupAssociation for unmarshalling response body from getting quality gate association
-type GetQualityGateUsergroupAssociation struct {
-	Paging Paging                                       `json:"paging"`
-	Groups []GetQualityGateUsergroupAssociationProjects `json:"groups,omitempty"`
-	Users  []GetQualityGateUsergroupAssociationProjects `json:"users,omitempty"`
-}
-
-// GetQualityGateUsergroupAssociationProjects used in GetQualityGateUsergroupAssociation
-type GetQualityGateUsergroupAssociationProjects struct {
-	Login       string `json:"login,omitempty"`
-	Name        string `json:"name"`
-	Description string `json:"description,omitempty"`
-	Selected    bool   `json:"selected"`
-}
-
 // Returns the resource represented by this file.
 func resourceSonarqubeQualityGateUsergroupAssociation() *schema.Resource {
 	return &schema.Resource{
 		Description: `Provides a Sonarqube Quality Gate Usergroup association resource. This can be used to associate a Quality Gate to an User or to a Group.
 The feature is available on SonarQube 9.2 or newer.`,
-		Create: resourceSonarqubeQualityGateUsergroupAssociationCreate,
-		Read:   resourceSonarqubeQualityGateUsergroupAssociationRead,
-		Delete: resourceSonarqubeQualityGateUsergroupAssociationDelete,
+		CreateContext: resourceSonarqubeQualityGateUsergroupAssociationCreate,
+		ReadContext:   resourceSonarqubeQualityGateUsergroupAssociationRead,
+		DeleteContext: resourceSonarqubeQualityGateUsergroupAssociationDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceSonarqubeQualityGateUsergroupAssociationImport,
+		},
+
+		CustomizeDiff: requireCapability(capabilityQualityGatePermissions),
+
+		Timeouts: &schema.ResourceTimeout{
+			Default: schema.DefaultTimeout(5 * time.Minute),
+		},
 
 		// Define the fields of this schema.
 		Schema: map[string]*schema.Schema{
@@ -61,37 +55,26 @@ The feature is available on SonarQube 9.2 or newer.`,
 	}
 }
 
-func resourceSonarqubeQualityGateUsergroupAssociationCreate(d *schema.ResourceData, m interface{}) error {
-	if err := checkGatePermissionFeatureSupport(m.(*ProviderConfiguration)); err != nil {
-		return err
+func resourceSonarqubeQualityGateUsergroupAssociationCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if err := m.(*ProviderConfiguration).checkCapability(capabilityQualityGatePermissions); err != nil {
+		return diag.FromErr(err)
 	}
 
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	rawQuery := url.Values{
-		"gateName": []string{d.Get("gatename").(string)},
+	qualityGatesService := m.(*ProviderConfiguration).client.QualityGates
+	request := client.QualityGatePermissionRequest{
+		GateName: d.Get("gatename").(string),
 	}
 
+	var err error
 	if _, ok := d.GetOk("login_name"); ok {
-		sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/qualitygates/add_user"
-		rawQuery.Add("login", d.Get("login_name").(string))
+		request.Login = d.Get("login_name").(string)
+		err = qualityGatesService.AddUser(ctx, request)
 	} else {
-		sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/qualitygates/add_group"
-		rawQuery.Add("groupName", d.Get("group_name").(string))
+		request.GroupName = d.Get("group_name").(string)
+		err = qualityGatesService.AddGroup(ctx, request)
 	}
-
-	sonarQubeURL.RawQuery = rawQuery.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusNoContent,
-		"resourceSonarqubeQualityGateUsergroupAssociationCreate",
-	)
-	defer resp.Body.Close()
-
 	if err != nil {
-		return fmt.Errorf("resourceSonarqubeQualityGateUsergroupAssociationCreate: Failed creating Sonarqube quality gate usergroup association for quality gate '%s': %+v", d.Get("gatename").(string), err)
+		return diag.Errorf("resourceSonarqubeQualityGateUsergroupAssociationCreate: Failed creating Sonarqube quality gate usergroup association for quality gate '%s': %+v", d.Get("gatename").(string), err)
 	}
 
 	if _, ok := d.GetOk("login_name"); ok {
@@ -99,109 +82,108 @@ func resourceSonarqubeQualityGateUsergroupAssociationCreate(d *schema.ResourceDa
 	} else {
 		d.SetId(createGatePermissionId(d.Get("gatename").(string), "group", d.Get("group_name").(string)))
 	}
-	return resourceSonarqubeQualityGateUsergroupAssociationRead(d, m)
+	return resourceSonarqubeQualityGateUsergroupAssociationRead(ctx, d, m)
 }
 
-func resourceSonarqubeQualityGateUsergroupAssociationRead(d *schema.ResourceData, m interface{}) error {
-	if err := checkGatePermissionFeatureSupport(m.(*ProviderConfiguration)); err != nil {
-		return err
+func resourceSonarqubeQualityGateUsergroupAssociationRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if err := m.(*ProviderConfiguration).checkCapability(capabilityQualityGatePermissions); err != nil {
+		return diag.FromErr(err)
 	}
 
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.RawQuery = url.Values{
-		"gateName": []string{d.Get("gatename").(string)},
-		"selected": []string{"selected"},
-	}.Encode()
+	qualityGatesService := m.(*ProviderConfiguration).client.QualityGates
+	request := client.QualityGateSearchPermissionsRequest{
+		GateName: d.Get("gatename").(string),
+		Selected: "selected",
+	}
 
-	if _, ok := d.GetOk("login_name"); ok {
-		sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/qualitygates/search_users"
+	var match func(value client.QualityGatePermission) bool
+	pager := qualityGatesService.SearchGroupsPages(request)
+	if login, ok := d.GetOk("login_name"); ok {
+		// Walk all users to see if the user we need exists.
+		pager = qualityGatesService.SearchUsersPages(request)
+		match = func(value client.QualityGatePermission) bool {
+			return strings.EqualFold(value.Login, login.(string))
+		}
 	} else {
-		sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/qualitygates/search_groups"
+		// Walk all groups to see if the group we need exists.
+		groupName := d.Get("group_name").(string)
+		match = func(value client.QualityGatePermission) bool {
+			return strings.EqualFold(value.Name, groupName)
+		}
 	}
 
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"GET",
-		sonarQubeURL.String(),
-		http.StatusOK,
-		"resourceSonarqubeQualityGateUsergroupAssociationRead",
-	)
+	permission, err := pager.Find(ctx, match)
 	if err != nil {
-		return fmt.Errorf("resourceSonarqubeQualityGateUsergroupAssociationRead: Failed to call quality gate usergroup association api: %+v", err)
+		if client.IsNotFound(err) {
+			removeResourceFromState(d, "resourceSonarqubeQualityGateUsergroupAssociationRead")
+			return nil
+		}
+		return diag.Errorf("resourceSonarqubeQualityGateUsergroupAssociationRead: Failed to call quality gate usergroup association api: %+v", err)
 	}
-	defer resp.Body.Close()
 
-	// Decode response into struct
-	qualityGateUsergroupAssociationReadResponse := GetQualityGateUsergroupAssociation{}
-	err = json.NewDecoder(resp.Body).Decode(&qualityGateUsergroupAssociationReadResponse)
-	if err != nil {
-		return fmt.Errorf("resourceQualityGateRead: Failed to decode json into struct: %+v", err)
+	if permission == nil {
+		removeResourceFromState(d, "resourceSonarqubeQualityGateUsergroupAssociationRead")
+		return nil
 	}
 
-	if _, ok := d.GetOk("login_name"); ok {
-		// Loop over all groups to see if the group we need exists.
-		login := d.Get("login_name").(string)
-		for _, value := range qualityGateUsergroupAssociationReadResponse.Users {
-			if strings.EqualFold(value.Login, login) {
-				d.Set("login_name", value.Login)
-				return nil
-			}
-		}
+	if permission.Login != "" {
+		d.Set("login_name", permission.Login)
 	} else {
-		// Loop over all groups to see if the group we need exists.
-		groupName := d.Get("group_name").(string)
-		for _, value := range qualityGateUsergroupAssociationReadResponse.Groups {
-			if strings.EqualFold(value.Name, groupName) {
-				return nil
-			}
-		}
+		d.Set("group_name", permission.Name)
 	}
-	return fmt.Errorf("resourceSonarqubeQualityGateUsergroupAssociationRead: Failed to call quality gate usergroup association api: %+v", err)
+	return nil
 }
 
-func resourceSonarqubeQualityGateUsergroupAssociationDelete(d *schema.ResourceData, m interface{}) error {
-	if err := checkGatePermissionFeatureSupport(m.(*ProviderConfiguration)); err != nil {
-		return err
+func resourceSonarqubeQualityGateUsergroupAssociationDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if err := m.(*ProviderConfiguration).checkCapability(capabilityQualityGatePermissions); err != nil {
+		return diag.FromErr(err)
 	}
 
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	rawQuery := url.Values{
-		"gateName": []string{d.Get("gatename").(string)},
+	qualityGatesService := m.(*ProviderConfiguration).client.QualityGates
+	request := client.QualityGatePermissionRequest{
+		GateName: d.Get("gatename").(string),
 	}
 
+	var err error
 	if _, ok := d.GetOk("login_name"); ok {
-		sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/qualitygates/remove_user"
-		rawQuery.Add("login", d.Get("login_name").(string))
+		request.Login = d.Get("login_name").(string)
+		err = qualityGatesService.RemoveUser(ctx, request)
 	} else {
-		sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/qualitygates/remove_group"
-		rawQuery.Add("groupName", d.Get("group_name").(string))
+		request.GroupName = d.Get("group_name").(string)
+		err = qualityGatesService.RemoveGroup(ctx, request)
 	}
-
-	sonarQubeURL.RawQuery = rawQuery.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusNoContent,
-		"resourceSonarqubeQualityGateProjectAssociationDelete",
-	)
 	if err != nil {
-		return fmt.Errorf("resourceSonarqubeQualityGateUsergroupAssociationDelete: Failed to call quality gate usergroup association api: %+v", err)
+		return diag.Errorf("resourceSonarqubeQualityGateUsergroupAssociationDelete: Failed to call quality gate usergroup association api: %+v", err)
 	}
-	defer resp.Body.Close()
 
 	return nil
 }
 
-func createGatePermissionId(gateName string, targetType string, target string) string {
-	return gateName + "[" + targetType + "/" + target + "]"
-}
+func resourceSonarqubeQualityGateUsergroupAssociationImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	// import id in format {gatename}/group/{group_name} or {gatename}/user/{login_name}
+	idSlice := strings.Split(d.Id(), "/")
+	if len(idSlice) < 3 || (idSlice[len(idSlice)-2] != "group" && idSlice[len(idSlice)-2] != "user") {
+		return nil, fmt.Errorf("resourceSonarqubeQualityGateUsergroupAssociationImport: Import id '%s' is not in format {gatename}/group/{group_name} or {gatename}/user/{login_name}", d.Id())
+	}
+	gateName := strings.Join(idSlice[:len(idSlice)-2], "/")
+	targetType, target := idSlice[len(idSlice)-2], idSlice[len(idSlice)-1]
 
-func checkGatePermissionFeatureSupport(conf *ProviderConfiguration) error {
-	minimumVersion, _ := version.NewVersion("9.2")
-	if conf.sonarQubeVersion.LessThan(minimumVersion) {
-		return fmt.Errorf("Minimum required SonarQube version for quality gate permissions is %s", minimumVersion)
+	d.Set("gatename", gateName)
+	if targetType == "user" {
+		d.Set("login_name", target)
+	} else {
+		d.Set("group_name", target)
 	}
-	return nil
+	d.SetId(createGatePermissionId(gateName, targetType, target))
+	if diags := resourceSonarqubeQualityGateUsergroupAssociationRead(ctx, d, m); diags.HasError() {
+		return nil, diagnosticsToError(diags)
+	}
+	if d.Id() == "" {
+		return nil, fmt.Errorf("resourceSonarqubeQualityGateUsergroupAssociationImport: The %s '%s' is not associated to the quality gate '%s'", targetType, target, gateName)
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+func createGatePermissionId(gateName string, targetType string, target string) string {
+	return gateName + "[" + targetType + "/" + target + "]"
 }
@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"testing"
 
-	"github.com/hashicorp/go-version"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 )
 
@@ -20,12 +19,7 @@ func testSweepSonarqubeQualitygateUsergroupAssociationSweeper(r string) error {
 	return nil
 }
 func testAccPreCheckQualityGatePermissionFeature(t *testing.T) {
-	sonarQubeVersion := testAccProvider.Meta().(*ProviderConfiguration).sonarQubeVersion
-
-	minimumVersion, _ := version.NewVersion("9.2")
-	if sonarQubeVersion.LessThan(minimumVersion) {
-		t.Skipf("Skipping test of unsupported feature")
-	}
+	testAccPreCheckCapability(t, capabilityQualityGatePermissions)
 }
 
 func testAccSonarqubeQualitygateGroupAssociationGateName(rnd string, name string) string {
@@ -56,8 +50,8 @@ func TestAccSonarqubeQualitygateGroupAssociationGateName(t *testing.T) {
 	name := "sonarqube_qualitygate_usergroup_association." + rnd
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t); testAccPreCheckQualityGatePermissionFeature(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t); testAccPreCheckQualityGatePermissionFeature(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubeQualitygateGroupAssociationGateName(rnd, "ping"),
@@ -66,6 +60,12 @@ func TestAccSonarqubeQualitygateGroupAssociationGateName(t *testing.T) {
 					resource.TestCheckResourceAttr(name, "group_name", "ping"),
 				),
 			},
+			{
+				ResourceName:      name,
+				ImportState:       true,
+				ImportStateId:     "ping/group/ping",
+				ImportStateVerify: true,
+			},
 		},
 	})
 }
@@ -99,8 +99,8 @@ func TestAccSonarqubeQualitygateUserAssociationGateName(t *testing.T) {
 	name := "sonarqube_qualitygate_usergroup_association." + rnd
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t); testAccPreCheckQualityGatePermissionFeature(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t); testAccPreCheckQualityGatePermissionFeature(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubeQualitygateUserAssociationGateName(rnd, "pong"),
@@ -109,6 +109,12 @@ func TestAccSonarqubeQualitygateUserAssociationGateName(t *testing.T) {
 					resource.TestCheckResourceAttr(name, "login_name", "pong"),
 				),
 			},
+			{
+				ResourceName:      name,
+				ImportState:       true,
+				ImportStateId:     "pong/user/pong",
+				ImportStateVerify: true,
+			},
 		},
 	})
 }
@@ -1,71 +1,26 @@
 package sonarqube
 
 import (
-	"encoding/json"
-	"fmt"
-	"net/http"
-	"net/url"
-	"strings"
+	"context"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
-// QualityProfile struct
-type QualityProfile struct {
-	IsDefault    bool   `json:"isDefault,omitempty"`
-	IsInherited  bool   `json:"isInherited,omitempty"`
-	Language     string `json:"language"`
-	LanguageName string `json:"languageName"`
-	Name         string `json:"name"`
-	Key          string `json:"key"`
-}
-
-// CreateQualityProfileResponse for unmarshalling response body from creating quality profiles
-type CreateQualityProfileResponse struct {
-	Profile  QualityProfile `json:"profile"`
-	Warnings []string       `json:"warnings"`
-}
-
-// GetQualityProfileList for unmarshalling response body of quality gate get
-type GetQualityProfileList struct {
-	Profiles []GetQualityProfile `json:"profiles"`
-}
-
-// GetQualityProfile for unmarshalling response body of quality gate get
-type GetQualityProfile struct {
-	Key                       string                   `json:"key"`
-	Name                      string                   `json:"name"`
-	Language                  string                   `json:"language"`
-	LanguageName              string                   `json:"languageName"`
-	IsInherited               bool                     `json:"isInherited"`
-	IsBuiltIn                 bool                     `json:"isBuiltIn"`
-	ActiveRuleCount           int                      `json:"activeRuleCount"`
-	ActiveDeprecatedRuleCount int                      `json:"activeDeprecatedRuleCount"`
-	IsDefault                 bool                     `json:"isDefault"`
-	RuleUpdatedAt             string                   `json:"ruleUpdatedAt"`
-	LastUsed                  string                   `json:"lastUsed"`
-	Actions                   GetQualityProfileActions `json:"actions"`
-}
-
-// GetQualityProfileActions for unmarshalling response body of quality gate get
-type GetQualityProfileActions struct {
-	Edit              bool `json:"edit"`
-	SetAsDefault      bool `json:"setAsDefault"`
-	Copy              bool `json:"copy"`
-	Delete            bool `json:"delete"`
-	AssociateProjects bool `json:"associateProjects"`
-}
-
 // Returns the resource represented by this file.
 func resourceSonarqubeQualityProfile() *schema.Resource {
 	return &schema.Resource{
-		Description: "Provides a Sonarqube Quality Profile resource. This can be used to create and manage Sonarqube Quality Profiles.",
-		Create:      resourceSonarqubeQualityProfileCreate,
-		Read:        resourceSonarqubeQualityProfileRead,
-		Delete:      resourceSonarqubeQualityProfileDelete,
+		Description:   "Provides a Sonarqube Quality Profile resource. This can be used to create and manage Sonarqube Quality Profiles.",
+		CreateContext: resourceSonarqubeQualityProfileCreate,
+		ReadContext:   resourceSonarqubeQualityProfileRead,
+		DeleteContext: resourceSonarqubeQualityProfileDelete,
 		Importer: &schema.ResourceImporter{
-			State: resourceSonarqubeQualityProfileImport,
+			StateContext: resourceSonarqubeQualityProfileImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Default: schema.DefaultTimeout(5 * time.Minute),
 		},
 
 		// Define the fields of this schema.
@@ -107,73 +62,38 @@ func resourceSonarqubeQualityProfile() *schema.Resource {
 	}
 }
 
-func resourceSonarqubeQualityProfileCreate(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/qualityprofiles/create"
-
-	sonarQubeURL.RawQuery = url.Values{
-		"name":     []string{d.Get("name").(string)},
-		"language": []string{d.Get("language").(string)},
-	}.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusOK,
-		"resourceSonarqubeQualityProfileCreate",
+func resourceSonarqubeQualityProfileCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	qualityProfile, err := m.(*ProviderConfiguration).client.QualityProfiles.Create(
+		ctx,
+		d.Get("name").(string),
+		d.Get("language").(string),
 	)
 	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	// Decode response into struct
-	qualityProfileResponse := CreateQualityProfileResponse{}
-	err = json.NewDecoder(resp.Body).Decode(&qualityProfileResponse)
-	if err != nil {
-		return fmt.Errorf("resourceSonarqubeQualityProfileCreate: Failed to decode json into struct: %+v", err)
+		return diag.Errorf("resourceSonarqubeQualityProfileCreate: Failed to create quality profile: %+v", err)
 	}
 
 	if d.Get("is_default").(bool) {
-		err := setDefaultQualityProfile(d, m, d.Get("is_default").(bool))
+		err := setDefaultQualityProfile(ctx, d, m, d.Get("is_default").(bool))
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
-	err = setParentQualityProfile(d, m)
+	err = setParentQualityProfile(ctx, d, m)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	d.SetId(qualityProfileResponse.Profile.Key)
-	return resourceSonarqubeQualityProfileRead(d, m)
+	d.SetId(qualityProfile.Key)
+	return resourceSonarqubeQualityProfileRead(ctx, d, m)
 }
 
-func resourceSonarqubeQualityProfileRead(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/qualityprofiles/search"
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"GET",
-		sonarQubeURL.String(),
-		http.StatusOK,
-		"resourceSonarqubeQualityProfileRead",
-	)
+func resourceSonarqubeQualityProfileRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	qualityProfiles, err := m.(*ProviderConfiguration).client.QualityProfiles.Search(ctx)
 	if err != nil {
-		return err
+		return diag.Errorf("resourceSonarqubeQualityProfileRead: Failed to search quality profiles: %+v", err)
 	}
-	defer resp.Body.Close()
 
-	// Decode response into struct
-	getQualityProfileResponse := GetQualityProfileList{}
-	err = json.NewDecoder(resp.Body).Decode(&getQualityProfileResponse)
-	if err != nil {
-		return fmt.Errorf("resourceSonarqubeQualityProfileRead: Failed to decode json into struct: %+v", err)
-	}
-
-	for _, value := range getQualityProfileResponse.Profiles {
+	for _, value := range qualityProfiles {
 		if d.Id() == value.Key {
 			d.SetId(value.Key)
 			d.Set("name", value.Name)
@@ -184,94 +104,45 @@ func resourceSonarqubeQualityProfileRead(d *schema.ResourceData, m interface{})
 		}
 	}
 
-	return fmt.Errorf("resourceSonarqubeQualityProfileRead: Failed to find project: %+v", d.Id())
+	removeResourceFromState(d, "resourceSonarqubeQualityProfileRead")
+	return nil
 }
 
-func resourceSonarqubeQualityProfileDelete(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/qualityprofiles/delete"
-
-	sonarQubeURL.RawQuery = url.Values{
-		"qualityProfile": []string{d.Get("name").(string)},
-		"language":       []string{d.Get("language").(string)},
-	}.Encode()
-
-	err := setDefaultQualityProfile(d, m, false)
+func resourceSonarqubeQualityProfileDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	err := setDefaultQualityProfile(ctx, d, m, false)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusNoContent,
-		"resourceSonarqubeQualityProfileDelete",
-	)
+	err = m.(*ProviderConfiguration).client.QualityProfiles.Delete(ctx, d.Get("name").(string), d.Get("language").(string))
 	if err != nil {
-		return fmt.Errorf("resourceSonarqubeQualityProfileDelete: Failed to delete quality profile: %+v", err)
+		return diag.Errorf("resourceSonarqubeQualityProfileDelete: Failed to delete quality profile: %+v", err)
 	}
-	defer resp.Body.Close()
 
 	return nil
 }
 
-func resourceSonarqubeQualityProfileImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
-	if err := resourceSonarqubeQualityProfileRead(d, m); err != nil {
-		return nil, err
+func resourceSonarqubeQualityProfileImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	if diags := resourceSonarqubeQualityProfileRead(ctx, d, m); diags.HasError() {
+		return nil, diagnosticsToError(diags)
 	}
 	return []*schema.ResourceData{d}, nil
 }
 
-func setDefaultQualityProfile(d *schema.ResourceData, m interface{}, setDefault bool) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/qualityprofiles/set_default"
-
+func setDefaultQualityProfile(ctx context.Context, d *schema.ResourceData, m interface{}, setDefault bool) error {
+	qualityProfile := "Sonar way"
 	if setDefault {
-		sonarQubeURL.RawQuery = url.Values{
-			"qualityProfile": []string{d.Get("name").(string)},
-			"language":       []string{d.Get("language").(string)},
-		}.Encode()
-	} else {
-		sonarQubeURL.RawQuery = url.Values{
-			"qualityProfile": []string{"Sonar way"},
-			"language":       []string{d.Get("language").(string)},
-		}.Encode()
+		qualityProfile = d.Get("name").(string)
 	}
 
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusNoContent,
-		"setDefaultQualityProfile",
-	)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-	return nil
+	return m.(*ProviderConfiguration).client.QualityProfiles.SetDefault(ctx, qualityProfile, d.Get("language").(string))
 }
 
-func setParentQualityProfile(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/qualityprofiles/change_parent"
-
-	sonarQubeURL.RawQuery = url.Values{
-		"qualityProfile":       []string{d.Get("name").(string)},
-		"language":             []string{d.Get("language").(string)},
-		"parentQualityProfile": []string{d.Get("parent").(string)},
-	}.Encode()
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusNoContent,
-		"setParentQualityProfile",
+func setParentQualityProfile(ctx context.Context, d *schema.ResourceData, m interface{}) error {
+	return m.(*ProviderConfiguration).client.QualityProfiles.ChangeParent(
+		ctx,
+		d.Get("name").(string),
+		d.Get("language").(string),
+		d.Get("parent").(string),
 	)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-	return nil
 }
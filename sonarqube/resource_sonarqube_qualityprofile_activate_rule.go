@@ -1,36 +1,28 @@
 package sonarqube
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"net/http"
-	"net/url"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/jdamata/terraform-provider-sonarqube/internal/client"
 )
 
-type Actives struct {
-	QProfile string   `json:"qProfile"`
-	Inherit  string   `json:"inherit"`
-	Severity string   `json:"severity"`
-	Params   []Params `json:"params"`
-}
-
-type GetActiveRules struct {
-	Rule    Rule      `json:"rule"`
-	Actives []Actives `json:"actives"`
-}
-
 func resourceSonarqubeQualityProfileRule() *schema.Resource {
 	return &schema.Resource{
-		Description: "Provides a Sonarqube Rules resource. This can be used to manage Sonarqube rules.",
-		Create:      resourceSonarqubeQualityProfileRuleCreate,
-		Delete:      resourceSonarqubeQualityProfileRuleDelete,
-		Read:        resourceSonarqubeQualityProfileRuleRead,
+		Description:   "Provides a Sonarqube Rules resource. This can be used to manage Sonarqube rules.",
+		CreateContext: resourceSonarqubeQualityProfileRuleCreate,
+		DeleteContext: resourceSonarqubeQualityProfileRuleDelete,
+		ReadContext:   resourceSonarqubeQualityProfileRuleRead,
 		Importer: &schema.ResourceImporter{
-			State: resourceSonarqubeQualityProfileRuleImporter,
+			StateContext: resourceSonarqubeQualityProfileRuleImporter,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Default: schema.DefaultTimeout(5 * time.Minute),
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -86,93 +78,84 @@ func resourceSonarqubeQualityProfileRule() *schema.Resource {
 	}
 }
 
-func resourceSonarqubeQualityProfileRuleCreate(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/qualityprofiles/activate_rule"
-
-	sonarQubeURL.RawQuery = url.Values{
-		"key":      []string{d.Get("key").(string)},
-		"params":   []string{d.Get("params").(string)},
-		"reset":    []string{d.Get("reset").(string)},
-		"rule":     []string{d.Get("rule").(string)},
-		"severity": []string{d.Get("severity").(string)},
-	}.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusNoContent,
-		"resourceSonarqubeQualityProfileRuleCreate",
-	)
+func resourceSonarqubeQualityProfileRuleCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	request := client.ActivateRuleRequest{
+		Key:      d.Get("key").(string),
+		Params:   d.Get("params").(string),
+		Reset:    d.Get("reset").(string),
+		Rule:     d.Get("rule").(string),
+		Severity: d.Get("severity").(string),
+	}
+	err := m.(*ProviderConfiguration).client.QualityProfiles.ActivateRule(ctx, request)
 	if err != nil {
-		return fmt.Errorf("resourceSonarqubeQualityProfileRuleCreate: Failed to delete quality profile: %+v", err)
+		return diag.Errorf("resourceSonarqubeQualityProfileRuleCreate: Failed to delete quality profile: %+v", err)
 	}
-	defer resp.Body.Close()
 
 	d.SetId(d.Get("rule").(string))
-	return resourceSonarqubeQualityProfileRuleRead(d, m)
+	return resourceSonarqubeQualityProfileRuleRead(ctx, d, m)
 }
 
-func resourceSonarqubeQualityProfileRuleDelete(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/qualityprofiles/deactivate_rule"
-	sonarQubeURL.RawQuery = url.Values{
-		"key":  []string{d.Get("key").(string)},
-		"rule": []string{d.Get("rule").(string)},
-	}.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusNoContent,
-		"resourceSonarqubeQualityProfileRuleDelete",
-	)
+func resourceSonarqubeQualityProfileRuleDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	err := m.(*ProviderConfiguration).client.QualityProfiles.DeactivateRule(ctx, d.Get("key").(string), d.Get("rule").(string))
 	if err != nil {
-		return fmt.Errorf("resourceSonarqubeQualityProfileRuleDelete: Failed to delete quality profile: %+v", err)
+		return diag.Errorf("resourceSonarqubeQualityProfileRuleDelete: Failed to delete quality profile: %+v", err)
 	}
-	defer resp.Body.Close()
 
 	return nil
 }
 
-func resourceSonarqubeQualityProfileRuleRead(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/rules/show"
-	sonarQubeURL.RawQuery = url.Values{
-		"key":     []string{d.Id()},
-		"actives": []string{"true"},
-	}.Encode()
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"GET",
-		sonarQubeURL.String(),
-		http.StatusOK,
-		"resourceSonarqubeQualityProfileRuleRead",
-	)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	activeRuleReadResponse := GetActiveRules{}
-	err = json.NewDecoder(resp.Body).Decode(&activeRuleReadResponse)
+func resourceSonarqubeQualityProfileRuleRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	activeRuleReadResponse, err := m.(*ProviderConfiguration).client.Rules.Show(ctx, d.Id())
 	if err != nil {
-		return fmt.Errorf("resourceSonarqubeQualityProfileRuleRead: Failed to decode json into struct: %+v", err)
+		if client.IsNotFound(err) {
+			removeResourceFromState(d, "resourceSonarqubeQualityProfileRuleRead")
+			return nil
+		}
+		return diag.Errorf("resourceSonarqubeQualityProfileRuleRead: Failed to read rule: %+v", err)
 	}
 
-	if d.Id() == activeRuleReadResponse.Rule.RuleKey {
-		d.SetId(activeRuleReadResponse.Rule.RuleKey)
+	if d.Id() == activeRuleReadResponse.Rule.Key {
+		d.SetId(activeRuleReadResponse.Rule.Key)
 		return nil
 	}
 
-	return fmt.Errorf("resourceSonarqubeQualityProfileRuleRead: Failed to find project: %+v", d.Id())
+	removeResourceFromState(d, "resourceSonarqubeQualityProfileRuleRead")
+	return nil
 }
 
-func resourceSonarqubeQualityProfileRuleImporter(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
-	if err := resourceSonarqubeQualityProfileRuleRead(d, m); err != nil {
-		return nil, err
+func resourceSonarqubeQualityProfileRuleImporter(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	// import id in format {key}/{rule} or {rule}
+	if key, rule, ok := strings.Cut(d.Id(), "/"); ok {
+		activeRuleReadResponse, err := m.(*ProviderConfiguration).client.Rules.Show(ctx, rule)
+		if err != nil {
+			return nil, fmt.Errorf("resourceSonarqubeQualityProfileRuleImporter: Failed to read rule: %+v", err)
+		}
+		var activeRule *client.ActiveRule
+		for i, active := range activeRuleReadResponse.Actives {
+			if active.QProfile == key {
+				activeRule = &activeRuleReadResponse.Actives[i]
+			}
+		}
+		if activeRule == nil {
+			return nil, fmt.Errorf("resourceSonarqubeQualityProfileRuleImporter: Rule '%s' is not activated in quality profile '%s'", rule, key)
+		}
+
+		d.SetId(rule)
+		d.Set("key", key)
+		d.Set("rule", rule)
+		d.Set("severity", activeRule.Severity)
+		d.Set("reset", "false")
+		if len(activeRule.Params) > 0 {
+			params := []string{}
+			for _, param := range activeRule.Params {
+				params = append(params, param.Key+"="+param.Value)
+			}
+			d.Set("params", strings.Join(params, ";"))
+		}
+	}
+
+	if diags := resourceSonarqubeQualityProfileRuleRead(ctx, d, m); diags.HasError() {
+		return nil, diagnosticsToError(diags)
 	}
 	return []*schema.ResourceData{d}, nil
 }
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
 )
 
 func init() {
@@ -46,8 +47,8 @@ func TestAccSonarqubeQualityprofileActivateRuleBasic(t *testing.T) {
 	name := "sonarqube_qualityprofile_activate_rule." + rnd
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubeQualityprofileActivateRuleBasicConfig(rnd, "testProfile", "activateRule", "BLOCKER"),
@@ -68,6 +69,22 @@ func TestAccSonarqubeQualityprofileActivateRuleBasic(t *testing.T) {
 					resource.TestCheckResourceAttr(name, "severity", "BLOCKER"),
 				),
 			},
+			{
+				ResourceName:      name,
+				ImportState:       true,
+				ImportStateIdFunc: testAccSonarqubeQualityprofileActivateRuleImportID(name),
+				ImportStateVerify: true,
+			},
 		},
 	})
 }
+
+func testAccSonarqubeQualityprofileActivateRuleImportID(resourceNode string) resource.ImportStateIdFunc {
+	return func(s *terraform.State) (string, error) {
+		rs, ok := s.RootModule().Resources[resourceNode]
+		if !ok {
+			return "", fmt.Errorf("Resource node not found: %s", resourceNode)
+		}
+		return fmt.Sprintf("%s/%s", rs.Primary.Attributes["key"], rs.Primary.Attributes["rule"]), nil
+	}
+}
@@ -1,39 +1,29 @@
 package sonarqube
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"net/http"
-	"net/url"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/jdamata/terraform-provider-sonarqube/internal/client"
 )
 
-// GetQualityProfileProjectAssociation for unmarshalling response body from getting quality profile association
-type GetQualityProfileProjectAssociation struct {
-	Paging  Paging                                       `json:"paging"`
-	Results []GetQualityProfileProjectAssociationResults `json:"results"`
-}
-
-// GetQualityProfileProjectAssociationResults used in GetQualityProfileProjectAssociation
-type GetQualityProfileProjectAssociationResults struct {
-	ID       string `json:"id"`
-	Name     string `json:"name"`
-	Key      string `json:"key"`
-	Selected bool   `json:"selected"`
-}
-
 // Returns the resource represented by this file.
 func resourceSonarqubeQualityProfileProjectAssociation() *schema.Resource {
 	return &schema.Resource{
-		Description: "Provides a Sonarqube Quality Profile Project association resource. This can be used to associate a Quality Profile to a Project",
-		Create:      resourceSonarqubeQualityProfileProjectAssociationCreate,
-		Read:        resourceSonarqubeQualityProfileProjectAssociationRead,
-		Delete:      resourceSonarqubeQualityProfileProjectAssociationDelete,
+		Description:   "Provides a Sonarqube Quality Profile Project association resource. This can be used to associate a Quality Profile to a Project",
+		CreateContext: resourceSonarqubeQualityProfileProjectAssociationCreate,
+		ReadContext:   resourceSonarqubeQualityProfileProjectAssociationRead,
+		DeleteContext: resourceSonarqubeQualityProfileProjectAssociationDelete,
 		Importer: &schema.ResourceImporter{
-			State: resourceSonarqubeQualityProfileProjectAssociationImport,
+			StateContext: resourceSonarqubeQualityProfileProjectAssociationImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Default: schema.DefaultTimeout(5 * time.Minute),
 		},
 
 		// Define the fields of this schema.
@@ -66,64 +56,36 @@ func resourceSonarqubeQualityProfileProjectAssociation() *schema.Resource {
 	}
 }
 
-func resourceSonarqubeQualityProfileProjectAssociationCreate(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/qualityprofiles/add_project"
-	sonarQubeURL.RawQuery = url.Values{
-		"language":       []string{d.Get("language").(string)},
-		"project":        []string{d.Get("project").(string)},
-		"qualityProfile": []string{d.Get("quality_profile").(string)},
-	}.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusNoContent,
-		"resourceSonarqubeQualityProfileProjectAssociationCreate",
+func resourceSonarqubeQualityProfileProjectAssociationCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	err := m.(*ProviderConfiguration).client.QualityProfiles.AddProject(
+		ctx,
+		d.Get("quality_profile").(string),
+		d.Get("language").(string),
+		d.Get("project").(string),
 	)
 	if err != nil {
-		return err
+		return diag.Errorf("resourceSonarqubeQualityProfileProjectAssociationCreate: Failed to associate quality profile: %+v", err)
 	}
-	defer resp.Body.Close()
 
 	id := fmt.Sprintf("%v/%v/%v", d.Get("quality_profile").(string), d.Get("project").(string), d.Get("language").(string))
 	d.SetId(id)
-	return resourceSonarqubeQualityProfileProjectAssociationRead(d, m)
+	return resourceSonarqubeQualityProfileProjectAssociationRead(ctx, d, m)
 }
 
-func resourceSonarqubeQualityProfileProjectAssociationRead(d *schema.ResourceData, m interface{}) error {
+func resourceSonarqubeQualityProfileProjectAssociationRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	var language string
 	var qualityProfile string
 
 	// Id is composed of qualityProfile name and project name
 	idSlice := strings.Split(d.Id(), "/")
 	// Call api/qualityprofiles/search to return the qualityProfileID
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURLSubPath := sonarQubeURL.Path
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURLSubPath, "/") + "/api/qualityprofiles/search"
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"GET",
-		sonarQubeURL.String(),
-		http.StatusOK,
-		"resourceSonarqubeQualityProfileProjectAssociationRead",
-	)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	// Decode response into struct
-	getQualityProfileResponse := GetQualityProfileList{}
-	err = json.NewDecoder(resp.Body).Decode(&getQualityProfileResponse)
+	qualityProfiles, err := m.(*ProviderConfiguration).client.QualityProfiles.Search(ctx)
 	if err != nil {
-		return fmt.Errorf("resourceSonarqubeQualityProfileProjectAssociationRead: Failed to decode json into struct: %+v", err)
+		return diag.Errorf("resourceSonarqubeQualityProfileProjectAssociationRead: Failed to search quality profiles: %+v", err)
 	}
 
 	var qualityProfileID string
-	for _, value := range getQualityProfileResponse.Profiles {
+	for _, value := range qualityProfiles {
 		if len(idSlice) == 3 {
 			if idSlice[0] == value.Name && idSlice[2] == value.Language {
 				qualityProfileID = value.Key
@@ -139,70 +101,56 @@ func resourceSonarqubeQualityProfileProjectAssociationRead(d *schema.ResourceDat
 		}
 	}
 
-	// With the qualityProfileID we can check if the project name is associated
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURLSubPath, "/") + "/api/qualityprofiles/projects"
-	sonarQubeURL.RawQuery = url.Values{
-		"key": []string{qualityProfileID},
-	}.Encode()
-
-	resp, err = httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"GET",
-		sonarQubeURL.String(),
-		http.StatusOK,
-		"resourceSonarqubeQualityProfileProjectAssociationRead",
-	)
-	if err != nil {
-		return err
+	if qualityProfileID == "" {
+		// The quality profile itself no longer exists
+		removeResourceFromState(d, "resourceSonarqubeQualityProfileProjectAssociationRead")
+		return nil
 	}
-	defer resp.Body.Close()
 
-	// Decode response into struct
-	getQualityProfileProjectResponse := GetQualityProfileProjectAssociation{}
-	err = json.NewDecoder(resp.Body).Decode(&getQualityProfileProjectResponse)
-	if err != nil {
-		return fmt.Errorf("resourceSonarqubeQualityProfileProjectAssociationRead: Failed to decode json into struct: %+v", err)
+	// With the qualityProfileID we can check if the project name is associated
+	request := client.QualityProfileProjectsRequest{
+		Key: qualityProfileID,
 	}
-	for _, value := range getQualityProfileProjectResponse.Results {
-		if idSlice[1] == value.Key {
-			d.SetId(d.Id())
-			d.Set("project", value.Key)
-			d.Set("quality_profile", qualityProfile)
-			d.Set("language", language)
+	project, err := m.(*ProviderConfiguration).client.QualityProfiles.ProjectsPages(request).Find(ctx, func(value client.QualityProfileProject) bool {
+		return idSlice[1] == value.Key
+	})
+	if err != nil {
+		if client.IsNotFound(err) {
+			removeResourceFromState(d, "resourceSonarqubeQualityProfileProjectAssociationRead")
 			return nil
 		}
+		return diag.Errorf("resourceSonarqubeQualityProfileProjectAssociationRead: Failed to list quality profile projects: %+v", err)
+	}
+
+	if project != nil {
+		d.SetId(d.Id())
+		d.Set("project", project.Key)
+		d.Set("quality_profile", qualityProfile)
+		d.Set("language", language)
+		return nil
 	}
 
-	return fmt.Errorf("resourceSonarqubeQualityProfileProjectAssociationRead: Failed to find project association: %+v", d.Id())
+	removeResourceFromState(d, "resourceSonarqubeQualityProfileProjectAssociationRead")
+	return nil
 }
 
-func resourceSonarqubeQualityProfileProjectAssociationDelete(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/qualityprofiles/remove_project"
-	sonarQubeURL.RawQuery = url.Values{
-		"language":       []string{d.Get("language").(string)},
-		"project":        []string{d.Get("project").(string)},
-		"qualityProfile": []string{d.Get("quality_profile").(string)},
-	}.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusNoContent,
-		"resourceSonarqubeQualityProfileProjectAssociationDelete",
+func resourceSonarqubeQualityProfileProjectAssociationDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	err := m.(*ProviderConfiguration).client.QualityProfiles.RemoveProject(
+		ctx,
+		d.Get("quality_profile").(string),
+		d.Get("language").(string),
+		d.Get("project").(string),
 	)
 	if err != nil {
-		return fmt.Errorf("resourceSonarqubeQualityProfileProjectAssociationDelete: Failed to delete quality profile: %+v", err)
+		return diag.Errorf("resourceSonarqubeQualityProfileProjectAssociationDelete: Failed to delete quality profile: %+v", err)
 	}
-	defer resp.Body.Close()
 
 	return nil
 }
 
-func resourceSonarqubeQualityProfileProjectAssociationImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
-	if err := resourceSonarqubeQualityProfileProjectAssociationRead(d, m); err != nil {
-		return nil, err
+func resourceSonarqubeQualityProfileProjectAssociationImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	if diags := resourceSonarqubeQualityProfileProjectAssociationRead(ctx, d, m); diags.HasError() {
+		return nil, diagnosticsToError(diags)
 	}
 	return []*schema.ResourceData{d}, nil
 }
@@ -43,8 +43,8 @@ func TestAccSonarqubeQualityProfileProjectAssociationBasic(t *testing.T) {
 	name := "sonarqube_qualityprofile_project_association." + rnd
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubeQualityProfileProjectAssociationBasicConfig(rnd, "testAccSonarqubeProfileProjectAssociation", "js"),
@@ -88,8 +88,8 @@ func TestAccSonarqubeQualityProfileProjectAssociationSonarWay(t *testing.T) {
 	name := "sonarqube_qualityprofile_project_association." + rnd
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubeQualityProfileProjectAssociationSonarWay(rnd, "testAccSonarqubeProfileProjectAssociation", "js"),
@@ -31,8 +31,8 @@ func TestAccSonarqubeQualityProfileBasic(t *testing.T) {
 	name := "sonarqube_qualityprofile." + rnd
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubeQualityProfileBasicConfig(rnd, "testAccSonarqubeQualityProfile", "js"),
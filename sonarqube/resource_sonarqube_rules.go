@@ -1,66 +1,27 @@
 package sonarqube
 
 import (
-	"encoding/json"
-	"fmt"
-	"net/http"
-	"net/url"
-	"strings"
+	"context"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/jdamata/terraform-provider-sonarqube/internal/client"
 )
 
-type Rule struct {
-	RuleKey     string   `json:"key"`
-	Repo        string   `json:"repo"`
-	Name        string   `json:"name"`
-	CreatedAt   string   `json:"createdAt"`
-	UpdatedAt   string   `json:"updatedAt"`
-	HtmlDesc    string   `json:"htmlDesc,omitempty"`
-	MdDesc      string   `json:"mdDesc,omitempty"`
-	Severity    string   `json:"severity"`
-	Status      string   `json:"status"`
-	InternalKey string   `json:"internalKey"`
-	IsTemplate  bool     `json:"isTemplate"`
-	Tags        []string `json:"tags"`
-	TemplateKey string   `json:"templateKey,omitempty"`
-	SysTags     []string `json:"sysTags"`
-	Lang        string   `json:"lang"`
-	LangName    string   `json:"langName"`
-	Scope       string   `json:"scope"`
-	IsExternal  bool     `json:"isExternal"`
-	Type        string   `json:"type"`
-	Params      []Params `json:"params,omitempty"`
-}
-
-type Params struct {
-	ParmKey      string `json:"key"`
-	HtmlDesc     string `json:"htmlDesc"`
-	DefaultValue string `json:"defaultValue"`
-	Type         string `json:"type"`
-}
-
-type GetRule struct {
-	Rule  []Rule `json:"rules"`
-	Total int    `json:"total"`
-	P     int    `json:"p"`
-	PS    int    `json:"ps"`
-}
-
-type CreateRuleResponse struct {
-	Rule Rule `json:"rule"`
-}
-
 func resourceSonarqubeRule() *schema.Resource {
 	return &schema.Resource{
-		Description: "Provides a Sonarqube Rules resource. This can be used to manage Sonarqube rules.",
-		Create:      resourceSonarqubeRuleCreate,
-		Read:        resourceSonarqubeRuleRead,
-		Update:      resourceSonarqubeRuleUpdate,
-		Delete:      resourceSonarqubeRuleDelete,
+		Description:   "Provides a Sonarqube Rules resource. This can be used to manage Sonarqube rules.",
+		CreateContext: resourceSonarqubeRuleCreate,
+		ReadContext:   resourceSonarqubeRuleRead,
+		UpdateContext: resourceSonarqubeRuleUpdate,
+		DeleteContext: resourceSonarqubeRuleDelete,
 		Importer: &schema.ResourceImporter{
-			State: resourceSonarqubeRuleImporter,
+			StateContext: resourceSonarqubeRuleImporter,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Default: schema.DefaultTimeout(5 * time.Minute),
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -157,135 +118,82 @@ func resourceSonarqubeRule() *schema.Resource {
 	}
 }
 
-func resourceSonarqubeRuleCreate(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/rules/create"
-
-	sonarQubeURL.RawQuery = url.Values{
-		"customKey":           []string{d.Get("custom_key").(string)},
-		"markdownDescription": []string{d.Get("markdown_description").(string)},
-		"name":                []string{d.Get("name").(string)},
-		"params":              []string{d.Get("params").(string)},
-		"preventReactivation": []string{d.Get("prevent_reactivation").(string)},
-		"severity":            []string{d.Get("severity").(string)},
-		"status":              []string{d.Get("status").(string)},
-		"templateKey":         []string{d.Get("template_key").(string)},
-		"type":                []string{d.Get("type").(string)},
-	}.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusOK,
-		"resourceSonarqubeRuleCreate",
-	)
-	if err != nil {
-		return err
+func resourceSonarqubeRuleCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	request := client.RulesCreateRequest{
+		CustomKey:           d.Get("custom_key").(string),
+		MarkdownDescription: d.Get("markdown_description").(string),
+		Name:                d.Get("name").(string),
+		Params:              d.Get("params").(string),
+		PreventReactivation: d.Get("prevent_reactivation").(string),
+		Severity:            d.Get("severity").(string),
+		Status:              d.Get("status").(string),
+		TemplateKey:         d.Get("template_key").(string),
+		Type:                d.Get("type").(string),
 	}
-	defer resp.Body.Close()
-
-	ruleCreateResponse := CreateRuleResponse{}
-	err = json.NewDecoder(resp.Body).Decode(&ruleCreateResponse)
+	rule, err := m.(*ProviderConfiguration).client.Rules.Create(ctx, request)
 	if err != nil {
-		return fmt.Errorf("resourceSonarqubeRuleCreate: Failed to decode json into struct: %+v", err)
+		return diag.Errorf("resourceSonarqubeRuleCreate: Failed to create rule: %+v", err)
 	}
-	d.SetId(ruleCreateResponse.Rule.RuleKey)
-	return resourceSonarqubeRuleRead(d, m)
-}
 
-func resourceSonarqubeRuleRead(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/rules/search"
-	sonarQubeURL.RawQuery = url.Values{
-		"rule_key": []string{d.Id()},
-	}.Encode()
+	d.SetId(rule.Key)
+	return resourceSonarqubeRuleRead(ctx, d, m)
+}
 
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"GET",
-		sonarQubeURL.String(),
-		http.StatusOK,
-		"resourceSonarqubeRuleRead",
-	)
-	if err != nil {
-		return err
+func resourceSonarqubeRuleRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	request := client.RulesSearchRequest{
+		RuleKey: d.Id(),
 	}
-	defer resp.Body.Close()
-
-	ruleReadResponse := GetRule{}
-	err = json.NewDecoder(resp.Body).Decode(&ruleReadResponse)
+	rule, err := m.(*ProviderConfiguration).client.Rules.SearchPages(request).Find(ctx, func(value client.Rule) bool {
+		return d.Id() == value.Key
+	})
 	if err != nil {
-		return fmt.Errorf("resourceSonarqubeRuleRead: Failed to decode json into struct: %+v", err)
+		return diag.Errorf("resourceSonarqubeRuleRead: Failed to search rules: %+v", err)
 	}
 
-	for _, value := range ruleReadResponse.Rule {
-		if d.Id() == value.RuleKey {
-			d.SetId(value.RuleKey)
-			d.Set("markdown_description", value.MdDesc)
-			d.Set("name", value.Name)
-			d.Set("severity", value.Severity)
-			d.Set("template_key", value.TemplateKey)
-			d.Set("status", value.Status)
-			d.Set("type", value.Type)
-			return nil
-		}
+	if rule == nil {
+		removeResourceFromState(d, "resourceSonarqubeRuleRead")
+		return nil
 	}
-	return fmt.Errorf("resourceSonarqubeRuleRead: Failed to find project: %+v", d.Id())
-}
 
-func resourceSonarqubeRuleDelete(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/rules/delete"
-	sonarQubeURL.RawQuery = url.Values{
-		"key": []string{d.Id()},
-	}.Encode()
+	d.SetId(rule.Key)
+	d.Set("markdown_description", rule.MdDesc)
+	d.Set("name", rule.Name)
+	d.Set("severity", rule.Severity)
+	d.Set("template_key", rule.TemplateKey)
+	d.Set("status", rule.Status)
+	d.Set("type", rule.Type)
+	return nil
+}
 
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusOK,
-		"resourceSonarqubeRuleDelete",
-	)
+func resourceSonarqubeRuleDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	err := m.(*ProviderConfiguration).client.Rules.Delete(ctx, d.Id())
 	if err != nil {
-		return err
+		return diag.Errorf("resourceSonarqubeRuleDelete: Failed to delete rule: %+v", err)
 	}
-	defer resp.Body.Close()
 
 	return nil
 }
 
-func resourceSonarqubeRuleImporter(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
-	if err := resourceSonarqubeRuleRead(d, m); err != nil {
-		return nil, err
+func resourceSonarqubeRuleImporter(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	if diags := resourceSonarqubeRuleRead(ctx, d, m); diags.HasError() {
+		return nil, diagnosticsToError(diags)
 	}
 	return []*schema.ResourceData{d}, nil
 }
 
-func resourceSonarqubeRuleUpdate(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/rules/update"
-	sonarQubeURL.RawQuery = url.Values{
-		"key":                  []string{d.Id()},
-		"markdown_description": []string{d.Get("markdown_description").(string)},
-		"name":                 []string{d.Get("name").(string)},
-		"params":               []string{d.Get("params").(string)},
-		"severity":             []string{d.Get("severity").(string)},
-		"status":               []string{d.Get("status").(string)},
-	}.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusOK,
-		"resourceSonarqubeRuleUpdate",
-	)
+func resourceSonarqubeRuleUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	request := client.RulesUpdateRequest{
+		Key:                 d.Id(),
+		MarkdownDescription: d.Get("markdown_description").(string),
+		Name:                d.Get("name").(string),
+		Params:              d.Get("params").(string),
+		Severity:            d.Get("severity").(string),
+		Status:              d.Get("status").(string),
+	}
+	err := m.(*ProviderConfiguration).client.Rules.Update(ctx, request)
 	if err != nil {
-		return err
+		return diag.Errorf("resourceSonarqubeRuleUpdate: Failed to update rule: %+v", err)
 	}
-	defer resp.Body.Close()
 
-	return resourceSonarqubeRuleRead(d, m)
+	return resourceSonarqubeRuleRead(ctx, d, m)
 }
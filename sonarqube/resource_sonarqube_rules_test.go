@@ -36,8 +36,8 @@ func TestAccSonarqubeRuleBasic(t *testing.T) {
 	name := "sonarqube_rule." + rnd
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubeRuleBasicConfig(rnd, "basicRule", "markdown_description", "name", "xml:XPathCheck", "INFO", "READY", "VULNERABILITY"),
@@ -1,258 +1,348 @@
 package sonarqube
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
-	"net/http"
-	"net/url"
 	"sort"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	rschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/jdamata/terraform-provider-sonarqube/internal/client"
 )
 
-type Setting struct {
-	Key         string              `json:"key"`
-	Value       string              `json:"value"`
-	Values      []string            `json:"values"`
-	Inherited   bool                `json:"inherited"`
-	FieldValues []map[string]string `json:"fieldValues"`
-}
-
-type GetSettings struct {
-	Setting            []Setting `json:"settings"`
-	SetSecuredSettings []string  `json:"setSecuredSettings"`
-}
-
-func (a Setting) ToMap() map[string]interface{} {
+// flattenSetting converts a setting returned by the API into the map used by the `setting` blocks
+func flattenSetting(setting client.Setting) map[string]interface{} {
 	obj := make(map[string]interface{})
 
-	obj["key"] = a.Key
-	obj["value"] = a.Value
-	if a.Values != nil {
-		obj["values"] = a.Values
+	obj["key"] = setting.Key
+	obj["value"] = setting.Value
+	if setting.Values != nil {
+		obj["values"] = setting.Values
 	}
-	if a.FieldValues != nil {
-		obj["field_values"] = a.FieldValues
+	if setting.FieldValues != nil {
+		obj["field_values"] = setting.FieldValues
 	}
 	return obj
 }
 
-func resourceSonarqubeSettings() *schema.Resource {
-	return &schema.Resource{
-		Description: "Provides a Sonarqube Settings resource. This can be used to manage Sonarqube settings.",
-		Create:      resourceSonarqubeSettingsCreate,
-		Read:        resourceSonarqubeSettingsRead,
-		Update:      resourceSonarqubeSettingsUpdate,
-		Delete:      resourceSonarqubeSettingsDelete,
-		Importer: &schema.ResourceImporter{
-			State: resourceSonarqubeSettingsImporter,
-		},
+var (
+	_ resource.Resource                = &settingResource{}
+	_ resource.ResourceWithConfigure   = &settingResource{}
+	_ resource.ResourceWithImportState = &settingResource{}
+)
+
+// settingResource manages sonarqube_setting with the plugin framework
+type settingResource struct {
+	providerConfiguration *ProviderConfiguration
+}
 
-		Schema: map[string]*schema.Schema{
-			"key": {
-				Type:        schema.TypeString,
+// settingResourceModel is the state of sonarqube_setting
+type settingResourceModel struct {
+	ID          types.String   `tfsdk:"id"`
+	Key         types.String   `tfsdk:"key"`
+	Value       types.String   `tfsdk:"value"`
+	Values      types.List     `tfsdk:"values"`
+	FieldValues types.List     `tfsdk:"field_values"`
+	Timeouts    timeouts.Value `tfsdk:"timeouts"`
+}
+
+func newSettingResource() resource.Resource {
+	return &settingResource{}
+}
+
+func (r *settingResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_setting"
+}
+
+func (r *settingResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	exactlyOneValue := []path.Expression{
+		path.MatchRoot("value"),
+		path.MatchRoot("values"),
+		path.MatchRoot("field_values"),
+	}
+
+	resp.Schema = rschema.Schema{
+		Description: "Provides a Sonarqube Settings resource. This can be used to manage Sonarqube settings.",
+		Attributes: map[string]rschema.Attribute{
+			"id": rschema.StringAttribute{
+				Computed:    true,
+				Description: "The key of the setting.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"key": rschema.StringAttribute{
 				Required:    true,
-				Description: "Setting key",
+				Description: "Setting key. Changing this forces a new resource to be created.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
-			"value": {
-				Type:         schema.TypeString,
-				Optional:     true,
-				Description:  "Setting value. To reset a value, please use the reset web service.",
-				ExactlyOneOf: []string{"value", "values", "field_values"},
+			"value": rschema.StringAttribute{
+				Optional:    true,
+				Description: "Setting value. To reset a value, please use the reset web service.",
+				Validators: []validator.String{
+					stringvalidator.ExactlyOneOf(exactlyOneValue...),
+				},
 			},
-			"values": {
-				Type:         schema.TypeList,
-				Optional:     true,
-				Description:  "Setting multi values for the supplied key",
-				ExactlyOneOf: []string{"value", "values", "field_values"},
-				Elem: &schema.Schema{
-					Type: schema.TypeString,
+			"values": rschema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Setting multi values for the supplied key",
+				Validators: []validator.List{
+					listvalidator.ExactlyOneOf(exactlyOneValue...),
 				},
 			},
-			"field_values": {
-				Type:         schema.TypeList,
-				Optional:     true,
-				Description:  "Setting field values for the supplied key",
-				ExactlyOneOf: []string{"value", "values", "field_values"},
-				Elem: &schema.Schema{
-					Type: schema.TypeMap,
-					Elem: schema.TypeString,
+			"field_values": rschema.ListAttribute{
+				Optional:    true,
+				ElementType: types.MapType{ElemType: types.StringType},
+				Description: "Setting field values for the supplied key",
+				Validators: []validator.List{
+					listvalidator.ExactlyOneOf(exactlyOneValue...),
 				},
 			},
 		},
+		Blocks: map[string]rschema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
+		},
 	}
 }
 
-func resourceSonarqubeSettingsCreate(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/settings/set"
-	sonarQubeURL.RawQuery = getCreateOrUpdateQueryRawQuery([]string{d.Get("key").(string)}, d)
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusNoContent,
-		"resourceSonarqubeSettingsCreate",
-	)
-	if err != nil {
-		return err
+func (r *settingResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.providerConfiguration = frameworkProviderConfiguration(req.ProviderData, &resp.Diagnostics)
+}
+
+func (r *settingResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan settingResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := plan.Timeouts.Create(ctx, frameworkDefaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
-	defer resp.Body.Close()
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
 
-	d.SetId(d.Get("key").(string))
-	return resourceSonarqubeSettingsRead(d, m)
+	request, diags := plan.settingsSetRequest(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if err := r.providerConfiguration.client.Settings.Set(ctx, request); err != nil {
+		resp.Diagnostics.AddError("resourceSonarqubeSettingsCreate: Failed to set setting", err.Error())
+		return
+	}
+
+	plan.ID = plan.Key
+	resp.Diagnostics.Append(r.read(ctx, &plan, "resourceSonarqubeSettingsCreate")...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
 
-func resourceSonarqubeSettingsRead(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/settings/values"
-	sonarQubeURL.RawQuery = url.Values{
-		"keys": []string{d.Id()},
-	}.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"GET",
-		sonarQubeURL.String(),
-		http.StatusOK,
-		"resourceSonarqubeSettingsRead",
-	)
-	if err != nil {
-		return err
+func (r *settingResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state settingResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
-	defer resp.Body.Close()
 
-	settingReadResponse := GetSettings{}
-	err = json.NewDecoder(resp.Body).Decode(&settingReadResponse)
-	if err != nil {
-		return fmt.Errorf("resourceSonarqubeSettingsRead: Failed to decode json into struct: %+v", err)
+	readTimeout, diags := state.Timeouts.Read(ctx, frameworkDefaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
 
-	for _, value := range settingReadResponse.Setting {
-		if d.Id() == value.Key {
-			d.Set("key", value.Key)
-			d.Set("value", value.Value)
-			d.Set("values", value.Values)
-			d.Set("field_values", value.FieldValues)
-			d.SetId(value.Key)
-			return nil
-		}
+	resp.Diagnostics.Append(r.read(ctx, &state, "resourceSonarqubeSettingsRead")...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
-	return fmt.Errorf("resourceSonarqubeSettingsRead: Failed to find setting: %+v", d.Id())
+	if state.ID.IsNull() {
+		removeFrameworkResourceFromState(ctx, &resp.State, state.Key.ValueString(), "resourceSonarqubeSettingsRead")
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
 
-func resourceSonarqubeSettingsDelete(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/settings/reset"
-	sonarQubeURL.RawQuery = url.Values{
-		"keys": []string{d.Id()},
-	}.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusNoContent,
-		"resourceSonarqubeSettingsDelete",
-	)
-	if err != nil {
-		return err
+func (r *settingResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan settingResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
-	defer resp.Body.Close()
 
-	return nil
+	updateTimeout, diags := plan.Timeouts.Update(ctx, frameworkDefaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	request, diags := plan.settingsSetRequest(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if err := r.providerConfiguration.client.Settings.Set(ctx, request); err != nil {
+		resp.Diagnostics.AddError("resourceSonarqubeSettingsUpdate: Failed to set setting", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(r.read(ctx, &plan, "resourceSonarqubeSettingsUpdate")...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
 
-func resourceSonarqubeSettingsImporter(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
-	d.Set("key", d.Id())
-	if err := resourceSonarqubeSettingsRead(d, m); err != nil {
-		return nil, err
+func (r *settingResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state settingResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, frameworkDefaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	if err := r.providerConfiguration.client.Settings.Reset(ctx, []string{state.ID.ValueString()}, ""); err != nil {
+		resp.Diagnostics.AddError("resourceSonarqubeSettingsDelete: Failed to reset setting", err.Error())
 	}
-	return []*schema.ResourceData{d}, nil
 }
 
-func resourceSonarqubeSettingsUpdate(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/settings/set"
+func (r *settingResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("key"), req.ID)...)
+}
 
-	sonarQubeURL.RawQuery = getCreateOrUpdateQueryRawQuery([]string{d.Id()}, d)
+// read refreshes model from the API. The ID of model is set to null if the setting does not exist anymore.
+func (r *settingResource) read(ctx context.Context, model *settingResourceModel, caller string) diag.Diagnostics {
+	var diags diag.Diagnostics
+	// Secured settings are never returned by the API, only their keys are listed. Keep the values from the configuration.
+	if strings.HasSuffix(model.ID.ValueString(), ".secured") {
+		keys, err := r.providerConfiguration.client.Settings.SecuredKeys(ctx, []string{model.ID.ValueString()}, "")
+		if err != nil {
+			diags.AddError(caller+": Failed to read setting", err.Error())
+			return diags
+		}
+		for _, key := range keys {
+			if key == model.ID.ValueString() {
+				model.Key = types.StringValue(key)
+				return diags
+			}
+		}
+		model.ID = types.StringNull()
+		return diags
+	}
 
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusNoContent,
-		"resourceSonarqubeSettingsUpdate",
-	)
+	settings, err := r.providerConfiguration.client.Settings.Values(ctx, []string{model.ID.ValueString()}, "")
 	if err != nil {
-		return err
+		diags.AddError(caller+": Failed to read setting", err.Error())
+		return diags
 	}
-	defer resp.Body.Close()
 
-	return resourceSonarqubeSettingsRead(d, m)
+	for _, setting := range settings {
+		if setting.Key != model.ID.ValueString() {
+			continue
+		}
+
+		model.Key = types.StringValue(setting.Key)
+
+		// Only one of value, values and field_values is set, the others are null rather than empty
+		model.Value = types.StringNull()
+		if setting.Value != "" {
+			model.Value = types.StringValue(setting.Value)
+		}
+		model.Values = types.ListNull(types.StringType)
+		if len(setting.Values) > 0 {
+			values, d := types.ListValueFrom(ctx, types.StringType, setting.Values)
+			diags.Append(d...)
+			model.Values = values
+		}
+		model.FieldValues = types.ListNull(types.MapType{ElemType: types.StringType})
+		if len(setting.FieldValues) > 0 {
+			fieldValues, d := types.ListValueFrom(ctx, types.MapType{ElemType: types.StringType}, setting.FieldValues)
+			diags.Append(d...)
+			model.FieldValues = fieldValues
+		}
+		return diags
+	}
+
+	model.ID = types.StringNull()
+	return diags
 }
 
-func getCreateOrUpdateQueryRawQuery(key []string, d *schema.ResourceData) string {
-	// build the base query
-	RawQuery := url.Values{
-		"key": key,
+// settingsSetRequest returns the api/settings/set parameters setting the value, values or field_values of model
+func (model settingResourceModel) settingsSetRequest(ctx context.Context) (client.SettingsSetRequest, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	request := client.SettingsSetRequest{
+		Key: model.Key.ValueString(),
 	}
-	// Add in value/values/fieldValues as appropriate
-	// single value
-	if value, ok := d.GetOk("value"); ok {
-		RawQuery.Add("value", value.(string))
-	} else {
+	if !model.Value.IsNull() {
+		// single value
+		request.Value = model.Value.ValueString()
+	} else if !model.Values.IsNull() {
 		// array of strings
-		if values, ok := d.GetOk("values"); ok {
-			for _, value := range values.([]interface{}) {
-				RawQuery.Add("values", value.(string))
-			}
-		} else {
-			// array of objects of key/value pairs
-			fieldValues := d.Get("field_values").([]interface{})
-			for _, value := range fieldValues {
-				b, _ := json.Marshal(value)
-				fv := string(b)
-				RawQuery.Add("fieldValues", fv)
-			}
+		diags.Append(model.Values.ElementsAs(ctx, &request.Values, false)...)
+	} else {
+		// array of objects of key/value pairs
+		diags.Append(model.FieldValues.ElementsAs(ctx, &request.FieldValues, false)...)
+	}
+	return request, diags
+}
+
+// expandSettingFieldValues converts `field_values` into the list of key/value pairs expected by the API
+func expandSettingFieldValues(fieldValues []interface{}) []map[string]string {
+	expanded := make([]map[string]string, 0, len(fieldValues))
+	for _, fieldValue := range fieldValues {
+		pairs := make(map[string]string)
+		for k, v := range fieldValue.(map[string]interface{}) {
+			pairs[k] = fmt.Sprint(v)
 		}
+		expanded = append(expanded, pairs)
 	}
-	return RawQuery.Encode()
+	return expanded
 }
 
 /* This content is used for settings parameter in multiple resources ('project', 'portfolio')  */
-func getComponentSettings(component string, m interface{}) ([]Setting, error) {
+func getComponentSettings(ctx context.Context, component string, m interface{}) ([]client.Setting, error) {
 	if component == "" {
-		return []Setting{}, nil
-	}
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/settings/values"
-	sonarQubeURL.RawQuery = url.Values{"component": []string{component}}.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"GET",
-		sonarQubeURL.String(),
-		http.StatusOK,
-		"getProjectSettings",
-	)
-	if err != nil {
-		return nil, err
+		return []client.Setting{}, nil
 	}
-	defer resp.Body.Close()
 
-	settingReadResponse := GetSettings{}
-	err = json.NewDecoder(resp.Body).Decode(&settingReadResponse)
+	settings, err := m.(*ProviderConfiguration).client.Settings.Values(ctx, nil, component)
 	if err != nil {
-		return nil, fmt.Errorf("getProjectSettings: Failed to decode json into struct: %+v", err)
+		return nil, fmt.Errorf("getComponentSettings: Failed to read settings of %s: %+v", component, err)
 	}
 
-	settingsList := make([]Setting, 0)
+	settingsList := make([]client.Setting, 0)
 	// Filter settings by parameter inherited
-	settingsList = append(settingsList, settingReadResponse.Setting...)
+	settingsList = append(settingsList, settings...)
 
 	// Make sure the order is always the same for when we are comparing lists of conditions
 	sort.Slice(settingsList, func(i, j int) bool {
@@ -262,11 +352,11 @@ func getComponentSettings(component string, m interface{}) ([]Setting, error) {
 	return settingsList, nil
 }
 
-func synchronizeSettings(d *schema.ResourceData, m interface{}) (bool, error) {
+func synchronizeSettings(ctx context.Context, d *schema.ResourceData, m interface{}) (bool, error) {
 	changed := false
 	componentId := d.Id()
 	componentSettings := d.Get("setting").([]interface{})
-	apiComponentSettings, _ := getComponentSettings(componentId, m)
+	apiComponentSettings, _ := getComponentSettings(ctx, componentId, m)
 
 	// Determine which conditions have been added or changed and update those
 	for _, s := range componentSettings {
@@ -279,7 +369,7 @@ func synchronizeSettings(d *schema.ResourceData, m interface{}) (bool, error) {
 			if key == apiSetting.Key {
 				exists = true
 				if checkSettingDiff(setting, apiSetting) {
-					err := setComponentSetting(componentId, setting, m, &changed)
+					err := setComponentSetting(ctx, componentId, setting, m, &changed)
 					if err != nil {
 						return false, fmt.Errorf("synchronizeSettings: Failed to update setting '%s': %+v", key, err)
 					}
@@ -288,7 +378,7 @@ func synchronizeSettings(d *schema.ResourceData, m interface{}) (bool, error) {
 		}
 		// Add the condition because it does not already exist
 		if !exists {
-			err := setComponentSetting(componentId, setting, m, &changed)
+			err := setComponentSetting(ctx, componentId, setting, m, &changed)
 			if err != nil {
 				return false, fmt.Errorf("synchronizeSettings: Failed to create setting '%s': %+v", key, err)
 			}
@@ -296,7 +386,7 @@ func synchronizeSettings(d *schema.ResourceData, m interface{}) (bool, error) {
 	}
 
 	// Determine if any settings have been removed and delete them
-	err := removeComponentSettings(componentId, componentSettings, &apiComponentSettings, m, &changed)
+	err := removeComponentSettings(ctx, componentId, componentSettings, &apiComponentSettings, m, &changed)
 	if err != nil {
 		return changed, err
 	}
@@ -308,7 +398,7 @@ func synchronizeSettings(d *schema.ResourceData, m interface{}) (bool, error) {
 	return changed, nil
 }
 
-func checkSettingDiff(a map[string]interface{}, b Setting) bool {
+func checkSettingDiff(a map[string]interface{}, b client.Setting) bool {
 	if a["field_values"] != nil {
 		// array of objects of key/value pairs
 		fieldValues := a["field_values"].([]interface{})
@@ -341,54 +431,38 @@ func checkSettingDiff(a map[string]interface{}, b Setting) bool {
 	return false
 }
 
-func getComponentSettingUrlEncode(setting map[string]interface{}) url.Values {
-	raw := url.Values{
-		"key": []string{setting["key"].(string)},
+func getComponentSettingsSetRequest(component string, setting map[string]interface{}) client.SettingsSetRequest {
+	request := client.SettingsSetRequest{
+		Key:       setting["key"].(string),
+		Component: component,
 	}
-	addedSetting := false
 	log.Printf("[DEBUG] setting.value '%s'", setting["value"])
 	log.Printf("[DEBUG] setting.values '%s'", setting["values"])
 	log.Printf("[DEBUG] setting.field_values '%s'", setting["field_values"])
 	if setting["value"] != nil && setting["value"] != "" {
-		raw.Add("value", setting["value"].(string))
-		addedSetting = true
+		request.Value = setting["value"].(string)
+		return request
 	}
 
-	if setting["values"] != nil && !addedSetting {
+	if setting["values"] != nil {
 		// array of strings
 		for _, value := range setting["values"].([]interface{}) {
-			raw.Add("values", value.(string))
-			addedSetting = true
+			request.Values = append(request.Values, value.(string))
+		}
+		if len(request.Values) > 0 {
+			return request
 		}
 	}
 
-	if setting["field_values"] != nil && !addedSetting {
+	if setting["field_values"] != nil {
 		// array of objects of key/value pairs
-		fieldValues := setting["field_values"].([]interface{})
-		for _, value := range fieldValues {
-			b, _ := json.Marshal(value)
-			fv := string(b)
-			raw.Add("fieldValues", fv)
-			addedSetting = true
-		}
+		request.FieldValues = expandSettingFieldValues(setting["field_values"].([]interface{}))
 	}
-	return raw
+	return request
 }
 
-func setComponentSetting(component string, setting map[string]interface{}, m interface{}, changed *bool) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/settings/set"
-	params := getComponentSettingUrlEncode(setting)
-	params.Add("component", component)
-	sonarQubeURL.RawQuery = params.Encode()
-
-	_, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusNoContent,
-		"setComponentSettings",
-	)
+func setComponentSetting(ctx context.Context, component string, setting map[string]interface{}, m interface{}, changed *bool) error {
+	err := m.(*ProviderConfiguration).client.Settings.Set(ctx, getComponentSettingsSetRequest(component, setting))
 	if err != nil {
 		return fmt.Errorf("setComponentSettings: Failed to set project setting key=%s: %+v", setting["key"].(string), err)
 	}
@@ -397,7 +471,7 @@ func setComponentSetting(component string, setting map[string]interface{}, m int
 	return nil
 }
 
-func removeComponentSettings(component string, newSettings []interface{}, apiProjectSettings *[]Setting, m interface{}, changed *bool) error {
+func removeComponentSettings(ctx context.Context, component string, newSettings []interface{}, apiProjectSettings *[]client.Setting, m interface{}, changed *bool) error {
 	if component == "" {
 		return nil
 	}
@@ -418,20 +492,7 @@ func removeComponentSettings(component string, newSettings []interface{}, apiPro
 	}
 	// Delete not found
 	if len(toDelete) > 0 {
-		sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-		sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/settings/reset"
-		sonarQubeURL.RawQuery = url.Values{
-			"component": []string{component},
-			"keys":      []string{strings.Join(toDelete, ",")},
-		}.Encode()
-
-		_, err := httpRequestHelper(
-			m.(*ProviderConfiguration).httpClient,
-			"POST",
-			sonarQubeURL.String(),
-			http.StatusNoContent,
-			"deleteSetting",
-		)
+		err := m.(*ProviderConfiguration).client.Settings.Reset(ctx, toDelete, component)
 		if err != nil {
 			return fmt.Errorf("removeComponentSettings: Failed to delete setting %s: %+v", component, err)
 		}
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
 )
 
 func init() {
@@ -47,8 +48,8 @@ func TestAccSonarqubeSettingBasic(t *testing.T) {
 	name := "sonarqube_setting." + rnd
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubeSettingBasicConfig(rnd, "sonar.demo", "sonarqube@example.org"),
@@ -69,13 +70,47 @@ func TestAccSonarqubeSettingBasic(t *testing.T) {
 		},
 	})
 }
+func TestAccSonarqubeSettingSecured(t *testing.T) {
+	rnd := generateRandomResourceName()
+	name := "sonarqube_setting." + rnd
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSonarqubeSettingBasicConfig(rnd, "sonar.demo.secured", "secret"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(name, "key", "sonar.demo.secured"),
+					resource.TestCheckResourceAttr(name, "value", "secret"),
+				),
+			},
+			{
+				// The value of a secured setting is never returned, refreshing it must keep it in the state
+				Config: testAccSonarqubeSettingBasicConfig(rnd, "sonar.demo.secured", "secret"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+			{
+				ResourceName:            name,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"value"},
+			},
+		},
+	})
+}
+
 func TestAccSonarqubeSettingMultipleValues(t *testing.T) {
 	key := "sonar.global.exclusions" // Needs to be a setting that accepts multiple values
 	rnd := generateRandomResourceName()
 	name := "sonarqube_setting." + rnd
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubeSettingConfigMultipleValues(rnd, key, []string{"foo", "bar"}),
@@ -104,8 +139,8 @@ func TestAccSonarqubeSettingMultipleFields(t *testing.T) {
 	name := "sonarqube_setting." + rnd
 	expected := map[string]string{"ruleKey": "foo", "resourceKey": "bar"}
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubeSettingConfigMultipleFields(rnd, key, expected),
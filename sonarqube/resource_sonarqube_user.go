@@ -1,47 +1,27 @@
 package sonarqube
 
 import (
-	"encoding/json"
-	"fmt"
-	"net/http"
-	"net/url"
-	"strconv"
-	"strings"
+	"context"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/jdamata/terraform-provider-sonarqube/internal/client"
 )
 
-// User struct
-type User struct {
-	Login       string   `json:"login,omitempty"`
-	Name        string   `json:"name,omitempty"`
-	Email       string   `json:"email,omitempty"`
-	Permissions []string `json:"permissions,omitempty"`
-	IsActive    bool     `json:"active,omitempty"`
-	IsLocal     bool     `json:"local,omitempty"`
-}
-
-// GetUser for unmarshalling response body where users are retured
-type GetUser struct {
-	Paging Paging `json:"paging"`
-	Users  []User `json:"users"`
-}
-
-// CreateUserResponse struct
-type CreateUserResponse struct {
-	User User `json:"user"`
-}
-
 // Returns the resource represented by this file.
 func resourceSonarqubeUser() *schema.Resource {
 	return &schema.Resource{
-		Description: "Provides a Sonarqube User resource. This can be used to manage Sonarqube Users.",
-		Create:      resourceSonarqubeUserCreate,
-		Read:        resourceSonarqubeUserRead,
-		Update:      resourceSonarqubeUserUpdate,
-		Delete:      resourceSonarqubeUserDelete,
+		Description:   "Provides a Sonarqube User resource. This can be used to manage Sonarqube Users.",
+		CreateContext: resourceSonarqubeUserCreate,
+		ReadContext:   resourceSonarqubeUserRead,
+		UpdateContext: resourceSonarqubeUserUpdate,
+		DeleteContext: resourceSonarqubeUserDelete,
 		Importer: &schema.ResourceImporter{
-			State: resourceSonarqubeUserImport,
+			StateContext: resourceSonarqubeUserImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Default: schema.DefaultTimeout(5 * time.Minute),
 		},
 
 		// Define the fields of this schema.
@@ -80,173 +60,133 @@ func resourceSonarqubeUser() *schema.Resource {
 	}
 }
 
-func resourceSonarqubeUserCreate(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/users/create"
-
-	isLocal := d.Get("is_local").(bool)
-
-	rawQuery := url.Values{
-		"login": []string{d.Get("login_name").(string)},
-		"name":  []string{d.Get("name").(string)},
-		"local": []string{strconv.FormatBool(isLocal)},
+func resourceSonarqubeUserCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	request := client.UsersCreateRequest{
+		Login: d.Get("login_name").(string),
+		Name:  d.Get("name").(string),
+		Local: d.Get("is_local").(bool),
 	}
 
 	if password, ok := d.GetOk("password"); ok {
-		rawQuery.Add("password", password.(string))
+		request.Password = password.(string)
 	}
 
 	if email, ok := d.GetOk("email"); ok {
-		rawQuery.Add("email", email.(string))
+		request.Email = email.(string)
 	}
 
-	sonarQubeURL.RawQuery = rawQuery.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusOK,
-		"resourceSonarqubeUserCreate",
-	)
-	if err != nil {
-		return fmt.Errorf("error creating Sonarqube user: %+v", err)
-	}
-	defer resp.Body.Close()
-
-	// Decode response into struct
-	userResponse := CreateUserResponse{}
-	err = json.NewDecoder(resp.Body).Decode(&userResponse)
+	user, err := m.(*ProviderConfiguration).client.Users.Create(ctx, request)
 	if err != nil {
-		return fmt.Errorf("resourceSonarqubeUserCreate: Failed to decode json into struct: %+v", err)
+		return diag.Errorf("error creating Sonarqube user: %+v", err)
 	}
 
-	if userResponse.User.Login != "" {
-		d.SetId(userResponse.User.Login)
+	if user.Login != "" {
+		d.SetId(user.Login)
 	} else {
-		return fmt.Errorf("resourceSonarqubeUserCreate: Create response didn't contain the user login")
+		return diag.Errorf("resourceSonarqubeUserCreate: Create response didn't contain the user login")
 	}
 
-	return resourceSonarqubeUserRead(d, m)
+	return resourceSonarqubeUserRead(ctx, d, m)
 }
 
-func resourceSonarqubeUserRead(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/users/search"
-
-	sonarQubeURL.RawQuery = url.Values{
-		"ps": []string{"500"},
-		"q":  []string{d.Id()},
-	}.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"GET",
-		sonarQubeURL.String(),
-		http.StatusOK,
-		"resourceSonarqubeUserRead",
-	)
-	if err != nil {
-		return fmt.Errorf("error reading Sonarqube user: %+v", err)
+func resourceSonarqubeUserRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	conf := m.(*ProviderConfiguration)
+
+	// api/users/search is deprecated on servers exposing the v2 users management API
+	if conf.sonarQubeAPI != nil && conf.sonarQubeAPI.hasV2Path("api/v2/users-management/users") {
+		user, err := conf.client.UsersManagement.FindUser(ctx, d.Id())
+		if err != nil {
+			return diag.Errorf("error reading Sonarqube user: %+v", err)
+		}
+		if user == nil {
+			removeResourceFromState(d, "resourceSonarqubeUserRead")
+			return nil
+		}
+
+		d.SetId(user.Login)
+		d.Set("login_name", user.Login)
+		d.Set("name", user.Name)
+		d.Set("email", user.Email)
+		d.Set("is_local", user.Local)
+		return nil
+	}
+
+	request := client.UsersSearchRequest{
+		Query: d.Id(),
 	}
-	defer resp.Body.Close()
 
-	// Decode response into struct
-	userResponse := GetUser{}
-	err = json.NewDecoder(resp.Body).Decode(&userResponse)
+	// Walk all users to see if the current user exists.
+	user, err := conf.client.Users.SearchPages(request).Find(ctx, func(value client.User) bool {
+		return d.Id() == value.Login
+	})
 	if err != nil {
-		return fmt.Errorf("resourceSonarqubeUserCreate: Failed to decode json into struct: %+v", err)
+		return diag.Errorf("error reading Sonarqube user: %+v", err)
 	}
 
-	// Loop over all users to see if the current user exists.
-	for _, value := range userResponse.Users {
-		if d.Id() == value.Login {
-			d.SetId(value.Login)
-			d.Set("login_name", value.Login)
-			d.Set("name", value.Name)
-			d.Set("email", value.Email)
-			d.Set("is_local", value.IsLocal)
-			return nil
-		}
+	if user == nil {
+		removeResourceFromState(d, "resourceSonarqubeUserRead")
+		return nil
 	}
 
-	return fmt.Errorf("resourceSonarqubeUserRead: Failed to find user: %+v", d.Id())
+	d.SetId(user.Login)
+	d.Set("login_name", user.Login)
+	d.Set("name", user.Name)
+	d.Set("email", user.Email)
+	d.Set("is_local", user.IsLocal)
+	return nil
 }
 
-func resourceSonarqubeUserUpdate(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURLSubPath := sonarQubeURL.Path
+func resourceSonarqubeUserUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	usersService := m.(*ProviderConfiguration).client.Users
+
 	// handle default updates (api/users/update)
 	if d.HasChange("email") {
-		sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURLSubPath, "/") + "/api/users/update"
-		sonarQubeURL.RawQuery = url.Values{
-			"login": []string{d.Id()},
-			"email": []string{d.Get("email").(string)},
-		}.Encode()
-
-		resp, err := httpRequestHelper(
-			m.(*ProviderConfiguration).httpClient,
-			"POST",
-			sonarQubeURL.String(),
-			http.StatusOK,
-			"resourceSonarqubeUserUpdate",
-		)
+		err := usersService.UpdateEmail(ctx, d.Id(), d.Get("email").(string))
 		if err != nil {
-			return fmt.Errorf("error updating Sonarqube user: %+v", err)
+			return diag.Errorf("error updating Sonarqube user: %+v", err)
 		}
-		defer resp.Body.Close()
 	}
 
 	// handle password updates (api/users/change_password)
 	if d.HasChange("password") {
-		sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURLSubPath, "/") + "/api/users/change_password"
-		sonarQubeURL.RawQuery = url.Values{
-			"login":    []string{d.Id()},
-			"password": []string{d.Get("password").(string)},
-		}.Encode()
-
-		resp, err := httpRequestHelper(
-			m.(*ProviderConfiguration).httpClient,
-			"POST",
-			sonarQubeURL.String(),
-			http.StatusNoContent,
-			"resourceSonarqubeUserUpdate",
-		)
+		err := usersService.ChangePassword(ctx, d.Id(), d.Get("password").(string))
 		if err != nil {
-			return fmt.Errorf("error updating Sonarqube user: %+v", err)
+			return diag.Errorf("error updating Sonarqube user: %+v", err)
 		}
-		defer resp.Body.Close()
 	}
 
-	return resourceSonarqubeUserRead(d, m)
+	return resourceSonarqubeUserRead(ctx, d, m)
 }
 
-func resourceSonarqubeUserDelete(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/users/deactivate"
-	sonarQubeURL.RawQuery = url.Values{
-		"login":     []string{d.Id()},
-		"anonymize": []string{strconv.FormatBool(m.(*ProviderConfiguration).sonarQubeAnonymizeUsers)},
-	}.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusOK,
-		"resourceSonarqubeUserDelete",
-	)
+func resourceSonarqubeUserDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	conf := m.(*ProviderConfiguration)
+
+	// api/users/deactivate is deprecated on servers exposing the v2 users management API
+	if conf.sonarQubeAPI != nil && conf.sonarQubeAPI.hasV2Path("api/v2/users-management/users/{id}") {
+		user, err := conf.client.UsersManagement.FindUser(ctx, d.Id())
+		if err != nil {
+			return diag.Errorf("error deleting (deactivating) Sonarqube user: %+v", err)
+		}
+		if user == nil {
+			return nil
+		}
+		if err := conf.client.UsersManagement.DeactivateUser(ctx, user.ID, conf.sonarQubeAnonymizeUsers); err != nil {
+			return diag.Errorf("error deleting (deactivating) Sonarqube user: %+v", err)
+		}
+		return nil
+	}
+
+	err := conf.client.Users.Deactivate(ctx, d.Id(), conf.sonarQubeAnonymizeUsers)
 	if err != nil {
-		return fmt.Errorf("error deleting (deactivating) Sonarqube user: %+v", err)
+		return diag.Errorf("error deleting (deactivating) Sonarqube user: %+v", err)
 	}
-	defer resp.Body.Close()
 
 	return nil
 }
 
-func resourceSonarqubeUserImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
-	if err := resourceSonarqubeUserRead(d, m); err != nil {
-		return nil, err
+func resourceSonarqubeUserImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	if diags := resourceSonarqubeUserRead(ctx, d, m); diags.HasError() {
+		return nil, diagnosticsToError(diags)
 	}
 	return []*schema.ResourceData{d}, nil
 }
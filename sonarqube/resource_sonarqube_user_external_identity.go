@@ -1,25 +1,30 @@
 package sonarqube
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"net/http"
-	"net/url"
-	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/jdamata/terraform-provider-sonarqube/internal/client"
 )
 
 // Returns the resource represented by this file.
 func resourceSonarqubeUserExternalIdentity() *schema.Resource {
 	return &schema.Resource{
 		Description: `Updates the _external identity_ of a _non local_ Sonarqube User. This can be used to set the _Identity Provider_ which should be used to
-authenticate a specific user.
+authenticate a specific user.`,
+		CreateContext: resourceSonarqubeUserExternalIdentityCreate,
+		ReadContext:   resourceSonarqubeUserExternalIdentityRead,
+		DeleteContext: resourceSonarqubeUserExternalIdentityDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceSonarqubeUserExternalIdentityImport,
+		},
 
-The Sonarqube API currently does not provide an endpoint to read the _external identity_ setting of an user.`,
-		Create: resourceSonarqubeUserExternalIdentityCreate,
-		Read:   resourceSonarqubeUserExternalIdentityRead,
-		Delete: resourceSonarqubeUserExternalIdentityDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Default: schema.DefaultTimeout(5 * time.Minute),
+		},
 
 		// Define the fields of this schema.
 		Schema: map[string]*schema.Schema{
@@ -45,86 +50,86 @@ The Sonarqube API currently does not provide an endpoint to read the _external i
 	}
 }
 
-func resourceSonarqubeUserExternalIdentityCreate(d *schema.ResourceData, m interface{}) error {
-	isLocal, err := isLocal(d.Get("login_name").(string), m)
+func resourceSonarqubeUserExternalIdentityCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	isLocal, err := isLocal(ctx, d.Get("login_name").(string), m)
 	if err != nil {
-		return fmt.Errorf("Error updating Sonarqube user: %+v", err)
+		return diag.Errorf("Error updating Sonarqube user: %+v", err)
 	}
 	if isLocal {
-		return fmt.Errorf("Error setting external identity: Sonarqube user '%+v' is not 'external'", d.Get("login_name").(string))
+		return diag.Errorf("Error setting external identity: Sonarqube user '%+v' is not 'external'", d.Get("login_name").(string))
 	}
 
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/users/update_identity_provider"
-
-	rawQuery := url.Values{
-		"login":               []string{d.Get("login_name").(string)},
-		"newExternalIdentity": []string{d.Get("external_identity").(string)},
-		"newExternalProvider": []string{d.Get("external_provider").(string)},
+	request := client.UsersUpdateIdentityProviderRequest{
+		Login:               d.Get("login_name").(string),
+		NewExternalIdentity: d.Get("external_identity").(string),
+		NewExternalProvider: d.Get("external_provider").(string),
+	}
+	err = m.(*ProviderConfiguration).client.Users.UpdateIdentityProvider(ctx, request)
+	if err != nil {
+		return diag.Errorf("Error updating Sonarqube user: %+v", err)
 	}
 
-	sonarQubeURL.RawQuery = rawQuery.Encode()
+	d.SetId(d.Get("login_name").(string))
+
+	return resourceSonarqubeUserExternalIdentityRead(ctx, d, m)
+}
+
+func resourceSonarqubeUserExternalIdentityRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	request := client.UsersSearchRequest{
+		Query: d.Id(),
+	}
 
-	_, err = httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusNoContent,
-		"resourceSonarqubeUserExternalIdentityCreate",
-	)
+	// Walk all users to find the requested user
+	user, err := m.(*ProviderConfiguration).client.Users.SearchPages(request).Find(ctx, func(value client.User) bool {
+		return d.Id() == value.Login
+	})
 	if err != nil {
-		return fmt.Errorf("Error updating Sonarqube user: %+v", err)
+		return diag.Errorf("resourceSonarqubeUserExternalIdentityRead: Failed to read user: %+v", err)
 	}
 
-	d.SetId(d.Get("login_name").(string))
-	d.Set("external_identity", d.Get("external_identity").(string))
-	d.Set("external_provider", d.Get("external_provider").(string))
+	if user == nil || user.IsLocal {
+		removeResourceFromState(d, "resourceSonarqubeUserExternalIdentityRead")
+		return nil
+	}
 
+	d.Set("login_name", user.Login)
+	d.Set("external_identity", user.ExternalIdentity)
+	d.Set("external_provider", user.ExternalProvider)
 	return nil
 }
 
-func resourceSonarqubeUserExternalIdentityRead(d *schema.ResourceData, m interface{}) error {
+func resourceSonarqubeUserExternalIdentityDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	return nil
 }
 
-func resourceSonarqubeUserExternalIdentityDelete(d *schema.ResourceData, m interface{}) error {
-	return nil
+func resourceSonarqubeUserExternalIdentityImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	// import id is the login name of the user
+	login := d.Id()
+	if diags := resourceSonarqubeUserExternalIdentityRead(ctx, d, m); diags.HasError() {
+		return nil, diagnosticsToError(diags)
+	}
+	if d.Id() == "" {
+		return nil, fmt.Errorf("resourceSonarqubeUserExternalIdentityImport: Failed to find external user '%s'", login)
+	}
+	return []*schema.ResourceData{d}, nil
 }
 
-func isLocal(login string, m interface{}) (bool, error) {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/users/search"
-
-	sonarQubeURL.RawQuery = url.Values{
-		"q": []string{login},
-	}.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"GET",
-		sonarQubeURL.String(),
-		http.StatusOK,
-		"resourceSonarqubeUserExternalIdentity",
-	)
-	if err != nil {
-		return false, fmt.Errorf("Error reading Sonarqube user: %+v", err)
+func isLocal(ctx context.Context, login string, m interface{}) (bool, error) {
+	request := client.UsersSearchRequest{
+		Query: login,
 	}
-	defer resp.Body.Close()
 
-	// Decode response into struct
-	userResponse := GetUser{}
-	err = json.NewDecoder(resp.Body).Decode(&userResponse)
+	// Walk all users to find the requested user
+	user, err := m.(*ProviderConfiguration).client.Users.SearchPages(request).Find(ctx, func(value client.User) bool {
+		return login == value.Login
+	})
 	if err != nil {
-		return false, fmt.Errorf("Failed to decode json into struct: %+v", err)
+		return false, fmt.Errorf("Error reading Sonarqube user: %+v", err)
 	}
 
-	// Loop over all users to find the requested user
-	for _, value := range userResponse.Users {
-		if login == value.Login {
-			return value.IsLocal, nil
-		}
+	if user == nil {
+		// User not found in response
+		return false, fmt.Errorf("Failed to find user: %+v", login)
 	}
-
-	// User not found in response
-	return false, fmt.Errorf("Failed to find user: %+v", login)
+	return user.IsLocal, nil
 }
@@ -33,8 +33,8 @@ func TestAccSonarqubeUserLocal(t *testing.T) {
 	name := "sonarqube_user." + rnd
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubeUserLocalConfig(rnd, "testAccSonarqubeUserLocal", "terraform-test@sonarqube.com", "secret-sauce37!"),
@@ -78,8 +78,8 @@ func TestAccSonarqubeUserNotLocal(t *testing.T) {
 	name := "sonarqube_user." + rnd
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubeUserNotLocalConfig(rnd, "testAccSonarqubeUserNotLocal", "terraform-test@sonarqube.com"),
@@ -99,3 +99,33 @@ func TestAccSonarqubeUserNotLocal(t *testing.T) {
 		},
 	})
 }
+
+func TestAccSonarqubeUserDiscoverCapabilities(t *testing.T) {
+	rnd := generateRandomResourceName()
+	name := "sonarqube_user." + rnd
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// The user is deactivated through the v2 web API when the server exposes it
+				Config: `provider "sonarqube" {
+					discover_capabilities    = true
+					anonymize_user_on_delete = true
+				}
+				` + testAccSonarqubeUserLocalConfig(rnd, "testAccSonarqubeUserDiscover", "terraform-test@sonarqube.com", "secret-sauce37!"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(name, "name", "testAccSonarqubeUserDiscover"),
+				),
+			},
+			{
+				// The user is read through the v2 web API as well
+				ResourceName:            name,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"password"},
+			},
+		},
+	})
+}
@@ -1,40 +1,24 @@
 package sonarqube
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"net/http"
-	"net/url"
 	"strings"
 	"time"
 
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/jdamata/terraform-provider-sonarqube/internal/client"
 )
 
-// GetTokens struct
-type GetTokens struct {
-	Login  string  `json:"login,omitempty"`
-	Tokens []Token `json:"userTokens,omitempty"`
-}
-
-// Token struct
-type Token struct {
-	Login          string       `json:"login,omitempty"`
-	Name           string       `json:"name,omitempty"`
-	Token          string       `json:"token,omitempty"`
-	ExpirationDate string       `json:"expirationDate,omitempty"`
-	Type           string       `json:"type,omitempty"`
-	CreatedAt      string       `json:"createdAt,omitempty"`
-	IsExpired      bool         `json:"isExpired,omitempty"`
-	Project        TokenProject `json:"project,omitempty"`
-}
-
-type TokenProject struct {
-	Key  string `json:"key,omitempty"`
-	Name string `json:"name,omitempty"`
-}
-
 // Token types
 type TokenType string
 
@@ -44,201 +28,290 @@ const (
 	ProjectAnalysisToken TokenType = "PROJECT_ANALYSIS_TOKEN"
 )
 
-// Returns the resource represented by this file.
-func resourceSonarqubeUserToken() *schema.Resource {
-	return &schema.Resource{
+var (
+	_ resource.Resource                = &userTokenResource{}
+	_ resource.ResourceWithConfigure   = &userTokenResource{}
+	_ resource.ResourceWithImportState = &userTokenResource{}
+)
+
+// userTokenResource manages sonarqube_user_token with the plugin framework
+type userTokenResource struct {
+	providerConfiguration *ProviderConfiguration
+}
+
+// userTokenResourceModel is the state of sonarqube_user_token
+type userTokenResourceModel struct {
+	ID             types.String   `tfsdk:"id"`
+	Name           types.String   `tfsdk:"name"`
+	LoginName      types.String   `tfsdk:"login_name"`
+	ExpirationDate types.String   `tfsdk:"expiration_date"`
+	Token          types.String   `tfsdk:"token"`
+	Type           types.String   `tfsdk:"type"`
+	ProjectKey     types.String   `tfsdk:"project_key"`
+	Timeouts       timeouts.Value `tfsdk:"timeouts"`
+}
+
+func newUserTokenResource() resource.Resource {
+	return &userTokenResource{}
+}
+
+func (r *userTokenResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_user_token"
+}
+
+func (r *userTokenResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
 		Description: "Provides a Sonarqube User token resource. This can be used to manage Sonarqube User tokens.",
-		Create:      resourceSonarqubeUserTokenCreate,
-		Read:        resourceSonarqubeUserTokenRead,
-		Delete:      resourceSonarqubeUserTokenDelete,
-
-		// Define the fields of this schema.
-		Schema: map[string]*schema.Schema{
-			"name": {
-				Type:             schema.TypeString,
-				Required:         true,
-				ForceNew:         true,
-				ValidateDiagFunc: validation.ToDiagFunc(validation.StringLenBetween(1, 100)),
-				Description:      "The name of the Token to create. Changing this forces a new resource to be created.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "The ID of the token, made of the login name and the token name (login_name/name).",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "The name of the Token to create. Changing this forces a new resource to be created.",
+				Validators: []validator.String{
+					stringvalidator.LengthBetween(1, 100),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
-			"login_name": {
-				Type:        schema.TypeString,
+			"login_name": schema.StringAttribute{
 				Optional:    true,
-				ForceNew:    true,
+				Computed:    true,
 				Description: "The login name of the User for which the token should be created. If not set, the token is created for the authenticated user. Changing this forces a new resource to be created.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplaceIfConfigured(),
+				},
 			},
-			"expiration_date": {
-				Type:        schema.TypeString,
+			"expiration_date": schema.StringAttribute{
 				Optional:    true,
-				ForceNew:    true,
 				Computed:    true,
 				Description: "The expiration date of the token being generated, in ISO 8601 format (YYYY-MM-DD). If not set, default to no expiration.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplaceIfConfigured(),
+				},
 			},
-			"token": {
-				Type:        schema.TypeString,
+			"token": schema.StringAttribute{
 				Computed:    true,
 				Sensitive:   true,
 				Description: "The token value.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
-			"type": {
-				Type:             schema.TypeString,
-				Optional:         true,
-				Default:          UserToken,
-				ForceNew:         true,
-				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{string(UserToken), string(GlobalAnalysisToken), string(ProjectAnalysisToken)}, false)),
-				Description:      "The kind of Token to create. Changing this forces a new resource to be created. Possible values are USER_TOKEN, GLOBAL_ANALYSIS_TOKEN, or PROJECT_ANALYSIS_TOKEN. Defaults to USER_TOKEN. If set to PROJECT_ANALYSIS_TOKEN, then the project_key must also be specified.",
+			"type": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(string(UserToken)),
+				Description: "The kind of Token to create. Changing this forces a new resource to be created. Possible values are USER_TOKEN, GLOBAL_ANALYSIS_TOKEN, or PROJECT_ANALYSIS_TOKEN. Defaults to USER_TOKEN. If set to PROJECT_ANALYSIS_TOKEN, then the project_key must also be specified.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(string(UserToken), string(GlobalAnalysisToken), string(ProjectAnalysisToken)),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
-			"project_key": {
-				Type:        schema.TypeString,
+			"project_key": schema.StringAttribute{
 				Optional:    true,
-				ForceNew:    true,
 				Description: "The key of the only project that can be analyzed by the PROJECT_ANALYSIS TOKEN being created. Changing this forces a new resource to be created.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Delete: true,
+			}),
+		},
 	}
 }
 
-func resourceSonarqubeUserTokenCreate(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/user_tokens/generate"
+func (r *userTokenResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.providerConfiguration = frameworkProviderConfiguration(req.ProviderData, &resp.Diagnostics)
+}
+
+func (r *userTokenResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan userTokenResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	tokenType := TokenType(d.Get("type").(string))
-	rawQuery := url.Values{
-		"name": []string{d.Get("name").(string)},
-		"type": []string{string(tokenType)},
+	createTimeout, diags := plan.Timeouts.Create(ctx, frameworkDefaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	tokenType := TokenType(plan.Type.ValueString())
+	request := client.UserTokensGenerateRequest{
+		Name:           plan.Name.ValueString(),
+		Type:           string(tokenType),
+		ExpirationDate: plan.ExpirationDate.ValueString(),
 	}
 
 	if tokenType == UserToken {
-		loginName := d.Get("login_name").(string)
-		if loginName != "" {
-			rawQuery.Add("login", loginName)
-		}
+		request.Login = plan.LoginName.ValueString()
 	} else if tokenType == ProjectAnalysisToken {
-		projectKey := d.Get("project_key").(string)
-		if projectKey == "" {
-			return fmt.Errorf("resourceSonarqubeUserTokenCreate: 'project_key' must be configured when the token 'type' is %s", ProjectAnalysisToken)
+		if plan.ProjectKey.ValueString() == "" {
+			resp.Diagnostics.AddAttributeError(path.Root("project_key"), "resourceSonarqubeUserTokenCreate: Invalid configuration", fmt.Sprintf("'project_key' must be configured when the token 'type' is %s", ProjectAnalysisToken))
+			return
 		}
-		rawQuery.Add("projectKey", projectKey)
+		request.ProjectKey = plan.ProjectKey.ValueString()
+	}
+	if tokenType != ProjectAnalysisToken && plan.ProjectKey.ValueString() != "" {
+		resp.Diagnostics.AddAttributeWarning(path.Root("project_key"), "project_key is ignored", fmt.Sprintf("'project_key' is only used when the token 'type' is %s", ProjectAnalysisToken))
 	}
 
-	if _, ok := d.GetOk("expiration_date"); ok {
-		rawQuery.Add("expirationDate", d.Get("expiration_date").(string))
+	tokenResponse, err := r.providerConfiguration.client.UserTokens.Generate(ctx, request)
+	if err != nil {
+		resp.Diagnostics.AddError("resourceSonarqubeUserTokenCreate: Failed to create user token", err.Error())
+		return
+	}
+	if tokenResponse.Login == "" {
+		resp.Diagnostics.AddError("resourceSonarqubeUserTokenCreate: Failed to create user token", "Create response didn't contain the user login")
+		return
+	}
+	// we set the token value here as the API wont return it later
+	if tokenResponse.Token == "" {
+		resp.Diagnostics.AddError("resourceSonarqubeUserTokenCreate: Failed to create user token", "Create response didn't contain the token")
+		return
 	}
 
-	sonarQubeURL.RawQuery = rawQuery.Encode()
+	// the ID consists of the configured login_name and the token name (foo/bar)
+	plan.ID = types.StringValue(fmt.Sprintf("%s/%s", plan.LoginName.ValueString(), plan.Name.ValueString()))
+	plan.Token = types.StringValue(tokenResponse.Token)
 
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusOK,
-		"resourceSonarqubeUserTokenCreate",
-	)
+	found, err := r.read(ctx, &plan)
 	if err != nil {
-		return fmt.Errorf("error creating Sonarqube user token: %+v", err)
+		resp.Diagnostics.AddError("resourceSonarqubeUserTokenCreate: Failed to read user token", err.Error())
+		return
 	}
-	defer resp.Body.Close()
+	if !found {
+		resp.Diagnostics.AddError("resourceSonarqubeUserTokenCreate: Failed to read user token", fmt.Sprintf("Token %s was not found after its creation", plan.ID.ValueString()))
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
 
-	// Decode response into struct
-	tokenResponse := Token{}
-	err = json.NewDecoder(resp.Body).Decode(&tokenResponse)
-	if err != nil {
-		return fmt.Errorf("resourceSonarqubeUserTokenCreate: Failed to decode json into struct: %+v", err)
+func (r *userTokenResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state userTokenResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	if tokenResponse.Login != "" {
-		// the ID consists of the login_name and the token name (foo/bar)
-		d.SetId(fmt.Sprintf("%s/%s", d.Get("login_name").(string), d.Get("name").(string)))
-		// we set the token value here as the API wont return it later
-		if tokenResponse.Token != "" {
-			d.Set("token", tokenResponse.Token)
-		} else {
-			return fmt.Errorf("resourceSonarqubeUserTokenCreate: Create response didn't contain the token")
-		}
-	} else {
-		return fmt.Errorf("resourceSonarqubeUserTokenCreate: Create response didn't contain the user login")
+	readTimeout, diags := state.Timeouts.Read(ctx, frameworkDefaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
 
-	return resourceSonarqubeUserTokenRead(d, m)
+	found, err := r.read(ctx, &state)
+	if err != nil {
+		resp.Diagnostics.AddError("resourceSonarqubeUserTokenRead: Failed to read user token", err.Error())
+		return
+	}
+	if !found {
+		removeFrameworkResourceFromState(ctx, &resp.State, state.ID.ValueString(), "resourceSonarqubeUserTokenRead")
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
 
-func resourceSonarqubeUserTokenRead(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/user_tokens/search"
-
-	// split d.Id into login_name and the token name (foo/bar)
-	login := strings.Split(d.Id(), "/")
-	if login[0] != "" {
-		sonarQubeURL.RawQuery = url.Values{
-			"login": []string{login[0]},
-		}.Encode()
-	}
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"GET",
-		sonarQubeURL.String(),
-		http.StatusOK,
-		"resourceSonarqubeUserTokenRead",
-	)
-	if err != nil {
-		return fmt.Errorf("error reading Sonarqube user tokens: %+v", err)
+// Tokens cannot be modified, every attribute but the computed ones forces a new resource
+func (r *userTokenResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan userTokenResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
-	defer resp.Body.Close()
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
 
-	// Decode response into struct
-	getTokensResponse := GetTokens{}
-	err = json.NewDecoder(resp.Body).Decode(&getTokensResponse)
-	if err != nil {
-		return fmt.Errorf("resourceSonarqubeUserTokenCreate: Failed to decode json into struct: %+v", err)
-	}
-
-	// Loop over all user token to see if the current token exists.
-	if getTokensResponse.Tokens != nil {
-		for _, value := range getTokensResponse.Tokens {
-			if d.Get("name").(string) == value.Name {
-				d.SetId(fmt.Sprintf("%s/%s", d.Get("login_name").(string), d.Get("name").(string)))
-				d.Set("login_name", getTokensResponse.Login)
-				d.Set("name", value.Name)
-				if value.ExpirationDate != "" {
-					dateReceived, errTimeParse := time.Parse("2006-01-02T15:04:05-0700", value.ExpirationDate)
-					if errTimeParse != nil {
-						return fmt.Errorf("resourceSonarqubeUserTokenCreate: Failed to parse ExpirationDate: %+v", err)
-					}
-					d.Set("expiration_date", dateReceived.Format("2006-01-02"))
-				}
-				return nil
-			}
-		}
+func (r *userTokenResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state userTokenResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, frameworkDefaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
 
-	return fmt.Errorf("resourceSonarqubeUserTokenCreate: Failed to find user token: %+v", d.Id())
+	err := r.providerConfiguration.client.UserTokens.Revoke(ctx, state.Name.ValueString(), state.LoginName.ValueString())
+	if err != nil && !client.IsNotFound(err) {
+		resp.Diagnostics.AddError("resourceSonarqubeUserTokenDelete: Failed to delete user token", err.Error())
+	}
 }
 
-func resourceSonarqubeUserTokenDelete(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/user_tokens/revoke"
-	rawQuery := url.Values{
-		"name": []string{d.Get("name").(string)},
+// ImportState imports a token from an ID made of the login name and the token name (login_name/name). The token
+// value cannot be read back from the API, token is null for imported tokens.
+func (r *userTokenResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	login, name, ok := strings.Cut(req.ID, "/")
+	if !ok || name == "" {
+		resp.Diagnostics.AddError("resourceSonarqubeUserTokenImport: Invalid import ID", fmt.Sprintf("Import ID '%s' is not in the format {login_name}/{name}", req.ID))
+		return
 	}
-	login := d.Get("login_name").(string)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), name)...)
 	if login != "" {
-		rawQuery.Add("login", login)
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("login_name"), login)...)
 	}
+}
 
-	sonarQubeURL.RawQuery = rawQuery.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusNoContent,
-		"resourceSonarqubeUserTokenDelete",
-	)
+// read refreshes model from the API and reports whether the token still exists
+func (r *userTokenResource) read(ctx context.Context, model *userTokenResourceModel) (bool, error) {
+	// split the ID into login_name and the token name (foo/bar)
+	login := strings.Split(model.ID.ValueString(), "/")[0]
+	tokens, err := r.providerConfiguration.client.UserTokens.Search(ctx, login)
 	if err != nil {
-		return fmt.Errorf("error deleting Sonarqube user token: %+v", err)
+		if client.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
 	}
-	defer resp.Body.Close()
 
-	return nil
+	for _, token := range tokens.Tokens {
+		if token.Name != model.Name.ValueString() {
+			continue
+		}
+
+		model.LoginName = types.StringValue(tokens.Login)
+		model.Type = types.StringValue(token.Type)
+		// project_key is only known for project analysis tokens, keep the configured value of other tokens
+		if token.Project.Key != "" {
+			model.ProjectKey = types.StringValue(token.Project.Key)
+		}
+		// A token without expiration date has a null expiration_date rather than an empty one
+		model.ExpirationDate = types.StringNull()
+		if token.ExpirationDate != "" {
+			expirationDate, err := time.Parse("2006-01-02T15:04:05-0700", token.ExpirationDate)
+			if err != nil {
+				return false, fmt.Errorf("failed to parse expiration date %s: %+v", token.ExpirationDate, err)
+			}
+			model.ExpirationDate = types.StringValue(expirationDate.Format("2006-01-02"))
+		}
+		return true, nil
+	}
+	return false, nil
 }
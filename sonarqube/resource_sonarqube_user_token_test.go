@@ -37,8 +37,8 @@ func TestAccSonarqubeUserTokenBasic(t *testing.T) {
 	name := "sonarqube_user_token." + rnd
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubeUserTokenBasicConfig(rnd, "testAccSonarqubeUserToken"),
@@ -46,6 +46,13 @@ func TestAccSonarqubeUserTokenBasic(t *testing.T) {
 					resource.TestCheckResourceAttr(name, "name", "testAccSonarqubeUserToken"),
 				),
 			},
+			{
+				ResourceName:      name,
+				ImportState:       true,
+				ImportStateVerify: true,
+				// The token value is only returned when the token is generated
+				ImportStateVerifyIgnore: []string{"token"},
+			},
 		},
 	})
 }
@@ -70,8 +77,8 @@ func TestAccSonarqubeUserTokenWithExpirationDate(t *testing.T) {
 	expiration_date := time.Now().AddDate(0, 0, 5).Format("2006-01-02")
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubeUserTokenExpirationDateConfig(rnd, "testAccSonarqubeUserTokenWithExpirationDate", expiration_date),
@@ -101,8 +108,8 @@ func testAccSonarqubeUserTokenNoLogin(t *testing.T) {
 	name := "sonarqube_user_token." + rnd
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubeUserTokenNoLoginConfig(rnd, "testAccSonarqubeUserTokenNoLogin"),
@@ -133,8 +140,8 @@ func testAccSonarqubeUserTokenGlobalAnalysisToken(t *testing.T) {
 	name := "sonarqube_user_token." + rnd
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubeUserTokenGlobalAnalysisTokenConfig(rnd, "testAccSonarqubeUserTokenGlobalAnalysisToken"),
@@ -166,8 +173,8 @@ func testAccSonarqubeUserTokenProjectAnalysisToken(t *testing.T) {
 	name := "sonarqube_user_token." + rnd
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubeUserTokenProjectAnalysisTokenConfig(rnd, "testAccSonarqubeUserTokenProjectAnalysisToken"),
@@ -1,41 +1,30 @@
 package sonarqube
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"log"
-	"net/http"
-	"net/url"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/jdamata/terraform-provider-sonarqube/internal/client"
 )
 
-type Webhook struct {
-	Key    string `json:"key"`
-	Name   string `json:"name"`
-	Url    string `json:"url"`
-	Secret string `json:"secret"`
-}
-
-type CreateWebhookResponse struct {
-	Webhook *Webhook `json:"webhook"`
-}
-
-type ListWebhooksResponse struct {
-	Webhooks []*Webhook `json:"webhooks"`
-}
-
 // Returns the resource represented by this file.
 func resourceSonarqubeWebhook() *schema.Resource {
 	return &schema.Resource{
-		Description: "Provides a Sonarqube Webhook resource. This can be used to manage Sonarqube webhooks.",
-		Create:      resourceSonarqubeWebhookCreate,
-		Read:        resourceSonarqubeWebhookRead,
-		Update:      resourceSonarqubeWebhookUpdate,
-		Delete:      resourceSonarqubeWebhookDelete,
+		Description:   "Provides a Sonarqube Webhook resource. This can be used to manage Sonarqube webhooks.",
+		CreateContext: resourceSonarqubeWebhookCreate,
+		ReadContext:   resourceSonarqubeWebhookRead,
+		UpdateContext: resourceSonarqubeWebhookUpdate,
+		DeleteContext: resourceSonarqubeWebhookDelete,
 		Importer: &schema.ResourceImporter{
-			State: resourceSonarqubeWebhookImport,
+			StateContext: resourceSonarqubeWebhookImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Default: schema.DefaultTimeout(5 * time.Minute),
 		},
 
 		// Define the fields of this schema.
@@ -67,78 +56,37 @@ func resourceSonarqubeWebhook() *schema.Resource {
 	}
 }
 
-func resourceSonarqubeWebhookCreate(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/webhooks/create"
-
-	params := url.Values{
-		"name": []string{d.Get("name").(string)},
-		"url":  []string{d.Get("url").(string)},
-	}
-	if secret, ok := d.GetOk("secret"); ok {
-		params.Set("secret", secret.(string))
-	}
-	if project, ok := d.GetOk("project"); ok {
-		params.Set("project", project.(string))
-	}
-
-	sonarQubeURL.RawQuery = params.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusOK,
-		"resourceWebhookCreate",
-	)
-	if err != nil {
-		return fmt.Errorf("resourceWebhookCreate: Failed to call %s: %+v", sonarQubeURL.Path, err)
+func resourceSonarqubeWebhookCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	request := client.WebhooksCreateRequest{
+		Name:    d.Get("name").(string),
+		Url:     d.Get("url").(string),
+		Secret:  d.Get("secret").(string),
+		Project: d.Get("project").(string),
 	}
-	defer resp.Body.Close()
 
-	webhookResponse := CreateWebhookResponse{}
-	err = json.NewDecoder(resp.Body).Decode(&webhookResponse)
+	webhook, err := m.(*ProviderConfiguration).client.Webhooks.Create(ctx, request)
 	if err != nil {
-		return fmt.Errorf("resourceWebhookCreate: Failed to decode json into struct: %+v", err)
+		return diag.Errorf("resourceWebhookCreate: Failed to create webhook: %+v", err)
 	}
 
-	d.SetId(webhookResponse.Webhook.Key)
+	d.SetId(webhook.Key)
 
-	return resourceSonarqubeWebhookRead(d, m)
+	return resourceSonarqubeWebhookRead(ctx, d, m)
 }
 
 // unfortunately, there doesn't seem to be a way to get a webhook by its ID. the best we can do is list all webhooks and
 // loop through the result until we find the one we're looking for.
-func resourceSonarqubeWebhookRead(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/webhooks/list"
-
-	if project, ok := d.GetOk("project"); ok {
-		rawQuery := url.Values{
-			"project": []string{string(project.(string))},
-		}
-		sonarQubeURL.RawQuery = rawQuery.Encode()
-	}
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"GET",
-		sonarQubeURL.String(),
-		http.StatusOK,
-		"resourceWebhookRead",
-	)
-	if err != nil {
-		return fmt.Errorf("resourceWebhookRead: Failed to call %s: %+v", sonarQubeURL.Path, err)
-	}
-	defer resp.Body.Close()
-
-	webhookResponse := ListWebhooksResponse{}
-	err = json.NewDecoder(resp.Body).Decode(&webhookResponse)
+func resourceSonarqubeWebhookRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	webhooks, err := m.(*ProviderConfiguration).client.Webhooks.List(ctx, d.Get("project").(string))
 	if err != nil {
-		return fmt.Errorf("resourceWebhookRead: Failed to decode json into struct: %+v", err)
+		if client.IsNotFound(err) {
+			removeResourceFromState(d, "resourceSonarqubeWebhookRead")
+			return nil
+		}
+		return diag.Errorf("resourceWebhookRead: Failed to list webhooks: %+v", err)
 	}
 
-	for _, webhook := range webhookResponse.Webhooks {
+	for _, webhook := range webhooks {
 		log.Printf("[DEBUG][resourceSonarqubeWebhookRead] webhook.Key: '%s' vs %s ", webhook.Key, d.Id())
 		if webhook.Key == d.Id() {
 			d.Set("name", webhook.Name)
@@ -156,66 +104,37 @@ func resourceSonarqubeWebhookRead(d *schema.ResourceData, m interface{}) error {
 		}
 	}
 
-	return fmt.Errorf("resourceWebhookRead: Failed to find webhook with key %s", d.Id())
+	removeResourceFromState(d, "resourceSonarqubeWebhookRead")
+	return nil
 }
 
-func resourceSonarqubeWebhookUpdate(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/webhooks/update"
-
-	params := url.Values{
-		"webhook": []string{d.Id()},
-		"name":    []string{d.Get("name").(string)},
-		"url":     []string{d.Get("url").(string)},
+func resourceSonarqubeWebhookUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	request := client.WebhooksUpdateRequest{
+		Webhook: d.Id(),
+		Name:    d.Get("name").(string),
+		Url:     d.Get("url").(string),
+		Secret:  d.Get("secret").(string),
+		Project: d.Get("project").(string),
 	}
-	project := d.Get("project").(string)
-	if project != "" {
-		params.Set("project", project)
-	}
-	if secret, ok := d.GetOk("secret"); ok {
-		params.Set("secret", secret.(string))
-	}
-	sonarQubeURL.RawQuery = params.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusNoContent,
-		"resourceWebhookUpdate",
-	)
+
+	err := m.(*ProviderConfiguration).client.Webhooks.Update(ctx, request)
 	if err != nil {
-		return fmt.Errorf("resourceWebhookUpdate: Failed to update webhook: %+v", err)
+		return diag.Errorf("resourceWebhookUpdate: Failed to update webhook: %+v", err)
 	}
-	defer resp.Body.Close()
 
-	return resourceSonarqubeWebhookRead(d, m)
+	return resourceSonarqubeWebhookRead(ctx, d, m)
 }
 
-func resourceSonarqubeWebhookDelete(d *schema.ResourceData, m interface{}) error {
-	sonarQubeURL := m.(*ProviderConfiguration).sonarQubeURL
-	sonarQubeURL.Path = strings.TrimSuffix(sonarQubeURL.Path, "/") + "/api/webhooks/delete"
-
-	sonarQubeURL.RawQuery = url.Values{
-		"webhook": []string{d.Id()},
-	}.Encode()
-
-	resp, err := httpRequestHelper(
-		m.(*ProviderConfiguration).httpClient,
-		"POST",
-		sonarQubeURL.String(),
-		http.StatusNoContent,
-		"resourceWebhookDelete",
-	)
+func resourceSonarqubeWebhookDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	err := m.(*ProviderConfiguration).client.Webhooks.Delete(ctx, d.Id())
 	if err != nil {
-		return fmt.Errorf("resourceWebhookDelete: Failed to delete webhook: %+v", err)
+		return diag.Errorf("resourceWebhookDelete: Failed to delete webhook: %+v", err)
 	}
-	defer resp.Body.Close()
 
 	return nil
 }
 
-func resourceSonarqubeWebhookImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+func resourceSonarqubeWebhookImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
 	// import id in format {key}/{project}
 	importIdComponents := strings.SplitN(d.Id(), "/", 2)
 
@@ -230,8 +149,8 @@ func resourceSonarqubeWebhookImport(d *schema.ResourceData, m interface{}) ([]*s
 
 	// set Id to key for Read
 	d.SetId(importIdComponents[0])
-	if err := resourceSonarqubeWebhookRead(d, m); err != nil {
-		return nil, err
+	if diags := resourceSonarqubeWebhookRead(ctx, d, m); diags.HasError() {
+		return nil, diagnosticsToError(diags)
 	}
 	return []*schema.ResourceData{d}, nil
 }
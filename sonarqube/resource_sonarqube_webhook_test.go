@@ -18,8 +18,8 @@ func TestAccSonarqubeWebhookBasic(t *testing.T) {
 	secret := acctest.RandString(16)
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubeWebhookBasicConfig(rnd, name, url, secret),
@@ -56,8 +56,8 @@ func TestAccSonarqubeWebhookUpdate(t *testing.T) {
 	secondSecret := acctest.RandString(16)
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubeWebhookBasicConfig(rnd, firstName, firstUrl, firstSecret),
@@ -98,8 +98,8 @@ func TestAccSonarqubeWebhookProjectBasic(t *testing.T) {
 	project := "testAccSonarqubeWebhookProject"
 
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheck(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccSonarqubeWebhookProjectBasicConfig(rnd, name, url, project),
@@ -1,8 +1,19 @@
 package sonarqube
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
 	"reflect"
 	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 // Checks if two string slices are equal, optionally ignoring ordering
@@ -18,3 +29,60 @@ func stringSlicesEqual(a, b []string, ignoreOrder bool) bool {
 
 	return reflect.DeepEqual(a, b)
 }
+
+// Removes a resource that was deleted outside of Terraform from the state, so the next plan recreates it instead of failing
+func removeResourceFromState(d *schema.ResourceData, caller string) {
+	log.Printf("[WARN][%s] %s no longer exists, removing it from the state", caller, d.Id())
+	d.SetId("")
+}
+
+// Removes a plugin framework resource that was deleted outside of Terraform from the state, like removeResourceFromState
+func removeFrameworkResourceFromState(ctx context.Context, state *tfsdk.State, id string, caller string) {
+	log.Printf("[WARN][%s] %s no longer exists, removing it from the state", caller, id)
+	state.RemoveResource(ctx)
+}
+
+// Validates that a string attribute holds a non-negative duration such as "500ms", "30s" or "2m"
+func validateDuration(i interface{}, k string) (warnings []string, errors []error) {
+	v, ok := i.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %s to be string", k)}
+	}
+	duration, err := time.ParseDuration(v)
+	if err != nil {
+		return nil, []error{fmt.Errorf("expected %s to be a duration such as \"30s\", got %q: %+v", k, v, err)}
+	}
+	if duration < 0 {
+		return nil, []error{fmt.Errorf("expected %s to not be negative, got %q", k, v)}
+	}
+	return nil, nil
+}
+
+// Converts the error diagnostics returned by a CRUD function to an error, e.g. to return them from an importer
+func diagnosticsToError(diags diag.Diagnostics) error {
+	var messages []string
+	for _, diagnostic := range diags {
+		if diagnostic.Severity != diag.Error {
+			continue
+		}
+		message := diagnostic.Summary
+		if diagnostic.Detail != "" {
+			message += ": " + diagnostic.Detail
+		}
+		messages = append(messages, message)
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+	return errors.New(strings.Join(messages, "; "))
+}
+
+// Returns an error diagnostic pointing at attribute, e.g. for an invalid combination of arguments
+func attributeDiagnostics(attribute string, summary string, err error) diag.Diagnostics {
+	return diag.Diagnostics{{
+		Severity:      diag.Error,
+		Summary:       summary,
+		Detail:        err.Error(),
+		AttributePath: cty.GetAttrPath(attribute),
+	}}
+}